@@ -7,9 +7,12 @@ import (
 	"strings"
 
 	"github.com/alexinslc/chunk/internal/bench"
+	"github.com/alexinslc/chunk/internal/search"
 	"github.com/spf13/cobra"
 )
 
+var benchTrustKeys []string
+
 var BenchCmd = &cobra.Command{
 	Use:   "bench",
 	Short: "Manage recipe benches",
@@ -39,7 +42,7 @@ Examples:
 		if len(args) > 1 {
 			url = args[1]
 		}
-		return addBench(name, url)
+		return addBench(name, url, benchTrustKeys)
 	},
 }
 
@@ -69,6 +72,52 @@ Example:
 	},
 }
 
+var benchTrustCmd = &cobra.Command{
+	Use:   "trust <user/repo> <public-key>",
+	Short: "Trust a recipe signing key for a bench",
+	Long: `Pin an ed25519 public key (base64) for a bench. Recipes from the bench are
+accepted if their .sig file verifies against any of its trusted keys.
+
+Example:
+  chunk bench trust myorg/recipes 3Jq1W0rQ...=`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return trustBenchKey(args[0], args[1])
+	},
+}
+
+var benchUntrustCmd = &cobra.Command{
+	Use:   "untrust <user/repo> <public-key>",
+	Short: "Remove a trusted signing key from a bench",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return untrustBenchKey(args[0], args[1])
+	},
+}
+
+var benchPolicyCmd = &cobra.Command{
+	Use:   "policy <off|warn|require> [user/repo]",
+	Short: "Set the recipe signature policy",
+	Long: `Set how recipe signatures are enforced, for one bench or (without a bench)
+as the default for all benches.
+
+  off      Do not check signatures
+  warn     Install unsigned or tampered recipes, but print a warning
+  require  Refuse recipes without a valid signature from a trusted key
+
+Examples:
+  chunk bench policy require                      # Default for all benches
+  chunk bench policy warn myorg/recipes           # Override for one bench`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := ""
+		if len(args) > 1 {
+			name = args[1]
+		}
+		return setBenchPolicy(args[0], name)
+	},
+}
+
 var benchListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List all benches",
@@ -111,12 +160,19 @@ func listBenches() error {
 	return nil
 }
 
-func addBench(name string, url string) error {
+func addBench(name string, url string, trustKeys []string) error {
 	manager, err := bench.NewManager()
 	if err != nil {
 		return fmt.Errorf("failed to initialize bench manager: %w", err)
 	}
 
+	// Validate keys before cloning so a typo does not leave an unpinned bench
+	for _, key := range trustKeys {
+		if _, err := bench.ParsePublicKey(key); err != nil {
+			return err
+		}
+	}
+
 	fmt.Println()
 	fmt.Printf("🔄 Adding bench: %s\n", name)
 	if url != "" {
@@ -132,6 +188,20 @@ func addBench(name string, url string) error {
 		return err
 	}
 
+	for _, key := range trustKeys {
+		if err := manager.Trust(name, key); err != nil {
+			return err
+		}
+	}
+
+	// A missing index only makes search slower, so do not fail the add
+	if b, err := manager.Get(name); err == nil {
+		fmt.Println("   Indexing recipes...")
+		if err := search.BuildIndex(*b); err != nil {
+			fmt.Printf("   ⚠️  Failed to build search index: %v\n", err)
+		}
+	}
+
 	fmt.Println()
 	fmt.Printf("✅ Bench '%s' added successfully!\n", name)
 	fmt.Println()
@@ -180,6 +250,14 @@ func infoBench(name string) error {
 	fmt.Printf("  Path:  %s\n", b.Path)
 	fmt.Printf("  Added: %s\n", b.Added.Format("2006-01-02 15:04:05"))
 	fmt.Println()
+	fmt.Printf("  Signature policy: %s\n", manager.Policy(*b))
+	if len(b.TrustedKeys) > 0 {
+		fmt.Println("  Trusted keys:")
+		for _, key := range b.TrustedKeys {
+			fmt.Printf("    %s\n", key)
+		}
+	}
+	fmt.Println()
 
 	// Count recipes
 	recipesPath := filepath.Join(b.Path, "Recipes")
@@ -202,12 +280,77 @@ func infoBench(name string) error {
 	return nil
 }
 
+func trustBenchKey(name, publicKey string) error {
+	manager, err := bench.NewManager()
+	if err != nil {
+		return fmt.Errorf("failed to initialize bench manager: %w", err)
+	}
+
+	if err := manager.Trust(name, publicKey); err != nil {
+		return err
+	}
+
+	fmt.Println()
+	fmt.Printf("✅ Key trusted for bench '%s'\n", name)
+	fmt.Println()
+
+	return nil
+}
+
+func untrustBenchKey(name, publicKey string) error {
+	manager, err := bench.NewManager()
+	if err != nil {
+		return fmt.Errorf("failed to initialize bench manager: %w", err)
+	}
+
+	if err := manager.Untrust(name, publicKey); err != nil {
+		return err
+	}
+
+	fmt.Println()
+	fmt.Printf("✅ Key removed from bench '%s'\n", name)
+	fmt.Println()
+
+	return nil
+}
+
+func setBenchPolicy(value, name string) error {
+	policy, err := bench.ParseSignaturePolicy(value)
+	if err != nil {
+		return err
+	}
+
+	manager, err := bench.NewManager()
+	if err != nil {
+		return fmt.Errorf("failed to initialize bench manager: %w", err)
+	}
+
+	if err := manager.SetPolicy(name, policy); err != nil {
+		return err
+	}
+
+	fmt.Println()
+	if name == "" {
+		fmt.Printf("✅ Default signature policy set to '%s'\n", policy)
+	} else {
+		fmt.Printf("✅ Signature policy for bench '%s' set to '%s'\n", name, policy)
+	}
+	fmt.Println()
+
+	return nil
+}
+
 func init() {
 	// Add subcommands
 	BenchCmd.AddCommand(benchAddCmd)
 	BenchCmd.AddCommand(benchRemoveCmd)
 	BenchCmd.AddCommand(benchInfoCmd)
 	BenchCmd.AddCommand(benchListCmd)
+	BenchCmd.AddCommand(benchTrustCmd)
+	BenchCmd.AddCommand(benchUntrustCmd)
+	BenchCmd.AddCommand(benchPolicyCmd)
+
+	benchAddCmd.Flags().StringArrayVar(&benchTrustKeys, "trust-key", nil, "Trust a recipe signing key (base64 ed25519 public key) for the new bench; repeatable")
 
 	// Suppress usage printing on errors
 	BenchCmd.SilenceUsage = true
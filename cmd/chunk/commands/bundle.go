@@ -0,0 +1,183 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/alexinslc/chunk/internal/install"
+	"github.com/alexinslc/chunk/internal/sources"
+	"github.com/alexinslc/chunk/internal/tracking"
+	"github.com/alexinslc/chunk/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	bundleOutput  string
+	bundleVariant string
+)
+
+var BundleCmd = &cobra.Command{
+	Use:   "bundle",
+	Short: "Build server bundles for offline installs",
+	Long: `Build self-contained server bundles for hosts without internet access.
+
+A bundle (.chunkbundle) holds the pack archive, every server mod, the mod
+loader together with the files its installer produces, the recipe snapshot
+and a checksum manifest. chunk install ./server.chunkbundle installs it
+without any network access.`,
+}
+
+var bundleExportCmd = &cobra.Command{
+	Use:   "export <installation|recipe>",
+	Short: "Export a server bundle",
+	Long: `Export a server bundle from an installed server or a modpack.
+
+An installation is given by instance name or server directory, and is
+exported exactly as its chunk.lock pins it, in the variant it was installed
+with. Anything else is a modpack identifier, as for chunk install, and its
+latest version is exported. The bundle records the recipe variant, and
+installing it records the variant with the installation.
+
+Exporting needs network access and Java: missing files are downloaded and
+the loader installer runs once, so the offline install does not have to.
+
+Examples:
+  chunk bundle export survival-eu                     # Installed instance
+  chunk bundle export /opt/minecraft/server           # Installation at a path
+  chunk bundle export atm9 -o atm9.chunkbundle        # Latest recipe
+  chunk bundle export atm9 --variant lite             # Latest recipe, lite variant
+  chunk install ./atm9.chunkbundle --dir ./server     # Install offline`,
+	Args: cobra.ExactArgs(1),
+	RunE: runBundleExport,
+}
+
+func runBundleExport(cmd *cobra.Command, args []string) error {
+	target := args[0]
+
+	installation, err := findBundleInstallation(target)
+	if err != nil {
+		return err
+	}
+	if installation != nil && bundleVariant != "" {
+		return fmt.Errorf("--variant only applies to recipes; %s is exported in the variant it was installed with", target)
+	}
+
+	output := bundleOutput
+	if output == "" {
+		name := bundleName(target)
+		if installation != nil && installation.Name != "" {
+			name = installation.Name
+		}
+		output = name + sources.BundleExtension
+	}
+	if !sources.IsBundle(output) {
+		output += sources.BundleExtension
+	}
+
+	fmt.Println()
+	if installation != nil {
+		ui.PrintInfo(fmt.Sprintf("Exporting installation %s (%s)", installation.Slug, installation.Path))
+	} else {
+		ui.PrintInfo(fmt.Sprintf("Exporting modpack %s", target))
+	}
+
+	ctx, stop := interruptContext(cmd)
+	defer stop()
+
+	result, err := install.NewInstaller().ExportBundle(ctx, &install.BundleOptions{
+		Identifier:   target,
+		Installation: installation,
+		Variant:      bundleVariant,
+		Output:       output,
+	})
+	if err != nil {
+		ui.PrintError(fmt.Sprintf("Export failed: %v", err))
+		return err
+	}
+
+	fmt.Println()
+	fmt.Println("✅ Bundle exported")
+	fmt.Println()
+	fmt.Printf("   Modpack:   %s\n", result.ModpackName)
+	fmt.Printf("   Minecraft: %s\n", result.MCVersion)
+	fmt.Printf("   Loader:    %s %s\n", result.Loader, result.LoaderVersion)
+	fmt.Printf("   Mods:      %d\n", result.Mods)
+	fmt.Printf("   Bundle:    %s (%.1f MB)\n", result.Path, float64(result.Size)/(1024*1024))
+	fmt.Println()
+	fmt.Println("To install it on a host without internet access:")
+	fmt.Printf("   chunk install ./%s\n", filepath.Base(result.Path))
+	fmt.Println()
+
+	return nil
+}
+
+// findBundleInstallation returns the tracked installation a bundle export
+// target names, by instance name or server directory, or nil for a modpack
+func findBundleInstallation(target string) (*tracking.Installation, error) {
+	installation, err := lookupInstance(target)
+	if err != nil || installation != nil {
+		return installation, err
+	}
+
+	info, err := os.Stat(target)
+	if err != nil || !info.IsDir() {
+		return nil, nil
+	}
+
+	absDir, err := filepath.Abs(target)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve server path: %w", err)
+	}
+
+	tracker, err := tracking.NewTracker()
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize tracker: %w", err)
+	}
+
+	installation, err = tracker.GetInstallation(absDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get installation info: %w", err)
+	}
+	if installation == nil {
+		return nil, fmt.Errorf("no installation found at %s", absDir)
+	}
+	return installation, nil
+}
+
+// bundleName derives a bundle file name from a modpack identifier
+func bundleName(identifier string) string {
+	name := identifier
+	if idx := strings.LastIndex(name, "::"); idx >= 0 {
+		name = name[idx+2:]
+	}
+	name = strings.TrimPrefix(name, "modrinth:")
+	name = filepath.Base(filepath.FromSlash(name))
+	name = strings.TrimSuffix(name, filepath.Ext(name))
+	if name == "" || name == "." || name == string(filepath.Separator) {
+		return "server"
+	}
+	return name
+}
+
+// InstallsBundle reports whether a command line installs a bundle, which
+// must not touch the network (not even to add the core bench)
+func InstallsBundle(cmd *cobra.Command, args []string) bool {
+	return cmd == InstallCmd && len(args) > 0 && sources.IsBundle(args[0])
+}
+
+func init() {
+	BundleCmd.AddCommand(bundleExportCmd)
+
+	bundleExportCmd.Flags().StringVarP(&bundleOutput, "output", "o", "", "Bundle file to write (default: ./<name>.chunkbundle)")
+	bundleExportCmd.Flags().StringVar(&bundleVariant, "variant", "", "Recipe variant to export (see chunk info <recipe>)")
+
+	// Suppress usage printing on errors
+	BundleCmd.SilenceUsage = true
+	BundleCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		fmt.Fprintf(os.Stderr, "Error: %v\n\n", err)
+		cmd.Usage()
+		return err
+	})
+}
@@ -0,0 +1,25 @@
+package commands
+
+import "testing"
+
+func TestBundleName(t *testing.T) {
+	tests := []struct {
+		identifier string
+		want       string
+	}{
+		{"atm9", "atm9"},
+		{"usechunk/recipes::atm9", "atm9"},
+		{"modrinth:fabulously-optimized", "fabulously-optimized"},
+		{"alexinslc/my-modpack", "my-modpack"},
+		{"./packs/test.mrpack", "test"},
+		{"/", "server"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.identifier, func(t *testing.T) {
+			if got := bundleName(tt.identifier); got != tt.want {
+				t.Errorf("bundleName(%q) = %q, want %q", tt.identifier, got, tt.want)
+			}
+		})
+	}
+}
@@ -27,6 +27,7 @@ This command will:
   - Identify downloads for outdated versions
   - Identify downloads for uninstalled modpacks
   - Identify failed downloads (partial files)
+  - Identify shared mod files no tracked installation uses
   - Calculate space that will be freed
   - Prompt before deletion
 
@@ -102,6 +103,9 @@ func showCacheStats(manager *cache.Manager) error {
 		if stats.PartialFiles > 0 {
 			fmt.Printf("  Failed downloads:      %d\n", stats.PartialFiles)
 		}
+		if stats.UnreferencedBlobs > 0 {
+			fmt.Printf("  Unused mod files:      %d\n", stats.UnreferencedBlobs)
+		}
 		fmt.Printf("  Reclaimable space:     %s\n", formatSize(stats.TotalSize))
 		fmt.Println()
 		fmt.Println("Run 'chunk cleanup' to remove these files.")
@@ -219,6 +223,10 @@ func cleanupRemovableFiles(manager *cache.Manager) error {
 // displayCachedFile displays information about a cached file
 func displayCachedFile(file *cache.CachedFile) {
 	filename := filepath.Base(file.Path)
+	if file.Blob != "" {
+		// Blob names are digests; show a short prefix
+		filename = "mod blob " + file.Blob[:min(12, len(file.Blob))]
+	}
 	sizeStr := formatSize(file.Size)
 
 	var reason string
@@ -229,6 +237,8 @@ func displayCachedFile(file *cache.CachedFile) {
 		reason = "outdated"
 	case "uninstalled":
 		reason = "no longer installed"
+	case "unreferenced":
+		reason = "not used by any installation"
 	default:
 		// This should not happen as Reason is always set by AnalyzeCache
 		reason = "unknown"
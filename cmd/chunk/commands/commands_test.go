@@ -65,6 +65,15 @@ func TestInstallCommandFlags(t *testing.T) {
 	if skipVerifyFlag.DefValue != "false" {
 		t.Errorf("Expected --skip-verify default to be 'false', got '%s'", skipVerifyFlag.DefValue)
 	}
+
+	// Check that --frozen flag exists
+	frozenFlag := InstallCmd.Flags().Lookup("frozen")
+	if frozenFlag == nil {
+		t.Fatal("Expected --frozen flag to exist")
+	}
+	if frozenFlag.DefValue != "false" {
+		t.Errorf("Expected --frozen default to be 'false', got '%s'", frozenFlag.DefValue)
+	}
 }
 
 func TestSearchCommand(t *testing.T) {
@@ -88,6 +97,21 @@ func TestSearchCommand(t *testing.T) {
 			args:    []string{},
 			wantErr: true,
 		},
+		{
+			name:    "search with only filters",
+			args:    []string{"--loader", "fabric", "--mc", ">=1.20", "--max-ram", "8"},
+			wantErr: false,
+		},
+		{
+			name:    "search with invalid version range",
+			args:    []string{"atm", "--mc", ">=1.20.x"},
+			wantErr: true,
+		},
+		{
+			name:    "search with invalid sort",
+			args:    []string{"atm", "--mc", "1.20.1", "--sort", "popular"},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -182,26 +206,33 @@ func TestUpgradeCommandFlags(t *testing.T) {
 }
 
 func TestDiffCommand(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
 	tests := []struct {
 		name    string
 		args    []string
 		wantErr bool
 	}{
 		{
-			name:    "diff with modpack name",
+			name:    "diff with modpack that is not installed",
 			args:    []string{"atm9"},
-			wantErr: false,
+			wantErr: true,
 		},
 		{
-			name:    "diff with github repo",
+			name:    "diff with github repo that is not installed",
 			args:    []string{"alexinslc/my-modpack"},
-			wantErr: false,
+			wantErr: true,
 		},
 		{
-			name:    "diff without args",
+			name:    "diff without args or installation",
 			args:    []string{},
 			wantErr: true,
 		},
+		{
+			name:    "diff with too many args",
+			args:    []string{"atm9", "extra"},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
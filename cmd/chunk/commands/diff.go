@@ -1,39 +1,266 @@
 package commands
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"path/filepath"
+	"strings"
 
+	"github.com/alexinslc/chunk/internal/config"
+	"github.com/alexinslc/chunk/internal/converter"
+	"github.com/alexinslc/chunk/internal/install"
+	"github.com/alexinslc/chunk/internal/preserve"
+	"github.com/alexinslc/chunk/internal/sources"
+	"github.com/alexinslc/chunk/internal/tracking"
 	"github.com/spf13/cobra"
 )
 
+var (
+	diffDir     string
+	diffAgainst string
+	diffJSON    bool
+)
+
 var DiffCmd = &cobra.Command{
-	Use:   "diff <modpack>",
+	Use:   "diff [modpack|instance]",
 	Short: "Show differences between modpack versions",
-	Long: `Display differences between the current installed version and the latest version.
+	Long: `Compare an installed server with the latest version of its modpack.
+
+The installed side is the recipe recorded when the server was installed
+(see chunk list). The other side is fetched from the same source, or from
+any source given with --against, in the variant the server was installed
+with. Recipe archives are downloaded to read
+their mod lists and kept in the download cache for the upgrade.
 
 Shows:
   - Minecraft version changes
-  - Mod loader version changes (Forge/Fabric/NeoForge)
+  - Mod loader changes (Forge/Fabric/NeoForge/Quilt)
   - Added mods
   - Removed mods
   - Updated mods
-  - Configuration changes
+  - Recommendations for breaking changes
 
 Examples:
-  chunk diff atm9
-  chunk diff alexinslc/my-cool-mod`,
-	Args: cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		modpack := args[0]
-		fmt.Printf("Checking differences for: %s\n", modpack)
-		fmt.Println()
-		fmt.Println("⚠️  Diff functionality not yet implemented")
+  chunk diff atm9                            # Installed atm9 vs its latest recipe
+  chunk diff survival-eu                     # The instance named survival-eu
+  chunk diff --dir /opt/minecraft/server     # Installation at a path
+  chunk diff atm9 --against my-bench::atm9   # Compare with another source
+  chunk diff atm9 --json                     # Machine-readable report`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runDiff,
+}
+
+func init() {
+	DiffCmd.Flags().StringVarP(&diffDir, "dir", "d", "", "Server directory of the installation (default: ./server)")
+	DiffCmd.Flags().StringVar(&diffAgainst, "against", "", "Modpack source to compare with (default: the installed modpack)")
+	DiffCmd.Flags().BoolVar(&diffJSON, "json", false, "Output in JSON format")
+}
+
+// diffReport is the --json output of chunk diff
+type diffReport struct {
+	Path   string `json:"path"`
+	Slug   string `json:"slug"`
+	Target string `json:"target"`
+	*preserve.ModpackDiff
+}
+
+func runDiff(cmd *cobra.Command, args []string) error {
+	tracker, err := tracking.NewTracker()
+	if err != nil {
+		return fmt.Errorf("failed to initialize tracker: %w", err)
+	}
+
+	slug := ""
+	if len(args) > 0 {
+		slug = args[0]
+	}
+
+	installation, err := findDiffInstallation(tracker, slug, diffDir)
+	if err != nil {
+		return err
+	}
+	if installation.RecipeSnapshot == nil {
+		return fmt.Errorf("installation at %s has no recorded recipe; reinstall it to enable diff", installation.Path)
+	}
+
+	target := diffAgainst
+	if target == "" {
+		target = installation.Slug
+	}
+
+	if !diffJSON {
+		fmt.Printf("Comparing %s (%s) with %s...\n", installation.Slug, installation.Path, target)
+	}
+
+	ctx, stop := interruptContext(cmd)
+	defer stop()
+
+	// The same modpack from any bench is compared in the installed variant
+	variant := ""
+	if _, recipeName := sources.ParseRecipeIdentifier(target); recipeName == installation.Slug {
+		variant = installation.Variant
+	}
+
+	newModpack, err := install.NewInstaller().FetchVariant(ctx, target, variant)
+	if err != nil {
+		return fmt.Errorf("failed to fetch %s: %w", target, err)
+	}
+
+	oldManifest, err := manifestFromSnapshot(installation.RecipeSnapshot)
+	if err != nil {
+		return err
+	}
+	if err := resolveDiffLoaders(ctx, converter.NewLoaderInstaller(), installation.Path, oldManifest, newModpack); err != nil {
+		return err
+	}
+
+	differ := preserve.NewVersionDiffer()
+	diff := differ.CompareModpacks(oldManifest, preserve.ManifestFromModpack(newModpack))
+
+	if diffJSON {
+		data, err := json.MarshalIndent(diffReport{
+			Path:        installation.Path,
+			Slug:        installation.Slug,
+			Target:      target,
+			ModpackDiff: diff,
+		}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if diff.MCVersionChange == nil && diff.LoaderChange == nil &&
+		len(diff.ModsAdded) == 0 && len(diff.ModsRemoved) == 0 && len(diff.ModsUpdated) == 0 {
 		fmt.Println()
-		fmt.Println("Diff output will show:")
-		fmt.Println("  - Minecraft version: 1.20.1 → 1.20.2")
-		fmt.Println("  - Mod loader: Forge 47.1.0 → 47.2.0")
-		fmt.Println("  + Added mods (green)")
-		fmt.Println("  - Removed mods (red)")
-		fmt.Println("  ~ Updated mods (yellow)")
-	},
+		fmt.Printf("✓ No differences: %s matches %s\n", installation.Slug, target)
+		return nil
+	}
+
+	differ.PrintDiff(diff)
+	fmt.Println()
+	fmt.Println("To apply these changes, run:")
+	fmt.Printf("  chunk upgrade %s --dir %s\n", target, installation.Path)
+	return nil
+}
+
+// resolveDiffLoaders settles the loader versions being compared: the one the
+// server was installed with, and the one an upgrade would install for the new
+// modpack, as recipes may ask for "latest" or leave the version out
+func resolveDiffLoaders(ctx context.Context, loaders *converter.LoaderInstaller, serverDir string, oldManifest *config.ChunkManifest, newModpack *sources.Modpack) error {
+	if installed, err := config.LoadChunkManifest(filepath.Join(serverDir, config.ChunkManifestFile)); err == nil && installed.LoaderVersion != "" {
+		oldManifest.LoaderVersion = installed.LoaderVersion
+	}
+
+	version, err := loaders.ResolveLoaderVersion(ctx, newModpack.Loader, newModpack.MCVersion, newModpack.LoaderVersion)
+	if err != nil {
+		return fmt.Errorf("failed to select %s version: %w", newModpack.Loader, err)
+	}
+	newModpack.LoaderVersion = version
+	return nil
+}
+
+// findDiffInstallation picks the tracked installation to compare: the one at
+// dir if given, otherwise the instance named slug or the only installation of
+// slug, otherwise ./server
+func findDiffInstallation(tracker *tracking.Tracker, slug, dir string) (*tracking.Installation, error) {
+	if dir == "" && slug != "" {
+		if tracking.ValidateName(slug) == nil {
+			instance, err := tracker.GetInstallationByName(slug)
+			if err != nil {
+				return nil, fmt.Errorf("failed to look up instance %s: %w", slug, err)
+			}
+			if instance != nil {
+				return instance, nil
+			}
+		}
+
+		installations, err := tracker.ListInstallations()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list installations: %w", err)
+		}
+
+		var matches []*tracking.Installation
+		for _, installation := range installations {
+			if installation.Slug == slug {
+				matches = append(matches, installation)
+			}
+		}
+
+		switch len(matches) {
+		case 0:
+			return nil, fmt.Errorf("%s is not installed; see chunk list", slug)
+		case 1:
+			return matches[0], nil
+		default:
+			paths := make([]string, 0, len(matches))
+			for _, match := range matches {
+				paths = append(paths, fmt.Sprintf("%s (%s)", match.Name, match.Path))
+			}
+			return nil, fmt.Errorf("%s is installed in several places, name an instance or use --dir: %s", slug, strings.Join(paths, ", "))
+		}
+	}
+
+	if dir == "" {
+		dir = "./server"
+	}
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve server path: %w", err)
+	}
+
+	installation, err := tracker.GetInstallation(absDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get installation info: %w", err)
+	}
+	if installation == nil {
+		return nil, fmt.Errorf("no installation found at %s", absDir)
+	}
+	return installation, nil
+}
+
+// snapshotRecipe is the part of a tracked recipe snapshot chunk diff reads
+type snapshotRecipe struct {
+	Name          string `json:"name"`
+	MCVersion     string `json:"mc_version"`
+	Loader        string `json:"loader"`
+	LoaderVersion string `json:"loader_version"`
+	Mods          []struct {
+		Name        string `json:"name"`
+		Version     string `json:"version"`
+		FileName    string `json:"filename"`
+		DownloadURL string `json:"download_url"`
+		Side        string `json:"side"`
+	} `json:"mods"`
+}
+
+// manifestFromSnapshot converts a tracking recipe snapshot into a manifest
+func manifestFromSnapshot(snapshot map[string]interface{}) (*config.ChunkManifest, error) {
+	// Snapshots are maps in memory and decoded JSON on disk; round-trip to read both
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read recipe snapshot: %w", err)
+	}
+
+	var recipe snapshotRecipe
+	if err := json.Unmarshal(data, &recipe); err != nil {
+		return nil, fmt.Errorf("failed to read recipe snapshot: %w", err)
+	}
+
+	manifest := &config.ChunkManifest{
+		Name:          recipe.Name,
+		MCVersion:     recipe.MCVersion,
+		Loader:        recipe.Loader,
+		LoaderVersion: recipe.LoaderVersion,
+	}
+	for _, mod := range recipe.Mods {
+		if mod.Side == string(sources.SideClient) {
+			continue
+		}
+		manifest.Mods = append(manifest.Mods, preserve.ManifestMod(mod.Name, mod.Version, mod.FileName, mod.DownloadURL, mod.Side))
+	}
+
+	return manifest, nil
 }
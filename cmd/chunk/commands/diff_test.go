@@ -0,0 +1,208 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alexinslc/chunk/internal/config"
+	"github.com/alexinslc/chunk/internal/converter"
+	"github.com/alexinslc/chunk/internal/metadata"
+	"github.com/alexinslc/chunk/internal/preserve"
+	"github.com/alexinslc/chunk/internal/sources"
+	"github.com/alexinslc/chunk/internal/tracking"
+)
+
+func TestManifestFromSnapshot(t *testing.T) {
+	snapshot := map[string]interface{}{
+		"name":           "ATM9",
+		"mc_version":     "1.20.1",
+		"loader":         "forge",
+		"loader_version": "47.2.0",
+		"mods": []map[string]interface{}{
+			{"name": "", "filename": "mods/jei-1.20.1-forge-15.2.0.27.jar", "side": "both"},
+			{"name": "", "filename": "mods/oculus-1.6.9.jar", "side": "client"},
+		},
+	}
+
+	// Snapshots read back from installed.json hold decoded JSON rather than typed maps
+	data, _ := json.Marshal(snapshot)
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Failed to decode snapshot: %v", err)
+	}
+
+	for name, snap := range map[string]map[string]interface{}{"in memory": snapshot, "decoded": decoded} {
+		t.Run(name, func(t *testing.T) {
+			manifest, err := manifestFromSnapshot(snap)
+			if err != nil {
+				t.Fatalf("manifestFromSnapshot failed: %v", err)
+			}
+			if manifest.MCVersion != "1.20.1" || manifest.LoaderVersion != "47.2.0" {
+				t.Errorf("Unexpected versions: %+v", manifest)
+			}
+			if len(manifest.Mods) != 1 {
+				t.Fatalf("Expected client mods to be skipped, got %+v", manifest.Mods)
+			}
+			if manifest.Mods[0].ID != "jei" || manifest.Mods[0].Version != "1.20.1-forge-15.2.0.27" {
+				t.Errorf("Unexpected mod: %+v", manifest.Mods[0])
+			}
+		})
+	}
+}
+
+func TestDiffSnapshotAgainstModpack(t *testing.T) {
+	oldManifest, err := manifestFromSnapshot(map[string]interface{}{
+		"mc_version":     "1.20.1",
+		"loader":         "forge",
+		"loader_version": "47.1.0",
+		"mods": []map[string]interface{}{
+			{"filename": "mods/jei-1.20.1-forge-15.2.0.27.jar"},
+			{"filename": "mods/create-1.20.1-0.5.1.f.jar"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("manifestFromSnapshot failed: %v", err)
+	}
+
+	newManifest := preserve.ManifestFromModpack(&sources.Modpack{
+		MCVersion:     "1.20.1",
+		Loader:        sources.LoaderForge,
+		LoaderVersion: "47.2.0",
+		Mods: []*sources.Mod{
+			{FileName: "mods/jei-1.20.1-forge-15.3.0.4.jar", Side: sources.SideBoth},
+			{FileName: "mods/ftb-chunks-forge-2001.3.1.jar", Side: sources.SideServer},
+		},
+	})
+
+	diff := preserve.NewVersionDiffer().CompareModpacks(oldManifest, newManifest)
+
+	if diff.LoaderChange == nil || diff.LoaderChange.VersionTo != "47.2.0" {
+		t.Errorf("Expected loader change to 47.2.0, got %+v", diff.LoaderChange)
+	}
+	if len(diff.ModsUpdated) != 1 || diff.ModsUpdated[0].ID != "jei" {
+		t.Errorf("Expected jei to be updated, got %+v", diff.ModsUpdated)
+	}
+	if len(diff.ModsAdded) != 1 || diff.ModsAdded[0].ID != "ftb-chunks-forge" {
+		t.Errorf("Expected ftb-chunks-forge to be added, got %+v", diff.ModsAdded)
+	}
+	if len(diff.ModsRemoved) != 1 || diff.ModsRemoved[0].ID != "create" {
+		t.Errorf("Expected create to be removed, got %+v", diff.ModsRemoved)
+	}
+}
+
+// stableForge publishes a single stable Forge version
+type stableForge struct{}
+
+func (stableForge) GetVersions(ctx context.Context) ([]metadata.LoaderVersion, error) {
+	return []metadata.LoaderVersion{{Version: "47.2.0", MinecraftVersion: "1.20.1", Stable: true}}, nil
+}
+
+func (f stableForge) GetVersionsForMC(ctx context.Context, mcVersion string) ([]metadata.LoaderVersion, error) {
+	return f.GetVersions(ctx)
+}
+
+func (stableForge) GetLatestVersion(ctx context.Context, mcVersion string) (*metadata.LoaderVersion, error) {
+	return &metadata.LoaderVersion{Version: "47.2.0", MinecraftVersion: "1.20.1", Stable: true}, nil
+}
+
+func (stableForge) IsVersionCompatible(ctx context.Context, loaderVersion, mcVersion string) (bool, error) {
+	return true, nil
+}
+
+func TestResolveDiffLoaders(t *testing.T) {
+	// The recipe asks for the latest Forge, which installed 47.2.0
+	serverDir := t.TempDir()
+	if err := config.SaveChunkManifest(filepath.Join(serverDir, config.ChunkManifestFile), &config.ChunkManifest{
+		Name: "ATM9", MCVersion: "1.20.1", Loader: "forge", LoaderVersion: "47.2.0",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	oldManifest, err := manifestFromSnapshot(map[string]interface{}{
+		"mc_version":     "1.20.1",
+		"loader":         "forge",
+		"loader_version": "latest",
+	})
+	if err != nil {
+		t.Fatalf("manifestFromSnapshot failed: %v", err)
+	}
+	newModpack := &sources.Modpack{MCVersion: "1.20.1", Loader: sources.LoaderForge, LoaderVersion: "latest"}
+
+	loaders := converter.NewLoaderInstaller()
+	loaders.Resolver = metadata.NewLoaderResolverWithProviders(map[metadata.LoaderType]metadata.VersionProvider{
+		metadata.LoaderForge: stableForge{},
+	})
+	if err := resolveDiffLoaders(context.Background(), loaders, serverDir, oldManifest, newModpack); err != nil {
+		t.Fatalf("resolveDiffLoaders failed: %v", err)
+	}
+	if oldManifest.LoaderVersion != "47.2.0" || newModpack.LoaderVersion != "47.2.0" {
+		t.Errorf("Expected both sides at 47.2.0, got %s and %s", oldManifest.LoaderVersion, newModpack.LoaderVersion)
+	}
+
+	diff := preserve.NewVersionDiffer().CompareModpacks(oldManifest, preserve.ManifestFromModpack(newModpack))
+	if diff.LoaderChange != nil {
+		t.Errorf("Expected no loader change, got %+v", diff.LoaderChange)
+	}
+}
+
+func TestFindDiffInstallation(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+
+	tracker, err := tracking.NewTracker()
+	if err != nil {
+		t.Fatalf("Failed to create tracker: %v", err)
+	}
+
+	for _, inst := range []struct{ slug, dir string }{
+		{"atm9", "atm9-a"},
+		{"atm9", "atm9-b"},
+		{"vault-hunters", "vh"},
+	} {
+		if err := tracker.AddInstallation(&tracking.Installation{
+			Slug:        inst.slug,
+			Version:     "1.0.0",
+			Bench:       "test",
+			Path:        filepath.Join(tmpDir, inst.dir),
+			InstalledAt: time.Now(),
+		}); err != nil {
+			t.Fatalf("Failed to track installation: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		slug     string
+		dir      string
+		wantPath string
+		wantErr  string
+	}{
+		{name: "unique slug", slug: "vault-hunters", wantPath: filepath.Join(tmpDir, "vh")},
+		{name: "instance name", slug: "atm9-b", wantPath: filepath.Join(tmpDir, "atm9-b")},
+		{name: "ambiguous slug", slug: "atm9", wantErr: "--dir"},
+		{name: "slug with dir", slug: "atm9", dir: filepath.Join(tmpDir, "atm9-b"), wantPath: filepath.Join(tmpDir, "atm9-b")},
+		{name: "not installed", slug: "skyfactory", wantErr: "not installed"},
+		{name: "untracked dir", dir: filepath.Join(tmpDir, "other"), wantErr: "no installation found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			installation, err := findDiffInstallation(tracker, tt.slug, tt.dir)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("findDiffInstallation failed: %v", err)
+			}
+			if installation.Path != tt.wantPath {
+				t.Errorf("Path = %s, want %s", installation.Path, tt.wantPath)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/alexinslc/chunk/internal/config"
+	"github.com/alexinslc/chunk/internal/converter"
+	"github.com/alexinslc/chunk/internal/java"
+	"github.com/alexinslc/chunk/internal/sources"
+	"github.com/alexinslc/chunk/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var exportForce bool
+
+var ExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export installed servers to other runtimes",
+	Long:  `Generate the files that run an installed server outside of chunk.`,
+}
+
+var exportDockerCmd = &cobra.Command{
+	Use:   "docker <installation>",
+	Short: "Generate a Dockerfile and compose file for an installation",
+	Long: `Generate a Dockerfile, compose.yaml, .dockerignore and entrypoint in the
+server directory of an installation, which becomes the build context.
+
+The image is based on eclipse-temurin with the Java major version the pack
+needs: the recipe's java_version if it sets one, otherwise the version its
+Minecraft version requires. The entrypoint launches the server with the same
+JVM flags and launch layout as start.sh. Worlds, configs and logs are kept in
+named volumes, so rebuilding the image keeps them.
+
+Files generated before are replaced; files chunk did not generate are only
+replaced with --force.
+
+Examples:
+  chunk export docker survival-eu                 # Installed instance
+  chunk export docker /opt/minecraft/server       # Installation at a path
+  chunk export docker survival-eu --force         # Replace an existing Dockerfile`,
+	Args: cobra.ExactArgs(1),
+	RunE: runExportDocker,
+}
+
+func init() {
+	ExportCmd.AddCommand(exportDockerCmd)
+
+	exportDockerCmd.Flags().BoolVar(&exportForce, "force", false, "Replace Docker files chunk did not generate")
+
+	// Suppress usage printing on errors
+	exportDockerCmd.SilenceUsage = true
+}
+
+func runExportDocker(cmd *cobra.Command, args []string) error {
+	installation, err := findBundleInstallation(args[0])
+	if err != nil {
+		return err
+	}
+	if installation == nil {
+		return fmt.Errorf("%s is not a tracked installation (see chunk list)", args[0])
+	}
+
+	manifest, err := config.LoadChunkManifest(filepath.Join(installation.Path, config.ChunkManifestFile))
+	if err != nil {
+		return fmt.Errorf("failed to read installed manifest: %w", err)
+	}
+
+	if !exportForce {
+		for _, name := range converter.DockerFiles {
+			data, err := os.ReadFile(filepath.Join(installation.Path, name))
+			if err == nil && !bytes.Contains(data, []byte("Generated by Chunk")) {
+				return fmt.Errorf("%s already exists in %s, use --force to replace it", name, installation.Path)
+			}
+		}
+	}
+
+	javaMajor := dockerJavaMajor(manifest)
+	err = converter.NewScriptGenerator().GenerateDocker(&converter.ConversionOptions{
+		DestDir:        installation.Path,
+		ModpackName:    manifest.Name,
+		MCVersion:      manifest.MCVersion,
+		Loader:         sources.LoaderType(manifest.Loader),
+		LoaderVersion:  manifest.LoaderVersion,
+		RecommendedRAM: manifest.RecommendedRAMGB,
+		JVMArgs:        manifest.JVMArgs,
+		Env:            manifest.Env,
+	}, &converter.DockerOptions{
+		Name:      installation.Name,
+		JavaMajor: javaMajor,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to generate Docker files: %w", err)
+	}
+
+	ui.PrintSuccess(fmt.Sprintf("Wrote Docker files for %s (Java %d) to %s", installation.Name, javaMajor, installation.Path))
+	fmt.Println()
+	fmt.Println("To run the server in a container:")
+	fmt.Printf("   cd %s\n", installation.Path)
+	fmt.Println("   # Set EULA to \"true\" in compose.yaml to accept the Minecraft EULA")
+	fmt.Println("   docker compose up -d --build")
+	fmt.Println()
+	fmt.Println("The world starts empty in its volume; copy an existing one in with docker compose cp.")
+	fmt.Println()
+	return nil
+}
+
+// dockerJavaMajor returns the Java major version the image of a pack is based
+// on: the recipe's java_version, or the lowest version the pack runs on
+func dockerJavaMajor(manifest *config.ChunkManifest) int {
+	if manifest.JavaVersion > 0 {
+		return manifest.JavaVersion
+	}
+	return max(java.GetRequiredJavaVersion(manifest.MCVersion), manifest.MinJava)
+}
@@ -126,6 +126,23 @@ func displayRecipeInfo(recipe *search.Recipe) error {
 		fmt.Println()
 	}
 
+	// Variants, installed with chunk install <recipe> --variant <name>
+	if len(recipe.Variants) > 0 {
+		fmt.Println("Variants:")
+		for _, variant := range recipe.Variants {
+			fmt.Printf("  %s", variant.Name)
+			if variant.Description != "" {
+				fmt.Printf(" - %s", variant.Description)
+			}
+			if variant.RecommendedRAMGB > 0 {
+				fmt.Printf(" (%d GB RAM)", variant.RecommendedRAMGB)
+			}
+			fmt.Println()
+		}
+		fmt.Printf("Install with: chunk install %s --variant <name>\n", recipe.Slug)
+		fmt.Println()
+	}
+
 	// Download info
 	if recipe.DownloadURL != "" {
 		fmt.Printf("Download URL: %s\n", recipe.DownloadURL)
@@ -186,6 +203,9 @@ func displayRecipeInfoJSON(recipe *search.Recipe) error {
 	if len(recipe.Tags) > 0 {
 		output["tags"] = recipe.Tags
 	}
+	if len(recipe.Variants) > 0 {
+		output["variants"] = recipe.Variants
+	}
 
 	jsonData, err := json.MarshalIndent(output, "", "  ")
 	if err != nil {
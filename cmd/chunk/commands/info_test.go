@@ -92,7 +92,11 @@ func TestInfoCommandWithBench(t *testing.T) {
   "license": "MIT",
   "homepage": "https://example.com/test-modpack",
   "download_url": "https://example.com/download",
-  "download_size_mb": 500
+  "download_size_mb": 500,
+  "variants": [
+    {"name": "lite", "description": "Fewer mods for small hosts", "download_url": "https://example.com/download-lite", "recommended_ram_gb": 4},
+    {"name": "full"}
+  ]
 }`
 	recipeFile := filepath.Join(recipesPath, "test-modpack.json")
 	if err := os.WriteFile(recipeFile, []byte(testRecipe), 0644); err != nil {
@@ -134,6 +138,9 @@ func TestInfoCommandWithBench(t *testing.T) {
 				"8 GB",
 				"10 GB",
 				"test-bench",
+				"lite - Fewer mods for small hosts (4 GB RAM)",
+				"  full\n",
+				"chunk install test-modpack --variant <name>",
 			},
 		},
 		{
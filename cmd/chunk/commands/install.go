@@ -1,8 +1,12 @@
 package commands
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/alexinslc/chunk/internal/install"
 	"github.com/alexinslc/chunk/internal/ui"
@@ -10,8 +14,12 @@ import (
 )
 
 var (
-	installDir string
-	skipVerify bool
+	installDir     string
+	skipVerify     bool
+	installFrozen  bool
+	installName    string
+	installVariant string
+	installRCON    bool
 )
 
 var InstallCmd = &cobra.Command{
@@ -24,13 +32,29 @@ Sources:
   - GitHub repository: chunk install alexinslc/my-cool-mod
   - Modrinth: chunk install modrinth:<slug>
   - Local file: chunk install ./modpack.mrpack
+  - Server bundle: chunk install ./server.chunkbundle (offline, see chunk bundle)
 
 The command will:
   - Download the modpack
   - Install the correct mod loader (Forge/Fabric/NeoForge)
   - Download all server-side mods
   - Generate server configurations
-  - Create start scripts`,
+  - Create start scripts
+  - Write chunk.lock pinning every downloaded artifact
+
+Use --frozen to reinstall exactly what an existing chunk.lock in the
+installation directory records. The install fails on any deviation.
+
+Use --name to give the server an instance name, which other commands accept
+in place of its directory (chunk upgrade survival-eu). Names must be unique.
+Without --dir, a named instance is installed to ./<name>.
+
+Use --variant to install one of a recipe's variants, such as a lite or
+performance flavor (see chunk info <recipe>). Upgrades keep the variant.
+
+Use --rcon to enable the server's RCON console on a free port with a
+generated password, for chunk rcon. The credentials are recorded in
+~/.chunk/installed.json.`,
 	Args: cobra.ExactArgs(1),
 	RunE: runInstall,
 }
@@ -52,6 +76,9 @@ func runInstall(cmd *cobra.Command, args []string) error {
 
 	// Normalize destination directory
 	destDir := installDir
+	if destDir == "" && installName != "" {
+		destDir = "./" + installName
+	}
 	if destDir == "" {
 		destDir = "./server"
 	}
@@ -61,10 +88,22 @@ func runInstall(cmd *cobra.Command, args []string) error {
 		DestDir:      destDir,
 		PreserveData: false,
 		SkipVerify:   skipVerify,
+		Frozen:       installFrozen,
+		Name:         installName,
+		Variant:      installVariant,
+		RCON:         installRCON,
 	}
 
-	result, err := installer.Install(opts)
+	// Ctrl-C stops in-flight downloads; the rollback below still runs
+	ctx, stop := interruptContext(cmd)
+	defer stop()
+
+	result, err := installer.Install(ctx, opts)
 	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			fmt.Println()
+			ui.PrintWarning("Installation cancelled")
+		}
 		// Attempt rollback on failure
 		if rollbackErr := installer.Rollback(); rollbackErr != nil {
 			ui.PrintError(fmt.Sprintf("Rollback failed: %v", rollbackErr))
@@ -95,11 +134,29 @@ func runInstall(cmd *cobra.Command, args []string) error {
 	}
 	fmt.Println()
 	fmt.Printf("   Mods:      %d installed\n", result.ModsInstalled)
+	if result.Modpack != nil && result.Modpack.Variant != "" {
+		fmt.Printf("   Variant:   %s\n", result.Modpack.Variant)
+	}
 	fmt.Printf("   Location:  %s\n", result.DestDir)
+	if result.LockPath != "" {
+		fmt.Printf("   Lock file: %s\n", result.LockPath)
+	}
+	if result.Name != "" {
+		fmt.Printf("   Instance:  %s\n", result.Name)
+	}
+	if result.RCON != nil {
+		fmt.Printf("   RCON:      port %d\n", result.RCON.Port)
+	}
 	fmt.Println()
 	fmt.Println("To start the server:")
 	fmt.Printf("   cd %s\n", result.DestDir)
 	fmt.Println("   ./start.sh (Linux/Mac) or start.bat (Windows)")
+	if result.Name != "" {
+		fmt.Printf("or in the background: chunk start %s\n", result.Name)
+		if result.RCON != nil {
+			fmt.Printf("then run console commands with: chunk rcon %s <command>\n", result.Name)
+		}
+	}
 	fmt.Println()
 	fmt.Println("NOTE: Review and accept eula.txt before starting!")
 	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
@@ -108,6 +165,17 @@ func runInstall(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// interruptContext returns a context cancelled on SIGINT or SIGTERM. The
+// signals stay captured until stop is called, so a second Ctrl-C cannot cut
+// a rollback short.
+func interruptContext(cmd *cobra.Command) (context.Context, context.CancelFunc) {
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+}
+
 func displayModpackInfo(info *install.ModpackDisplayInfo) {
 	if info == nil {
 		return
@@ -141,6 +209,10 @@ func displayModpackInfo(info *install.ModpackDisplayInfo) {
 func init() {
 	InstallCmd.Flags().StringVarP(&installDir, "dir", "d", "", "Installation directory (default: ./server)")
 	InstallCmd.Flags().BoolVar(&skipVerify, "skip-verify", false, "Skip checksum verification of downloaded files (not recommended)")
+	InstallCmd.Flags().BoolVar(&installFrozen, "frozen", false, "Install only the artifacts pinned in chunk.lock and fail on any deviation")
+	InstallCmd.Flags().StringVar(&installName, "name", "", "Instance name for the server (default: derived from the directory)")
+	InstallCmd.Flags().StringVar(&installVariant, "variant", "", "Recipe variant to install (see chunk info <recipe>)")
+	InstallCmd.Flags().BoolVar(&installRCON, "rcon", false, "Enable RCON on a free port with a generated password")
 
 	// Suppress usage printing on errors
 	InstallCmd.SilenceUsage = true
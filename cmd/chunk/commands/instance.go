@@ -0,0 +1,26 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/alexinslc/chunk/internal/tracking"
+)
+
+// lookupInstance returns the tracked installation named name, or nil if no
+// installation has that name (the argument is then a modpack identifier)
+func lookupInstance(name string) (*tracking.Installation, error) {
+	if name == "" || tracking.ValidateName(name) != nil {
+		return nil, nil
+	}
+
+	tracker, err := tracking.NewTracker()
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize tracker: %w", err)
+	}
+
+	installation, err := tracker.GetInstallationByName(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up instance %s: %w", name, err)
+	}
+	return installation, nil
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/alexinslc/chunk/internal/bench"
+	"github.com/alexinslc/chunk/internal/ping"
 	"github.com/alexinslc/chunk/internal/search"
 	"github.com/alexinslc/chunk/internal/tracking"
 	"github.com/spf13/cobra"
@@ -23,11 +24,13 @@ Examples:
   chunk list                # Show all installed modpacks
   chunk list --paths        # Show only installation paths
   chunk list --json         # Output in JSON format
-  chunk list --outdated     # Show which have updates available`,
+  chunk list --outdated     # Show which have updates available
+  chunk list --ping         # Show which servers are up`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		pathsOnly, _ := cmd.Flags().GetBool("paths")
 		jsonOutput, _ := cmd.Flags().GetBool("json")
 		outdated, _ := cmd.Flags().GetBool("outdated")
+		checkStatus, _ := cmd.Flags().GetBool("ping")
 
 		tracker, err := tracking.NewTracker()
 		if err != nil {
@@ -66,7 +69,7 @@ Examples:
 			return displayJSON(installations)
 		}
 
-		return displayList(installations, outdated)
+		return displayList(installations, outdated, checkStatus)
 	},
 }
 
@@ -74,6 +77,7 @@ func init() {
 	ListCmd.Flags().Bool("paths", false, "Show only installation paths")
 	ListCmd.Flags().Bool("json", false, "Output in JSON format")
 	ListCmd.Flags().Bool("outdated", false, "Show which installations have updates available")
+	ListCmd.Flags().Bool("ping", false, "Ping each server and show whether it is up")
 }
 
 // displayPaths shows only installation paths
@@ -95,7 +99,7 @@ func displayJSON(installations []*tracking.Installation) error {
 }
 
 // displayList shows formatted list of installations
-func displayList(installations []*tracking.Installation, checkOutdated bool) error {
+func displayList(installations []*tracking.Installation, checkOutdated, checkStatus bool) error {
 	fmt.Println()
 	fmt.Printf("==> Installed modpacks (%d)\n", len(installations))
 	fmt.Println()
@@ -112,9 +116,19 @@ func displayList(installations []*tracking.Installation, checkOutdated bool) err
 		}
 	}
 
+	// Ping all servers up front, so offline ones do not add up
+	var statuses map[string]*ping.Status
+	if checkStatus {
+		statuses = pingInstallations(installations)
+	}
+
 	for _, inst := range installations {
-		// Display slug and version
-		fmt.Printf("%s (%s)", inst.Slug, inst.Version)
+		// Display instance name, slug and version
+		if inst.Name != "" && inst.Name != inst.Slug {
+			fmt.Printf("%s: %s (%s)", inst.Name, inst.Slug, inst.Version)
+		} else {
+			fmt.Printf("%s (%s)", inst.Slug, inst.Version)
+		}
 
 		// Check if outdated
 		if checkOutdated && recipeCache != nil {
@@ -123,6 +137,14 @@ func displayList(installations []*tracking.Installation, checkOutdated bool) err
 			}
 		}
 
+		if checkStatus {
+			if status, up := statuses[inst.Path]; up {
+				fmt.Printf(" [up: %d/%d players]", status.Online, status.Max)
+			} else {
+				fmt.Print(" [down]")
+			}
+		}
+
 		fmt.Println()
 
 		// Display installation path
@@ -249,7 +249,7 @@ func TestDisplayListEmpty(t *testing.T) {
 	installations := []*tracking.Installation{}
 
 	// This should not panic or error
-	err := displayList(installations, false)
+	err := displayList(installations, false, false)
 	if err != nil {
 		t.Fatalf("displayList with empty installations should not error: %v", err)
 	}
@@ -274,7 +274,7 @@ func TestDisplayListBasic(t *testing.T) {
 	r, w, _ := os.Pipe()
 	os.Stdout = w
 
-	err := displayList(installations, false)
+	err := displayList(installations, false, false)
 	if err != nil {
 		t.Fatalf("displayList failed: %v", err)
 	}
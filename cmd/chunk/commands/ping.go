@@ -0,0 +1,190 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alexinslc/chunk/internal/converter"
+	"github.com/alexinslc/chunk/internal/ping"
+	"github.com/alexinslc/chunk/internal/tracking"
+	"github.com/alexinslc/chunk/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// listPingTimeout bounds the ping of each installation in chunk list --ping
+const listPingTimeout = 2 * time.Second
+
+var (
+	pingJSON    bool
+	pingTimeout time.Duration
+)
+
+var PingCmd = &cobra.Command{
+	Use:   "ping <installation|host[:port]>",
+	Short: "Query a server's player count, MOTD and version",
+	Long: `Query a server with the Server List Ping protocol, as the multiplayer
+screen does, and show its players, MOTD, version and latency. Servers before
+Minecraft 1.7 are queried with the legacy ping.
+
+An installation, given by instance name or server directory, is pinged at
+the server-ip and server-port of its server.properties. Anything else is a
+host with an optional port, 25565 by default.
+
+Examples:
+  chunk ping survival-eu                  # Installed instance
+  chunk ping mc.example.com               # Remote server on port 25565
+  chunk ping 192.168.1.20:25570 --json    # Output in JSON format`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPing,
+}
+
+func init() {
+	PingCmd.Flags().BoolVar(&pingJSON, "json", false, "Output in JSON format")
+	PingCmd.Flags().DurationVar(&pingTimeout, "timeout", ping.DefaultTimeout, "How long to wait for the server to respond")
+
+	// Suppress usage printing on errors
+	PingCmd.SilenceUsage = true
+}
+
+// pingResult is the JSON output of chunk ping
+type pingResult struct {
+	Target  string       `json:"target"`
+	Address string       `json:"address"`
+	Up      bool         `json:"up"`
+	Status  *ping.Status `json:"status,omitempty"`
+	Latency float64      `json:"latency_ms,omitempty"`
+	Error   string       `json:"error,omitempty"`
+}
+
+func runPing(cmd *cobra.Command, args []string) error {
+	target := args[0]
+	address, err := pingAddress(target)
+	if err != nil {
+		return err
+	}
+
+	status, pingErr := ping.Ping(address, pingTimeout)
+
+	if pingJSON {
+		result := pingResult{Target: target, Address: address, Up: pingErr == nil, Status: status}
+		if pingErr != nil {
+			result.Error = pingErr.Error()
+		} else {
+			result.Latency = float64(status.Latency.Microseconds()) / 1000
+		}
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	label := target
+	if address != target {
+		label = fmt.Sprintf("%s at %s", target, address)
+	}
+	if pingErr != nil {
+		return fmt.Errorf("%s is not responding: %w", label, pingErr)
+	}
+
+	fmt.Println()
+	ui.PrintSuccess(fmt.Sprintf("%s is up (%d ms)", label, status.Latency.Milliseconds()))
+	fmt.Println()
+	version := status.Version
+	if status.Protocol > 0 {
+		version = fmt.Sprintf("%s (protocol %d)", version, status.Protocol)
+	}
+	if status.Legacy {
+		version += ", legacy ping"
+	}
+	fmt.Printf("   Version:  %s\n", version)
+	players := fmt.Sprintf("%d/%d", status.Online, status.Max)
+	if len(status.Sample) > 0 {
+		players += ": " + strings.Join(status.Sample, ", ")
+	}
+	fmt.Printf("   Players:  %s\n", players)
+	for i, line := range strings.Split(status.MOTD, "\n") {
+		if i == 0 {
+			fmt.Printf("   MOTD:     %s\n", line)
+		} else {
+			fmt.Printf("             %s\n", line)
+		}
+	}
+	fmt.Println()
+	return nil
+}
+
+// pingAddress returns the address a ping target names: the address an
+// installation's server listens on, or host[:port]
+func pingAddress(target string) (string, error) {
+	instance, err := lookupInstance(target)
+	if err != nil {
+		return "", err
+	}
+	if instance != nil {
+		return serverAddress(instance.Path)
+	}
+	if info, err := os.Stat(target); err == nil && info.IsDir() {
+		return serverAddress(target)
+	}
+
+	if _, _, err := net.SplitHostPort(target); err != nil {
+		return net.JoinHostPort(target, strconv.Itoa(ping.DefaultPort)), nil
+	}
+	return target, nil
+}
+
+// serverAddress returns the address the server in serverDir listens on,
+// from the server-ip and server-port of its server.properties
+func serverAddress(serverDir string) (string, error) {
+	host, port := "127.0.0.1", ping.DefaultPort
+
+	properties, err := converter.ReadServerProperties(serverDir)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return "", err
+	}
+	if ip := properties["server-ip"]; ip != "" {
+		host = ip
+	}
+	if value := properties["server-port"]; value != "" {
+		port, err = strconv.Atoi(value)
+		if err != nil {
+			return "", fmt.Errorf("invalid server-port %q in %s", value, converter.ServerPropertiesFile)
+		}
+	}
+	return net.JoinHostPort(host, strconv.Itoa(port)), nil
+}
+
+// pingInstallations pings every installation at once and returns the status
+// of those that answered, by path
+func pingInstallations(installations []*tracking.Installation) map[string]*ping.Status {
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	statuses := make(map[string]*ping.Status)
+	for _, inst := range installations {
+		address, err := serverAddress(inst.Path)
+		if err != nil {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if status, err := ping.Ping(address, listPingTimeout); err == nil {
+				mu.Lock()
+				statuses[inst.Path] = status
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	return statuses
+}
@@ -0,0 +1,37 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPingAddress(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	dir := t.TempDir()
+	tests := []struct {
+		target string
+		want   string
+	}{
+		{"mc.example.com", "mc.example.com:25565"},
+		{"mc.example.com:25570", "mc.example.com:25570"},
+		{"::1", "[::1]:25565"},
+		// A server directory without server.properties uses the defaults
+		{dir, "127.0.0.1:25565"},
+	}
+	for _, tt := range tests {
+		address, err := pingAddress(tt.target)
+		if err != nil || address != tt.want {
+			t.Errorf("pingAddress(%q) = %q, %v, want %q", tt.target, address, err, tt.want)
+		}
+	}
+
+	properties := "server-ip=10.0.0.5\nserver-port=25570\n"
+	if err := os.WriteFile(filepath.Join(dir, "server.properties"), []byte(properties), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if address, err := pingAddress(dir); err != nil || address != "10.0.0.5:25570" {
+		t.Errorf("Expected the address from server.properties, got %q, %v", address, err)
+	}
+}
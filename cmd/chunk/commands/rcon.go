@@ -0,0 +1,150 @@
+package commands
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alexinslc/chunk/internal/converter"
+	"github.com/alexinslc/chunk/internal/rcon"
+	"github.com/alexinslc/chunk/internal/tracking"
+	"github.com/spf13/cobra"
+)
+
+var rconTimeout time.Duration
+
+var RconCmd = &cobra.Command{
+	Use:   "rcon <installation> [command...]",
+	Short: "Run console commands on a running server over RCON",
+	Long: `Run a console command on a running server over RCON and print its output.
+
+Without a command, an interactive shell reads commands line by line until
+exit, quit or Ctrl-D.
+
+The installation is given by instance name or server directory. The RCON
+port and password are read from its server.properties, or from the
+credentials chunk install --rcon recorded.
+
+Examples:
+  chunk rcon survival-eu list                     # Run one command
+  chunk rcon survival-eu say Restarting in 5 minutes
+  chunk rcon survival-eu                          # Interactive shell`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runRcon,
+}
+
+func init() {
+	RconCmd.Flags().DurationVar(&rconTimeout, "timeout", rcon.DefaultTimeout, "How long to wait for the server to respond")
+	// Everything after the installation is the command, including its flags
+	RconCmd.Flags().SetInterspersed(false)
+
+	// Suppress usage printing on errors
+	RconCmd.SilenceUsage = true
+}
+
+func runRcon(cmd *cobra.Command, args []string) error {
+	installation, err := findBundleInstallation(args[0])
+	if err != nil {
+		return err
+	}
+	if installation == nil {
+		return fmt.Errorf("%s is not a tracked installation (see chunk list)", args[0])
+	}
+
+	address, password, err := rconTarget(installation)
+	if err != nil {
+		return err
+	}
+	client, err := rcon.Dial(address, password, rconTimeout)
+	if errors.Is(err, rcon.ErrAuthFailed) {
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("%w (is the server running?)", err)
+	}
+	defer client.Close()
+
+	// Commands may be typed as in game, but RCON takes them without the slash
+	if len(args) > 1 {
+		output, err := client.Execute(strings.TrimPrefix(strings.Join(args[1:], " "), "/"))
+		if err != nil {
+			return err
+		}
+		printRCONOutput(os.Stdout, output)
+		return nil
+	}
+
+	fmt.Printf("Connected to %s. Type exit or press Ctrl-D to leave.\n", installation.Name)
+	return rconShell(client, installation.Name, os.Stdin, os.Stdout)
+}
+
+// rconTarget returns the address and password of an installation's RCON
+// console. server.properties takes precedence over the recorded credentials,
+// since it may have been edited after chunk install --rcon.
+func rconTarget(installation *tracking.Installation) (string, string, error) {
+	properties, err := converter.ReadServerProperties(installation.Path)
+	if err != nil {
+		if installation.RCON == nil {
+			return "", "", err
+		}
+		return net.JoinHostPort("127.0.0.1", strconv.Itoa(installation.RCON.Port)), installation.RCON.Password, nil
+	}
+
+	if properties["enable-rcon"] != "true" || properties["rcon.password"] == "" {
+		return "", "", fmt.Errorf("RCON is not enabled for %s: reinstall it with --rcon, or set enable-rcon, rcon.port and rcon.password in %s", installation.Name, converter.ServerPropertiesFile)
+	}
+
+	port := rcon.DefaultPort
+	if value := properties["rcon.port"]; value != "" {
+		port, err = strconv.Atoi(value)
+		if err != nil {
+			return "", "", fmt.Errorf("invalid rcon.port %q in %s", value, converter.ServerPropertiesFile)
+		}
+	}
+	// The server listens on server-ip, or on every interface if it is empty
+	host := properties["server-ip"]
+	if host == "" {
+		host = "127.0.0.1"
+	}
+	return net.JoinHostPort(host, strconv.Itoa(port)), properties["rcon.password"], nil
+}
+
+// rconShell runs the commands read from in until exit, quit or the end of input
+func rconShell(client *rcon.Client, name string, in io.Reader, out io.Writer) error {
+	scanner := bufio.NewScanner(in)
+	for {
+		fmt.Fprintf(out, "%s> ", name)
+		if !scanner.Scan() {
+			fmt.Fprintln(out)
+			return scanner.Err()
+		}
+
+		command := strings.TrimSpace(scanner.Text())
+		switch command {
+		case "":
+			continue
+		case "exit", "quit":
+			return nil
+		}
+
+		output, err := client.Execute(strings.TrimPrefix(command, "/"))
+		if err != nil {
+			return err
+		}
+		printRCONOutput(out, output)
+	}
+}
+
+// printRCONOutput prints command output without formatting codes
+func printRCONOutput(out io.Writer, output string) {
+	output = strings.TrimRight(rcon.StripFormatting(output), "\n")
+	if output != "" {
+		fmt.Fprintln(out, output)
+	}
+}
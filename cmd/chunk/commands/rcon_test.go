@@ -0,0 +1,41 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alexinslc/chunk/internal/tracking"
+)
+
+func TestRconTarget(t *testing.T) {
+	dir := t.TempDir()
+	installation := &tracking.Installation{
+		Name: "survival-eu",
+		Path: dir,
+		RCON: &tracking.RCON{Port: 25580, Password: "recorded"},
+	}
+
+	// Without server.properties, the recorded credentials are used
+	address, password, err := rconTarget(installation)
+	if err != nil || address != "127.0.0.1:25580" || password != "recorded" {
+		t.Errorf("Expected recorded credentials, got %s, %s, %v", address, password, err)
+	}
+
+	// server.properties takes precedence
+	properties := "enable-rcon=true\nrcon.port=25590\nrcon.password=edited\nserver-ip=\n"
+	if err := os.WriteFile(filepath.Join(dir, "server.properties"), []byte(properties), 0600); err != nil {
+		t.Fatal(err)
+	}
+	address, password, err = rconTarget(installation)
+	if err != nil || address != "127.0.0.1:25590" || password != "edited" {
+		t.Errorf("Expected credentials from server.properties, got %s, %s, %v", address, password, err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "server.properties"), []byte("enable-rcon=false\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := rconTarget(installation); err == nil {
+		t.Error("Expected an error when RCON is disabled")
+	}
+}
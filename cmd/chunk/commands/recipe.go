@@ -26,6 +26,8 @@ import (
 var (
 	templateRecipe string
 	outputDir      string
+	signingKeyPath string
+	keygenOutput   string
 )
 
 var RecipeCmd = &cobra.Command{
@@ -78,6 +80,89 @@ Example:
 	RunE: runRecipeValidate,
 }
 
+var recipeKeygenCmd = &cobra.Command{
+	Use:   "keygen",
+	Short: "Generate a recipe signing key",
+	Long: `Generate an ed25519 key pair for signing recipes.
+
+The private key is written to the output file (readable only by you) and the
+public key is printed. Bench users pin the public key with chunk bench trust.
+
+Example:
+  chunk recipe keygen --output ~/.chunk/signing.key`,
+	Args: cobra.NoArgs,
+	RunE: runRecipeKeygen,
+}
+
+var recipeSignCmd = &cobra.Command{
+	Use:   "sign <file>...",
+	Short: "Sign recipe files",
+	Long: `Sign recipe files with an ed25519 private key. Each signature is written
+next to its recipe as <file>.sig and must be committed to the bench with it.
+
+The key is read from --key, or from the CHUNK_SIGNING_KEY environment variable
+(base64) when --key is not given.
+
+Example:
+  chunk recipe sign Recipes/atm9.json --key ~/.chunk/signing.key`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runRecipeSign,
+}
+
+func runRecipeKeygen(cmd *cobra.Command, args []string) error {
+	if _, err := os.Stat(keygenOutput); err == nil {
+		return fmt.Errorf("%s already exists; refusing to overwrite a signing key", keygenOutput)
+	}
+
+	publicKey, privateKey, err := bench.GenerateKey()
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(keygenOutput, []byte(privateKey+"\n"), 0600); err != nil {
+		return fmt.Errorf("failed to write private key: %w", err)
+	}
+
+	fmt.Println()
+	fmt.Printf("✅ Private key written to %s\n", keygenOutput)
+	fmt.Println()
+	fmt.Println("Public key:")
+	fmt.Printf("  %s\n", publicKey)
+	fmt.Println()
+	fmt.Println("Bench users trust it with:")
+	fmt.Printf("  chunk bench trust <bench> %s\n", publicKey)
+	fmt.Println()
+
+	return nil
+}
+
+func runRecipeSign(cmd *cobra.Command, args []string) error {
+	encoded := os.Getenv("CHUNK_SIGNING_KEY")
+	if signingKeyPath != "" {
+		data, err := os.ReadFile(signingKeyPath)
+		if err != nil {
+			return fmt.Errorf("failed to read signing key: %w", err)
+		}
+		encoded = string(data)
+	}
+	if encoded == "" {
+		return fmt.Errorf("no signing key: use --key or set CHUNK_SIGNING_KEY")
+	}
+
+	privateKey, err := bench.ParsePrivateKey(encoded)
+	if err != nil {
+		return err
+	}
+
+	for _, path := range args {
+		if err := bench.SignRecipe(path, privateKey); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		fmt.Printf("✓ Signed %s\n", path)
+	}
+
+	return nil
+}
+
 func runRecipeValidate(cmd *cobra.Command, args []string) error {
 	path := args[0]
 
@@ -181,53 +266,56 @@ func printValidationResults(recipe *search.Recipe, result *validation.Validation
 		{"Required fields present", !hasErrorForField(result, "name", "mc_version", "loader", "download_url"), ""},
 	}
 
-	// Check URL reachability
-	urlError := hasErrorForField(result, "download_url")
-	if !urlError && recipe.DownloadURL != "" {
-		sizeStr := ""
-		if downloadSize > 0 {
-			sizeMB := downloadSize / (1024 * 1024)
-			sizeStr = fmt.Sprintf(" (%d MB)", sizeMB)
+	// Check URL reachability and checksum; schema v2 recipes may list their
+	// mods instead of an archive
+	if recipe.DownloadURL != "" || len(recipe.Files) == 0 {
+		urlError := hasErrorForField(result, "download_url")
+		if !urlError && recipe.DownloadURL != "" {
+			sizeStr := ""
+			if downloadSize > 0 {
+				sizeMB := downloadSize / (1024 * 1024)
+				sizeStr = fmt.Sprintf(" (%d MB)", sizeMB)
+			}
+			checks = append(checks, struct {
+				name    string
+				passed  bool
+				message string
+			}{"Download URL reachable", true, sizeStr})
+		} else if !urlError && recipe.DownloadURL == "" {
+			checks = append(checks, struct {
+				name    string
+				passed  bool
+				message string
+			}{"Download URL reachable", false, " (missing)"})
+		} else {
+			checks = append(checks, struct {
+				name    string
+				passed  bool
+				message string
+			}{"Download URL reachable", false, ""})
+		}
+
+		// Check checksum
+		checksumError := hasErrorForField(result, "sha256")
+		if !checksumError && recipe.SHA256 != "" {
+			checks = append(checks, struct {
+				name    string
+				passed  bool
+				message string
+			}{"Checksum matches", true, ""})
+		} else if !checksumError && recipe.SHA256 == "" {
+			checks = append(checks, struct {
+				name    string
+				passed  bool
+				message string
+			}{"Checksum provided", false, " (recommended)"})
+		} else {
+			checks = append(checks, struct {
+				name    string
+				passed  bool
+				message string
+			}{"Checksum matches", false, ""})
 		}
-		checks = append(checks, struct {
-			name    string
-			passed  bool
-			message string
-		}{"Download URL reachable", true, sizeStr})
-	} else if !urlError && recipe.DownloadURL == "" {
-		checks = append(checks, struct {
-			name    string
-			passed  bool
-			message string
-		}{"Download URL reachable", false, " (missing)"})
-	} else {
-		checks = append(checks, struct {
-			name    string
-			passed  bool
-			message string
-		}{"Download URL reachable", false, ""})
-	}
-
-	// Check checksum
-	checksumError := hasErrorForField(result, "sha256")
-	if !checksumError && recipe.SHA256 != "" {
-		checks = append(checks, struct {
-			name    string
-			passed  bool
-			message string
-		}{"Checksum matches", true, ""})
-	} else if !checksumError && recipe.SHA256 == "" {
-		checks = append(checks, struct {
-			name    string
-			passed  bool
-			message string
-		}{"Checksum provided", false, " (recommended)"})
-	} else {
-		checks = append(checks, struct {
-			name    string
-			passed  bool
-			message string
-		}{"Checksum matches", false, ""})
 	}
 
 	// Check Minecraft version
@@ -266,6 +354,16 @@ func printValidationResults(recipe *search.Recipe, result *validation.Validation
 		}{"Loader version valid", false, ""})
 	}
 
+	// Check schema v2 fields
+	if recipe.Schema() == search.RecipeSchemaV2 {
+		schemaError := hasErrorWithPrefix(result, "schema_version", "files", "server_overrides", "jvm_args", "env", "min_java", "max_java")
+		checks = append(checks, struct {
+			name    string
+			passed  bool
+			message string
+		}{"Schema v2 fields valid", !schemaError, fmt.Sprintf(" (%d files, %d server overrides)", len(recipe.Files), len(recipe.ServerOverrides))})
+	}
+
 	// Check license
 	licenseError := hasErrorForField(result, "license")
 	if !licenseError && recipe.License != "" {
@@ -340,6 +438,19 @@ func hasErrorForField(result *validation.ValidationResult, fields ...string) boo
 	return false
 }
 
+// hasErrorWithPrefix reports errors for fields or their entries, such as
+// "files[0].url" for "files"
+func hasErrorWithPrefix(result *validation.ValidationResult, prefixes ...string) bool {
+	for _, prefix := range prefixes {
+		for _, err := range result.Errors {
+			if err.Field == prefix || strings.HasPrefix(err.Field, prefix+"[") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func runRecipeCreate(cmd *cobra.Command, args []string) error {
 	reader := bufio.NewReader(os.Stdin)
 
@@ -600,7 +711,7 @@ func promptLoader(reader *bufio.Reader, defaultValue string) (string, error) {
 	if defaultValue != "" {
 		suffix = fmt.Sprintf(" [%s]", defaultValue)
 	}
-	fmt.Printf("Loader [forge/fabric/neoforge]%s: ", suffix)
+	fmt.Printf("Loader [forge/fabric/neoforge/quilt]%s: ", suffix)
 
 	text, err := reader.ReadString('\n')
 	if err != nil {
@@ -624,10 +735,11 @@ func promptLoader(reader *bufio.Reader, defaultValue string) (string, error) {
 		"forge":    true,
 		"fabric":   true,
 		"neoforge": true,
+		"quilt":    true,
 	}
 
 	if !validLoaders[text] {
-		return "", fmt.Errorf("invalid loader: %s (must be forge, fabric, or neoforge)", text)
+		return "", fmt.Errorf("invalid loader: %s (must be forge, fabric, neoforge, or quilt)", text)
 	}
 
 	return text, nil
@@ -882,11 +994,16 @@ func init() {
 	// Add subcommands
 	RecipeCmd.AddCommand(recipeCreateCmd)
 	RecipeCmd.AddCommand(recipeValidateCmd)
+	RecipeCmd.AddCommand(recipeKeygenCmd)
+	RecipeCmd.AddCommand(recipeSignCmd)
 
 	// Flags for create command
 	recipeCreateCmd.Flags().StringVar(&templateRecipe, "template", "", "Start from an existing recipe (name, slug, or file path)")
 	recipeCreateCmd.Flags().StringVar(&outputDir, "output", "", "Output directory for the recipe file (default: current directory)")
 
+	recipeKeygenCmd.Flags().StringVar(&keygenOutput, "output", "chunk-signing.key", "File to write the private key to")
+	recipeSignCmd.Flags().StringVar(&signingKeyPath, "key", "", "Private key file created by chunk recipe keygen")
+
 	// Suppress usage printing on errors
 	RecipeCmd.SilenceUsage = true
 	RecipeCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
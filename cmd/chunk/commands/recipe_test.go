@@ -330,6 +330,13 @@ func TestPromptLoader(t *testing.T) {
 			expected:     "neoforge",
 			expectError:  false,
 		},
+		{
+			name:         "valid quilt",
+			input:        "quilt\n",
+			defaultValue: "",
+			expected:     "quilt",
+			expectError:  false,
+		},
 		{
 			name:         "invalid loader",
 			input:        "invalid\n",
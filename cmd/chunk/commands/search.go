@@ -1,20 +1,32 @@
 package commands
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/alexinslc/chunk/internal/search"
+	"github.com/alexinslc/chunk/internal/sources"
 	"github.com/spf13/cobra"
 )
 
 var (
-	searchBench string
+	searchBench  string
+	searchLoader string
+	searchMC     string
+	searchTags   []string
+	searchMaxRAM int
+	searchAuthor string
+	searchSort   string
+	searchJSON   bool
+	searchRemote bool
 )
 
 var SearchCmd = &cobra.Command{
-	Use:   "search <query>",
-	Short: "Search for modpacks in local benches",
+	Use:   "search [query]",
+	Short: "Search for modpacks in local benches or every source",
 	Long: `Search for modpacks across all installed recipe benches.
 
 Searches local recipe files for matches in:
@@ -24,28 +36,72 @@ Searches local recipe files for matches in:
   - Tags
   - Author
 
+With --remote, ChunkHub, Modrinth, GitHub and source plugins are searched
+as well, and each result is shown with the identifier chunk install takes.
+
+Filters narrow the results; the query may be left out when filtering.
+--mc accepts an exact version (1.20.1), a series (1.20.x) or a range
+(>=1.20, or >=1.19.2,<1.21).
+
 Examples:
   chunk search "all the mods"
   chunk search atm
   chunk search fabric
-  chunk search atm --bench usechunk/recipes`,
-	Args: cobra.ExactArgs(1),
+  chunk search atm --bench usechunk/recipes
+  chunk search --loader fabric --mc 1.20.1 --max-ram 8
+  chunk search --mc ">=1.20" --tag tech --sort newest --json
+  chunk search skyblock --remote --loader fabric`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		query := args[0]
+		query := ""
+		if len(args) > 0 {
+			query = args[0]
+		}
+
+		filter := search.Filter{
+			Loader:    searchLoader,
+			MCVersion: searchMC,
+			Tags:      searchTags,
+			MaxRAMGB:  searchMaxRAM,
+			Author:    searchAuthor,
+			Bench:     searchBench,
+		}
+		if query == "" && filter.IsEmpty() {
+			return errors.New("requires a query or at least one filter")
+		}
+		if err := filter.Validate(); err != nil {
+			return err
+		}
+		order, err := search.ParseSortOrder(searchSort)
+		if err != nil {
+			return err
+		}
+
+		if searchRemote {
+			return runRemoteSearch(cmd, query, filter, order)
+		}
 
 		searcher, err := search.NewSearcher()
 		if err != nil {
 			return fmt.Errorf("failed to initialize search: %w", err)
 		}
 
-		results, err := searcher.Search(query, searchBench)
+		results, err := searcher.Search(query, filter, order)
 		if err != nil {
 			return fmt.Errorf("search failed: %w", err)
 		}
 
+		if searchJSON {
+			return displaySearchJSON(results)
+		}
+
 		if len(results) == 0 {
 			fmt.Println()
-			fmt.Printf("No recipes found matching '%s'\n", query)
+			if query != "" {
+				fmt.Printf("No recipes found matching '%s'\n", query)
+			} else {
+				fmt.Println("No recipes found matching the filters")
+			}
 			fmt.Println()
 			fmt.Println("Try:")
 			fmt.Println("  - Different search terms")
@@ -91,6 +147,175 @@ Examples:
 
 func init() {
 	SearchCmd.Flags().StringVar(&searchBench, "bench", "", "Limit search to specific bench (e.g., usechunk/recipes)")
+	SearchCmd.Flags().StringVar(&searchLoader, "loader", "", "Only show packs for this loader (forge, neoforge, fabric, quilt)")
+	SearchCmd.Flags().StringVar(&searchMC, "mc", "", "Minecraft version or range (e.g., 1.20.1, 1.20.x, >=1.20)")
+	SearchCmd.Flags().StringArrayVar(&searchTags, "tag", nil, "Only show packs with this tag; repeatable")
+	SearchCmd.Flags().IntVar(&searchMaxRAM, "max-ram", 0, "Only show packs recommending at most this many GB of RAM")
+	SearchCmd.Flags().StringVar(&searchAuthor, "author", "", "Only show packs whose author contains this text")
+	SearchCmd.Flags().StringVar(&searchSort, "sort", string(search.SortRelevance), "Sort by relevance, name or newest")
+	SearchCmd.Flags().BoolVar(&searchJSON, "json", false, "Output in JSON format")
+	SearchCmd.Flags().BoolVar(&searchRemote, "remote", false, "Also search ChunkHub, Modrinth, GitHub and source plugins")
+	SearchCmd.MarkFlagsMutuallyExclusive("remote", "bench")
+}
+
+// runRemoteSearch searches local benches and every remote source
+func runRemoteSearch(cmd *cobra.Command, query string, filter search.Filter, order search.SortOrder) error {
+	ctx, stop := interruptContext(cmd)
+	defer stop()
+
+	results, err := sources.NewSourceManager().Search(ctx, query, filter, order)
+	if err != nil {
+		return fmt.Errorf("search failed: %w", err)
+	}
+
+	if searchJSON {
+		return displayRemoteSearchJSON(results)
+	}
+
+	if len(results) == 0 {
+		fmt.Println()
+		fmt.Println("No modpacks found in any source")
+		fmt.Println()
+		return nil
+	}
+
+	fmt.Println()
+	fmt.Printf("==> Found %d modpack(s)\n", len(results))
+	fmt.Println()
+
+	for _, r := range results {
+		fmt.Printf("%s (%s)\n", installIdentifier(r), r.Source)
+		if r.Description != "" {
+			fmt.Printf("  %s\n", r.Description)
+		}
+
+		details := []string{}
+		if r.MCVersion != "" {
+			details = append(details, "MC "+r.MCVersion)
+		}
+		if r.Loader != "" {
+			details = append(details, capitalize(string(r.Loader)))
+		}
+		if r.RecommendedRAM > 0 {
+			details = append(details, fmt.Sprintf("%dGB RAM", r.RecommendedRAM))
+		}
+		if r.Downloads > 0 {
+			details = append(details, fmt.Sprintf("%d downloads", r.Downloads))
+		}
+		if len(details) > 0 {
+			fmt.Printf("  %s\n", strings.Join(details, " | "))
+		}
+
+		fmt.Println()
+	}
+
+	return nil
+}
+
+// installIdentifier returns the identifier chunk install takes for a result;
+// recipes are qualified with their bench
+func installIdentifier(r *sources.ModpackSearchResult) string {
+	if r.Bench != "" {
+		return r.Bench + "::" + r.Identifier
+	}
+	return r.Identifier
+}
+
+// remoteSearchResultJSON is the --json form of a --remote search result
+type remoteSearchResultJSON struct {
+	Identifier       string     `json:"identifier"`
+	Source           string     `json:"source"`
+	Name             string     `json:"name"`
+	Description      string     `json:"description,omitempty"`
+	MCVersion        string     `json:"mc_version,omitempty"`
+	MCVersions       []string   `json:"mc_versions,omitempty"`
+	Loader           string     `json:"loader,omitempty"`
+	RecommendedRAMGB int        `json:"recommended_ram_gb,omitempty"`
+	Downloads        int        `json:"downloads,omitempty"`
+	Tags             []string   `json:"tags,omitempty"`
+	Author           string     `json:"author,omitempty"`
+	UpdatedAt        *time.Time `json:"updated_at,omitempty"`
+}
+
+// displayRemoteSearchJSON outputs --remote search results in JSON format
+func displayRemoteSearchJSON(results []*sources.ModpackSearchResult) error {
+	out := make([]remoteSearchResultJSON, 0, len(results))
+	for _, r := range results {
+		entry := remoteSearchResultJSON{
+			Identifier:       installIdentifier(r),
+			Source:           r.Source,
+			Name:             r.Name,
+			Description:      r.Description,
+			MCVersion:        r.MCVersion,
+			MCVersions:       r.MCVersions,
+			Loader:           string(r.Loader),
+			RecommendedRAMGB: r.RecommendedRAM,
+			Downloads:        r.Downloads,
+			Tags:             r.Tags,
+			Author:           r.Author,
+		}
+		if !r.UpdatedAt.IsZero() {
+			updated := r.UpdatedAt.UTC()
+			entry.UpdatedAt = &updated
+		}
+		out = append(out, entry)
+	}
+
+	jsonData, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	fmt.Println(string(jsonData))
+	return nil
+}
+
+// searchResultJSON is the --json form of a search result
+type searchResultJSON struct {
+	Slug             string     `json:"slug"`
+	Bench            string     `json:"bench"`
+	Name             string     `json:"name"`
+	Description      string     `json:"description,omitempty"`
+	MCVersion        string     `json:"mc_version"`
+	Loader           string     `json:"loader"`
+	LoaderVersion    string     `json:"loader_version,omitempty"`
+	RecommendedRAMGB int        `json:"recommended_ram_gb,omitempty"`
+	Tags             []string   `json:"tags,omitempty"`
+	Author           string     `json:"author,omitempty"`
+	Score            int        `json:"score"`
+	UpdatedAt        *time.Time `json:"updated_at,omitempty"`
+}
+
+// displaySearchJSON outputs search results in JSON format
+func displaySearchJSON(results []*search.SearchResult) error {
+	out := make([]searchResultJSON, 0, len(results))
+	for _, result := range results {
+		r := result.Recipe
+		entry := searchResultJSON{
+			Slug:             r.Slug,
+			Bench:            r.BenchName,
+			Name:             r.Name,
+			Description:      r.Description,
+			MCVersion:        r.MCVersion,
+			Loader:           r.Loader,
+			LoaderVersion:    r.LoaderVersion,
+			RecommendedRAMGB: r.RecommendedRAMGB,
+			Tags:             r.Tags,
+			Author:           r.Author,
+			Score:            result.Score,
+		}
+		if !r.UpdatedAt.IsZero() {
+			updated := r.UpdatedAt.UTC()
+			entry.UpdatedAt = &updated
+		}
+		out = append(out, entry)
+	}
+
+	jsonData, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	fmt.Println(string(jsonData))
+	return nil
 }
 
 func capitalize(s string) string {
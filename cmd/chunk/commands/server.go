@@ -0,0 +1,423 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/alexinslc/chunk/internal/supervisor"
+	"github.com/alexinslc/chunk/internal/tracking"
+	"github.com/alexinslc/chunk/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// startTimeout is how long chunk start waits for the server process to launch
+const startTimeout = 30 * time.Second
+
+// eulaAcceptedRegexp matches an accepted eula.txt
+var eulaAcceptedRegexp = regexp.MustCompile(`(?mi)^\s*eula\s*=\s*true\s*$`)
+
+var (
+	supervisedDir    string
+	startForeground  bool
+	startRestart     bool
+	startMaxRestarts int
+	startStopTimeout time.Duration
+	statusJSON       bool
+)
+
+var StartCmd = &cobra.Command{
+	Use:   "start [instance]",
+	Short: "Start a server in the background",
+	Long: `Start an installed server under the chunk supervisor.
+
+The supervisor launches the server the same way start.sh does, writes its
+state to .chunk-server.json in the server directory and captures the server
+output in logs/console.log, rotated at 10 MB with 5 old files kept.
+
+With --restart, a server that crashes is restarted after a backoff delay that
+starts at 5 seconds and doubles up to 5 minutes. After --max-restarts crashes
+in a row the supervisor gives up. A server that ran for 10 minutes before
+crashing starts the count over.
+
+The argument is an instance name (see chunk list); without one, --dir
+selects the server directory.
+
+Examples:
+  chunk start survival-eu                  # Start the instance named survival-eu
+  chunk start --dir /opt/minecraft/server  # Start the server in a directory
+  chunk start survival-eu --restart        # Restart the server if it crashes
+  chunk start survival-eu --foreground     # Supervise in this terminal (for service managers)`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runStart,
+}
+
+var StopCmd = &cobra.Command{
+	Use:   "stop [instance]",
+	Short: "Stop a server started with chunk start",
+	Long: `Stop a server started with chunk start.
+
+The server is sent the stop command so it saves the world and exits. If it
+is still running after the stop timeout (--stop-timeout of chunk start, 60
+seconds by default), it is sent SIGTERM, and SIGKILL 10 seconds later.
+
+Examples:
+  chunk stop survival-eu
+  chunk stop --dir /opt/minecraft/server`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runStop,
+}
+
+var RestartCmd = &cobra.Command{
+	Use:   "restart [instance]",
+	Short: "Restart a server started with chunk start",
+	Long: `Stop a server and start it again with the same supervisor settings.
+
+A server that is not running is started with the default settings.
+
+Examples:
+  chunk restart survival-eu
+  chunk restart --dir /opt/minecraft/server`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runRestart,
+}
+
+var StatusCmd = &cobra.Command{
+	Use:   "status [instance]",
+	Short: "Show whether servers are running",
+	Long: `Show the supervisor state of servers started with chunk start.
+
+Without an argument, the status of every tracked installation is shown.
+
+Examples:
+  chunk status                # Status of all tracked installations
+  chunk status survival-eu    # Status of one instance
+  chunk status --json         # Output in JSON format`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runStatus,
+}
+
+func init() {
+	for _, cmd := range []*cobra.Command{StartCmd, StopCmd, RestartCmd, StatusCmd} {
+		cmd.Flags().StringVar(&supervisedDir, "dir", "", "Server directory (default: ./server)")
+	}
+	StartCmd.Flags().BoolVar(&startForeground, "foreground", false, "Supervise the server in this process instead of in the background")
+	StartCmd.Flags().BoolVar(&startRestart, "restart", false, "Restart the server with backoff when it crashes")
+	StartCmd.Flags().IntVar(&startMaxRestarts, "max-restarts", supervisor.DefaultMaxRestarts, "Crashes in a row to restart after (0 for no limit)")
+	StartCmd.Flags().DurationVar(&startStopTimeout, "stop-timeout", supervisor.DefaultStopTimeout, "How long the server gets to stop before it is terminated")
+	StatusCmd.Flags().BoolVar(&statusJSON, "json", false, "Output in JSON format")
+
+	// Suppress usage printing on errors
+	for _, cmd := range []*cobra.Command{StartCmd, StopCmd, RestartCmd, StatusCmd} {
+		cmd.SilenceUsage = true
+	}
+}
+
+// ManagesServer reports whether cmd starts, stops, inspects or controls a
+// server or manages its service unit, which needs no benches
+func ManagesServer(cmd *cobra.Command) bool {
+	switch cmd {
+	case StartCmd, StopCmd, RestartCmd, StatusCmd, RconCmd, PingCmd:
+		return true
+	}
+	return cmd.Parent() == ServiceCmd
+}
+
+// resolveServer returns the name and absolute directory of the server an
+// instance name argument or --dir selects
+func resolveServer(args []string) (string, string, error) {
+	dir := supervisedDir
+	name := ""
+	if len(args) > 0 && dir == "" {
+		instance, err := lookupInstance(args[0])
+		if err != nil {
+			return "", "", err
+		}
+		if instance == nil {
+			return "", "", fmt.Errorf("no installation named %s (see chunk list)", args[0])
+		}
+		name, dir = instance.Name, instance.Path
+	}
+	if dir == "" {
+		dir = "./server"
+	}
+
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to resolve server directory: %w", err)
+	}
+	if name == "" {
+		name = absDir
+		if installation, err := trackedInstallation(absDir); err == nil && installation != nil {
+			name = installation.Name
+		}
+	}
+	return name, absDir, nil
+}
+
+func runStart(cmd *cobra.Command, args []string) error {
+	name, dir, err := resolveServer(args)
+	if err != nil {
+		return err
+	}
+
+	opts := supervisor.Options{
+		ServerDir:   dir,
+		StopTimeout: startStopTimeout,
+		Restart: supervisor.RestartPolicy{
+			Enabled:     startRestart,
+			MaxRestarts: startMaxRestarts,
+		},
+	}
+	return startServer(cmd, name, opts)
+}
+
+// startServer starts a supervisor for the server in opts.ServerDir, in the
+// background unless --foreground is set
+func startServer(cmd *cobra.Command, name string, opts supervisor.Options) error {
+	dir := opts.ServerDir
+	state, err := supervisor.ReadState(dir)
+	if err != nil {
+		return err
+	}
+	if state != nil && state.Running() {
+		return fmt.Errorf("%s is already running (supervisor pid %d)", name, state.SupervisorPID)
+	}
+
+	// Fail here rather than in the background when the server cannot start
+	if _, _, err := supervisor.LaunchCommand(dir); err != nil {
+		return err
+	}
+	eula, err := os.ReadFile(filepath.Join(dir, "eula.txt"))
+	if err != nil || !eulaAcceptedRegexp.Match(eula) {
+		return fmt.Errorf("the Minecraft EULA is not accepted: review %s and set eula=true", filepath.Join(dir, "eula.txt"))
+	}
+
+	if startForeground {
+		ctx, stop := interruptContext(cmd)
+		defer stop()
+		return supervisor.New(opts).Run(ctx)
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate chunk executable: %w", err)
+	}
+	supervisorArgs := []string{"start", "--foreground", "--dir", dir,
+		"--stop-timeout", opts.StopTimeout.String(),
+		"--max-restarts", fmt.Sprint(opts.Restart.MaxRestarts)}
+	if opts.Restart.Enabled {
+		supervisorArgs = append(supervisorArgs, "--restart")
+	}
+
+	// Anything the supervisor prints before it opens its own log ends up
+	// in the console log too
+	logPath := filepath.Join(dir, filepath.FromSlash(supervisor.ConsoleLog))
+	if err := os.MkdirAll(filepath.Dir(logPath), 0755); err != nil {
+		return fmt.Errorf("failed to create log directory: %w", err)
+	}
+	logFile, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open log: %w", err)
+	}
+	defer logFile.Close()
+
+	child := exec.Command(executable, supervisorArgs...)
+	child.Dir = dir
+	child.Stdout = logFile
+	child.Stderr = logFile
+	supervisor.Detach(child)
+	if err := child.Start(); err != nil {
+		return fmt.Errorf("failed to start supervisor: %w", err)
+	}
+	exited := make(chan error, 1)
+	go func() {
+		exited <- child.Wait()
+	}()
+
+	spinner := ui.NewSpinner(fmt.Sprintf("Starting %s...", name))
+	spinner.Start()
+	state, err = supervisor.WaitStarted(dir, child.Process.Pid, exited, startTimeout)
+	if err != nil {
+		spinner.Error(fmt.Sprintf("Failed to start %s", name))
+		return err
+	}
+	spinner.Success(fmt.Sprintf("Started %s (pid %d)", name, state.PID))
+
+	fmt.Printf("  Log: %s\n", state.LogFile)
+	if opts.Restart.Enabled {
+		fmt.Println("  Restarts on crash: yes")
+	}
+	fmt.Println()
+	return nil
+}
+
+func runStop(cmd *cobra.Command, args []string) error {
+	name, dir, err := resolveServer(args)
+	if err != nil {
+		return err
+	}
+	return stopServer(name, dir)
+}
+
+func stopServer(name, dir string) error {
+	spinner := ui.NewSpinner(fmt.Sprintf("Stopping %s...", name))
+	spinner.Start()
+	if err := supervisor.Stop(dir); err != nil {
+		if errors.Is(err, supervisor.ErrNotRunning) {
+			spinner.Stop()
+			ui.PrintInfo(fmt.Sprintf("%s is not running", name))
+			return nil
+		}
+		spinner.Error(fmt.Sprintf("Failed to stop %s", name))
+		return err
+	}
+	spinner.Success(fmt.Sprintf("Stopped %s", name))
+	return nil
+}
+
+func runRestart(cmd *cobra.Command, args []string) error {
+	name, dir, err := resolveServer(args)
+	if err != nil {
+		return err
+	}
+
+	state, err := supervisor.ReadState(dir)
+	if err != nil {
+		return err
+	}
+	opts := supervisor.Options{
+		ServerDir:   dir,
+		StopTimeout: supervisor.DefaultStopTimeout,
+		Restart:     supervisor.RestartPolicy{MaxRestarts: supervisor.DefaultMaxRestarts},
+	}
+	if state != nil {
+		opts.StopTimeout = state.StopTimeout
+		opts.Restart = state.Restart
+	}
+
+	if err := stopServer(name, dir); err != nil {
+		return err
+	}
+	return startServer(cmd, name, opts)
+}
+
+// serverStatus is the status of one server as shown by chunk status
+type serverStatus struct {
+	Name          string            `json:"name"`
+	Path          string            `json:"path"`
+	Status        supervisor.Status `json:"status"`
+	PID           int               `json:"pid,omitempty"`
+	SupervisorPID int               `json:"supervisor_pid,omitempty"`
+	StartedAt     *time.Time        `json:"started_at,omitempty"`
+	Restarts      int               `json:"restarts"`
+	ExitCode      int               `json:"exit_code,omitempty"`
+	LogFile       string            `json:"log_file,omitempty"`
+}
+
+func runStatus(cmd *cobra.Command, args []string) error {
+	var targets [][2]string
+	if len(args) > 0 || supervisedDir != "" {
+		name, dir, err := resolveServer(args)
+		if err != nil {
+			return err
+		}
+		targets = append(targets, [2]string{name, dir})
+	} else {
+		tracker, err := tracking.NewTracker()
+		if err != nil {
+			return fmt.Errorf("failed to initialize tracker: %w", err)
+		}
+		installations, err := tracker.ListInstallations()
+		if err != nil {
+			return fmt.Errorf("failed to list installations: %w", err)
+		}
+		for _, inst := range installations {
+			targets = append(targets, [2]string{inst.Name, inst.Path})
+		}
+	}
+
+	statuses := make([]*serverStatus, 0, len(targets))
+	for _, target := range targets {
+		status, err := readServerStatus(target[0], target[1])
+		if err != nil {
+			return err
+		}
+		statuses = append(statuses, status)
+	}
+
+	if statusJSON {
+		data, err := json.MarshalIndent(statuses, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(statuses) == 0 {
+		fmt.Println()
+		fmt.Println("No modpacks installed yet.")
+		fmt.Println()
+		return nil
+	}
+
+	fmt.Println()
+	for _, status := range statuses {
+		displayServerStatus(status)
+	}
+	return nil
+}
+
+// readServerStatus reads the state file of a server. Servers never started
+// with chunk start are reported as stopped.
+func readServerStatus(name, dir string) (*serverStatus, error) {
+	status := &serverStatus{Name: name, Path: dir, Status: supervisor.StatusStopped}
+
+	state, err := supervisor.ReadState(dir)
+	if err != nil {
+		return nil, err
+	}
+	if state == nil {
+		return status, nil
+	}
+
+	status.Status = state.Current()
+	status.Restarts = state.Restarts
+	status.ExitCode = state.ExitCode
+	status.LogFile = state.LogFile
+	if state.Running() {
+		status.PID = state.PID
+		status.SupervisorPID = state.SupervisorPID
+		if !state.StartedAt.IsZero() {
+			status.StartedAt = &state.StartedAt
+		}
+	}
+	return status, nil
+}
+
+func displayServerStatus(status *serverStatus) {
+	fmt.Printf("%s: %s\n", status.Name, status.Status)
+	fmt.Printf("  Path: %s\n", status.Path)
+	if status.PID != 0 {
+		fmt.Printf("  PID: %d\n", status.PID)
+	}
+	if status.StartedAt != nil {
+		fmt.Printf("  Started: %s\n", formatRelativeTime(*status.StartedAt))
+	}
+	if status.Restarts > 0 {
+		fmt.Printf("  Restarts: %d\n", status.Restarts)
+	}
+	if status.ExitCode != 0 && (status.Status == supervisor.StatusCrashed || status.Status == supervisor.StatusRestarting) {
+		fmt.Printf("  Last exit code: %d\n", status.ExitCode)
+	}
+	if status.LogFile != "" {
+		fmt.Printf("  Log: %s\n", status.LogFile)
+	}
+	fmt.Println()
+}
@@ -0,0 +1,248 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/alexinslc/chunk/internal/config"
+	"github.com/alexinslc/chunk/internal/converter"
+	"github.com/alexinslc/chunk/internal/supervisor"
+	"github.com/alexinslc/chunk/internal/tracking"
+	"github.com/alexinslc/chunk/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// systemUnitDir is where units for the system service manager are installed
+const systemUnitDir = "/etc/systemd/system"
+
+// unitsDir is where units are written by default, under ~/.chunk. Upgrades
+// and reinstalls replace the server directory, so units are kept out of it.
+const unitsDir = "units"
+
+var (
+	serviceTarget      string
+	serviceUserUnit    bool
+	serviceSystemUnit  bool
+	serviceRunAs       string
+	serviceStopTimeout time.Duration
+)
+
+var ServiceCmd = &cobra.Command{
+	Use:   "service",
+	Short: "Manage systemd units for installed servers",
+	Long: `Generate and remove systemd units that run installed servers.
+
+The unit runs the server under the chunk supervisor (chunk start --foreground),
+restarts it when it crashes and stops it with chunk stop, so the world is saved.`,
+}
+
+var serviceInstallCmd = &cobra.Command{
+	Use:   "install [instance]",
+	Short: "Write a systemd unit for an installation",
+	Long: `Write a hardened systemd unit, chunk-<instance>.service, for a tracked
+installation.
+
+The unit is written to ~/.chunk/units unless --target, --user
+(~/.config/systemd/user) or --system (/etc/systemd/system) selects another
+directory. Its memory limits are derived from the pack's RAM setting. The
+unit path is recorded in ~/.chunk/installed.json, so chunk service remove and
+chunk uninstall can remove it.
+
+Examples:
+  chunk service install survival-eu                      # Write the unit to ~/.chunk/units
+  chunk service install survival-eu --user               # Install as a user unit
+  sudo chunk service install survival-eu --system --run-as minecraft
+  chunk service install survival-eu --target ./units     # Write the unit to ./units`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runServiceInstall,
+}
+
+var serviceRemoveCmd = &cobra.Command{
+	Use:   "remove [instance]",
+	Short: "Remove the systemd unit of an installation",
+	Long: `Remove the systemd unit chunk service install wrote for an installation.
+
+Stop and disable the unit with systemctl first.
+
+Examples:
+  chunk service remove survival-eu`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runServiceRemove,
+}
+
+func init() {
+	ServiceCmd.AddCommand(serviceInstallCmd)
+	ServiceCmd.AddCommand(serviceRemoveCmd)
+
+	for _, cmd := range []*cobra.Command{serviceInstallCmd, serviceRemoveCmd} {
+		cmd.Flags().StringVar(&supervisedDir, "dir", "", "Server directory (default: ./server)")
+	}
+	serviceInstallCmd.Flags().StringVar(&serviceTarget, "target", "", "Directory to write the unit to (default: ~/.chunk/units)")
+	serviceInstallCmd.Flags().BoolVar(&serviceUserUnit, "user", false, "Install as a user unit in ~/.config/systemd/user")
+	serviceInstallCmd.Flags().BoolVar(&serviceSystemUnit, "system", false, "Install as a system unit in "+systemUnitDir)
+	serviceInstallCmd.Flags().StringVar(&serviceRunAs, "run-as", "", "Account the server runs as in a system unit (default: the current user)")
+	serviceInstallCmd.Flags().DurationVar(&serviceStopTimeout, "stop-timeout", supervisor.DefaultStopTimeout, "How long the server gets to stop before it is terminated")
+	serviceInstallCmd.MarkFlagsMutuallyExclusive("target", "user", "system")
+
+	// Suppress usage printing on errors
+	serviceInstallCmd.SilenceUsage = true
+	serviceRemoveCmd.SilenceUsage = true
+}
+
+// trackedServer returns the tracked installation an instance name argument
+// or --dir selects
+func trackedServer(args []string) (*tracking.Installation, error) {
+	name, dir, err := resolveServer(args)
+	if err != nil {
+		return nil, err
+	}
+	installation, err := trackedInstallation(dir)
+	if err != nil {
+		return nil, err
+	}
+	if installation == nil {
+		return nil, fmt.Errorf("%s is not a tracked installation (see chunk list)", name)
+	}
+	return installation, nil
+}
+
+func runServiceInstall(cmd *cobra.Command, args []string) error {
+	installation, err := trackedServer(args)
+	if err != nil {
+		return err
+	}
+
+	manifest, err := config.LoadChunkManifest(filepath.Join(installation.Path, config.ChunkManifestFile))
+	if err != nil {
+		return fmt.Errorf("failed to read installed manifest: %w", err)
+	}
+
+	chunkPath, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate chunk executable: %w", err)
+	}
+
+	targetDir := serviceTarget
+	switch {
+	case serviceUserUnit:
+		configDir, err := os.UserConfigDir()
+		if err != nil {
+			return fmt.Errorf("failed to locate user config directory: %w", err)
+		}
+		targetDir = filepath.Join(configDir, "systemd", "user")
+	case serviceSystemUnit:
+		targetDir = systemUnitDir
+	case targetDir == "":
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return fmt.Errorf("failed to get home directory: %w", err)
+		}
+		targetDir = filepath.Join(home, ".chunk", unitsDir)
+	}
+	targetDir, err = filepath.Abs(targetDir)
+	if err != nil {
+		return fmt.Errorf("failed to resolve target directory: %w", err)
+	}
+	if rel, err := filepath.Rel(installation.Path, targetDir); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		ui.PrintWarning("The unit is inside the server directory; upgrades and reinstalls replace the directory and remove it")
+	}
+
+	runAs := serviceRunAs
+	if runAs == "" && !serviceUserUnit {
+		current, err := user.Current()
+		if err != nil {
+			return fmt.Errorf("failed to determine current user, use --run-as: %w", err)
+		}
+		runAs = current.Username
+	}
+
+	unit := converter.NewScriptGenerator().GenerateSystemdUnit(&converter.ServiceOptions{
+		Name:           installation.Name,
+		ServerDir:      installation.Path,
+		ChunkPath:      chunkPath,
+		User:           runAs,
+		UserUnit:       serviceUserUnit,
+		RecommendedRAM: manifest.RecommendedRAMGB,
+		StopTimeout:    serviceStopTimeout,
+	})
+
+	if err := os.MkdirAll(targetDir, 0755); err != nil {
+		return fmt.Errorf("failed to create target directory: %w", err)
+	}
+	unitName := converter.UnitName(installation.Name)
+	unitPath := filepath.Join(targetDir, unitName)
+	if err := os.WriteFile(unitPath, []byte(unit), 0644); err != nil {
+		return fmt.Errorf("failed to write service unit: %w", err)
+	}
+
+	// A unit written elsewhere before is replaced by this one
+	if installation.ServiceUnit != "" && installation.ServiceUnit != unitPath {
+		if err := os.Remove(installation.ServiceUnit); err != nil && !os.IsNotExist(err) {
+			ui.PrintWarning(fmt.Sprintf("Failed to remove previous unit %s: %v", installation.ServiceUnit, err))
+		}
+	}
+	if err := recordServiceUnit(installation, unitPath); err != nil {
+		return err
+	}
+
+	ui.PrintSuccess(fmt.Sprintf("Wrote %s", unitPath))
+	fmt.Println()
+	switch {
+	case serviceUserUnit:
+		fmt.Println("To enable the server:")
+		fmt.Println("   systemctl --user daemon-reload")
+		fmt.Printf("   systemctl --user enable --now %s\n", unitName)
+		fmt.Println()
+		fmt.Println("To keep it running after you log out:")
+		fmt.Println("   loginctl enable-linger")
+	case serviceSystemUnit:
+		fmt.Println("To enable the server:")
+		fmt.Println("   sudo systemctl daemon-reload")
+		fmt.Printf("   sudo systemctl enable --now %s\n", unitName)
+	default:
+		fmt.Printf("Copy or link it into %s (or ~/.config/systemd/user without User=),\n", systemUnitDir)
+		fmt.Println("then reload systemd and enable the unit.")
+	}
+	fmt.Println()
+	return nil
+}
+
+func runServiceRemove(cmd *cobra.Command, args []string) error {
+	installation, err := trackedServer(args)
+	if err != nil {
+		return err
+	}
+	if installation.ServiceUnit == "" {
+		ui.PrintInfo(fmt.Sprintf("%s has no service unit", installation.Name))
+		return nil
+	}
+
+	if err := os.Remove(installation.ServiceUnit); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove service unit: %w", err)
+	}
+	unitPath := installation.ServiceUnit
+	if err := recordServiceUnit(installation, ""); err != nil {
+		return err
+	}
+
+	ui.PrintSuccess(fmt.Sprintf("Removed %s", unitPath))
+	return nil
+}
+
+// recordServiceUnit stores the unit path of an installation in the tracker
+func recordServiceUnit(installation *tracking.Installation, unitPath string) error {
+	tracker, err := tracking.NewTracker()
+	if err != nil {
+		return fmt.Errorf("failed to initialize tracker: %w", err)
+	}
+
+	installation.ServiceUnit = unitPath
+	if err := tracker.UpdateInstallation(installation); err != nil {
+		return fmt.Errorf("failed to record service unit: %w", err)
+	}
+	return nil
+}
@@ -16,7 +16,7 @@ var (
 )
 
 var UninstallCmd = &cobra.Command{
-	Use:   "uninstall <modpack>",
+	Use:   "uninstall <modpack|instance>",
 	Short: "Uninstall a modpack server",
 	Long: `Uninstall a modpack server installation and optionally preserve world data.
 
@@ -26,7 +26,11 @@ This command will:
   - Remove the installation from tracking
   - Prompt for confirmation before deletion
 
+The argument is either an instance name (see chunk list), which selects the
+server to remove, or the modpack installed in --dir.
+
 Examples:
+  chunk uninstall survival-eu               # Uninstall the instance named survival-eu
   chunk uninstall atm9                      # Prompt for world preservation
   chunk uninstall atm9 --keep-worlds        # Keep world and player data
   chunk uninstall atm9 --force              # No confirmation prompts
@@ -45,6 +49,17 @@ func runUninstall(cmd *cobra.Command, args []string) error {
 
 	// Normalize destination directory
 	destDir := uninstallDir
+	if destDir == "" {
+		instance, err := lookupInstance(modpack)
+		if err != nil {
+			return err
+		}
+		if instance != nil {
+			destDir = instance.Path
+			modpack = instance.Slug
+			fmt.Printf("Instance %s: %s\n", instance.Name, instance.Path)
+		}
+	}
 	if destDir == "" {
 		destDir = "./server"
 	}
@@ -5,6 +5,7 @@ import (
 	"os"
 
 	"github.com/alexinslc/chunk/internal/bench"
+	"github.com/alexinslc/chunk/internal/search"
 	"github.com/spf13/cobra"
 )
 
@@ -66,6 +67,20 @@ func runUpdate() error {
 		results = allResults
 	}
 
+	// Rebuild the search index of every bench that changed or has no index
+	for _, result := range results {
+		if result.Error != nil {
+			continue
+		}
+		b, err := manager.Get(result.BenchName)
+		if err != nil {
+			continue
+		}
+		if err := search.EnsureIndex(*b); err != nil {
+			result.Error = fmt.Errorf("failed to build search index: %w", err)
+		}
+	}
+
 	// Display results
 	updatedCount := 0
 	for _, result := range results {
@@ -1,7 +1,9 @@
 package commands
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -9,16 +11,18 @@ import (
 	"github.com/alexinslc/chunk/internal/install"
 	"github.com/alexinslc/chunk/internal/preserve"
 	"github.com/alexinslc/chunk/internal/sources"
+	"github.com/alexinslc/chunk/internal/supervisor"
 	"github.com/alexinslc/chunk/internal/tracking"
 	"github.com/alexinslc/chunk/internal/ui"
 	"github.com/spf13/cobra"
 )
 
 var (
-	upgradeDir    string
-	dryRun        bool
-	skipBackup    bool
-	upgradeVerify bool
+	upgradeDir     string
+	dryRun         bool
+	skipBackup     bool
+	upgradeVerify  bool
+	upgradeVariant string
 )
 
 var UpgradeCmd = &cobra.Command{
@@ -34,10 +38,18 @@ This command will:
   - Update mods and mod loader if needed
   - Provide warnings before any destructive operations
 
+The argument is either an instance name (see chunk list), which upgrades
+that server to the latest version of its modpack, or a modpack identifier
+to install into the server directory.
+
+Servers installed with a recipe variant keep it; use --variant to switch.
+
 Examples:
   chunk upgrade                              # Upgrade from installed.json
+  chunk upgrade survival-eu                  # Upgrade the instance named survival-eu
   chunk upgrade atm9                         # Upgrade specific modpack
   chunk upgrade atm9 --dir /opt/minecraft/server
+  chunk upgrade survival-eu --variant full   # Switch the instance to the full variant
   chunk upgrade --dry-run                    # Preview changes without upgrading`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runUpgrade,
@@ -48,8 +60,25 @@ func runUpgrade(cmd *cobra.Command, args []string) error {
 	fmt.Println("🔄 Chunk Modpack Upgrader")
 	fmt.Println()
 
+	// Ctrl-C stops in-flight downloads; the rollback still runs
+	ctx, stop := interruptContext(cmd)
+	defer stop()
+
+	// An instance name selects both the server directory and its modpack
+	var instance *tracking.Installation
+	if len(args) > 0 && upgradeDir == "" {
+		var err error
+		if instance, err = lookupInstance(args[0]); err != nil {
+			return err
+		}
+	}
+
 	// Determine server directory
 	serverDir := upgradeDir
+	if instance != nil {
+		serverDir = instance.Path
+		ui.PrintInfo(fmt.Sprintf("Instance %s: %s", instance.Name, instance.Path))
+	}
 	if serverDir == "" {
 		serverDir = "./server"
 	}
@@ -67,18 +96,24 @@ func runUpgrade(cmd *cobra.Command, args []string) error {
 
 	// Try to get modpack identifier from args or from tracking
 	var identifier string
-	if len(args) > 0 {
+	var trackedVariant string
+	if instance != nil {
+		identifier = instance.Slug
+		trackedVariant = instance.Variant
+		ui.PrintInfo(fmt.Sprintf("Detected modpack: %s", identifier))
+	} else if len(args) > 0 {
 		identifier = args[0]
+		// Upgrading a server to the modpack it already runs keeps its variant
+		if installation, err := trackedInstallation(absServerDir); err == nil && installation != nil {
+			if _, recipeName := sources.ParseRecipeIdentifier(identifier); recipeName == installation.Slug {
+				trackedVariant = installation.Variant
+			}
+		}
 	} else {
 		// Try to get from tracking system
-		tracker, err := tracking.NewTracker()
+		installation, err := trackedInstallation(absServerDir)
 		if err != nil {
-			return fmt.Errorf("failed to initialize tracker: %w", err)
-		}
-
-		installation, err := tracker.GetInstallation(absServerDir)
-		if err != nil {
-			return fmt.Errorf("failed to get installation info: %w", err)
+			return err
 		}
 
 		if installation == nil {
@@ -86,9 +121,18 @@ func runUpgrade(cmd *cobra.Command, args []string) error {
 		}
 
 		identifier = installation.Slug
+		trackedVariant = installation.Variant
 		ui.PrintInfo(fmt.Sprintf("Detected modpack: %s", identifier))
 	}
 
+	variant := upgradeVariant
+	if variant == "" {
+		variant = trackedVariant
+	}
+	if variant != "" {
+		ui.PrintInfo(fmt.Sprintf("Variant: %s", variant))
+	}
+
 	// Get current version info
 	currentVersion, currentRecipe, err := getCurrentVersion(absServerDir)
 	if err != nil {
@@ -101,7 +145,7 @@ func runUpgrade(cmd *cobra.Command, args []string) error {
 	// Fetch latest version from sources
 	ui.PrintInfo("Checking for updates...")
 	sourceManager := sources.NewSourceManager()
-	newModpack, err := sourceManager.Fetch(identifier)
+	newModpack, err := sourceManager.FetchVariant(ctx, identifier, variant)
 	if err != nil {
 		return fmt.Errorf("failed to fetch latest version: %w", err)
 	}
@@ -114,7 +158,11 @@ func runUpgrade(cmd *cobra.Command, args []string) error {
 	// version fields in .chunk-recipe.json, it will detect changes. However, if the version
 	// is derived from "mc_version-loader" format (fallback), it may not detect modpack
 	// version updates that keep the same Minecraft and loader versions.
-	if currentVersion == newVersion && currentVersion != "unknown" {
+	currentVariant := ""
+	if currentRecipe != nil {
+		currentVariant, _ = currentRecipe["variant"].(string)
+	}
+	if currentVersion == newVersion && currentVersion != "unknown" && currentVariant == newModpack.Variant {
 		fmt.Println()
 		ui.PrintSuccess("Already up to date!")
 		return nil
@@ -131,6 +179,9 @@ func runUpgrade(cmd *cobra.Command, args []string) error {
 	fmt.Printf("   Minecraft:     %s\n", newModpack.MCVersion)
 	fmt.Printf("   Loader:        %s %s\n", newModpack.Loader, newModpack.LoaderVersion)
 	fmt.Printf("   Server Mods:   %d\n", len(newModpack.Mods))
+	if newModpack.Variant != "" {
+		fmt.Printf("   Variant:       %s\n", newModpack.Variant)
+	}
 
 	// Show version comparison if we have recipe data
 	if currentRecipe != nil {
@@ -150,6 +201,9 @@ func runUpgrade(cmd *cobra.Command, args []string) error {
 		if currentLoaderVersion != "" && currentLoaderVersion != newModpack.LoaderVersion {
 			fmt.Printf("   📦 Loader version: %s → %s\n", currentLoaderVersion, newModpack.LoaderVersion)
 		}
+		if currentVariant != newModpack.Variant {
+			fmt.Printf("   🔀 Variant: %s → %s\n", variantLabel(currentVariant), variantLabel(newModpack.Variant))
+		}
 	}
 
 	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
@@ -163,6 +217,11 @@ func runUpgrade(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
+	// A backup of a running server catches its worlds mid-write
+	if err := supervisor.EnsureStopped(absServerDir); err != nil {
+		return err
+	}
+
 	// Check for critical files to preserve
 	preserver := preserve.NewDataPreserver()
 	criticalFiles := preserver.GetCriticalFiles(absServerDir)
@@ -199,52 +258,34 @@ func runUpgrade(cmd *cobra.Command, args []string) error {
 		DestDir:      absServerDir,
 		PreserveData: true,
 		SkipVerify:   !upgradeVerify,
+		Variant:      variant,
 	}
 
-	result, err := installer.Install(opts)
+	result, err := installer.Install(ctx, opts)
 	if err != nil {
-		// Attempt rollback if we have a backup
+		if errors.Is(err, context.Canceled) {
+			fmt.Println()
+			ui.PrintWarning("Upgrade cancelled")
+		}
+		// Discard the staged server; the live one is untouched until the swap
+		rollbackErr := installer.Rollback()
+		if rollbackErr == nil {
+			return fmt.Errorf("upgrade failed: %w", err)
+		}
+		ui.PrintError(fmt.Sprintf("Rollback failed: %v", rollbackErr))
+
+		// Fall back to the data backup if we have one
 		if backupDir != "" {
-			ui.PrintWarning("Installation failed, attempting rollback...")
+			ui.PrintWarning("Restoring preserved data from backup...")
 			if restoreErr := preserver.RestoreFromBackup(absServerDir, backupDir); restoreErr != nil {
-				ui.PrintError(fmt.Sprintf("Rollback failed: %v", restoreErr))
+				ui.PrintError(fmt.Sprintf("Restore failed: %v", restoreErr))
 				return fmt.Errorf("upgrade failed and rollback failed: %w, rollback error: %v", err, restoreErr)
 			}
-			ui.PrintSuccess("Successfully rolled back to previous version")
+			ui.PrintSuccess("Preserved data restored from backup")
 		}
 		return fmt.Errorf("upgrade failed: %w", err)
 	}
 
-	// Restore preserved data
-	if backupDir != "" {
-		fmt.Println()
-		spinner := ui.NewSpinner("Restoring preserved data...")
-		spinner.Start()
-
-		// Remove worlds that may have been created by the new installation
-		// before restoring the original worlds from backup
-		worldPaths := []string{"world", "world_nether", "world_the_end"}
-		for _, worldPath := range worldPaths {
-			dstPath := filepath.Join(absServerDir, worldPath)
-			srcPath := filepath.Join(backupDir, worldPath)
-
-			// Only remove if backup has this world
-			if _, err := os.Stat(srcPath); err == nil {
-				if err := os.RemoveAll(dstPath); err != nil {
-					ui.PrintWarning(fmt.Sprintf("Failed to remove existing %s: %v", worldPath, err))
-				}
-			}
-		}
-
-		// Use the existing RestoreFromBackup method to restore all backed up data
-		if err := preserver.RestoreFromBackup(absServerDir, backupDir); err != nil {
-			spinner.Error(fmt.Sprintf("Failed to restore data: %v", err))
-			ui.PrintWarning("Some data may not have been restored correctly")
-		} else {
-			spinner.Success("Data restored")
-		}
-	}
-
 	// Update tracking
 	if trackErr := install.TrackInstallation(result, identifier); trackErr != nil {
 		ui.PrintWarning(fmt.Sprintf("Failed to update tracking: %v", trackErr))
@@ -277,6 +318,28 @@ func runUpgrade(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// trackedInstallation returns the tracked installation at serverDir, or nil
+func trackedInstallation(serverDir string) (*tracking.Installation, error) {
+	tracker, err := tracking.NewTracker()
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize tracker: %w", err)
+	}
+
+	installation, err := tracker.GetInstallation(serverDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get installation info: %w", err)
+	}
+	return installation, nil
+}
+
+// variantLabel names a variant for display; the recipe as written is "default"
+func variantLabel(variant string) string {
+	if variant == "" {
+		return "default"
+	}
+	return variant
+}
+
 // getCurrentVersion reads the current version from .chunk-recipe.json
 func getCurrentVersion(serverDir string) (string, map[string]interface{}, error) {
 	recipeFile := filepath.Join(serverDir, ".chunk-recipe.json")
@@ -323,6 +386,7 @@ func init() {
 	UpgradeCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Preview changes without upgrading")
 	UpgradeCmd.Flags().BoolVar(&skipBackup, "skip-backup", false, "Skip backup creation (not recommended)")
 	UpgradeCmd.Flags().BoolVar(&upgradeVerify, "verify", true, "Verify checksums of downloaded files")
+	UpgradeCmd.Flags().StringVar(&upgradeVariant, "variant", "", "Recipe variant to upgrade to (default: the installed variant)")
 
 	// Suppress usage printing on errors
 	UpgradeCmd.SilenceUsage = true
@@ -7,6 +7,7 @@ import (
 	"github.com/alexinslc/chunk/cmd/chunk/commands"
 	"github.com/alexinslc/chunk/internal/bench"
 	"github.com/alexinslc/chunk/internal/telemetry"
+	"github.com/alexinslc/chunk/internal/ui"
 	"github.com/spf13/cobra"
 )
 
@@ -33,6 +34,11 @@ Examples:
 		if err := telemetry.PromptForTelemetry(); err != nil {
 			fmt.Fprintf(os.Stderr, "Warning: Could not handle telemetry prompt: %v\n", err)
 		}
+		// Bundles are installed on hosts without network access, and
+		// running servers needs no benches
+		if commands.InstallsBundle(cmd, args) || commands.ManagesServer(cmd) {
+			return
+		}
 		if err := bench.EnsureCoreBench(); err != nil {
 			fmt.Fprintf(os.Stderr, "Warning: Could not auto-add core bench: %v\n", err)
 		}
@@ -40,6 +46,7 @@ Examples:
 }
 
 func init() {
+	rootCmd.PersistentFlags().BoolVarP(&ui.Verbose, "verbose", "v", false, "Show detailed output, such as download mirror rewrites")
 	rootCmd.SetVersionTemplate(`{{printf "chunk version %s\n" .Version}}`)
 	rootCmd.AddCommand(commands.InstallCmd)
 	rootCmd.AddCommand(commands.UninstallCmd)
@@ -54,6 +61,15 @@ func init() {
 	rootCmd.AddCommand(commands.CleanupCmd)
 	rootCmd.AddCommand(commands.RecipeCmd)
 	rootCmd.AddCommand(commands.DoctorCmd)
+	rootCmd.AddCommand(commands.BundleCmd)
+	rootCmd.AddCommand(commands.StartCmd)
+	rootCmd.AddCommand(commands.StopCmd)
+	rootCmd.AddCommand(commands.RestartCmd)
+	rootCmd.AddCommand(commands.StatusCmd)
+	rootCmd.AddCommand(commands.ServiceCmd)
+	rootCmd.AddCommand(commands.ExportCmd)
+	rootCmd.AddCommand(commands.RconCmd)
+	rootCmd.AddCommand(commands.PingCmd)
 }
 
 func main() {
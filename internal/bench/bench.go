@@ -36,6 +36,24 @@ func GetBenchesDir() (string, error) {
 	return filepath.Join(home, ".chunk", "Benches"), nil
 }
 
+// IndexPath returns where the search index of a bench is stored
+func IndexPath(name string) (string, error) {
+	benchesDir, err := GetBenchesDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(benchesDir, ".index", name+".json"), nil
+}
+
+// HeadCommit returns the commit a bench checkout is at
+func HeadCommit(path string) (string, error) {
+	output, err := exec.Command("git", "-C", path, "rev-parse", "HEAD").Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
 // NormalizeGitHubURL converts user/repo shorthand to full GitHub URL
 func NormalizeGitHubURL(input string) string {
 	// If it looks like a URL already, return as-is
@@ -152,6 +170,9 @@ func (m *Manager) Remove(name string) error {
 	if err := os.RemoveAll(benchToRemove.Path); err != nil {
 		return fmt.Errorf("failed to remove bench directory: %w", err)
 	}
+	if indexPath, err := IndexPath(name); err == nil {
+		_ = os.Remove(indexPath)
+	}
 
 	// Remove from config
 	m.config.Benches = append(m.config.Benches[:benchIndex], m.config.Benches[benchIndex+1:]...)
@@ -263,13 +284,11 @@ func (m *Manager) Update(name string) (*UpdateResult, error) {
 	}
 
 	// Get the current HEAD before pulling
-	oldHeadCmd := exec.Command("git", "-C", benchToUpdate.Path, "rev-parse", "HEAD")
-	oldHeadOutput, err := oldHeadCmd.Output()
+	oldHead, err := HeadCommit(benchToUpdate.Path)
 	if err != nil {
 		result.Error = fmt.Errorf("failed to get current HEAD: %w", err)
 		return result, result.Error
 	}
-	oldHead := strings.TrimSpace(string(oldHeadOutput))
 
 	// Run git pull
 	var stdout, stderr bytes.Buffer
@@ -283,13 +302,11 @@ func (m *Manager) Update(name string) (*UpdateResult, error) {
 	}
 
 	// Get the new HEAD after pulling
-	newHeadCmd := exec.Command("git", "-C", benchToUpdate.Path, "rev-parse", "HEAD")
-	newHeadOutput, err := newHeadCmd.Output()
+	newHead, err := HeadCommit(benchToUpdate.Path)
 	if err != nil {
 		result.Error = fmt.Errorf("failed to get new HEAD: %w", err)
 		return result, result.Error
 	}
-	newHead := strings.TrimSpace(string(newHeadOutput))
 
 	// Check if already up to date
 	if oldHead == newHead {
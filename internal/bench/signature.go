@@ -0,0 +1,224 @@
+package bench
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/alexinslc/chunk/internal/config"
+)
+
+// SignaturePolicy controls how recipe signatures are enforced
+type SignaturePolicy string
+
+const (
+	// PolicyOff skips signature checks
+	PolicyOff SignaturePolicy = "off"
+	// PolicyWarn installs unsigned or badly signed recipes with a warning
+	PolicyWarn SignaturePolicy = "warn"
+	// PolicyRequire rejects recipes without a valid signature from a trusted key
+	PolicyRequire SignaturePolicy = "require"
+)
+
+// SignatureExt is appended to a recipe's file name for its detached signature
+const SignatureExt = ".sig"
+
+var (
+	// ErrUnsigned is returned when a recipe has no signature file
+	ErrUnsigned = errors.New("recipe is not signed")
+	// ErrBadSignature is returned when no trusted key verifies the signature
+	ErrBadSignature = errors.New("recipe signature does not match any trusted key")
+)
+
+// ParseSignaturePolicy parses off, warn or require. An empty string is off.
+func ParseSignaturePolicy(s string) (SignaturePolicy, error) {
+	switch SignaturePolicy(strings.ToLower(strings.TrimSpace(s))) {
+	case "", PolicyOff:
+		return PolicyOff, nil
+	case PolicyWarn:
+		return PolicyWarn, nil
+	case PolicyRequire:
+		return PolicyRequire, nil
+	default:
+		return "", fmt.Errorf("invalid signature policy %q: must be off, warn or require", s)
+	}
+}
+
+// EffectivePolicy returns the bench's own policy, or the global one if the
+// bench does not set it. Unparseable policies are treated as require so that
+// a typo never weakens verification.
+func EffectivePolicy(cfg *config.Config, b config.Bench) SignaturePolicy {
+	value := b.SignaturePolicy
+	if value == "" && cfg != nil {
+		value = cfg.SignaturePolicy
+	}
+	policy, err := ParseSignaturePolicy(value)
+	if err != nil {
+		return PolicyRequire
+	}
+	return policy
+}
+
+// ParsePublicKey decodes a base64 ed25519 public key
+func ParsePublicKey(s string) (ed25519.PublicKey, error) {
+	data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil || len(data) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid public key %q: must be a base64 ed25519 public key", s)
+	}
+	return ed25519.PublicKey(data), nil
+}
+
+// ParsePrivateKey decodes a base64 ed25519 private key
+func ParsePrivateKey(s string) (ed25519.PrivateKey, error) {
+	data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil || len(data) != ed25519.PrivateKeySize {
+		return nil, fmt.Errorf("invalid private key: must be a base64 ed25519 private key")
+	}
+	return ed25519.PrivateKey(data), nil
+}
+
+// GenerateKey creates a new signing key pair, both base64 encoded
+func GenerateKey() (publicKey, privateKey string, err error) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to generate key: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(pub), base64.StdEncoding.EncodeToString(priv), nil
+}
+
+// SignRecipe writes the detached signature of the recipe file at path to
+// path + SignatureExt
+func SignRecipe(path string, privateKey ed25519.PrivateKey) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read recipe: %w", err)
+	}
+	signature := base64.StdEncoding.EncodeToString(ed25519.Sign(privateKey, data))
+	if err := os.WriteFile(path+SignatureExt, []byte(signature+"\n"), 0644); err != nil {
+		return fmt.Errorf("failed to write signature: %w", err)
+	}
+	return nil
+}
+
+// VerifyRecipe checks the recipe file at path against its detached signature.
+// The signature is accepted if any of trustedKeys verifies it.
+func VerifyRecipe(path string, trustedKeys []string) error {
+	encoded, err := os.ReadFile(path + SignatureExt)
+	if os.IsNotExist(err) {
+		return ErrUnsigned
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read signature: %w", err)
+	}
+	signature, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(encoded)))
+	if err != nil || len(signature) != ed25519.SignatureSize {
+		return ErrBadSignature
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read recipe: %w", err)
+	}
+
+	for _, key := range trustedKeys {
+		publicKey, err := ParsePublicKey(key)
+		if err != nil {
+			continue
+		}
+		if ed25519.Verify(publicKey, data, signature) {
+			return nil
+		}
+	}
+	return ErrBadSignature
+}
+
+// Trust pins a public key for a bench
+func (m *Manager) Trust(name string, publicKey string) error {
+	b, err := m.Get(name)
+	if err != nil {
+		return err
+	}
+	if _, err := ParsePublicKey(publicKey); err != nil {
+		return err
+	}
+	publicKey = strings.TrimSpace(publicKey)
+	for _, key := range b.TrustedKeys {
+		if key == publicKey {
+			return fmt.Errorf("key is already trusted for bench '%s'", name)
+		}
+	}
+	b.TrustedKeys = append(b.TrustedKeys, publicKey)
+
+	if err := m.config.Save(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	return nil
+}
+
+// Untrust removes a pinned public key from a bench
+func (m *Manager) Untrust(name string, publicKey string) error {
+	b, err := m.Get(name)
+	if err != nil {
+		return err
+	}
+	publicKey = strings.TrimSpace(publicKey)
+	for i, key := range b.TrustedKeys {
+		if key == publicKey {
+			b.TrustedKeys = append(b.TrustedKeys[:i], b.TrustedKeys[i+1:]...)
+			if err := m.config.Save(); err != nil {
+				return fmt.Errorf("failed to save config: %w", err)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("key is not trusted for bench '%s'", name)
+}
+
+// SetPolicy sets the signature policy of a bench, or the global policy when
+// name is empty
+func (m *Manager) SetPolicy(name string, policy SignaturePolicy) error {
+	if name == "" {
+		m.config.SignaturePolicy = string(policy)
+	} else {
+		b, err := m.Get(name)
+		if err != nil {
+			return err
+		}
+		b.SignaturePolicy = string(policy)
+	}
+
+	if err := m.config.Save(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	return nil
+}
+
+// Policy returns the signature policy in effect for a bench
+func (m *Manager) Policy(b config.Bench) SignaturePolicy {
+	return EffectivePolicy(m.config, b)
+}
+
+// CheckRecipe applies the bench's signature policy to the recipe file at path.
+// It returns an error only when the policy rejects the recipe; in warn mode the
+// verification failure is returned as the warning instead.
+func (m *Manager) CheckRecipe(b config.Bench, path string) (warning error, err error) {
+	policy := m.Policy(b)
+	if policy == PolicyOff {
+		return nil, nil
+	}
+
+	verifyErr := VerifyRecipe(path, b.TrustedKeys)
+	if verifyErr == nil {
+		return nil, nil
+	}
+	if policy == PolicyWarn {
+		return verifyErr, nil
+	}
+	if len(b.TrustedKeys) == 0 {
+		return nil, fmt.Errorf("%w (bench '%s' has no trusted keys; add one with: chunk bench trust %s <public-key>)", verifyErr, b.Name, b.Name)
+	}
+	return nil, verifyErr
+}
@@ -0,0 +1,182 @@
+package bench
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alexinslc/chunk/internal/config"
+)
+
+func TestParseSignaturePolicy(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    SignaturePolicy
+		wantErr bool
+	}{
+		{input: "", want: PolicyOff},
+		{input: "off", want: PolicyOff},
+		{input: "Warn", want: PolicyWarn},
+		{input: " require ", want: PolicyRequire},
+		{input: "strict", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseSignaturePolicy(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSignaturePolicy(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseSignaturePolicy(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEffectivePolicy(t *testing.T) {
+	cfg := &config.Config{SignaturePolicy: "warn"}
+
+	if got := EffectivePolicy(cfg, config.Bench{}); got != PolicyWarn {
+		t.Errorf("Expected global policy warn, got %q", got)
+	}
+	if got := EffectivePolicy(cfg, config.Bench{SignaturePolicy: "require"}); got != PolicyRequire {
+		t.Errorf("Expected bench policy require, got %q", got)
+	}
+	if got := EffectivePolicy(cfg, config.Bench{SignaturePolicy: "typo"}); got != PolicyRequire {
+		t.Errorf("Expected invalid policy to be treated as require, got %q", got)
+	}
+	if got := EffectivePolicy(&config.Config{}, config.Bench{}); got != PolicyOff {
+		t.Errorf("Expected default policy off, got %q", got)
+	}
+}
+
+func TestSignAndVerifyRecipe(t *testing.T) {
+	publicKey, privateKey, err := GenerateKey()
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	otherKey, _, err := GenerateKey()
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	signer, err := ParsePrivateKey(privateKey)
+	if err != nil {
+		t.Fatalf("ParsePrivateKey() error = %v", err)
+	}
+
+	recipePath := filepath.Join(t.TempDir(), "atm9.json")
+	if err := os.WriteFile(recipePath, []byte(`{"name": "ATM9", "download_url": "https://example.com/atm9.zip"}`), 0644); err != nil {
+		t.Fatalf("Failed to write recipe: %v", err)
+	}
+
+	if err := VerifyRecipe(recipePath, []string{publicKey}); !errors.Is(err, ErrUnsigned) {
+		t.Errorf("Expected ErrUnsigned before signing, got %v", err)
+	}
+
+	if err := SignRecipe(recipePath, signer); err != nil {
+		t.Fatalf("SignRecipe() error = %v", err)
+	}
+
+	if err := VerifyRecipe(recipePath, []string{otherKey, publicKey}); err != nil {
+		t.Errorf("Expected valid signature, got %v", err)
+	}
+	if err := VerifyRecipe(recipePath, []string{otherKey}); !errors.Is(err, ErrBadSignature) {
+		t.Errorf("Expected ErrBadSignature for untrusted key, got %v", err)
+	}
+
+	// Tamper with the download URL
+	if err := os.WriteFile(recipePath, []byte(`{"name": "ATM9", "download_url": "https://evil.example.com/atm9.zip"}`), 0644); err != nil {
+		t.Fatalf("Failed to write recipe: %v", err)
+	}
+	if err := VerifyRecipe(recipePath, []string{publicKey}); !errors.Is(err, ErrBadSignature) {
+		t.Errorf("Expected ErrBadSignature for tampered recipe, got %v", err)
+	}
+}
+
+func TestManagerCheckRecipe(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", tmpDir)
+	defer os.Setenv("HOME", originalHome)
+
+	publicKey, privateKey, err := GenerateKey()
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	signer, _ := ParsePrivateKey(privateKey)
+
+	signedPath := filepath.Join(tmpDir, "signed.json")
+	unsignedPath := filepath.Join(tmpDir, "unsigned.json")
+	for _, path := range []string{signedPath, unsignedPath} {
+		if err := os.WriteFile(path, []byte(`{"name": "Pack"}`), 0644); err != nil {
+			t.Fatalf("Failed to write recipe: %v", err)
+		}
+	}
+	if err := SignRecipe(signedPath, signer); err != nil {
+		t.Fatalf("SignRecipe() error = %v", err)
+	}
+
+	cfg := &config.Config{
+		ConfigVersion: "1.0",
+		Benches:       []config.Bench{{Name: "test/bench", Path: tmpDir}},
+	}
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+
+	manager, err := NewManager()
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
+	}
+	if err := manager.Trust("test/bench", "not-a-key"); err == nil {
+		t.Error("Expected error trusting an invalid key")
+	}
+	if err := manager.Trust("test/bench", publicKey); err != nil {
+		t.Fatalf("Trust() error = %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		policy      SignaturePolicy
+		path        string
+		wantWarning bool
+		wantErr     bool
+	}{
+		{name: "off ignores unsigned", policy: PolicyOff, path: unsignedPath},
+		{name: "warn allows unsigned", policy: PolicyWarn, path: unsignedPath, wantWarning: true},
+		{name: "require rejects unsigned", policy: PolicyRequire, path: unsignedPath, wantErr: true},
+		{name: "require accepts signed", policy: PolicyRequire, path: signedPath},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := manager.SetPolicy("test/bench", tt.policy); err != nil {
+				t.Fatalf("SetPolicy() error = %v", err)
+			}
+			b, _ := manager.Get("test/bench")
+
+			warning, err := manager.CheckRecipe(*b, tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CheckRecipe() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if (warning != nil) != tt.wantWarning {
+				t.Errorf("CheckRecipe() warning = %v, wantWarning %v", warning, tt.wantWarning)
+			}
+		})
+	}
+
+	// Pinned keys and policy survive a reload
+	reloaded, err := NewManager()
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
+	}
+	b, _ := reloaded.Get("test/bench")
+	if len(b.TrustedKeys) != 1 || b.TrustedKeys[0] != publicKey {
+		t.Errorf("Expected trusted key to be saved, got %v", b.TrustedKeys)
+	}
+	if reloaded.Policy(*b) != PolicyRequire {
+		t.Errorf("Expected saved policy require, got %q", reloaded.Policy(*b))
+	}
+}
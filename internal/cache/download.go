@@ -0,0 +1,379 @@
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alexinslc/chunk/internal/checksum"
+)
+
+const (
+	// PartialDir is the subdirectory of the download cache holding interrupted downloads
+	PartialDir = "partial"
+	// PartSuffix is the extension of an interrupted download
+	PartSuffix = ".part"
+
+	defaultMaxRetries = 3
+)
+
+// ProgressFunc receives the bytes downloaded so far and the total size (-1 if unknown)
+type ProgressFunc func(downloaded, total int64)
+
+// partState records how a .part file was obtained so a resume can be validated
+type partState struct {
+	URL          string `json:"url"`
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+	Total        int64  `json:"total"`
+}
+
+// validator returns the If-Range value for the part, or "" if it cannot be validated
+func (s *partState) validator() string {
+	if s.ETag != "" && !strings.HasPrefix(s.ETag, "W/") {
+		return s.ETag
+	}
+	return s.LastModified
+}
+
+// Downloader fetches files over HTTP and resumes interrupted transfers from
+// .part files using Range requests validated with If-Range.
+type Downloader struct {
+	httpClient *http.Client
+	partDir    string
+	MaxRetries int
+	retryDelay time.Duration
+}
+
+// NewDownloader creates a downloader keeping .part files in partDir.
+// An empty partDir keeps them next to the destination file.
+func NewDownloader(httpClient *http.Client, partDir string) *Downloader {
+	return &Downloader{
+		httpClient: httpClient,
+		partDir:    partDir,
+		MaxRetries: defaultMaxRetries,
+		retryDelay: time.Second,
+	}
+}
+
+// NewDefaultDownloader creates a downloader using the shared download cache for .part files
+func NewDefaultDownloader(httpClient *http.Client) *Downloader {
+	partDir := ""
+	if home, err := os.UserHomeDir(); err == nil {
+		partDir = filepath.Join(home, ".chunk", DownloadsDir, PartialDir)
+	}
+	return NewDownloader(httpClient, partDir)
+}
+
+// NewDownloader creates a downloader using this cache's partial download area
+func (m *Manager) NewDownloader(httpClient *http.Client) *Downloader {
+	return NewDownloader(httpClient, m.PartialDir())
+}
+
+// PartialDir returns the directory holding interrupted downloads
+func (m *Manager) PartialDir() string {
+	return filepath.Join(m.cacheDir, PartialDir)
+}
+
+// PartPath returns the .part file used while downloading url to destPath.
+// It is keyed by both, so concurrent downloads of one URL to different
+// destinations do not share a .part file.
+func (d *Downloader) PartPath(url, destPath string) string {
+	if d.partDir == "" {
+		return destPath + PartSuffix
+	}
+	if abs, err := filepath.Abs(destPath); err == nil {
+		destPath = abs
+	}
+	sum := sha256.Sum256([]byte(url + "\x00" + destPath))
+	name := hex.EncodeToString(sum[:8]) + "-" + sanitizeFilename(filepath.Base(destPath))
+	return filepath.Join(d.partDir, name+PartSuffix)
+}
+
+// Download fetches url into destPath. An existing .part file from an earlier
+// attempt is resumed when the server confirms it is unchanged. If expected
+// has checksums, the complete file is verified before it is moved into place.
+// When ctx is cancelled the .part file is kept so a later run can resume it.
+func (d *Downloader) Download(ctx context.Context, url, destPath string, expected *checksum.Checksums, progress ProgressFunc) error {
+	partPath := d.PartPath(url, destPath)
+	if err := os.MkdirAll(filepath.Dir(partPath), 0755); err != nil {
+		return fmt.Errorf("failed to create partial download directory: %w", err)
+	}
+
+	verify := expected != nil && expected.HasAny()
+	restarted := false
+	var lastErr error
+
+	for attempt := 0; attempt <= d.MaxRetries; attempt++ {
+		if attempt > 0 && d.retryDelay > 0 {
+			select {
+			case <-time.After(d.retryDelay * time.Duration(attempt)):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		resumed, err := d.fetch(ctx, url, partPath, verify, progress)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			lastErr = err
+			var permanent *permanentError
+			if errors.As(err, &permanent) {
+				return permanent.err
+			}
+			continue
+		}
+
+		if verify {
+			if err := checksum.VerifyFile(partPath, expected); err != nil {
+				removePart(partPath)
+				// A resumed file may have been stitched from two versions; retry once from scratch
+				if resumed && !restarted {
+					restarted = true
+					lastErr = err
+					continue
+				}
+				return fmt.Errorf("checksum verification failed: %w", err)
+			}
+		}
+
+		if err := movePart(partPath, destPath); err != nil {
+			return err
+		}
+		return nil
+	}
+
+	return fmt.Errorf("download failed after %d attempts: %w", d.MaxRetries+1, lastErr)
+}
+
+// permanentError marks failures that retrying will not fix
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+// fetch performs one request, appending to the .part file when resuming.
+// It reports whether the transfer continued an earlier partial download.
+func (d *Downloader) fetch(ctx context.Context, url, partPath string, verify bool, progress ProgressFunc) (bool, error) {
+	var offset int64
+	state := loadPartState(partPath)
+	if info, err := os.Stat(partPath); err == nil && state != nil && state.URL == url {
+		offset = info.Size()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return false, &permanentError{err: err}
+	}
+
+	// Only resume when the server can confirm the file is unchanged, or when
+	// the final checksum will catch a mismatch
+	if offset > 0 {
+		if v := state.validator(); v != "" {
+			req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+			req.Header.Set("If-Range", v)
+		} else if verify {
+			req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+		} else {
+			offset = 0
+		}
+	}
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	flags := os.O_WRONLY | os.O_CREATE
+	var total int64 = -1
+
+	switch {
+	case resp.StatusCode == http.StatusPartialContent && offset > 0:
+		start, size, ok := parseContentRange(resp.Header.Get("Content-Range"))
+		if !ok || start != offset {
+			removePart(partPath)
+			return false, fmt.Errorf("server returned unexpected range %q", resp.Header.Get("Content-Range"))
+		}
+		flags |= os.O_APPEND
+		total = size
+	case resp.StatusCode == http.StatusOK:
+		// Fresh download, or the file changed and If-Range sent it in full
+		offset = 0
+		flags |= os.O_TRUNC
+		if resp.ContentLength >= 0 {
+			total = resp.ContentLength
+		}
+	case resp.StatusCode == http.StatusRequestedRangeNotSatisfiable:
+		removePart(partPath)
+		return false, fmt.Errorf("server rejected resume range, restarting download")
+	case resp.StatusCode >= 500:
+		return false, fmt.Errorf("download failed: status %d", resp.StatusCode)
+	default:
+		return false, &permanentError{err: fmt.Errorf("download failed: status %d", resp.StatusCode)}
+	}
+
+	newState := &partState{
+		URL:          url,
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		Total:        total,
+	}
+	if offset > 0 && newState.ETag == "" && newState.LastModified == "" {
+		newState.ETag, newState.LastModified = state.ETag, state.LastModified
+	}
+	if err := savePartState(partPath, newState); err != nil {
+		return false, &permanentError{err: err}
+	}
+
+	out, err := os.OpenFile(partPath, flags, 0644)
+	if err != nil {
+		return false, &permanentError{err: fmt.Errorf("failed to open partial download: %w", err)}
+	}
+	defer out.Close()
+
+	downloaded := offset
+	if progress != nil {
+		progress(downloaded, total)
+	}
+
+	buffer := make([]byte, 32*1024)
+	for {
+		n, readErr := resp.Body.Read(buffer)
+		if n > 0 {
+			if _, err := out.Write(buffer[:n]); err != nil {
+				return false, &permanentError{err: fmt.Errorf("failed to write: %w", err)}
+			}
+			downloaded += int64(n)
+			if progress != nil {
+				progress(downloaded, total)
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return false, fmt.Errorf("failed to read: %w", readErr)
+		}
+	}
+
+	if total >= 0 && downloaded != total {
+		return false, fmt.Errorf("download incomplete: got %d of %d bytes", downloaded, total)
+	}
+
+	return offset > 0, nil
+}
+
+// parseContentRange parses "bytes start-end/size"; size is -1 when unknown
+func parseContentRange(header string) (start, size int64, ok bool) {
+	spec, found := strings.CutPrefix(header, "bytes ")
+	if !found {
+		return 0, 0, false
+	}
+
+	rangePart, sizePart, found := strings.Cut(spec, "/")
+	if !found {
+		return 0, 0, false
+	}
+
+	startPart, _, found := strings.Cut(rangePart, "-")
+	if !found {
+		return 0, 0, false
+	}
+
+	start, err := strconv.ParseInt(startPart, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	size = -1
+	if sizePart != "*" {
+		if size, err = strconv.ParseInt(sizePart, 10, 64); err != nil {
+			return 0, 0, false
+		}
+	}
+
+	return start, size, true
+}
+
+func loadPartState(partPath string) *partState {
+	data, err := os.ReadFile(metadataPath(partPath))
+	if err != nil {
+		return nil
+	}
+
+	var state partState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil
+	}
+	return &state
+}
+
+func savePartState(partPath string, state *partState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal download state: %w", err)
+	}
+	if err := os.WriteFile(metadataPath(partPath), data, 0644); err != nil {
+		return fmt.Errorf("failed to write download state: %w", err)
+	}
+	return nil
+}
+
+func removePart(partPath string) {
+	os.Remove(partPath)
+	os.Remove(metadataPath(partPath))
+}
+
+// movePart moves a completed download into place, copying across filesystems
+func movePart(partPath, destPath string) error {
+	if err := os.MkdirAll(filepath.Dir(destPath), 0755); err != nil {
+		return fmt.Errorf("failed to create destination directory: %w", err)
+	}
+
+	if err := os.Rename(partPath, destPath); err != nil {
+		if err := copyFile(partPath, destPath); err != nil {
+			return fmt.Errorf("failed to move download into place: %w", err)
+		}
+		os.Remove(partPath)
+	}
+
+	os.Remove(metadataPath(partPath))
+	return nil
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(dst)
+		return err
+	}
+
+	return out.Close()
+}
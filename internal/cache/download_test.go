@@ -0,0 +1,300 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alexinslc/chunk/internal/checksum"
+)
+
+const testPayload = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+// newTestDownloader returns a downloader that does not sleep between retries
+func newTestDownloader(partDir string) *Downloader {
+	d := NewDownloader(http.DefaultClient, partDir)
+	d.retryDelay = 0
+	return d
+}
+
+// serveContent serves testPayload with the given ETag, honouring Range and If-Range
+func serveContent(etag *string, ranges *[]string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if ranges != nil {
+			*ranges = append(*ranges, r.Header.Get("Range"))
+		}
+		w.Header().Set("ETag", *etag)
+		http.ServeContent(w, r, "file.bin", time.Time{}, strings.NewReader(testPayload))
+	}
+}
+
+// writePart simulates an interrupted download of the first n bytes
+func writePart(t *testing.T, d *Downloader, url, destPath string, n int, etag string) string {
+	t.Helper()
+
+	partPath := d.PartPath(url, destPath)
+	if err := os.MkdirAll(filepath.Dir(partPath), 0755); err != nil {
+		t.Fatalf("Failed to create partial dir: %v", err)
+	}
+	if err := os.WriteFile(partPath, []byte(testPayload[:n]), 0644); err != nil {
+		t.Fatalf("Failed to write part file: %v", err)
+	}
+	if err := savePartState(partPath, &partState{URL: url, ETag: etag, Total: int64(len(testPayload))}); err != nil {
+		t.Fatalf("Failed to write part state: %v", err)
+	}
+	return partPath
+}
+
+func TestDownloaderResume(t *testing.T) {
+	tests := []struct {
+		name        string
+		partETag    string
+		serverETag  string
+		wantRange   string
+		wantContent string
+	}{
+		{name: "unchanged file resumes", partETag: `"v1"`, serverETag: `"v1"`, wantRange: "bytes=10-"},
+		{name: "changed file restarts", partETag: `"v1"`, serverETag: `"v2"`, wantRange: "bytes=10-"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ranges []string
+			etag := tt.serverETag
+			server := httptest.NewServer(serveContent(&etag, &ranges))
+			defer server.Close()
+
+			tmpDir := t.TempDir()
+			d := newTestDownloader(filepath.Join(tmpDir, PartialDir))
+			destPath := filepath.Join(tmpDir, "file.bin")
+			url := server.URL + "/file.bin"
+
+			partPath := writePart(t, d, url, destPath, 10, tt.partETag)
+
+			var lastDownloaded int64
+			err := d.Download(context.Background(), url, destPath, nil, func(downloaded, total int64) {
+				lastDownloaded = downloaded
+			})
+			if err != nil {
+				t.Fatalf("Download failed: %v", err)
+			}
+
+			data, err := os.ReadFile(destPath)
+			if err != nil {
+				t.Fatalf("Failed to read download: %v", err)
+			}
+			if string(data) != testPayload {
+				t.Errorf("Downloaded content = %q, want %q", data, testPayload)
+			}
+			if lastDownloaded != int64(len(testPayload)) {
+				t.Errorf("Expected progress to reach %d, got %d", len(testPayload), lastDownloaded)
+			}
+			if len(ranges) != 1 || ranges[0] != tt.wantRange {
+				t.Errorf("Expected one request with Range %q, got %v", tt.wantRange, ranges)
+			}
+			if _, err := os.Stat(partPath); !os.IsNotExist(err) {
+				t.Error("Expected part file to be removed after download")
+			}
+			if _, err := os.Stat(metadataPath(partPath)); !os.IsNotExist(err) {
+				t.Error("Expected part state to be removed after download")
+			}
+		})
+	}
+}
+
+func TestDownloaderResumeVerifiesChecksum(t *testing.T) {
+	etag := `"v1"`
+	var ranges []string
+	server := httptest.NewServer(serveContent(&etag, &ranges))
+	defer server.Close()
+
+	tmpDir := t.TempDir()
+	d := newTestDownloader(filepath.Join(tmpDir, PartialDir))
+	destPath := filepath.Join(tmpDir, "file.bin")
+	url := server.URL + "/file.bin"
+
+	// A corrupted part passes If-Range but fails the final checksum, so the
+	// download restarts from scratch
+	partPath := d.PartPath(url, destPath)
+	writePart(t, d, url, destPath, 10, etag)
+	if err := os.WriteFile(partPath, []byte("XXXXXXXXXX"), 0644); err != nil {
+		t.Fatalf("Failed to corrupt part file: %v", err)
+	}
+
+	sourcePath := filepath.Join(tmpDir, "source.bin")
+	if err := os.WriteFile(sourcePath, []byte(testPayload), 0644); err != nil {
+		t.Fatalf("Failed to write source: %v", err)
+	}
+	expected, err := checksum.CalculateFile(sourcePath)
+	if err != nil {
+		t.Fatalf("Failed to calculate checksum: %v", err)
+	}
+
+	if err := d.Download(context.Background(), url, destPath, expected, nil); err != nil {
+		t.Fatalf("Download failed: %v", err)
+	}
+
+	data, _ := os.ReadFile(destPath)
+	if string(data) != testPayload {
+		t.Errorf("Downloaded content = %q, want %q", data, testPayload)
+	}
+	if len(ranges) != 2 || ranges[0] != "bytes=10-" || ranges[1] != "" {
+		t.Errorf("Expected a resume followed by a full download, got %v", ranges)
+	}
+}
+
+func TestDownloaderChecksumMismatch(t *testing.T) {
+	etag := `"v1"`
+	server := httptest.NewServer(serveContent(&etag, nil))
+	defer server.Close()
+
+	tmpDir := t.TempDir()
+	d := newTestDownloader(filepath.Join(tmpDir, PartialDir))
+	destPath := filepath.Join(tmpDir, "file.bin")
+	url := server.URL + "/file.bin"
+
+	err := d.Download(context.Background(), url, destPath, &checksum.Checksums{SHA256: strings.Repeat("0", 64)}, nil)
+	if err == nil {
+		t.Fatal("Expected checksum mismatch error")
+	}
+	if _, err := os.Stat(destPath); !os.IsNotExist(err) {
+		t.Error("Expected no file at destination after checksum mismatch")
+	}
+	if _, err := os.Stat(d.PartPath(url, destPath)); !os.IsNotExist(err) {
+		t.Error("Expected part file to be discarded after checksum mismatch")
+	}
+}
+
+func TestDownloaderNotFound(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	tmpDir := t.TempDir()
+	d := newTestDownloader(filepath.Join(tmpDir, PartialDir))
+	if err := d.Download(context.Background(), server.URL+"/missing", filepath.Join(tmpDir, "missing.bin"), nil, nil); err == nil {
+		t.Fatal("Expected error for missing file")
+	}
+	if requests != 1 {
+		t.Errorf("Expected a permanent error not to be retried, got %d requests", requests)
+	}
+}
+
+func TestDownloaderCancelKeepsPart(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Length", "36")
+		w.Write([]byte(testPayload[:10]))
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	tmpDir := t.TempDir()
+	d := newTestDownloader(filepath.Join(tmpDir, PartialDir))
+	url := server.URL + "/file.bin"
+	destPath := filepath.Join(tmpDir, "file.bin")
+
+	err := d.Download(ctx, url, destPath, nil, func(downloaded, total int64) {
+		if downloaded > 0 {
+			cancel()
+		}
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("Expected a cancelled download not to be retried, got %d requests", requests)
+	}
+	if _, err := os.Stat(destPath); !os.IsNotExist(err) {
+		t.Error("Expected no file at destination after cancel")
+	}
+	if _, err := os.Stat(d.PartPath(url, destPath)); err != nil {
+		t.Errorf("Expected part file to be kept for resume: %v", err)
+	}
+}
+
+func TestPartPathPerDestination(t *testing.T) {
+	tmpDir := t.TempDir()
+	d := newTestDownloader(filepath.Join(tmpDir, PartialDir))
+	url := "https://example.com/mods/jei.jar"
+
+	first := d.PartPath(url, filepath.Join(tmpDir, "survival", "mods", "jei.jar"))
+	second := d.PartPath(url, filepath.Join(tmpDir, "creative", "mods", "jei.jar"))
+	if first == second {
+		t.Errorf("Expected different part files for different destinations, got %s for both", first)
+	}
+	if again := d.PartPath(url, filepath.Join(tmpDir, "survival", "mods", "jei.jar")); again != first {
+		t.Errorf("Expected the same part file for the same download, got %s and %s", first, again)
+	}
+}
+
+func TestParseContentRange(t *testing.T) {
+	tests := []struct {
+		header    string
+		wantStart int64
+		wantSize  int64
+		wantOK    bool
+	}{
+		{header: "bytes 10-35/36", wantStart: 10, wantSize: 36, wantOK: true},
+		{header: "bytes 0-99/*", wantStart: 0, wantSize: -1, wantOK: true},
+		{header: "bytes */36", wantOK: false},
+		{header: "items 0-1/2", wantOK: false},
+		{header: "", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			start, size, ok := parseContentRange(tt.header)
+			if ok != tt.wantOK {
+				t.Fatalf("parseContentRange(%q) ok = %v, want %v", tt.header, ok, tt.wantOK)
+			}
+			if ok && (start != tt.wantStart || size != tt.wantSize) {
+				t.Errorf("parseContentRange(%q) = %d, %d; want %d, %d", tt.header, start, size, tt.wantStart, tt.wantSize)
+			}
+		})
+	}
+}
+
+func TestListCachedFilesIncludesPartials(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+
+	manager, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+
+	d := manager.NewDownloader(http.DefaultClient)
+	partPath := writePart(t, d, "https://example.com/pack.mrpack", manager.GetCachePath("atm9", "0.3.1", "modpack.mrpack"), 10, `"v1"`)
+
+	stats, err := manager.AnalyzeCache()
+	if err != nil {
+		t.Fatalf("AnalyzeCache failed: %v", err)
+	}
+	if stats.PartialFiles != 1 || len(stats.FilesToRemove) != 1 || stats.FilesToRemove[0].Path != partPath {
+		t.Fatalf("Expected the part file to be reported as partial, got %+v", stats)
+	}
+
+	if err := manager.CleanupFiles(stats.FilesToRemove); err != nil {
+		t.Fatalf("CleanupFiles failed: %v", err)
+	}
+	for _, path := range []string{partPath, metadataPath(partPath)} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("Expected %s to be removed", path)
+		}
+	}
+}
@@ -35,17 +35,19 @@ type CachedFile struct {
 	Path     string
 	Metadata *DownloadMetadata
 	Size     int64
-	Reason   string // Reason for removal: "partial", "outdated", "uninstalled"
+	Blob     string // SHA-512 digest if the file is a content-addressable blob
+	Reason   string // Reason for removal: "partial", "outdated", "uninstalled", "unreferenced"
 }
 
 // CleanupStats contains statistics about a cleanup operation
 type CleanupStats struct {
-	TotalFiles       int
-	OutdatedFiles    int
-	UninstalledFiles int
-	PartialFiles     int
-	TotalSize        int64
-	FilesToRemove    []*CachedFile
+	TotalFiles        int
+	OutdatedFiles     int
+	UninstalledFiles  int
+	PartialFiles      int
+	UnreferencedBlobs int
+	TotalSize         int64
+	FilesToRemove     []*CachedFile
 }
 
 // Manager handles download cache operations
@@ -86,8 +88,13 @@ func (m *Manager) GetCachePath(slug, version, filename string) string {
 
 // GetMetadataPath returns the path to the metadata file associated with a cached download.
 func (m *Manager) GetMetadataPath(cachePath string) string {
-	dir := filepath.Dir(cachePath)
-	base := filepath.Base(cachePath)
+	return metadataPath(cachePath)
+}
+
+// metadataPath returns the hidden sidecar file stored next to a cached file
+func metadataPath(path string) string {
+	dir := filepath.Dir(path)
+	base := filepath.Base(path)
 	return filepath.Join(dir, "."+base+".metadata.json")
 }
 
@@ -157,11 +164,39 @@ func (m *Manager) ListCachedFiles() ([]*CachedFile, error) {
 		})
 	}
 
+	// Interrupted downloads have no metadata and are reported as partial files
+	partialEntries, err := os.ReadDir(m.PartialDir())
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read partial download directory: %w", err)
+	}
+	for _, entry := range partialEntries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), PartSuffix) {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		cachedFiles = append(cachedFiles, &CachedFile{
+			Path: filepath.Join(m.PartialDir(), entry.Name()),
+			Size: info.Size(),
+		})
+	}
+
+	blobs, err := m.listBlobs()
+	if err != nil {
+		return nil, err
+	}
+	cachedFiles = append(cachedFiles, blobs...)
+
 	return cachedFiles, nil
 }
 
 // AnalyzeCache analyzes the cache and identifies files that can be removed based on
-// outdated versions, uninstalled modpacks, and partial downloads.
+// outdated versions, uninstalled modpacks, partial downloads, and blobs that no
+// tracked installation references.
 func (m *Manager) AnalyzeCache() (*CleanupStats, error) {
 	cachedFiles, err := m.ListCachedFiles()
 	if err != nil {
@@ -182,6 +217,16 @@ func (m *Manager) AnalyzeCache() (*CleanupStats, error) {
 		installedMap[inst.Slug][inst.Version] = true
 	}
 
+	var referenced map[string]bool
+	for _, file := range cachedFiles {
+		if file.Blob != "" {
+			if referenced, err = m.referencedBlobs(); err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
+
 	stats := &CleanupStats{
 		TotalFiles:    len(cachedFiles),
 		FilesToRemove: []*CachedFile{},
@@ -191,8 +236,14 @@ func (m *Manager) AnalyzeCache() (*CleanupStats, error) {
 		shouldRemove := false
 		var reason string
 
-		// Check if it's a partial download (no metadata)
-		if file.Metadata == nil {
+		if file.Blob != "" {
+			if !referenced[file.Blob] {
+				stats.UnreferencedBlobs++
+				shouldRemove = true
+				reason = "unreferenced"
+			}
+		} else if file.Metadata == nil {
+			// Partial download (no metadata)
 			stats.PartialFiles++
 			shouldRemove = true
 			reason = "partial"
@@ -259,7 +310,7 @@ func (m *Manager) CleanupFiles(files []*CachedFile) error {
 			return fmt.Errorf("failed to remove %s: %w", file.Path, err)
 		}
 	}
-	return nil
+	return m.pruneBlobIndex()
 }
 
 // CleanupAll removes all cached files from the download cache.
@@ -0,0 +1,219 @@
+package cache
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/alexinslc/chunk/internal/checksum"
+	"github.com/alexinslc/chunk/internal/lockfile"
+)
+
+// BlobsDir is the subdirectory of the download cache holding the
+// content-addressable store. Blobs live under blobs/sha512/<ab>/<sha512>;
+// blobs/sha256/<ab>/<sha256> holds index files naming the SHA-512 blob.
+const BlobsDir = "blobs"
+
+// blobPath returns the location of a blob or index entry for a hex digest
+func (m *Manager) blobPath(algorithm checksum.Algorithm, digest string) string {
+	digest = strings.ToLower(digest)
+	prefix := digest
+	if len(prefix) > 2 {
+		prefix = prefix[:2]
+	}
+	return filepath.Join(m.cacheDir, BlobsDir, string(algorithm), prefix, digest)
+}
+
+// FindBlob returns the stored blob matching the expected checksums, or "" if
+// the store has none. A blob that no longer matches its digest is discarded.
+func (m *Manager) FindBlob(expected *checksum.Checksums) string {
+	// Blobs are indexed by SHA-512 and SHA-256 only
+	if expected == nil || (expected.SHA512 == "" && expected.SHA256 == "") {
+		return ""
+	}
+
+	digest := expected.SHA512
+	if digest == "" {
+		index, err := os.ReadFile(m.blobPath(checksum.AlgorithmSHA256, expected.SHA256))
+		if err != nil {
+			return ""
+		}
+		digest = strings.TrimSpace(string(index))
+	}
+
+	blobPath := m.blobPath(checksum.AlgorithmSHA512, digest)
+	if _, err := os.Stat(blobPath); err != nil {
+		return ""
+	}
+
+	// Blobs are hardlinked into servers, so an in-place edit there changes the blob too
+	if err := checksum.VerifyFile(blobPath, expected); err != nil {
+		os.Remove(blobPath)
+		return ""
+	}
+
+	return blobPath
+}
+
+// StoreBlob adds a file to the store, hardlinking it when the filesystem
+// allows and copying otherwise. Storing content that is already present is a no-op.
+func (m *Manager) StoreBlob(filePath string) (string, error) {
+	sums, err := checksum.CalculateFile(filePath)
+	if err != nil {
+		return "", err
+	}
+
+	blobPath := m.blobPath(checksum.AlgorithmSHA512, sums.SHA512)
+	if _, err := os.Stat(blobPath); os.IsNotExist(err) {
+		if err := linkOrCopy(filePath, blobPath); err != nil {
+			return "", fmt.Errorf("failed to store blob: %w", err)
+		}
+	}
+
+	indexPath := m.blobPath(checksum.AlgorithmSHA256, sums.SHA256)
+	if err := os.MkdirAll(filepath.Dir(indexPath), 0755); err != nil {
+		return "", fmt.Errorf("failed to create blob index directory: %w", err)
+	}
+	if err := os.WriteFile(indexPath, []byte(sums.SHA512), 0644); err != nil {
+		return "", fmt.Errorf("failed to write blob index: %w", err)
+	}
+
+	return blobPath, nil
+}
+
+// LinkBlob places a stored blob at destPath, replacing any existing file
+func (m *Manager) LinkBlob(blobPath, destPath string) error {
+	if err := os.MkdirAll(filepath.Dir(destPath), 0755); err != nil {
+		return fmt.Errorf("failed to create destination directory: %w", err)
+	}
+
+	// Link under a temporary name first so destPath is replaced atomically
+	tmpPath := destPath + ".chunk-tmp"
+	os.Remove(tmpPath)
+	if err := linkOrCopy(blobPath, tmpPath); err != nil {
+		return fmt.Errorf("failed to link cached file: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, destPath); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to link cached file: %w", err)
+	}
+
+	return nil
+}
+
+// linkOrCopy hardlinks src to dst, falling back to a copy across filesystems
+func linkOrCopy(src, dst string) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	if err := os.Link(src, dst); err == nil {
+		return nil
+	}
+	return copyFile(src, dst)
+}
+
+// listBlobs returns every blob in the store
+func (m *Manager) listBlobs() ([]*CachedFile, error) {
+	root := filepath.Join(m.cacheDir, BlobsDir, string(checksum.AlgorithmSHA512))
+
+	var blobs []*CachedFile
+	err := filepath.WalkDir(root, func(path string, entry os.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return nil
+		}
+
+		blobs = append(blobs, &CachedFile{
+			Path: path,
+			Size: info.Size(),
+			Blob: entry.Name(),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read blob store: %w", err)
+	}
+
+	return blobs, nil
+}
+
+// referencedBlobs returns the SHA-512 digests of files used by tracked
+// installations, read from their chunk.lock or, without one, from their mods directory
+func (m *Manager) referencedBlobs() (map[string]bool, error) {
+	installations, err := m.tracker.ListInstallations()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list installations: %w", err)
+	}
+
+	referenced := make(map[string]bool)
+	for _, inst := range installations {
+		if lock, err := lockfile.Load(lockfile.Path(inst.Path)); err == nil {
+			for _, artifact := range lock.Artifacts {
+				if artifact.SHA512 != "" {
+					referenced[strings.ToLower(artifact.SHA512)] = true
+				}
+			}
+			continue
+		}
+
+		entries, err := os.ReadDir(filepath.Join(inst.Path, "mods"))
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if entry.IsDir() {
+				continue
+			}
+			sums, err := checksum.CalculateFile(filepath.Join(inst.Path, "mods", entry.Name()))
+			if err != nil {
+				continue
+			}
+			referenced[sums.SHA512] = true
+		}
+	}
+
+	return referenced, nil
+}
+
+// pruneBlobIndex removes SHA-256 index entries whose blob no longer exists
+func (m *Manager) pruneBlobIndex() error {
+	root := filepath.Join(m.cacheDir, BlobsDir, string(checksum.AlgorithmSHA256))
+
+	err := filepath.WalkDir(root, func(path string, entry os.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+
+		index, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		if _, err := os.Stat(m.blobPath(checksum.AlgorithmSHA512, strings.TrimSpace(string(index)))); os.IsNotExist(err) {
+			os.Remove(path)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to prune blob index: %w", err)
+	}
+
+	return nil
+}
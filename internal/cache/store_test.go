@@ -0,0 +1,177 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alexinslc/chunk/internal/checksum"
+	"github.com/alexinslc/chunk/internal/lockfile"
+	"github.com/alexinslc/chunk/internal/tracking"
+)
+
+// writeMod writes a mod jar and returns its checksums
+func writeMod(t *testing.T, path, content string) *checksum.Checksums {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("Failed to create mods dir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write mod: %v", err)
+	}
+	sums, err := checksum.CalculateFile(path)
+	if err != nil {
+		t.Fatalf("Failed to calculate checksum: %v", err)
+	}
+	return sums
+}
+
+func TestStoreAndFindBlob(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+
+	manager, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+
+	modPath := filepath.Join(tmpDir, "server-a", "mods", "jei.jar")
+	sums := writeMod(t, modPath, "jei content")
+
+	if blob := manager.FindBlob(sums); blob != "" {
+		t.Fatalf("Expected empty store, found %s", blob)
+	}
+
+	blobPath, err := manager.StoreBlob(modPath)
+	if err != nil {
+		t.Fatalf("StoreBlob failed: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		expected *checksum.Checksums
+		want     string
+	}{
+		{name: "by sha512", expected: &checksum.Checksums{SHA512: sums.SHA512}, want: blobPath},
+		{name: "by sha256", expected: &checksum.Checksums{SHA256: sums.SHA256}, want: blobPath},
+		{name: "unknown content", expected: &checksum.Checksums{SHA256: "ab" + sums.SHA256[2:]}, want: ""},
+		{name: "no checksums", expected: &checksum.Checksums{}, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := manager.FindBlob(tt.expected); got != tt.want {
+				t.Errorf("FindBlob() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	destPath := filepath.Join(tmpDir, "server-b", "mods", "jei.jar")
+	if err := manager.LinkBlob(blobPath, destPath); err != nil {
+		t.Fatalf("LinkBlob failed: %v", err)
+	}
+	if err := checksum.VerifyFile(destPath, sums); err != nil {
+		t.Errorf("Linked file does not match: %v", err)
+	}
+}
+
+func TestFindBlobDiscardsCorruptBlob(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+
+	manager, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+
+	modPath := filepath.Join(tmpDir, "server", "mods", "jei.jar")
+	sums := writeMod(t, modPath, "jei content")
+	if _, err := manager.StoreBlob(modPath); err != nil {
+		t.Fatalf("StoreBlob failed: %v", err)
+	}
+
+	// Editing a hardlinked mod in place changes the blob as well
+	if err := os.WriteFile(modPath, []byte("edited"), 0644); err != nil {
+		t.Fatalf("Failed to edit mod: %v", err)
+	}
+
+	if blob := manager.FindBlob(sums); blob != "" {
+		t.Errorf("Expected corrupt blob to be discarded, got %s", blob)
+	}
+}
+
+func TestAnalyzeCacheUnreferencedBlobs(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+
+	manager, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+
+	// server-a has a lock file, server-b only a mods directory
+	serverA := filepath.Join(tmpDir, "server-a")
+	lockedPath := filepath.Join(serverA, "mods", "locked.jar")
+	writeMod(t, lockedPath, "locked")
+	lock := lockfile.New("atm9", "ATM9", "1.20.1", "forge", "47.2.0")
+	artifact, err := lockfile.NewArtifact(lockfile.KindMod, "locked.jar", "https://example.com/locked.jar", lockedPath, "mods/locked.jar")
+	if err != nil {
+		t.Fatalf("NewArtifact failed: %v", err)
+	}
+	lock.Add(artifact)
+	if err := lock.Save(lockfile.Path(serverA)); err != nil {
+		t.Fatalf("Failed to save lock: %v", err)
+	}
+
+	serverB := filepath.Join(tmpDir, "server-b")
+	scannedPath := filepath.Join(serverB, "mods", "scanned.jar")
+	writeMod(t, scannedPath, "scanned")
+
+	orphanPath := filepath.Join(tmpDir, "old", "mods", "orphan.jar")
+	orphanSums := writeMod(t, orphanPath, "orphan")
+
+	for _, path := range []string{lockedPath, scannedPath, orphanPath} {
+		if _, err := manager.StoreBlob(path); err != nil {
+			t.Fatalf("StoreBlob failed: %v", err)
+		}
+	}
+
+	tracker, _ := tracking.NewTracker()
+	for _, path := range []string{serverA, serverB} {
+		tracker.AddInstallation(&tracking.Installation{
+			Slug:        filepath.Base(path),
+			Version:     "1.0.0",
+			Bench:       "test",
+			Path:        path,
+			InstalledAt: time.Now(),
+		})
+	}
+
+	stats, err := manager.AnalyzeCache()
+	if err != nil {
+		t.Fatalf("AnalyzeCache failed: %v", err)
+	}
+
+	if stats.UnreferencedBlobs != 1 || len(stats.FilesToRemove) != 1 {
+		t.Fatalf("Expected only the orphan blob to be removable, got %+v", stats)
+	}
+	if stats.FilesToRemove[0].Blob != orphanSums.SHA512 || stats.FilesToRemove[0].Reason != "unreferenced" {
+		t.Errorf("Unexpected removable file: %+v", stats.FilesToRemove[0])
+	}
+
+	if err := manager.CleanupFiles(stats.FilesToRemove); err != nil {
+		t.Fatalf("CleanupFiles failed: %v", err)
+	}
+
+	if blob := manager.FindBlob(&checksum.Checksums{SHA256: orphanSums.SHA256}); blob != "" {
+		t.Errorf("Expected orphan blob to be removed, found %s", blob)
+	}
+	if _, err := os.Stat(manager.blobPath(checksum.AlgorithmSHA256, orphanSums.SHA256)); !os.IsNotExist(err) {
+		t.Error("Expected orphan blob index entry to be pruned")
+	}
+	if _, err := os.Stat(orphanPath); err != nil {
+		t.Errorf("Expected the hardlinked server file to survive cleanup: %v", err)
+	}
+}
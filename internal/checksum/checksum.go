@@ -1,7 +1,9 @@
-// Package checksum provides file integrity verification using SHA-256 and SHA-512 checksums.
+// Package checksum provides file integrity verification using SHA-256 and SHA-512 checksums,
+// and the SHA-1 hashes of sources that publish nothing stronger.
 package checksum
 
 import (
+	"crypto/sha1"
 	"crypto/sha256"
 	"crypto/sha512"
 	"encoding/hex"
@@ -21,6 +23,9 @@ const (
 	AlgorithmSHA256 Algorithm = "sha256"
 	// AlgorithmSHA512 represents the SHA-512 hash algorithm.
 	AlgorithmSHA512 Algorithm = "sha512"
+	// AlgorithmSHA1 represents the SHA-1 hash algorithm, only used when a
+	// source publishes no other hash.
+	AlgorithmSHA1 Algorithm = "sha1"
 )
 
 var (
@@ -49,15 +54,17 @@ func (e *MismatchError) Unwrap() error {
 	return ErrChecksumMismatch
 }
 
-// Checksums holds SHA-256 and SHA-512 checksums for a file.
+// Checksums holds SHA-256 and SHA-512 checksums for a file. SHA1 is only
+// verified when neither of the others is set.
 type Checksums struct {
 	SHA256 string
 	SHA512 string
+	SHA1   string
 }
 
 // HasAny returns true if at least one checksum is set.
 func (c *Checksums) HasAny() bool {
-	return c.SHA256 != "" || c.SHA512 != ""
+	return c.SHA256 != "" || c.SHA512 != "" || c.SHA1 != ""
 }
 
 // CalculateFile calculates checksums for a file at the given path.
@@ -71,13 +78,14 @@ func CalculateFile(filePath string) (*Checksums, error) {
 	return Calculate(file)
 }
 
-// Calculate calculates both SHA-256 and SHA-512 checksums for the given reader.
+// Calculate calculates the SHA-256, SHA-512 and SHA-1 checksums for the given reader.
 func Calculate(r io.Reader) (*Checksums, error) {
 	sha256Hash := sha256.New()
 	sha512Hash := sha512.New()
+	sha1Hash := sha1.New()
 
-	// Use a multi-writer to calculate both hashes in a single pass
-	multiWriter := io.MultiWriter(sha256Hash, sha512Hash)
+	// Use a multi-writer to calculate every hash in a single pass
+	multiWriter := io.MultiWriter(sha256Hash, sha512Hash, sha1Hash)
 
 	if _, err := io.Copy(multiWriter, r); err != nil {
 		return nil, fmt.Errorf("failed to calculate checksums: %w", err)
@@ -86,11 +94,12 @@ func Calculate(r io.Reader) (*Checksums, error) {
 	return &Checksums{
 		SHA256: hex.EncodeToString(sha256Hash.Sum(nil)),
 		SHA512: hex.EncodeToString(sha512Hash.Sum(nil)),
+		SHA1:   hex.EncodeToString(sha1Hash.Sum(nil)),
 	}, nil
 }
 
 // VerifyFile verifies a file's checksum against expected values.
-// It will verify SHA-512 first if available, then SHA-256.
+// It will verify SHA-512 first if available, then SHA-256, then SHA-1.
 // Returns nil if verification passes, or an error if it fails.
 func VerifyFile(filePath string, expected *Checksums) error {
 	if expected == nil || !expected.HasAny() {
@@ -134,6 +143,15 @@ func VerifyFile(filePath string, expected *Checksums) error {
 		return nil
 	}
 
+	// SHA-1 is only left when the source published nothing stronger
+	if !strings.EqualFold(calculated.SHA1, expected.SHA1) {
+		return &MismatchError{
+			FilePath:  filePath,
+			Algorithm: AlgorithmSHA1,
+			Expected:  expected.SHA1,
+			Actual:    calculated.SHA1,
+		}
+	}
 	return nil
 }
 
@@ -148,14 +166,19 @@ func VerifyReader(r io.Reader, expected *Checksums) (io.Reader, *VerificationRes
 	}
 
 	var h hash.Hash
-	if expected.SHA512 != "" {
+	switch {
+	case expected.SHA512 != "":
 		h = sha512.New()
 		result.Algorithm = AlgorithmSHA512
 		result.Expected = expected.SHA512
-	} else {
+	case expected.SHA256 != "":
 		h = sha256.New()
 		result.Algorithm = AlgorithmSHA256
 		result.Expected = expected.SHA256
+	default:
+		h = sha1.New()
+		result.Algorithm = AlgorithmSHA1
+		result.Expected = expected.SHA1
 	}
 
 	result.hash = h
@@ -165,6 +165,19 @@ func TestVerifyFile(t *testing.T) {
 			errType:     ErrChecksumMismatch,
 			description: "should fail with wrong SHA512",
 		},
+		{
+			name:        "valid SHA1",
+			checksums:   &Checksums{SHA1: "2aae6c35c94fcfb415dbe95f408b9ce91ee846ed"},
+			wantErr:     false,
+			description: "should pass with correct SHA1",
+		},
+		{
+			name:        "invalid SHA1",
+			checksums:   &Checksums{SHA1: "0000000000000000000000000000000000000000"},
+			wantErr:     true,
+			errType:     ErrChecksumMismatch,
+			description: "should fail with wrong SHA1",
+		},
 		{
 			name:        "nil checksums",
 			checksums:   nil,
@@ -6,15 +6,35 @@ import (
 	"os"
 )
 
+// ChunkManifestFile is the installed manifest written to a server directory
+const ChunkManifestFile = ".chunk.json"
+
 type ChunkManifest struct {
-	Name             string   `json:"name"`
-	Description      string   `json:"description,omitempty"`
-	MCVersion        string   `json:"mc_version"`
-	Loader           string   `json:"loader"`
-	LoaderVersion    string   `json:"loader_version,omitempty"`
-	RecommendedRAMGB int      `json:"recommended_ram_gb"`
-	Dependencies     []string `json:"dependencies,omitempty"`
-	JavaVersion      int      `json:"java_version,omitempty"`
+	Name             string        `json:"name"`
+	Description      string        `json:"description,omitempty"`
+	MCVersion        string        `json:"mc_version"`
+	Loader           string        `json:"loader"`
+	LoaderVersion    string        `json:"loader_version,omitempty"`
+	RecommendedRAMGB int           `json:"recommended_ram_gb"`
+	Dependencies     []string      `json:"dependencies,omitempty"`
+	JavaVersion      int           `json:"java_version,omitempty"`
+	Mods             []ManifestMod `json:"mods,omitempty"`
+	// JVMArgs and Env are the recipe's extra launch settings
+	JVMArgs []string          `json:"jvm_args,omitempty"`
+	Env     map[string]string `json:"env,omitempty"`
+	// MinJava and MaxJava bound the Java major version the server runs on
+	MinJava int `json:"min_java,omitempty"`
+	MaxJava int `json:"max_java,omitempty"`
+}
+
+// ManifestMod is a server mod listed in .chunk.json
+type ManifestMod struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Version  string `json:"version"`
+	URL      string `json:"url,omitempty"`
+	Side     string `json:"side,omitempty"`
+	FileName string `json:"filename,omitempty"`
 }
 
 func LoadChunkManifest(path string) (*ChunkManifest, error) {
@@ -52,10 +72,11 @@ func validateChunkManifest(manifest *ChunkManifest) error {
 		"forge":    true,
 		"fabric":   true,
 		"neoforge": true,
+		"quilt":    true,
 	}
 
 	if !validLoaders[manifest.Loader] {
-		return fmt.Errorf("invalid loader: %s (must be forge, fabric, or neoforge)", manifest.Loader)
+		return fmt.Errorf("invalid loader: %s (must be forge, fabric, neoforge, or quilt)", manifest.Loader)
 	}
 
 	if manifest.RecommendedRAMGB < 0 {
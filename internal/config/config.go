@@ -13,14 +13,32 @@ type Bench struct {
 	Path        string     `json:"path"`
 	Added       time.Time  `json:"added"`
 	LastUpdated *time.Time `json:"last_updated,omitempty"`
+	// TrustedKeys are the base64 ed25519 public keys whose recipe signatures
+	// this bench accepts
+	TrustedKeys []string `json:"trusted_keys,omitempty"`
+	// SignaturePolicy overrides Config.SignaturePolicy for this bench
+	SignaturePolicy string `json:"signature_policy,omitempty"`
+}
+
+// MirrorRule sends downloads whose URL starts with Prefix to Mirror instead,
+// keeping the rest of the URL
+type MirrorRule struct {
+	Prefix string `json:"prefix"`
+	Mirror string `json:"mirror"`
+	// Fallback retries the original URL when the mirror fails
+	Fallback bool `json:"fallback,omitempty"`
 }
 
 type Config struct {
-	TelemetryEnabled *bool   `json:"telemetry_enabled,omitempty"`
-	TelemetryAsked   bool    `json:"telemetry_asked"`
-	ConfigVersion    string  `json:"config_version"`
-	ChunkHubAPIKey   string  `json:"chunkhub_api_key,omitempty"`
-	Benches          []Bench `json:"benches,omitempty"`
+	TelemetryEnabled *bool        `json:"telemetry_enabled,omitempty"`
+	TelemetryAsked   bool         `json:"telemetry_asked"`
+	ConfigVersion    string       `json:"config_version"`
+	ChunkHubAPIKey   string       `json:"chunkhub_api_key,omitempty"`
+	CurseForgeAPIKey string       `json:"curseforge_api_key,omitempty"`
+	Benches          []Bench      `json:"benches,omitempty"`
+	Mirrors          []MirrorRule `json:"mirrors,omitempty"`
+	// SignaturePolicy is off, warn or require; empty means off
+	SignaturePolicy string `json:"signature_policy,omitempty"`
 }
 
 func GetConfigPath() (string, error) {
@@ -96,3 +114,7 @@ func (c *Config) GetChunkHubAPIKey() string {
 func (c *Config) SetChunkHubAPIKey(apiKey string) {
 	c.ChunkHubAPIKey = apiKey
 }
+
+func (c *Config) GetCurseForgeAPIKey() string {
+	return c.CurseForgeAPIKey
+}
@@ -1,11 +1,20 @@
 package converter
 
 import (
+	"bufio"
+	"bytes"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/alexinslc/chunk/internal/rcon"
 )
 
+// ServerPropertiesFile is the server configuration in a server directory
+const ServerPropertiesFile = "server.properties"
+
 type ConfigGenerator struct{}
 
 func NewConfigGenerator() *ConfigGenerator {
@@ -25,7 +34,12 @@ func (c *ConfigGenerator) Generate(opts *ConversionOptions) error {
 }
 
 func (c *ConfigGenerator) generateServerProperties(opts *ConversionOptions) error {
-	configPath := filepath.Join(opts.DestDir, "server.properties")
+	configPath := filepath.Join(opts.DestDir, ServerPropertiesFile)
+
+	rconPort := opts.RCONPort
+	if rconPort == 0 {
+		rconPort = rcon.DefaultPort
+	}
 
 	config := fmt.Sprintf(`#Minecraft server properties
 #Generated by Chunk
@@ -40,7 +54,7 @@ broadcast-rcon-to-ops=true
 enable-command-block=false
 enable-jmx-monitoring=false
 enable-query=false
-enable-rcon=false
+enable-rcon=%t
 enable-status=true
 enforce-whitelist=false
 entity-broadcast-range-percentage=100
@@ -64,8 +78,8 @@ prevent-proxy-connections=false
 pvp=true
 query.port=25565
 rate-limit=0
-rcon.password=
-rcon.port=25575
+rcon.password=%s
+rcon.port=%d
 require-resource-pack=false
 resource-pack=
 resource-pack-prompt=
@@ -82,9 +96,14 @@ text-filtering-config=
 use-native-transport=true
 view-distance=10
 white-list=false
-`, opts.ModpackName, opts.ModpackName)
+`, opts.ModpackName, opts.ModpackName, opts.RCONPassword != "", opts.RCONPassword, rconPort)
 
-	return os.WriteFile(configPath, []byte(config), 0644)
+	// Only the server's owner may read the RCON password
+	mode := os.FileMode(0644)
+	if opts.RCONPassword != "" {
+		mode = 0600
+	}
+	return os.WriteFile(configPath, []byte(config), mode)
 }
 
 func (c *ConfigGenerator) generateEULA(opts *ConversionOptions) error {
@@ -97,3 +116,109 @@ eula=false
 
 	return os.WriteFile(eulaPath, []byte(eula), 0644)
 }
+
+// EnableRCON turns on RCON with the given port and password in the
+// server.properties of a server directory, keeping every other setting.
+// Only the server's owner may read the file afterwards.
+func EnableRCON(serverDir string, port int, password string) error {
+	path := filepath.Join(serverDir, ServerPropertiesFile)
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %w", ServerPropertiesFile, err)
+	}
+
+	settings := []struct{ key, value string }{
+		{"enable-rcon", "true"},
+		{"rcon.port", strconv.Itoa(port)},
+		{"rcon.password", password},
+	}
+	var lines []string
+	if len(data) > 0 {
+		lines = strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	}
+	for _, setting := range settings {
+		found := false
+		for i, line := range lines {
+			trimmed := strings.TrimSpace(line)
+			if trimmed == "" || trimmed[0] == '#' || trimmed[0] == '!' {
+				continue
+			}
+			key, _, ok := strings.Cut(trimmed, "=")
+			if !ok {
+				key, _, _ = strings.Cut(trimmed, ":")
+			}
+			if unescapeProperty(strings.TrimSpace(key)) == setting.key {
+				lines[i] = setting.key + "=" + setting.value
+				found = true
+			}
+		}
+		if !found {
+			lines = append(lines, setting.key+"="+setting.value)
+		}
+	}
+
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", ServerPropertiesFile, err)
+	}
+	return os.Chmod(path, 0600)
+}
+
+// ReadServerProperties reads the server.properties of a server directory
+func ReadServerProperties(serverDir string) (map[string]string, error) {
+	data, err := os.ReadFile(filepath.Join(serverDir, ServerPropertiesFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", ServerPropertiesFile, err)
+	}
+	return ParseServerProperties(data), nil
+}
+
+// ParseServerProperties parses the Java properties format of
+// server.properties. Comments are skipped and escapes such as
+// minecraft\:normal are resolved; line continuations are not supported.
+func ParseServerProperties(data []byte) map[string]string {
+	properties := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' || line[0] == '!' {
+			continue
+		}
+		key, value, found := strings.Cut(line, "=")
+		if !found {
+			key, value, _ = strings.Cut(line, ":")
+		}
+		properties[unescapeProperty(strings.TrimSpace(key))] = unescapeProperty(strings.TrimSpace(value))
+	}
+	return properties
+}
+
+func unescapeProperty(s string) string {
+	if !strings.Contains(s, "\\") {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+			switch s[i] {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			case 'u':
+				// Non-ASCII characters, such as the § of formatting codes
+				if r, err := strconv.ParseUint(s[i+1:min(i+5, len(s))], 16, 16); err == nil && i+5 <= len(s) {
+					b.WriteRune(rune(r))
+					i += 4
+				} else {
+					b.WriteByte('u')
+				}
+			default:
+				b.WriteByte(s[i])
+			}
+			continue
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
@@ -0,0 +1,76 @@
+package converter
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGenerateServerPropertiesRCON(t *testing.T) {
+	generator := NewConfigGenerator()
+
+	opts := &ConversionOptions{DestDir: t.TempDir(), ModpackName: "Test"}
+	if err := generator.generateServerProperties(opts); err != nil {
+		t.Fatalf("generateServerProperties failed: %v", err)
+	}
+	properties, err := ReadServerProperties(opts.DestDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if properties["enable-rcon"] != "false" || properties["rcon.port"] != "25575" {
+		t.Errorf("Expected RCON to be disabled by default, got enable-rcon=%s rcon.port=%s", properties["enable-rcon"], properties["rcon.port"])
+	}
+
+	opts.RCONPort = 25580
+	opts.RCONPassword = "secret"
+	if err := generator.generateServerProperties(opts); err != nil {
+		t.Fatalf("generateServerProperties failed: %v", err)
+	}
+	properties, err = ReadServerProperties(opts.DestDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if properties["enable-rcon"] != "true" || properties["rcon.port"] != "25580" || properties["rcon.password"] != "secret" {
+		t.Errorf("Expected RCON on port 25580, got %v", properties)
+	}
+}
+
+func TestServerPropertiesPermissions(t *testing.T) {
+	opts := &ConversionOptions{DestDir: t.TempDir(), ModpackName: "Test", RCONPassword: "secret"}
+	if err := NewConfigGenerator().generateServerProperties(opts); err != nil {
+		t.Fatalf("generateServerProperties failed: %v", err)
+	}
+	info, err := os.Stat(filepath.Join(opts.DestDir, ServerPropertiesFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm()&0077 != 0 && os.PathSeparator == '/' {
+		t.Errorf("Expected server.properties with a password to be private, got %v", info.Mode())
+	}
+}
+
+func TestEnableRCON(t *testing.T) {
+	serverDir := t.TempDir()
+	existing := "#Edited by hand\nmotd=Kept\nenable-rcon=false\nrcon.port=25575\n"
+	if err := os.WriteFile(filepath.Join(serverDir, ServerPropertiesFile), []byte(existing), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := EnableRCON(serverDir, 25580, "secret"); err != nil {
+		t.Fatalf("EnableRCON failed: %v", err)
+	}
+	properties, err := ReadServerProperties(serverDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if properties["motd"] != "Kept" || properties["enable-rcon"] != "true" || properties["rcon.port"] != "25580" || properties["rcon.password"] != "secret" {
+		t.Errorf("Expected RCON on port 25580 with other settings kept, got %v", properties)
+	}
+	info, err := os.Stat(filepath.Join(serverDir, ServerPropertiesFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm()&0077 != 0 && os.PathSeparator == '/' {
+		t.Errorf("Expected server.properties with a password to be private, got %v", info.Mode())
+	}
+}
@@ -0,0 +1,199 @@
+package converter
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	// DockerServerDir is where the server lives inside the image
+	DockerServerDir = "/server"
+	// DockerEntrypoint is the entrypoint script written next to the Dockerfile
+	DockerEntrypoint = "docker-entrypoint.sh"
+	// DockerPropertiesFile is the server.properties copied into the image,
+	// with RCON disabled so its password stays out of the image layers
+	DockerPropertiesFile = "docker.server.properties"
+
+	defaultServerPort = 25565
+)
+
+// DockerFiles are the files GenerateDocker writes into the server directory
+var DockerFiles = []string{"Dockerfile", "compose.yaml", ".dockerignore", DockerEntrypoint, DockerPropertiesFile}
+
+// DockerOptions configures the container files of an installation
+type DockerOptions struct {
+	// Name names the image and the compose service
+	Name string
+	// JavaMajor selects the eclipse-temurin base image
+	JavaMajor int
+}
+
+// GenerateDocker writes a Dockerfile, compose file, .dockerignore and
+// entrypoint into the server directory, which is the build context. The
+// entrypoint launches the server the same way start.sh does.
+func (s *ScriptGenerator) GenerateDocker(opts *ConversionOptions, docker *DockerOptions) error {
+	// java comes from the image, so the host's Java installations are irrelevant
+	launchOpts := *opts
+	launchOpts.MinJava, launchOpts.MaxJava = 0, 0
+	command, err := s.LaunchCommand(&launchOpts)
+	if err != nil {
+		return err
+	}
+
+	// The world and port are whatever server.properties says, if it exists
+	levelName, port := "world", defaultServerPort
+	if properties, err := ReadServerProperties(opts.DestDir); err == nil {
+		if name := properties["level-name"]; name != "" {
+			levelName = name
+		}
+		if p, err := strconv.Atoi(properties["server-port"]); err == nil && p > 0 {
+			port = p
+		}
+	}
+
+	files := map[string]string{
+		"Dockerfile":         s.dockerfile(opts, docker, levelName, port),
+		"compose.yaml":       s.composeFile(opts, docker, levelName, port),
+		".dockerignore":      s.dockerignore(levelName),
+		DockerEntrypoint:     s.dockerEntrypoint(opts, command),
+		DockerPropertiesFile: s.dockerProperties(opts.DestDir),
+	}
+	for _, name := range DockerFiles {
+		mode := os.FileMode(0644)
+		if name == DockerEntrypoint {
+			mode = 0755
+		}
+		if err := os.WriteFile(filepath.Join(opts.DestDir, name), []byte(files[name]), mode); err != nil {
+			return fmt.Errorf("failed to write %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *ScriptGenerator) dockerfile(opts *ConversionOptions, docker *DockerOptions, levelName string, port int) string {
+	// Named volumes copy ownership from the image, so the mount points must
+	// exist and belong to minecraft or the server cannot write to them
+	mounts := shellQuote(DockerServerDir+"/"+levelName) + " " + shellQuote(DockerServerDir+"/logs")
+	return fmt.Sprintf(`# Dockerfile for %s
+# Generated by Chunk
+FROM eclipse-temurin:%d-jre
+
+RUN groupadd --system minecraft && \
+    useradd --system --gid minecraft --home-dir %s minecraft
+
+WORKDIR %s
+COPY --chown=minecraft:minecraft . %s
+COPY --chown=minecraft:minecraft %s %s/%s
+RUN chmod 0755 %s/%s
+RUN mkdir -p %s && chown minecraft:minecraft %s
+
+USER minecraft
+EXPOSE %d
+ENTRYPOINT ["%s/%s"]
+`, opts.ModpackName, docker.JavaMajor, DockerServerDir, DockerServerDir, DockerServerDir,
+		DockerPropertiesFile, DockerServerDir, ServerPropertiesFile,
+		DockerServerDir, DockerEntrypoint, mounts, mounts, port, DockerServerDir, DockerEntrypoint)
+}
+
+func (s *ScriptGenerator) composeFile(opts *ConversionOptions, docker *DockerOptions, levelName string, port int) string {
+	heapMB := s.calculateRAM(opts)
+	return fmt.Sprintf(`# Compose file for %s
+# Generated by Chunk
+services:
+  %s:
+    build: .
+    image: %s:latest
+    restart: unless-stopped
+    # Keep a console attached, for docker attach
+    stdin_open: true
+    tty: true
+    ports:
+      - "%d:%d"
+    environment:
+      # Set to "true" to accept the Minecraft EULA (https://aka.ms/MinecraftEULA)
+      EULA: "false"
+    volumes:
+      - %s
+      - %s
+      - %s
+    # The server saves the world when it receives SIGTERM
+    stop_grace_period: 60s
+    # Heap of %d MB plus what the JVM needs beyond it
+    mem_limit: %dm
+
+volumes:
+  worlds:
+  configs:
+  logs:
+`, opts.ModpackName, docker.Name, docker.Name, port, port,
+		strconv.Quote("worlds:"+DockerServerDir+"/"+levelName),
+		strconv.Quote("configs:"+DockerServerDir+"/config"),
+		strconv.Quote("logs:"+DockerServerDir+"/logs"),
+		heapMB, heapMB*3/2)
+}
+
+func (s *ScriptGenerator) dockerignore(levelName string) string {
+	return fmt.Sprintf(`# Generated by Chunk
+# Worlds and logs live in volumes, not in the image
+# server.properties may hold the RCON password; the image gets
+# docker.server.properties in its place
+server.properties
+%s/
+%s_nether/
+%s_the_end/
+logs/
+crash-reports/
+.chunk-backup/
+.chunk-server.json*
+Dockerfile
+compose.yaml
+.dockerignore
+start.bat
+`, levelName, levelName, levelName)
+}
+
+// dockerProperties returns the server.properties of serverDir with RCON
+// disabled and its password removed
+func (s *ScriptGenerator) dockerProperties(serverDir string) string {
+	data, _ := os.ReadFile(filepath.Join(serverDir, ServerPropertiesFile))
+
+	var b strings.Builder
+	b.WriteString("#Generated by Chunk from server.properties, with RCON disabled\n")
+	for _, line := range strings.Split(strings.TrimRight(string(data), "\n"), "\n") {
+		key, _, _ := strings.Cut(line, "=")
+		switch strings.TrimSpace(key) {
+		case "enable-rcon":
+			line = "enable-rcon=false"
+		case "rcon.password":
+			line = "rcon.password="
+		}
+		b.WriteString(line + "\n")
+	}
+	return b.String()
+}
+
+func (s *ScriptGenerator) dockerEntrypoint(opts *ConversionOptions, command []string) string {
+	quoted := make([]string, len(command))
+	for i, arg := range command {
+		quoted[i] = shellQuote(arg)
+	}
+
+	return fmt.Sprintf(`#!/bin/sh
+# Entrypoint for %s
+# Generated by Chunk
+set -e
+cd %s
+
+# Accept the EULA when the container is started with EULA=true
+case "$EULA" in
+  [Tt][Rr][Uu][Ee]) echo "eula=true" > eula.txt ;;
+esac
+%s
+# exec, so java receives the SIGTERM of docker stop and saves the world
+exec %s
+`, opts.ModpackName, DockerServerDir, shellEnv(opts.Env), strings.Join(quoted, " "))
+}
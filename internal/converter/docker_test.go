@@ -0,0 +1,117 @@
+package converter
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alexinslc/chunk/internal/sources"
+)
+
+func TestGenerateDocker(t *testing.T) {
+	dir := t.TempDir()
+	properties := "#Minecraft server properties\nenable-rcon=true\nlevel-name=survival\nrcon.password=secret\nserver-port=25570\n"
+	if err := os.WriteFile(filepath.Join(dir, ServerPropertiesFile), []byte(properties), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := &ConversionOptions{
+		DestDir:        dir,
+		ModpackName:    "Test Pack",
+		MCVersion:      "1.20.1",
+		Loader:         sources.LoaderForge,
+		RecommendedRAM: 4,
+		Launch: &LaunchLayout{
+			Kind:     LaunchArgsFile,
+			ArgsFile: "libraries/net/minecraftforge/forge/1.20.1-47.2.0/unix_args.txt",
+		},
+		Env: map[string]string{"TZ": "Europe/Berlin"},
+	}
+	if err := NewScriptGenerator().GenerateDocker(opts, &DockerOptions{Name: "survival-eu", JavaMajor: 17}); err != nil {
+		t.Fatalf("GenerateDocker failed: %v", err)
+	}
+
+	read := func(name string) string {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("Expected %s to be written: %v", name, err)
+		}
+		return string(data)
+	}
+
+	expected := map[string][]string{
+		"Dockerfile": {
+			"FROM eclipse-temurin:17-jre\n",
+			"EXPOSE 25570\n",
+			`ENTRYPOINT ["/server/docker-entrypoint.sh"]`,
+			"COPY --chown=minecraft:minecraft docker.server.properties /server/server.properties\n",
+		},
+		"compose.yaml": {
+			"  survival-eu:\n",
+			`"25570:25570"`,
+			`"worlds:/server/survival"`,
+			`"configs:/server/config"`,
+			`"logs:/server/logs"`,
+			"mem_limit: 6144m\n",
+		},
+		".dockerignore": {"\nsurvival/\n", "\nlogs/\n", "\nserver.properties\n"},
+		DockerPropertiesFile: {
+			"\nenable-rcon=false\n",
+			"\nrcon.password=\n",
+			"\nlevel-name=survival\n",
+		},
+		DockerEntrypoint: {
+			"export TZ=Europe/Berlin\n",
+			"exec java -Xms2048M -Xmx4096M ",
+			" @libraries/net/minecraftforge/forge/1.20.1-47.2.0/unix_args.txt nogui\n",
+		},
+	}
+	for name, wants := range expected {
+		content := read(name)
+		for _, want := range wants {
+			if !strings.Contains(content, want) {
+				t.Errorf("Expected %s to contain %q, got:\n%s", name, want, content)
+			}
+		}
+	}
+
+	// The volume mount points must be created for minecraft before dropping root
+	dockerfile := read("Dockerfile")
+	mkdir := "RUN mkdir -p /server/survival /server/logs && chown minecraft:minecraft /server/survival /server/logs\n"
+	if i := strings.Index(dockerfile, mkdir); i < 0 || i > strings.Index(dockerfile, "USER minecraft") {
+		t.Errorf("Expected Dockerfile to create the mount points before USER, got:\n%s", dockerfile)
+	}
+
+	// The RCON password must not end up in the image
+	if strings.Contains(read(DockerPropertiesFile), "secret") {
+		t.Errorf("Expected %s without the RCON password", DockerPropertiesFile)
+	}
+
+	info, err := os.Stat(filepath.Join(dir, DockerEntrypoint))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm()&0111 == 0 {
+		t.Errorf("Expected %s to be executable, got %v", DockerEntrypoint, info.Mode())
+	}
+}
+
+func TestParseServerProperties(t *testing.T) {
+	properties := ParseServerProperties([]byte("# comment\n! also a comment\nmotd=A \\u00A7aServer\nserver-port = 25566\nlevel-name:world\n\nenable-rcon=false\n"))
+
+	expected := map[string]string{
+		"motd":        "A §aServer",
+		"server-port": "25566",
+		"level-name":  "world",
+		"enable-rcon": "false",
+	}
+	if len(properties) != len(expected) {
+		t.Errorf("Expected %d properties, got %v", len(expected), properties)
+	}
+	for key, want := range expected {
+		if properties[key] != want {
+			t.Errorf("Expected %s=%q, got %q", key, want, properties[key])
+		}
+	}
+}
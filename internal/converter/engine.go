@@ -1,10 +1,12 @@
 package converter
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
 
+	"github.com/alexinslc/chunk/internal/checksum"
 	"github.com/alexinslc/chunk/internal/sources"
 )
 
@@ -32,9 +34,24 @@ type ConversionOptions struct {
 	LoaderVersion  string
 	RecommendedRAM int
 	PreserveData   bool
+	// Launch is how the installed server starts; detected from DestDir when nil
+	Launch *LaunchLayout
+	// JVMArgs are appended to the default JVM flags in the start scripts
+	JVMArgs []string
+	// Env is exported by the start scripts before launching the server
+	Env map[string]string
+	// MinJava and MaxJava bound the Java major version; zero is unconstrained
+	MinJava int
+	MaxJava int
+	// RCONPassword enables RCON on RCONPort in server.properties when set
+	RCONPort     int
+	RCONPassword string
+	// LoaderChecksums, when set, verify the downloaded loader jar or
+	// installer before anything runs it
+	LoaderChecksums *checksum.Checksums
 }
 
-func (e *ConversionEngine) Convert(modpack *sources.Modpack, destDir string) error {
+func (e *ConversionEngine) Convert(ctx context.Context, modpack *sources.Modpack, destDir string) error {
 	opts := &ConversionOptions{
 		DestDir:        destDir,
 		ModpackName:    modpack.Name,
@@ -43,12 +60,16 @@ func (e *ConversionEngine) Convert(modpack *sources.Modpack, destDir string) err
 		LoaderVersion:  modpack.LoaderVersion,
 		RecommendedRAM: modpack.RecommendedRAM,
 		PreserveData:   false,
+		JVMArgs:        modpack.JVMArgs,
+		Env:            modpack.Env,
+		MinJava:        modpack.MinJava,
+		MaxJava:        modpack.MaxJava,
 	}
 
-	return e.ConvertWithOptions(modpack, opts)
+	return e.ConvertWithOptions(ctx, modpack, opts)
 }
 
-func (e *ConversionEngine) ConvertWithOptions(modpack *sources.Modpack, opts *ConversionOptions) error {
+func (e *ConversionEngine) ConvertWithOptions(ctx context.Context, modpack *sources.Modpack, opts *ConversionOptions) error {
 	if err := e.validateModpack(modpack); err != nil {
 		return fmt.Errorf("invalid modpack: %w", err)
 	}
@@ -57,13 +78,13 @@ func (e *ConversionEngine) ConvertWithOptions(modpack *sources.Modpack, opts *Co
 		return fmt.Errorf("failed to prepare directory: %w", err)
 	}
 
-	if err := e.loaderInstaller.Install(opts); err != nil {
+	if err := e.loaderInstaller.Install(ctx, opts); err != nil {
 		return fmt.Errorf("failed to install mod loader: %w", err)
 	}
 
 	serverMods := e.filterServerMods(modpack.Mods)
 
-	if err := e.modManager.DownloadMods(serverMods, opts.DestDir); err != nil {
+	if err := e.modManager.DownloadMods(ctx, serverMods, opts.DestDir); err != nil {
 		return fmt.Errorf("failed to download mods: %w", err)
 	}
 
@@ -89,7 +110,8 @@ func (e *ConversionEngine) validateModpack(modpack *sources.Modpack) error {
 
 	if modpack.Loader != sources.LoaderForge &&
 		modpack.Loader != sources.LoaderFabric &&
-		modpack.Loader != sources.LoaderNeoForge {
+		modpack.Loader != sources.LoaderNeoForge &&
+		modpack.Loader != sources.LoaderQuilt {
 		return fmt.Errorf("unsupported mod loader: %s", modpack.Loader)
 	}
 
@@ -0,0 +1,169 @@
+package converter
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/alexinslc/chunk/internal/sources"
+)
+
+// LaunchKind is how a server is started
+type LaunchKind string
+
+const (
+	// LaunchJar starts the server with java -jar <jar>
+	LaunchJar LaunchKind = "jar"
+	// LaunchArgsFile starts the server with java @<args file>, as modern
+	// Forge and NeoForge installers set up through run.sh
+	LaunchArgsFile LaunchKind = "argsfile"
+)
+
+const (
+	unixArgsFile    = "unix_args.txt"
+	winArgsFile     = "win_args.txt"
+	userJVMArgsFile = "user_jvm_args.txt"
+)
+
+// LaunchLayout describes how to start an installed server
+type LaunchLayout struct {
+	Kind LaunchKind
+	// Jar is the server jar relative to the server directory, for LaunchJar
+	Jar string
+	// ArgsFile is the slash-separated path of unix_args.txt relative to the
+	// server directory, for LaunchArgsFile
+	ArgsFile string
+	// UserJVMArgs is set when the installer created user_jvm_args.txt
+	UserJVMArgs bool
+}
+
+// JavaArgs returns the java arguments that select what to run, after the JVM flags
+func (l *LaunchLayout) JavaArgs(windows bool) string {
+	return strings.Join(l.Args(windows), " ")
+}
+
+// Args returns the java arguments that select what to run as separate arguments
+func (l *LaunchLayout) Args(windows bool) []string {
+	if l.Kind == LaunchJar {
+		return []string{"-jar", l.Jar}
+	}
+
+	argsFile := l.ArgsFile
+	if windows {
+		argsFile = strings.TrimSuffix(argsFile, unixArgsFile) + winArgsFile
+	}
+
+	args := []string{"@" + argsFile}
+	if l.UserJVMArgs {
+		args = append([]string{"@" + userJVMArgsFile}, args...)
+	}
+	return args
+}
+
+// DetectLaunchLayout inspects a server directory after the loader was
+// installed and works out how the server has to be started
+func DetectLaunchLayout(serverDir string, loader sources.LoaderType, loaderVersion string) (*LaunchLayout, error) {
+	switch loader {
+	case sources.LoaderFabric:
+		return detectJar(serverDir, "fabric-server-launch.jar")
+	case sources.LoaderQuilt:
+		return detectJar(serverDir, "quilt-server-launch.jar")
+	case sources.LoaderForge, sources.LoaderNeoForge:
+		if layout, err := detectArgsFile(serverDir, loaderVersion); err != nil || layout != nil {
+			return layout, err
+		}
+		if layout := detectUniversalJar(serverDir); layout != nil {
+			return layout, nil
+		}
+		return nil, fmt.Errorf("no %s server found in %s: expected libraries/.../%s or a forge universal jar", loader, serverDir, unixArgsFile)
+	default:
+		return detectJar(serverDir, "server.jar")
+	}
+}
+
+func detectJar(serverDir, jar string) (*LaunchLayout, error) {
+	if _, err := os.Stat(filepath.Join(serverDir, jar)); err != nil {
+		return nil, fmt.Errorf("server jar %s not found in %s", jar, serverDir)
+	}
+	return &LaunchLayout{Kind: LaunchJar, Jar: jar}, nil
+}
+
+// detectArgsFile finds the unix_args.txt written by modern installers,
+// preferring the one for loaderVersion if several versions are present
+func detectArgsFile(serverDir, loaderVersion string) (*LaunchLayout, error) {
+	librariesDir := filepath.Join(serverDir, "libraries")
+	if _, err := os.Stat(librariesDir); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	var candidates []string
+	err := filepath.WalkDir(librariesDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.IsDir() && entry.Name() == unixArgsFile {
+			rel, err := filepath.Rel(serverDir, path)
+			if err != nil {
+				return err
+			}
+			candidates = append(candidates, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan libraries: %w", err)
+	}
+
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	sort.Strings(candidates)
+	argsFile := candidates[len(candidates)-1]
+	if loaderVersion != "" {
+		for _, candidate := range candidates {
+			if strings.Contains(filepath.Base(filepath.Dir(candidate)), loaderVersion) {
+				argsFile = candidate
+				break
+			}
+		}
+	}
+
+	_, err = os.Stat(filepath.Join(serverDir, userJVMArgsFile))
+
+	return &LaunchLayout{
+		Kind:        LaunchArgsFile,
+		ArgsFile:    argsFile,
+		UserJVMArgs: err == nil,
+	}, nil
+}
+
+// detectUniversalJar finds the server jar legacy Forge installers leave in the
+// server root, such as forge-1.12.2-14.23.5.2859.jar or forge-1.7.10-...-universal.jar
+func detectUniversalJar(serverDir string) *LaunchLayout {
+	matches, _ := filepath.Glob(filepath.Join(serverDir, "forge-*.jar"))
+
+	var jars []string
+	for _, match := range matches {
+		if !strings.Contains(filepath.Base(match), "installer") {
+			jars = append(jars, filepath.Base(match))
+		}
+	}
+	if len(jars) == 0 {
+		return nil
+	}
+
+	sort.Strings(jars)
+	jar := jars[0]
+	for _, candidate := range jars {
+		if strings.Contains(candidate, "universal") {
+			jar = candidate
+			break
+		}
+	}
+
+	return &LaunchLayout{Kind: LaunchJar, Jar: jar}
+}
@@ -0,0 +1,271 @@
+package converter
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/alexinslc/chunk/internal/sources"
+)
+
+// writeFiles creates empty files relative to dir
+func writeFiles(t *testing.T, dir string, files ...string) {
+	t.Helper()
+
+	for _, file := range files {
+		path := filepath.Join(dir, filepath.FromSlash(file))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create dir for %s: %v", file, err)
+		}
+		if err := os.WriteFile(path, []byte{}, 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", file, err)
+		}
+	}
+}
+
+func TestDetectLaunchLayout(t *testing.T) {
+	tests := []struct {
+		name          string
+		loader        sources.LoaderType
+		loaderVersion string
+		files         []string
+		wantArgs      string
+		wantErr       bool
+	}{
+		{
+			name:          "modern forge",
+			loader:        sources.LoaderForge,
+			loaderVersion: "47.2.0",
+			files: []string{
+				"forge-installer.jar",
+				"run.sh",
+				"user_jvm_args.txt",
+				"libraries/net/minecraftforge/forge/1.20.1-47.2.0/unix_args.txt",
+			},
+			wantArgs: "@user_jvm_args.txt @libraries/net/minecraftforge/forge/1.20.1-47.2.0/unix_args.txt",
+		},
+		{
+			name:          "modern forge prefers installed version",
+			loader:        sources.LoaderForge,
+			loaderVersion: "47.1.0",
+			files: []string{
+				"libraries/net/minecraftforge/forge/1.20.1-47.1.0/unix_args.txt",
+				"libraries/net/minecraftforge/forge/1.20.1-47.2.0/unix_args.txt",
+			},
+			wantArgs: "@libraries/net/minecraftforge/forge/1.20.1-47.1.0/unix_args.txt",
+		},
+		{
+			name:          "neoforge",
+			loader:        sources.LoaderNeoForge,
+			loaderVersion: "20.4.80-beta",
+			files:         []string{"libraries/net/neoforged/neoforge/20.4.80-beta/unix_args.txt"},
+			wantArgs:      "@libraries/net/neoforged/neoforge/20.4.80-beta/unix_args.txt",
+		},
+		{
+			name:   "legacy forge",
+			loader: sources.LoaderForge,
+			files: []string{
+				"forge-installer.jar",
+				"forge-1.12.2-14.23.5.2859.jar",
+				"minecraft_server.1.12.2.jar",
+			},
+			wantArgs: "-jar forge-1.12.2-14.23.5.2859.jar",
+		},
+		{
+			name:     "legacy forge universal jar",
+			loader:   sources.LoaderForge,
+			files:    []string{"forge-1.7.10-10.13.4.1614-1.7.10-universal.jar", "forge-1.7.10-10.13.4.1614-1.7.10-installer.jar"},
+			wantArgs: "-jar forge-1.7.10-10.13.4.1614-1.7.10-universal.jar",
+		},
+		{
+			name:     "fabric",
+			loader:   sources.LoaderFabric,
+			files:    []string{"fabric-server-launch.jar"},
+			wantArgs: "-jar fabric-server-launch.jar",
+		},
+		{
+			name:     "quilt",
+			loader:   sources.LoaderQuilt,
+			files:    []string{"quilt-installer.jar", "quilt-server-launch.jar", "server.jar"},
+			wantArgs: "-jar quilt-server-launch.jar",
+		},
+		{
+			name:    "forge installer never ran",
+			loader:  sources.LoaderForge,
+			files:   []string{"forge-installer.jar"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			serverDir := t.TempDir()
+			writeFiles(t, serverDir, tt.files...)
+
+			layout, err := DetectLaunchLayout(serverDir, tt.loader, tt.loaderVersion)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DetectLaunchLayout() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && layout.JavaArgs(false) != tt.wantArgs {
+				t.Errorf("JavaArgs() = %q, want %q", layout.JavaArgs(false), tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestLaunchLayoutWindowsArgs(t *testing.T) {
+	layout := &LaunchLayout{
+		Kind:     LaunchArgsFile,
+		ArgsFile: "libraries/net/minecraftforge/forge/1.20.1-47.2.0/unix_args.txt",
+	}
+
+	want := "@libraries/net/minecraftforge/forge/1.20.1-47.2.0/win_args.txt"
+	if got := layout.JavaArgs(true); got != want {
+		t.Errorf("JavaArgs(true) = %q, want %q", got, want)
+	}
+}
+
+// fakeJava puts a java executable on PATH that reports Java 17 and, when
+// run as an installer, executes installScript in the working directory
+func fakeJava(t *testing.T, installScript string) {
+	t.Helper()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("fake java requires a POSIX shell")
+	}
+
+	binDir := t.TempDir()
+	script := `#!/bin/sh
+if [ "$1" = "-version" ]; then
+  echo 'openjdk version "17.0.9" 2023-10-17' >&2
+  exit 0
+fi
+` + installScript
+
+	if err := os.WriteFile(filepath.Join(binDir, "java"), []byte(script), 0755); err != nil {
+		t.Fatalf("Failed to write fake java: %v", err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestRunServerInstaller(t *testing.T) {
+	fakeJava(t, `
+[ "$1" = "-jar" ] && [ "$3" = "--installServer" ] || { echo "unexpected args: $*"; exit 2; }
+echo "Extracting main jar"
+mkdir -p libraries/net/minecraftforge/forge/1.20.1-47.2.0
+echo "-DlibraryDirectory=libraries" > libraries/net/minecraftforge/forge/1.20.1-47.2.0/unix_args.txt
+echo "# JVM args" > user_jvm_args.txt
+echo "The server installed successfully"
+`)
+
+	serverDir := t.TempDir()
+	writeFiles(t, serverDir, "forge-installer.jar")
+
+	opts := &ConversionOptions{
+		DestDir:       serverDir,
+		ModpackName:   "Test Pack",
+		MCVersion:     "1.20.1",
+		Loader:        sources.LoaderForge,
+		LoaderVersion: "47.2.0",
+	}
+
+	installer := NewLoaderInstaller()
+	if err := installer.runServerInstaller(context.Background(), opts, filepath.Join(serverDir, "forge-installer.jar")); err != nil {
+		t.Fatalf("runServerInstaller failed: %v", err)
+	}
+
+	log, err := os.ReadFile(filepath.Join(serverDir, "logs", "forge-installer.log"))
+	if err != nil {
+		t.Fatalf("Expected installer log: %v", err)
+	}
+	if !strings.Contains(string(log), "installed successfully") {
+		t.Errorf("Expected installer output in log, got %q", log)
+	}
+
+	// The start script launches through the args file the installer produced
+	if err := NewScriptGenerator().Generate(opts); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	script, err := os.ReadFile(filepath.Join(serverDir, "start.sh"))
+	if err != nil {
+		t.Fatalf("Failed to read start.sh: %v", err)
+	}
+	if !strings.Contains(string(script), "@user_jvm_args.txt @libraries/net/minecraftforge/forge/1.20.1-47.2.0/unix_args.txt nogui") {
+		t.Errorf("Expected start.sh to use unix_args.txt, got:\n%s", script)
+	}
+	if strings.Contains(string(script), "forge-server.jar") {
+		t.Error("Expected start.sh not to reference forge-server.jar")
+	}
+
+	bat, err := os.ReadFile(filepath.Join(serverDir, "start.bat"))
+	if err != nil {
+		t.Fatalf("Failed to read start.bat: %v", err)
+	}
+	if !strings.Contains(string(bat), "1.20.1-47.2.0/win_args.txt nogui") {
+		t.Errorf("Expected start.bat to use win_args.txt, got:\n%s", bat)
+	}
+}
+
+func TestRunServerInstallerFailure(t *testing.T) {
+	fakeJava(t, `
+echo "Downloading libraries"
+echo "Error: could not download minecraft server jar"
+exit 1
+`)
+
+	serverDir := t.TempDir()
+	opts := &ConversionOptions{
+		DestDir:   serverDir,
+		MCVersion: "1.20.1",
+		Loader:    sources.LoaderNeoForge,
+	}
+
+	err := NewLoaderInstaller().runServerInstaller(context.Background(), opts, filepath.Join(serverDir, "neoforge-installer.jar"))
+	if err == nil {
+		t.Fatal("Expected error when the installer fails")
+	}
+
+	logPath := filepath.Join(serverDir, "logs", "neoforge-installer.log")
+	if !strings.Contains(err.Error(), logPath) || !strings.Contains(err.Error(), "could not download minecraft server jar") {
+		t.Errorf("Expected error to name the log and the last output line, got: %v", err)
+	}
+	if _, err := os.Stat(logPath); err != nil {
+		t.Errorf("Expected installer log to be written: %v", err)
+	}
+}
+
+func TestRunServerInstallerQuilt(t *testing.T) {
+	fakeJava(t, `
+[ "$3" = "install" ] && [ "$4" = "server" ] && [ "$5" = "1.20.1" ] && [ "$6" = "0.23.1" ] || { echo "unexpected args: $*"; exit 2; }
+touch quilt-server-launch.jar server.jar
+`)
+
+	serverDir := t.TempDir()
+	opts := &ConversionOptions{
+		DestDir:       serverDir,
+		ModpackName:   "Quilt Pack",
+		MCVersion:     "1.20.1",
+		Loader:        sources.LoaderQuilt,
+		LoaderVersion: "0.23.1",
+	}
+
+	if err := NewLoaderInstaller().runServerInstaller(context.Background(), opts, filepath.Join(serverDir, "quilt-installer.jar")); err != nil {
+		t.Fatalf("runServerInstaller failed: %v", err)
+	}
+
+	if err := NewScriptGenerator().Generate(opts); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	script, err := os.ReadFile(filepath.Join(serverDir, "start.sh"))
+	if err != nil {
+		t.Fatalf("Failed to read start.sh: %v", err)
+	}
+	if !strings.Contains(string(script), "-jar quilt-server-launch.jar nogui") {
+		t.Errorf("Expected start.sh to run the Quilt server launcher, got:\n%s", script)
+	}
+}
@@ -1,145 +1,245 @@
 package converter
 
 import (
+	"context"
 	"fmt"
-	"io"
 	"net/http"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"strings"
 	"time"
 
+	"github.com/alexinslc/chunk/internal/cache"
+	"github.com/alexinslc/chunk/internal/java"
+	"github.com/alexinslc/chunk/internal/metadata"
+	"github.com/alexinslc/chunk/internal/mirror"
 	"github.com/alexinslc/chunk/internal/sources"
 )
 
+// QuiltInstallerVersion is the Quilt installer release used to set up Quilt servers
+const QuiltInstallerVersion = "0.9.2"
+
 type LoaderInstaller struct {
 	httpClient *http.Client
+	downloader *cache.Downloader
+	// Resolver selects loader versions; created on first use when nil
+	Resolver *metadata.LoaderResolver
 }
 
 func NewLoaderInstaller() *LoaderInstaller {
+	httpClient := &http.Client{
+		Timeout:   5 * time.Minute,
+		Transport: &mirror.Transport{},
+	}
 	return &LoaderInstaller{
-		httpClient: &http.Client{
-			Timeout: 5 * time.Minute,
-		},
+		httpClient: httpClient,
+		downloader: cache.NewDefaultDownloader(httpClient),
 	}
 }
 
-func (l *LoaderInstaller) Install(opts *ConversionOptions) error {
-	switch opts.Loader {
-	case sources.LoaderForge:
-		return l.installForge(opts)
-	case sources.LoaderFabric:
-		return l.installFabric(opts)
-	case sources.LoaderNeoForge:
-		return l.installNeoForge(opts)
-	default:
-		return fmt.Errorf("unsupported loader: %s", opts.Loader)
-	}
+// LoaderArtifact describes the file a loader install downloads into the server directory
+type LoaderArtifact struct {
+	URL      string
+	FileName string
 }
 
-func (l *LoaderInstaller) installForge(opts *ConversionOptions) error {
-	version := opts.LoaderVersion
-	if version == "" {
-		version = l.detectForgeVersion(opts.MCVersion)
+func (l *LoaderInstaller) Install(ctx context.Context, opts *ConversionOptions) error {
+	if err := l.ResolveVersion(ctx, opts); err != nil {
+		return err
+	}
+
+	artifact, err := l.Artifact(ctx, opts)
+	if err != nil {
+		return err
 	}
 
-	downloadURL := fmt.Sprintf("https://maven.minecraftforge.net/net/minecraftforge/forge/%s-%s/forge-%s-%s-installer.jar",
-		opts.MCVersion, version, opts.MCVersion, version)
+	destPath := filepath.Join(opts.DestDir, artifact.FileName)
+	if err := l.downloader.Download(ctx, artifact.URL, destPath, opts.LoaderChecksums, nil); err != nil {
+		return fmt.Errorf("failed to download %s %s: %w", opts.Loader, loaderFileKind(opts.Loader), err)
+	}
 
-	installerPath := filepath.Join(opts.DestDir, "forge-installer.jar")
+	if opts.Loader != sources.LoaderFabric {
+		if err := l.runServerInstaller(ctx, opts, destPath); err != nil {
+			return err
+		}
+	}
 
-	if err := l.downloadFile(downloadURL, installerPath); err != nil {
-		return fmt.Errorf("failed to download forge installer: %w", err)
+	layout, err := DetectLaunchLayout(opts.DestDir, opts.Loader, opts.LoaderVersion)
+	if err != nil {
+		return err
 	}
+	opts.Launch = layout
 
 	return nil
 }
 
-func (l *LoaderInstaller) installFabric(opts *ConversionOptions) error {
-	version := opts.LoaderVersion
-	if version == "" {
-		version = "latest"
+// runServerInstaller runs a Forge, NeoForge or Quilt installer jar headlessly
+// in the server directory. Its output is saved to logs/<loader>-installer.log.
+func (l *LoaderInstaller) runServerInstaller(ctx context.Context, opts *ConversionOptions, installerPath string) error {
+	javaInstall, err := java.NewJavaDetector().FindInRange(opts.MCVersion, opts.MinJava, opts.MaxJava)
+	if err != nil {
+		return fmt.Errorf("cannot run %s installer: %w", opts.Loader, err)
+	}
+
+	logsDir := filepath.Join(opts.DestDir, "logs")
+	if err := os.MkdirAll(logsDir, 0755); err != nil {
+		return fmt.Errorf("failed to create logs directory: %w", err)
 	}
+	logPath := filepath.Join(logsDir, fmt.Sprintf("%s-installer.log", opts.Loader))
 
-	downloadURL := fmt.Sprintf("https://meta.fabricmc.net/v2/versions/loader/%s/%s/stable/server/jar",
-		opts.MCVersion, version)
+	absInstaller, err := filepath.Abs(installerPath)
+	if err != nil {
+		return fmt.Errorf("failed to resolve installer path: %w", err)
+	}
 
-	serverPath := filepath.Join(opts.DestDir, "fabric-server-launch.jar")
+	args := append([]string{"-jar", absInstaller}, installerArgs(opts)...)
+	cmd := exec.CommandContext(ctx, javaInstall.Path, args...)
+	cmd.Dir = opts.DestDir
+	output, runErr := cmd.CombinedOutput()
 
-	if err := l.downloadFile(downloadURL, serverPath); err != nil {
-		return fmt.Errorf("failed to download fabric server: %w", err)
+	if err := os.WriteFile(logPath, output, 0644); err != nil {
+		return fmt.Errorf("failed to write installer log: %w", err)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if runErr != nil {
+		return fmt.Errorf("%s installer failed (see %s): %w%s", opts.Loader, logPath, runErr, lastLine(output))
 	}
 
 	return nil
 }
 
-func (l *LoaderInstaller) installNeoForge(opts *ConversionOptions) error {
-	version := opts.LoaderVersion
-	if version == "" {
-		version = l.detectNeoForgeVersion(opts.MCVersion)
+// installerArgs returns the arguments that make a loader installer set up a server
+func installerArgs(opts *ConversionOptions) []string {
+	if opts.Loader != sources.LoaderQuilt {
+		return []string{"--installServer"}
 	}
 
-	downloadURL := fmt.Sprintf("https://maven.neoforged.net/releases/net/neoforged/forge/%s/forge-%s-installer.jar",
-		version, version)
+	// The Quilt installer writes quilt-server-launch.jar and, with
+	// --download-server, the vanilla server.jar it launches
+	args := []string{"install", "server", opts.MCVersion}
+	if opts.LoaderVersion != "" {
+		args = append(args, opts.LoaderVersion)
+	}
+	return append(args, "--download-server", "--install-dir=.")
+}
 
-	installerPath := filepath.Join(opts.DestDir, "neoforge-installer.jar")
+// lastLine returns the last non-empty line of output, formatted for an error message
+func lastLine(output []byte) string {
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	line := strings.TrimSpace(lines[len(lines)-1])
+	if line == "" {
+		return ""
+	}
+	return ": " + line
+}
 
-	if err := l.downloadFile(downloadURL, installerPath); err != nil {
-		return fmt.Errorf("failed to download neoforge installer: %w", err)
+// ResolveLoaderVersion returns the loader version to install for a Minecraft
+// version, checked against the loader's published versions. An empty or
+// "latest" version selects the recommended (or newest) release.
+func (l *LoaderInstaller) ResolveLoaderVersion(ctx context.Context, loader sources.LoaderType, mcVersion, version string) (string, error) {
+	if l.Resolver == nil {
+		// Without a cache directory versions are still resolved, just not cached
+		metadataCache, _ := metadata.NewCache()
+		l.Resolver = metadata.NewLoaderResolver(metadataCache)
 	}
 
-	return nil
+	return l.Resolver.Resolve(ctx, metadata.LoaderType(loader), mcVersion, version)
 }
 
-func (l *LoaderInstaller) downloadFile(url, destPath string) error {
-	resp, err := l.httpClient.Get(url)
+// ResolveVersion settles opts.LoaderVersion with ResolveLoaderVersion
+func (l *LoaderInstaller) ResolveVersion(ctx context.Context, opts *ConversionOptions) error {
+	version, err := l.ResolveLoaderVersion(ctx, opts.Loader, opts.MCVersion, opts.LoaderVersion)
 	if err != nil {
 		return err
 	}
-	defer resp.Body.Close()
+	opts.LoaderVersion = version
+	return nil
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("download failed: status %d", resp.StatusCode)
+// Artifact resolves the download URL and local file name for the loader,
+// selecting a loader version first if opts does not name one
+func (l *LoaderInstaller) Artifact(ctx context.Context, opts *ConversionOptions) (*LoaderArtifact, error) {
+	if opts.LoaderVersion == "" && opts.Loader != sources.LoaderQuilt {
+		if err := l.ResolveVersion(ctx, opts); err != nil {
+			return nil, err
+		}
 	}
 
-	out, err := os.Create(destPath)
-	if err != nil {
-		return err
+	switch opts.Loader {
+	case sources.LoaderForge:
+		return l.forgeArtifact(opts), nil
+	case sources.LoaderFabric:
+		return l.fabricArtifact(opts), nil
+	case sources.LoaderNeoForge:
+		return l.neoForgeArtifact(opts), nil
+	case sources.LoaderQuilt:
+		return l.quiltArtifact(), nil
+	default:
+		return nil, fmt.Errorf("unsupported loader: %s", opts.Loader)
 	}
-	defer out.Close()
+}
+
+func (l *LoaderInstaller) forgeArtifact(opts *ConversionOptions) *LoaderArtifact {
+	version := strings.TrimPrefix(opts.LoaderVersion, opts.MCVersion+"-")
 
-	_, err = io.Copy(out, resp.Body)
-	return err
+	return &LoaderArtifact{
+		URL: fmt.Sprintf("https://maven.minecraftforge.net/net/minecraftforge/forge/%s-%s/forge-%s-%s-installer.jar",
+			opts.MCVersion, version, opts.MCVersion, version),
+		FileName: "forge-installer.jar",
+	}
 }
 
-func (l *LoaderInstaller) detectForgeVersion(mcVersion string) string {
-	versionMap := map[string]string{
-		"1.20.1": "47.2.0",
-		"1.20":   "46.0.14",
-		"1.19.4": "45.1.0",
-		"1.19.2": "43.2.0",
-		"1.18.2": "40.2.0",
-		"1.16.5": "36.2.39",
-		"1.12.2": "14.23.5.2859",
+func (l *LoaderInstaller) fabricArtifact(opts *ConversionOptions) *LoaderArtifact {
+	version := opts.LoaderVersion
+
+	return &LoaderArtifact{
+		URL: fmt.Sprintf("https://meta.fabricmc.net/v2/versions/loader/%s/%s/stable/server/jar",
+			opts.MCVersion, version),
+		FileName: "fabric-server-launch.jar",
 	}
+}
+
+// neoForgeArtifact is the NeoForge installer; builds for Minecraft 1.20.1
+// were published under the legacy net.neoforged:forge coordinates
+func (l *LoaderInstaller) neoForgeArtifact(opts *ConversionOptions) *LoaderArtifact {
+	version := opts.LoaderVersion
 
-	if version, ok := versionMap[mcVersion]; ok {
-		return version
+	if opts.MCVersion == metadata.NeoForgeLegacyMCVersion {
+		version = strings.TrimPrefix(version, opts.MCVersion+"-")
+		return &LoaderArtifact{
+			URL: fmt.Sprintf("https://maven.neoforged.net/releases/net/neoforged/forge/%s-%s/forge-%s-%s-installer.jar",
+				opts.MCVersion, version, opts.MCVersion, version),
+			FileName: "neoforge-installer.jar",
+		}
 	}
 
-	return "latest"
+	return &LoaderArtifact{
+		URL: fmt.Sprintf("https://maven.neoforged.net/releases/net/neoforged/neoforge/%s/neoforge-%s-installer.jar",
+			version, version),
+		FileName: "neoforge-installer.jar",
+	}
 }
 
-func (l *LoaderInstaller) detectNeoForgeVersion(mcVersion string) string {
-	versionMap := map[string]string{
-		"1.20.1": "20.1.84",
-		"1.20":   "20.0.0",
+// quiltArtifact is the Quilt installer; it installs whichever loader version it is asked for
+func (l *LoaderInstaller) quiltArtifact() *LoaderArtifact {
+	return &LoaderArtifact{
+		URL: fmt.Sprintf("https://maven.quiltmc.org/repository/release/org/quiltmc/quilt-installer/%s/quilt-installer-%s.jar",
+			QuiltInstallerVersion, QuiltInstallerVersion),
+		FileName: "quilt-installer.jar",
 	}
+}
 
-	if version, ok := versionMap[mcVersion]; ok {
-		return version
+// loaderFileKind names the downloaded file for error messages
+func loaderFileKind(loader sources.LoaderType) string {
+	if loader == sources.LoaderFabric {
+		return "server"
 	}
-
-	return "latest"
+	return "installer"
 }
 
 func DetectLoader(modpack *sources.Modpack) sources.LoaderType {
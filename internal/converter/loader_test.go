@@ -0,0 +1,156 @@
+package converter
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alexinslc/chunk/internal/cache"
+	"github.com/alexinslc/chunk/internal/checksum"
+	"github.com/alexinslc/chunk/internal/config"
+	"github.com/alexinslc/chunk/internal/metadata"
+	"github.com/alexinslc/chunk/internal/mirror"
+	"github.com/alexinslc/chunk/internal/sources"
+)
+
+// staticVersions is a metadata.VersionProvider with a fixed version list, newest first
+type staticVersions []metadata.LoaderVersion
+
+func (s staticVersions) GetVersions(ctx context.Context) ([]metadata.LoaderVersion, error) {
+	return s, nil
+}
+
+func (s staticVersions) GetVersionsForMC(ctx context.Context, mcVersion string) ([]metadata.LoaderVersion, error) {
+	return s, nil
+}
+
+func (s staticVersions) GetLatestVersion(ctx context.Context, mcVersion string) (*metadata.LoaderVersion, error) {
+	for _, v := range s {
+		if v.Stable {
+			return &v, nil
+		}
+	}
+	return nil, metadata.ErrNotFound
+}
+
+func (s staticVersions) IsVersionCompatible(ctx context.Context, loaderVersion, mcVersion string) (bool, error) {
+	return false, nil
+}
+
+func TestLoaderInstallerArtifact(t *testing.T) {
+	installer := NewLoaderInstaller()
+	installer.Resolver = metadata.NewLoaderResolverWithProviders(map[metadata.LoaderType]metadata.VersionProvider{
+		metadata.LoaderForge: staticVersions{
+			{Version: "47.2.5"},
+			{Version: "47.2.0", Stable: true},
+		},
+		metadata.LoaderNeoForge: staticVersions{{Version: "21.1.77", Stable: true}},
+		metadata.LoaderFabric:   staticVersions{{Version: "0.16.9", Stable: true}},
+	})
+
+	tests := []struct {
+		name          string
+		mcVersion     string
+		loader        sources.LoaderType
+		loaderVersion string
+		wantURL       string
+		wantVersion   string
+	}{
+		{
+			name:        "forge recommended",
+			mcVersion:   "1.20.1",
+			loader:      sources.LoaderForge,
+			wantURL:     "https://maven.minecraftforge.net/net/minecraftforge/forge/1.20.1-47.2.0/forge-1.20.1-47.2.0-installer.jar",
+			wantVersion: "47.2.0",
+		},
+		{
+			name:          "forge mc prefixed version",
+			mcVersion:     "1.20.1",
+			loader:        sources.LoaderForge,
+			loaderVersion: "1.20.1-47.2.5",
+			wantURL:       "https://maven.minecraftforge.net/net/minecraftforge/forge/1.20.1-47.2.5/forge-1.20.1-47.2.5-installer.jar",
+			wantVersion:   "1.20.1-47.2.5",
+		},
+		{
+			name:        "neoforge",
+			mcVersion:   "1.21.1",
+			loader:      sources.LoaderNeoForge,
+			wantURL:     "https://maven.neoforged.net/releases/net/neoforged/neoforge/21.1.77/neoforge-21.1.77-installer.jar",
+			wantVersion: "21.1.77",
+		},
+		{
+			name:          "legacy neoforge",
+			mcVersion:     "1.20.1",
+			loader:        sources.LoaderNeoForge,
+			loaderVersion: "47.1.106",
+			wantURL:       "https://maven.neoforged.net/releases/net/neoforged/forge/1.20.1-47.1.106/forge-1.20.1-47.1.106-installer.jar",
+			wantVersion:   "47.1.106",
+		},
+		{
+			name:        "fabric",
+			mcVersion:   "1.21.1",
+			loader:      sources.LoaderFabric,
+			wantURL:     "https://meta.fabricmc.net/v2/versions/loader/1.21.1/0.16.9/stable/server/jar",
+			wantVersion: "0.16.9",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := &ConversionOptions{MCVersion: tt.mcVersion, Loader: tt.loader, LoaderVersion: tt.loaderVersion}
+
+			artifact, err := installer.Artifact(context.Background(), opts)
+			if err != nil {
+				t.Fatalf("Artifact() error = %v", err)
+			}
+			if artifact.URL != tt.wantURL {
+				t.Errorf("URL = %s, want %s", artifact.URL, tt.wantURL)
+			}
+			if opts.LoaderVersion != tt.wantVersion {
+				t.Errorf("LoaderVersion = %q, want %q", opts.LoaderVersion, tt.wantVersion)
+			}
+		})
+	}
+}
+
+func TestLoaderInstallerResolveUnknownVersion(t *testing.T) {
+	installer := NewLoaderInstaller()
+	installer.Resolver = metadata.NewLoaderResolverWithProviders(map[metadata.LoaderType]metadata.VersionProvider{
+		metadata.LoaderForge: staticVersions{{Version: "47.2.0", Stable: true}},
+	})
+
+	opts := &ConversionOptions{MCVersion: "1.20.1", Loader: sources.LoaderForge, LoaderVersion: "latest-ish"}
+	if err := installer.ResolveVersion(context.Background(), opts); !errors.Is(err, metadata.ErrInvalidVersion) {
+		t.Fatalf("Expected ErrInvalidVersion, got %v", err)
+	}
+}
+
+func TestLoaderInstallerVerifiesDownload(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("tampered launcher"))
+	}))
+	defer server.Close()
+
+	installer := NewLoaderInstaller()
+	installer.downloader = cache.NewDownloader(&http.Client{Transport: &mirror.Transport{
+		Rewriter: mirror.NewRewriter([]config.MirrorRule{{Prefix: "https://meta.fabricmc.net/", Mirror: server.URL + "/"}}),
+	}}, t.TempDir())
+
+	opts := &ConversionOptions{
+		DestDir:         t.TempDir(),
+		MCVersion:       "1.21.1",
+		Loader:          sources.LoaderFabric,
+		LoaderVersion:   "0.16.9",
+		LoaderChecksums: &checksum.Checksums{SHA256: "0000000000000000000000000000000000000000000000000000000000000000"},
+	}
+	if err := installer.Install(context.Background(), opts); !errors.Is(err, checksum.ErrChecksumMismatch) {
+		t.Fatalf("Expected ErrChecksumMismatch, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(opts.DestDir, "fabric-server-launch.jar")); !os.IsNotExist(err) {
+		t.Error("Expected the unverified download not to be left in the server directory")
+	}
+}
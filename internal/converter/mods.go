@@ -1,33 +1,43 @@
 package converter
 
 import (
+	"context"
 	"fmt"
-	"io"
 	"net/http"
 	"os"
 	"path/filepath"
 	"sync"
 	"time"
 
+	"github.com/alexinslc/chunk/internal/cache"
 	"github.com/alexinslc/chunk/internal/checksum"
+	"github.com/alexinslc/chunk/internal/mirror"
 	"github.com/alexinslc/chunk/internal/sources"
 	"github.com/alexinslc/chunk/internal/ui"
+	"github.com/alexinslc/chunk/internal/validation"
 )
 
 type ModManager struct {
 	httpClient *http.Client
+	downloader *cache.Downloader
 	SkipVerify bool
+	// Cache, if set, is checked for a matching mod before downloading and
+	// receives every downloaded mod so other servers can share it
+	Cache *cache.Manager
 }
 
 func NewModManager() *ModManager {
+	httpClient := &http.Client{
+		Timeout:   5 * time.Minute,
+		Transport: &mirror.Transport{},
+	}
 	return &ModManager{
-		httpClient: &http.Client{
-			Timeout: 5 * time.Minute,
-		},
+		httpClient: httpClient,
+		downloader: cache.NewDefaultDownloader(httpClient),
 	}
 }
 
-func (m *ModManager) DownloadMods(mods []*sources.Mod, destDir string) error {
+func (m *ModManager) DownloadMods(ctx context.Context, mods []*sources.Mod, destDir string) error {
 	modsDir := filepath.Join(destDir, "mods")
 	if err := os.MkdirAll(modsDir, 0755); err != nil {
 		return fmt.Errorf("failed to create mods directory: %w", err)
@@ -39,7 +49,7 @@ func (m *ModManager) DownloadMods(mods []*sources.Mod, destDir string) error {
 		return nil
 	}
 
-	return m.downloadModsConcurrent(serverMods, modsDir)
+	return m.downloadModsConcurrent(ctx, serverMods, modsDir)
 }
 
 func (m *ModManager) FilterServerMods(mods []*sources.Mod) []*sources.Mod {
@@ -65,7 +75,7 @@ func (m *ModManager) isServerMod(mod *sources.Mod) bool {
 	}
 }
 
-func (m *ModManager) downloadModsConcurrent(mods []*sources.Mod, destDir string) error {
+func (m *ModManager) downloadModsConcurrent(ctx context.Context, mods []*sources.Mod, destDir string) error {
 	var wg sync.WaitGroup
 	errChan := make(chan error, len(mods))
 	semaphore := make(chan struct{}, 5)
@@ -80,7 +90,12 @@ func (m *ModManager) downloadModsConcurrent(mods []*sources.Mod, destDir string)
 			semaphore <- struct{}{}
 			defer func() { <-semaphore }()
 
-			if err := m.downloadMod(mod, destDir); err != nil {
+			// Do not start queued downloads once the install is cancelled
+			if ctx.Err() != nil {
+				return
+			}
+
+			if err := m.downloadMod(ctx, mod, destDir); err != nil {
 				errChan <- fmt.Errorf("failed to download %s: %w", mod.FileName, err)
 				return
 			}
@@ -93,6 +108,10 @@ func (m *ModManager) downloadModsConcurrent(mods []*sources.Mod, destDir string)
 	progressBar.Finish()
 	close(errChan)
 
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	for err := range errChan {
 		return err
 	}
@@ -100,20 +119,20 @@ func (m *ModManager) downloadModsConcurrent(mods []*sources.Mod, destDir string)
 	return nil
 }
 
-func (m *ModManager) downloadMod(mod *sources.Mod, destDir string) error {
+func (m *ModManager) downloadMod(ctx context.Context, mod *sources.Mod, destDir string) error {
 	if mod.DownloadURL == "" {
 		return fmt.Errorf("no download URL for mod: %s", mod.FileName)
 	}
 
+	// Whatever the source, a mod is only ever written into destDir
+	if !validation.IsValidFileName(mod.FileName) {
+		return fmt.Errorf("invalid mod file name %q: must be a bare file name", mod.FileName)
+	}
 	destPath := filepath.Join(destDir, mod.FileName)
 
 	// Check if file already exists and verify its checksum if available
 	if _, err := os.Stat(destPath); err == nil {
-		if !m.SkipVerify && (mod.SHA256 != "" || mod.SHA512 != "") {
-			checksums := &checksum.Checksums{
-				SHA256: mod.SHA256,
-				SHA512: mod.SHA512,
-			}
+		if checksums := modChecksums(mod); !m.SkipVerify && checksums.HasAny() {
 			if err := checksum.VerifyFile(destPath, checksums); err == nil {
 				// File exists and checksum matches, skip download
 				return nil
@@ -125,52 +144,35 @@ func (m *ModManager) downloadMod(mod *sources.Mod, destDir string) error {
 		}
 	}
 
-	resp, err := m.httpClient.Get(mod.DownloadURL)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("download failed: status %d", resp.StatusCode)
-	}
-
-	out, err := os.Create(destPath)
-	if err != nil {
-		return err
+	// Verify the complete file, including after a resumed download
+	var expected *checksum.Checksums
+	if checksums := modChecksums(mod); !m.SkipVerify && checksums.HasAny() {
+		expected = checksums
 	}
-	defer out.Close()
 
-	// Set up checksum verification during download if enabled and checksums are available
-	var reader io.Reader = resp.Body
-	var verifyResult *checksum.VerificationResult
-
-	if !m.SkipVerify && (mod.SHA256 != "" || mod.SHA512 != "") {
-		checksums := &checksum.Checksums{
-			SHA256: mod.SHA256,
-			SHA512: mod.SHA512,
+	if m.Cache != nil {
+		if blobPath := m.Cache.FindBlob(expected); blobPath != "" {
+			return m.Cache.LinkBlob(blobPath, destPath)
 		}
-		reader, verifyResult = checksum.VerifyReader(resp.Body, checksums)
 	}
 
-	if _, err := io.Copy(out, reader); err != nil {
-		// Clean up partial file on error (defer will handle closing)
-		os.Remove(destPath)
-		return fmt.Errorf("failed to write file: %w", err)
+	if err := m.downloader.Download(ctx, mod.DownloadURL, destPath, expected, nil); err != nil {
+		return err
 	}
 
-	// Complete checksum verification if it was set up
-	if verifyResult != nil {
-		if err := verifyResult.Verify(destPath); err != nil {
-			// Remove file with bad checksum (defer will handle closing)
-			os.Remove(destPath)
-			return fmt.Errorf("checksum verification failed for %s: %w", mod.FileName, err)
-		}
+	if m.Cache != nil {
+		// The shared store is an optimisation; the mod is installed either way
+		_, _ = m.Cache.StoreBlob(destPath)
 	}
 
 	return nil
 }
 
+// modChecksums returns the checksums a mod is verified against
+func modChecksums(mod *sources.Mod) *checksum.Checksums {
+	return &checksum.Checksums{SHA256: mod.SHA256, SHA512: mod.SHA512, SHA1: mod.SHA1}
+}
+
 func (m *ModManager) ResolveDependencies(mods []*sources.Mod) ([]*sources.Mod, error) {
 	return mods, nil
 }
@@ -1,12 +1,14 @@
 package converter
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"testing"
 
+	"github.com/alexinslc/chunk/internal/cache"
 	"github.com/alexinslc/chunk/internal/sources"
 )
 
@@ -78,6 +80,16 @@ func TestModManager_DownloadModWithChecksum(t *testing.T) {
 			skipVerify: false,
 			wantErr:    true,
 		},
+		{
+			name: "file name with a path",
+			mod: &sources.Mod{
+				Name:        "TestMod",
+				FileName:    "../start.sh",
+				DownloadURL: server.URL + "/mod.jar",
+			},
+			skipVerify: false,
+			wantErr:    true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -91,7 +103,7 @@ func TestModManager_DownloadModWithChecksum(t *testing.T) {
 			modManager := NewModManager()
 			modManager.SkipVerify = tt.skipVerify
 
-			err := modManager.downloadMod(tt.mod, testDir)
+			err := modManager.downloadMod(context.Background(), tt.mod, testDir)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("downloadMod() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -146,3 +158,49 @@ func TestModManager_SkipVerifyOption(t *testing.T) {
 		t.Error("SkipVerify should be true after setting")
 	}
 }
+
+func TestModManager_DownloadModUsesSharedCache(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	testContent := []byte("test mod content for checksum verification")
+	correctSHA256 := "541c932013bf9f85f22ee8e198d5d51fa7c2a031c25e54e7ba423b16ffed2c86"
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write(testContent)
+	}))
+	defer server.Close()
+
+	cacheManager, err := cache.NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create cache manager: %v", err)
+	}
+
+	mod := &sources.Mod{
+		Name:        "TestMod",
+		FileName:    "testmod.jar",
+		DownloadURL: server.URL + "/mod.jar",
+		SHA256:      correctSHA256,
+	}
+
+	// Two servers installing the same mod download it once
+	for _, serverName := range []string{"server-a", "server-b"} {
+		modManager := NewModManager()
+		modManager.Cache = cacheManager
+
+		destDir := filepath.Join(t.TempDir(), serverName)
+		if err := modManager.downloadMod(context.Background(), mod, destDir); err != nil {
+			t.Fatalf("downloadMod() for %s failed: %v", serverName, err)
+		}
+
+		data, err := os.ReadFile(filepath.Join(destDir, mod.FileName))
+		if err != nil || string(data) != string(testContent) {
+			t.Errorf("Expected %s to contain the mod, got %q, %v", serverName, data, err)
+		}
+	}
+
+	if requests != 1 {
+		t.Errorf("Expected 1 download, got %d", requests)
+	}
+}
@@ -4,10 +4,29 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
 
-	"github.com/alexinslc/chunk/internal/sources"
+	"github.com/alexinslc/chunk/internal/java"
 )
 
+// shellSafeRegexp matches arguments that need no quoting in start.sh
+var shellSafeRegexp = regexp.MustCompile(`^[A-Za-z0-9_@%+=:,./-]+$`)
+
+// jvmFlags are the G1 tuning flags (Aikar's flags) every launch passes after
+// the heap size
+var jvmFlags = []string{
+	"-XX:+UseG1GC", "-XX:+ParallelRefProcEnabled", "-XX:MaxGCPauseMillis=200",
+	"-XX:+UnlockExperimentalVMOptions", "-XX:+DisableExplicitGC", "-XX:+AlwaysPreTouch",
+	"-XX:G1NewSizePercent=30", "-XX:G1MaxNewSizePercent=40", "-XX:G1HeapRegionSize=8M",
+	"-XX:G1ReservePercent=20", "-XX:G1HeapWastePercent=5", "-XX:G1MixedGCCountTarget=4",
+	"-XX:InitiatingHeapOccupancyPercent=15", "-XX:G1MixedGCLiveThresholdPercent=90",
+	"-XX:G1RSetUpdatingPauseTimePercent=5", "-XX:SurvivorRatio=32", "-XX:+PerfDisableSharedMem",
+	"-XX:MaxTenuringThreshold=1", "-Dusing.aikars.flags=https://mcflags.emc.gs",
+	"-Daikars.new.flags=true",
+}
+
 type ScriptGenerator struct{}
 
 func NewScriptGenerator() *ScriptGenerator {
@@ -26,21 +45,39 @@ func (s *ScriptGenerator) Generate(opts *ConversionOptions) error {
 	return nil
 }
 
+// LaunchCommand returns the command line start.sh runs, java executable
+// first, so the server can be launched without going through the script
+func (s *ScriptGenerator) LaunchCommand(opts *ConversionOptions) ([]string, error) {
+	layout, err := s.launchLayout(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	ramMB := s.calculateRAM(opts)
+	command := []string{s.javaCommand(opts), fmt.Sprintf("-Xms%dM", ramMB/2), fmt.Sprintf("-Xmx%dM", ramMB)}
+	command = append(command, jvmFlags...)
+	command = append(command, opts.JVMArgs...)
+	command = append(command, layout.Args(false)...)
+	return append(command, "nogui"), nil
+}
+
+func (s *ScriptGenerator) launchLayout(opts *ConversionOptions) (*LaunchLayout, error) {
+	if opts.Launch != nil {
+		return opts.Launch, nil
+	}
+	return DetectLaunchLayout(opts.DestDir, opts.Loader, opts.LoaderVersion)
+}
+
 func (s *ScriptGenerator) generateStartScript(opts *ConversionOptions) error {
 	ramMB := s.calculateRAM(opts)
 
-	var jarFile string
-	switch opts.Loader {
-	case sources.LoaderForge:
-		jarFile = "forge-server.jar"
-	case sources.LoaderFabric:
-		jarFile = "fabric-server-launch.jar"
-	case sources.LoaderNeoForge:
-		jarFile = "neoforge-server.jar"
-	default:
-		jarFile = "server.jar"
+	layout, err := s.launchLayout(opts)
+	if err != nil {
+		return err
 	}
 
+	javaCmd := s.javaCommand(opts)
+
 	script := fmt.Sprintf(`#!/bin/bash
 # Start script for %s
 # Generated by Chunk
@@ -50,20 +87,14 @@ echo "Minecraft Version: %s"
 echo "Loader: %s"
 echo "Allocated RAM: %dMB"
 echo ""
-
-java -Xms%dM -Xmx%dM -XX:+UseG1GC -XX:+ParallelRefProcEnabled -XX:MaxGCPauseMillis=200 \
-  -XX:+UnlockExperimentalVMOptions -XX:+DisableExplicitGC -XX:+AlwaysPreTouch \
-  -XX:G1NewSizePercent=30 -XX:G1MaxNewSizePercent=40 -XX:G1HeapRegionSize=8M \
-  -XX:G1ReservePercent=20 -XX:G1HeapWastePercent=5 -XX:G1MixedGCCountTarget=4 \
-  -XX:InitiatingHeapOccupancyPercent=15 -XX:G1MixedGCLiveThresholdPercent=90 \
-  -XX:G1RSetUpdatingPauseTimePercent=5 -XX:SurvivorRatio=32 -XX:+PerfDisableSharedMem \
-  -XX:MaxTenuringThreshold=1 -Dusing.aikars.flags=https://mcflags.emc.gs \
-  -Daikars.new.flags=true \
-  -jar %s nogui
+%s
+%s -Xms%dM -Xmx%dM %s%s \
+  %s nogui
 
 echo ""
 echo "Server stopped."
-`, opts.ModpackName, opts.ModpackName, opts.MCVersion, opts.Loader, ramMB, ramMB/2, ramMB, jarFile)
+`, opts.ModpackName, opts.ModpackName, opts.MCVersion, opts.Loader, ramMB,
+		shellEnv(opts.Env), shellQuote(javaCmd), ramMB/2, ramMB, shellFlags(jvmFlags), shellArgs(opts.JVMArgs), layout.JavaArgs(false))
 
 	scriptPath := filepath.Join(opts.DestDir, "start.sh")
 	if err := os.WriteFile(scriptPath, []byte(script), 0755); err != nil {
@@ -79,13 +110,14 @@ echo Minecraft Version: %s
 echo Loader: %s
 echo Allocated RAM: %dMB
 echo.
-
-java -Xms%dM -Xmx%dM -XX:+UseG1GC -XX:+ParallelRefProcEnabled -XX:MaxGCPauseMillis=200 -XX:+UnlockExperimentalVMOptions -XX:+DisableExplicitGC -XX:+AlwaysPreTouch -XX:G1NewSizePercent=30 -XX:G1MaxNewSizePercent=40 -XX:G1HeapRegionSize=8M -XX:G1ReservePercent=20 -XX:G1HeapWastePercent=5 -XX:G1MixedGCCountTarget=4 -XX:InitiatingHeapOccupancyPercent=15 -XX:G1MixedGCLiveThresholdPercent=90 -XX:G1RSetUpdatingPauseTimePercent=5 -XX:SurvivorRatio=32 -XX:+PerfDisableSharedMem -XX:MaxTenuringThreshold=1 -Dusing.aikars.flags=https://mcflags.emc.gs -Daikars.new.flags=true -jar %s nogui
+%s
+%s -Xms%dM -Xmx%dM %s%s %s nogui
 
 echo.
 echo Server stopped.
 pause
-`, opts.ModpackName, opts.ModpackName, opts.MCVersion, opts.Loader, ramMB, ramMB/2, ramMB, jarFile)
+`, opts.ModpackName, opts.ModpackName, opts.MCVersion, opts.Loader, ramMB,
+		batchEnv(opts.Env), batchQuote(javaCmd), ramMB/2, ramMB, strings.Join(jvmFlags, " "), batchArgs(opts.JVMArgs), layout.JavaArgs(true))
 
 	batPath := filepath.Join(opts.DestDir, "start.bat")
 	return os.WriteFile(batPath, []byte(batScript), 0755)
@@ -112,3 +144,85 @@ func (s *ScriptGenerator) calculateRAM(opts *ConversionOptions) int {
 
 	return 4096
 }
+
+// javaCommand returns the java executable for the start scripts. When the
+// pack bounds the Java version, a matching installation is used if one is
+// found; otherwise the scripts rely on java from PATH.
+func (s *ScriptGenerator) javaCommand(opts *ConversionOptions) string {
+	if opts.MinJava == 0 && opts.MaxJava == 0 {
+		return "java"
+	}
+	install, err := java.NewJavaDetector().FindInRange(opts.MCVersion, opts.MinJava, opts.MaxJava)
+	if err != nil {
+		return "java"
+	}
+	return install.Path
+}
+
+// sortedEnv returns the names of env in a stable order
+func sortedEnv(env map[string]string) []string {
+	names := make([]string, 0, len(env))
+	for name := range env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func shellEnv(env map[string]string) string {
+	var b strings.Builder
+	for _, name := range sortedEnv(env) {
+		fmt.Fprintf(&b, "export %s=%s\n", name, shellQuote(env[name]))
+	}
+	return b.String()
+}
+
+func batchEnv(env map[string]string) string {
+	var b strings.Builder
+	for _, name := range sortedEnv(env) {
+		fmt.Fprintf(&b, "set \"%s=%s\"\n", name, strings.ReplaceAll(env[name], "%", "%%"))
+	}
+	return b.String()
+}
+
+// shellFlags formats flags for start.sh, three to a line
+func shellFlags(flags []string) string {
+	var lines []string
+	for i := 0; i < len(flags); i += 3 {
+		lines = append(lines, strings.Join(flags[i:min(i+3, len(flags))], " "))
+	}
+	return strings.Join(lines, " \\\n  ")
+}
+
+// shellArgs formats extra JVM arguments for start.sh, each preceded by a space
+func shellArgs(args []string) string {
+	var b strings.Builder
+	for _, arg := range args {
+		b.WriteString(" " + shellQuote(arg))
+	}
+	return b.String()
+}
+
+// batchArgs formats extra JVM arguments for start.bat, each preceded by a space
+func batchArgs(args []string) string {
+	var b strings.Builder
+	for _, arg := range args {
+		b.WriteString(" " + batchQuote(arg))
+	}
+	return b.String()
+}
+
+func shellQuote(s string) string {
+	if shellSafeRegexp.MatchString(s) {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func batchQuote(s string) string {
+	s = strings.ReplaceAll(s, "%", "%%")
+	if s != "" && !strings.ContainsAny(s, " \t&|<>^()\"") {
+		return s
+	}
+	return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+}
@@ -0,0 +1,114 @@
+package converter
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alexinslc/chunk/internal/sources"
+)
+
+func TestGenerateStartScriptLaunchSettings(t *testing.T) {
+	serverDir := t.TempDir()
+	opts := &ConversionOptions{
+		DestDir:     serverDir,
+		ModpackName: "Lite Pack",
+		MCVersion:   "1.20.1",
+		Loader:      sources.LoaderFabric,
+		Launch:      &LaunchLayout{Kind: LaunchJar, Jar: "fabric-server-launch.jar"},
+		JVMArgs:     []string{"-Dfml.readTimeout=180", "-Dmotd=Hello World"},
+		Env:         map[string]string{"TZ": "UTC", "GREETING": "it's 100%"},
+	}
+
+	if err := NewScriptGenerator().Generate(opts); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	script, err := os.ReadFile(filepath.Join(serverDir, "start.sh"))
+	if err != nil {
+		t.Fatalf("Failed to read start.sh: %v", err)
+	}
+	for _, want := range []string{
+		"export GREETING='it'\\''s 100%'\nexport TZ=UTC\n",
+		"-Daikars.new.flags=true -Dfml.readTimeout=180 '-Dmotd=Hello World' \\\n  -jar fabric-server-launch.jar nogui",
+	} {
+		if !strings.Contains(string(script), want) {
+			t.Errorf("Expected start.sh to contain %q, got:\n%s", want, script)
+		}
+	}
+
+	bat, err := os.ReadFile(filepath.Join(serverDir, "start.bat"))
+	if err != nil {
+		t.Fatalf("Failed to read start.bat: %v", err)
+	}
+	for _, want := range []string{
+		`set "GREETING=it's 100%%"`,
+		`set "TZ=UTC"`,
+		`-Daikars.new.flags=true -Dfml.readTimeout=180 "-Dmotd=Hello World" -jar fabric-server-launch.jar nogui`,
+	} {
+		if !strings.Contains(string(bat), want) {
+			t.Errorf("Expected start.bat to contain %q, got:\n%s", want, bat)
+		}
+	}
+}
+
+func TestGenerateStartScriptDefaults(t *testing.T) {
+	serverDir := t.TempDir()
+	opts := &ConversionOptions{
+		DestDir:     serverDir,
+		ModpackName: "Plain Pack",
+		MCVersion:   "1.20.1",
+		Loader:      sources.LoaderFabric,
+		Launch:      &LaunchLayout{Kind: LaunchJar, Jar: "fabric-server-launch.jar"},
+	}
+
+	if err := NewScriptGenerator().Generate(opts); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	script, err := os.ReadFile(filepath.Join(serverDir, "start.sh"))
+	if err != nil {
+		t.Fatalf("Failed to read start.sh: %v", err)
+	}
+	if strings.Contains(string(script), "export ") {
+		t.Errorf("Expected no exports without env, got:\n%s", script)
+	}
+	if !strings.Contains(string(script), "echo \"\"\n\njava -Xms2048M -Xmx4096M") {
+		t.Errorf("Expected the default java command, got:\n%s", script)
+	}
+	for _, flag := range jvmFlags {
+		if !strings.Contains(string(script), " "+flag+" ") {
+			t.Errorf("Expected start.sh to pass %s, got:\n%s", flag, script)
+		}
+	}
+}
+
+func TestLaunchCommand(t *testing.T) {
+	opts := &ConversionOptions{
+		DestDir:        t.TempDir(),
+		MCVersion:      "1.20.1",
+		Loader:         sources.LoaderForge,
+		RecommendedRAM: 8,
+		Launch: &LaunchLayout{
+			Kind:        LaunchArgsFile,
+			ArgsFile:    "libraries/net/minecraftforge/forge/1.20.1-47.2.0/unix_args.txt",
+			UserJVMArgs: true,
+		},
+		JVMArgs: []string{"-Dmotd=Hello World"},
+	}
+
+	command, err := NewScriptGenerator().LaunchCommand(opts)
+	if err != nil {
+		t.Fatalf("LaunchCommand failed: %v", err)
+	}
+
+	if command[0] != "java" || command[1] != "-Xms4096M" || command[2] != "-Xmx8192M" {
+		t.Errorf("Expected java with an 8 GB heap, got %v", command[:3])
+	}
+	tail := strings.Join(command[len(command)-4:], " ")
+	want := "-Dmotd=Hello World @user_jvm_args.txt @libraries/net/minecraftforge/forge/1.20.1-47.2.0/unix_args.txt nogui"
+	if tail != want {
+		t.Errorf("Expected command to end with %q, got %q", want, tail)
+	}
+}
@@ -0,0 +1,115 @@
+package converter
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// stopGrace is how much longer than the server's stop timeout systemd waits
+// for chunk stop, covering the SIGTERM and SIGKILL steps that follow it
+const stopGrace = 15 * time.Second
+
+// ServiceOptions configures the systemd unit of an installation
+type ServiceOptions struct {
+	// Name is the instance name; the unit is chunk-<name>.service
+	Name      string
+	ServerDir string
+	// ChunkPath is the absolute path of the chunk executable
+	ChunkPath string
+	// User is the account the server runs as. It is left out of user units,
+	// which always run as their owner.
+	User     string
+	UserUnit bool
+	// RecommendedRAM is the pack's RAM setting in GB; the memory limits are
+	// derived from the heap size it gives
+	RecommendedRAM int
+	// StopTimeout is how long the server gets to stop before it is terminated
+	StopTimeout time.Duration
+}
+
+// UnitName returns the file name of the systemd unit of an instance
+func UnitName(name string) string {
+	return "chunk-" + name + ".service"
+}
+
+// GenerateSystemdUnit returns a hardened systemd unit that runs the server
+// under the chunk supervisor. systemd restarts it when it crashes, and
+// stopping the unit sends the server the stop command.
+func (s *ScriptGenerator) GenerateSystemdUnit(opts *ServiceOptions) string {
+	heapMB := s.calculateRAM(&ConversionOptions{RecommendedRAM: opts.RecommendedRAM})
+	stopTimeout := opts.StopTimeout
+	dir := systemdQuote(opts.ServerDir)
+	chunk := systemdQuote(opts.ChunkPath)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `# systemd unit for %s
+# Generated by Chunk
+
+[Unit]
+Description=Minecraft server %s (chunk)
+After=network-online.target
+Wants=network-online.target
+StartLimitIntervalSec=600
+StartLimitBurst=5
+
+[Service]
+Type=simple
+`, opts.Name, opts.Name)
+	if !opts.UserUnit && opts.User != "" {
+		fmt.Fprintf(&b, "User=%s\n", opts.User)
+	}
+	fmt.Fprintf(&b, `WorkingDirectory=%s
+ExecStart=%s start --foreground --dir %s --stop-timeout %s
+ExecStop=%s stop --dir %s
+Restart=on-failure
+RestartSec=10
+TimeoutStopSec=%d
+
+# The JVM needs memory beyond its %d MB heap for metaspace, threads and
+# native buffers
+MemoryHigh=%dM
+MemoryMax=%dM
+LimitNOFILE=65536
+
+NoNewPrivileges=true
+PrivateTmp=true
+ProtectSystem=strict
+ProtectHome=read-only
+ReadWritePaths=%s
+ProtectKernelTunables=true
+ProtectKernelModules=true
+ProtectControlGroups=true
+RestrictSUIDSGID=true
+RestrictRealtime=true
+LockPersonality=true
+
+[Install]
+`, systemdEscape(opts.ServerDir), chunk, dir, stopTimeout, chunk, dir,
+		int(math.Ceil((stopTimeout + stopGrace).Seconds())),
+		heapMB, heapMB*5/4, heapMB*3/2, dir)
+	if opts.UserUnit {
+		b.WriteString("WantedBy=default.target\n")
+	} else {
+		b.WriteString("WantedBy=multi-user.target\n")
+	}
+
+	return b.String()
+}
+
+// systemdEscape escapes the % specifier prefix
+func systemdEscape(s string) string {
+	return strings.ReplaceAll(s, "%", "%%")
+}
+
+// systemdQuote escapes and, if needed, quotes a path for ExecStart= and
+// other settings that split on whitespace
+func systemdQuote(s string) string {
+	s = systemdEscape(s)
+	if !strings.ContainsAny(s, " \t\"'\\") {
+		return s
+	}
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
@@ -0,0 +1,56 @@
+package converter
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGenerateSystemdUnit(t *testing.T) {
+	generator := NewScriptGenerator()
+	opts := &ServiceOptions{
+		Name:           "survival-eu",
+		ServerDir:      "/srv/minecraft/survival eu",
+		ChunkPath:      "/usr/local/bin/chunk",
+		User:           "minecraft",
+		RecommendedRAM: 8,
+		StopTimeout:    60 * time.Second,
+	}
+
+	unit := generator.GenerateSystemdUnit(opts)
+	for _, want := range []string{
+		"User=minecraft\n",
+		"WorkingDirectory=/srv/minecraft/survival eu\n",
+		`ExecStart=/usr/local/bin/chunk start --foreground --dir "/srv/minecraft/survival eu" --stop-timeout 1m0s` + "\n",
+		`ExecStop=/usr/local/bin/chunk stop --dir "/srv/minecraft/survival eu"` + "\n",
+		"Restart=on-failure\n",
+		"TimeoutStopSec=75\n",
+		"MemoryHigh=10240M\n",
+		"MemoryMax=12288M\n",
+		"ProtectSystem=strict\n",
+		`ReadWritePaths="/srv/minecraft/survival eu"` + "\n",
+		"WantedBy=multi-user.target\n",
+	} {
+		if !strings.Contains(unit, want) {
+			t.Errorf("Expected unit to contain %q, got:\n%s", want, unit)
+		}
+	}
+
+	// User units run as their owner and start with the user's session
+	opts.UserUnit = true
+	opts.RecommendedRAM = 0
+	opts.ServerDir = "/home/alex/100%"
+	unit = generator.GenerateSystemdUnit(opts)
+	for _, want := range []string{
+		"WorkingDirectory=/home/alex/100%%\n",
+		"MemoryMax=6144M\n",
+		"WantedBy=default.target\n",
+	} {
+		if !strings.Contains(unit, want) {
+			t.Errorf("Expected user unit to contain %q, got:\n%s", want, unit)
+		}
+	}
+	if strings.Contains(unit, "User=") {
+		t.Errorf("Expected no User= in a user unit, got:\n%s", unit)
+	}
+}
@@ -0,0 +1,613 @@
+package install
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/alexinslc/chunk/internal/cache"
+	"github.com/alexinslc/chunk/internal/checksum"
+	"github.com/alexinslc/chunk/internal/config"
+	"github.com/alexinslc/chunk/internal/converter"
+	"github.com/alexinslc/chunk/internal/lockfile"
+	"github.com/alexinslc/chunk/internal/sources"
+	"github.com/alexinslc/chunk/internal/tracking"
+	"github.com/alexinslc/chunk/internal/ui"
+)
+
+// BundleOptions configures a bundle export
+type BundleOptions struct {
+	// Identifier is the modpack to export when Installation is nil
+	Identifier string
+	// Installation, if set, is exported exactly as its chunk.lock pins it
+	Installation *tracking.Installation
+	// Variant is the recipe variant to export when Installation is nil
+	Variant string
+	// Output is the bundle file to write
+	Output string
+}
+
+// BundleResult describes a written bundle
+type BundleResult struct {
+	Path          string
+	ModpackName   string
+	MCVersion     string
+	Loader        sources.LoaderType
+	LoaderVersion string
+	Mods          int
+	Size          int64
+}
+
+// ExportBundle writes a self-contained server bundle: the pack archive, every
+// server mod, the loader installer or jar together with the files the
+// installer produces, the recipe snapshot and a checksum manifest. Installing
+// the bundle needs no network access, so everything is fetched here.
+func (i *Installer) ExportBundle(ctx context.Context, opts *BundleOptions) (*BundleResult, error) {
+	workDir, err := os.MkdirTemp("", "chunk-bundle-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create work directory: %w", err)
+	}
+	defer os.RemoveAll(workDir)
+
+	var modpack *sources.Modpack
+	var pack *sources.BundleSource
+	var packURL string
+	serverDir := ""
+
+	if opts.Installation != nil {
+		serverDir = opts.Installation.Path
+		modpack, pack, packURL, err = i.bundleInstallation(ctx, opts.Installation, workDir)
+	} else {
+		i.variant = opts.Variant
+		modpack, pack, packURL, err = i.bundleModpack(ctx, opts.Identifier, workDir)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &sources.BundleManifest{
+		Name:           modpack.Name,
+		Identifier:     modpack.Identifier,
+		Description:    modpack.Description,
+		Author:         modpack.Author,
+		Source:         modpack.Source,
+		MCVersion:      modpack.MCVersion,
+		Loader:         modpack.Loader,
+		LoaderVersion:  modpack.LoaderVersion,
+		RecommendedRAM: modpack.RecommendedRAM,
+		JVMArgs:        modpack.JVMArgs,
+		Env:            modpack.Env,
+		MinJava:        modpack.MinJava,
+		MaxJava:        modpack.MaxJava,
+		Variant:        modpack.Variant,
+		CreatedAt:      time.Now().UTC(),
+	}
+	if manifest.Identifier == "" {
+		manifest.Identifier = opts.Identifier
+	}
+
+	var files []sources.BundleSource
+	if pack != nil {
+		files = append(files, *pack)
+		manifest.Pack = &sources.BundleEntry{Path: pack.Path, URL: packURL}
+	}
+
+	spinner := ui.NewSpinner(fmt.Sprintf("Installing %s loader for the bundle...", modpack.Loader))
+	spinner.Start()
+	loaderFiles, loaderEntry, err := i.bundleLoader(ctx, modpack, filepath.Join(workDir, "loader"))
+	if err != nil {
+		spinner.Error(fmt.Sprintf("Failed to install loader: %v", err))
+		return nil, fmt.Errorf("failed to install mod loader: %w", err)
+	}
+	spinner.Success(fmt.Sprintf("%s loader ready", modpack.Loader))
+	manifest.LoaderFile = *loaderEntry
+
+	serverFiles, serverEntries, err := i.bundleServerFiles(ctx, modpack.ServerFiles, workDir, serverDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect server files: %w", err)
+	}
+	manifest.ServerFiles = serverEntries
+
+	// A server file replaces the loader file at the same path on install,
+	// so only the server file is bundled
+	overridden := make(map[string]bool, len(serverEntries))
+	for _, entry := range serverEntries {
+		overridden[entry.Path] = true
+	}
+	for _, file := range loaderFiles {
+		if !overridden[file.Path] {
+			files = append(files, file)
+		}
+	}
+	files = append(files, serverFiles...)
+
+	modFiles, mods, err := i.bundleMods(ctx, modpack, workDir, serverDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect mods: %w", err)
+	}
+	files = append(files, modFiles...)
+	manifest.Mods = mods
+
+	spinner = ui.NewSpinner(fmt.Sprintf("Writing %s...", opts.Output))
+	spinner.Start()
+	if err := sources.WriteBundle(opts.Output, manifest, createRecipeSnapshot(modpack), files); err != nil {
+		spinner.Error(fmt.Sprintf("Failed to write bundle: %v", err))
+		return nil, err
+	}
+	spinner.Success("Bundle written")
+
+	info, err := os.Stat(opts.Output)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bundle: %w", err)
+	}
+
+	return &BundleResult{
+		Path:          opts.Output,
+		ModpackName:   modpack.Name,
+		MCVersion:     modpack.MCVersion,
+		Loader:        modpack.Loader,
+		LoaderVersion: modpack.LoaderVersion,
+		Mods:          len(mods),
+		Size:          info.Size(),
+	}, nil
+}
+
+// bundleInstallation reads what an installation's chunk.lock pins, so the
+// bundle reproduces the installed server rather than the latest recipe
+func (i *Installer) bundleInstallation(ctx context.Context, installation *tracking.Installation, workDir string) (*sources.Modpack, *sources.BundleSource, string, error) {
+	lock, err := lockfile.Load(lockfile.Path(installation.Path))
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("cannot export %s without its lock file; reinstall it first: %w", installation.Path, err)
+	}
+	if installation.RecipeSnapshot == nil {
+		return nil, nil, "", fmt.Errorf("installation at %s has no recorded recipe; reinstall it first", installation.Path)
+	}
+
+	modpack, err := modpackFromSnapshot(installation.RecipeSnapshot)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	if modpack.Identifier == "" {
+		modpack.Identifier = installation.Slug
+	}
+	if modpack.Variant == "" {
+		modpack.Variant = installation.Variant
+	}
+	modpack.MCVersion = lock.MCVersion
+	modpack.Loader = sources.LoaderType(lock.Loader)
+	modpack.LoaderVersion = lock.LoaderVersion
+	if err := applyLockedMods(lock, modpack); err != nil {
+		return nil, nil, "", err
+	}
+	for _, file := range lock.ByKind(lockfile.KindFile) {
+		modpack.ServerFiles = append(modpack.ServerFiles, &sources.ServerFile{
+			Path:        file.Name,
+			DownloadURL: file.URL,
+			SHA256:      file.SHA256,
+			SHA512:      file.SHA512,
+		})
+	}
+	// The snapshot leaves out the launch settings; the installed manifest has them
+	if installed, err := config.LoadChunkManifest(filepath.Join(installation.Path, config.ChunkManifestFile)); err == nil {
+		modpack.JVMArgs = installed.JVMArgs
+		modpack.Env = installed.Env
+		modpack.MinJava = installed.MinJava
+		modpack.MaxJava = installed.MaxJava
+	}
+	// The loader is checked against the lock as in a frozen install
+	i.frozenLock = lock
+
+	packs := lock.ByKind(lockfile.KindPack)
+	if len(packs) == 0 {
+		return modpack, nil, "", nil
+	}
+	locked := packs[0]
+
+	// Local pack archives are locked by their file:// URL
+	if strings.HasPrefix(locked.URL, "file://") {
+		packPath := filepath.FromSlash(strings.TrimPrefix(locked.URL, "file://"))
+		if err := locked.Match(packPath); err != nil {
+			return nil, nil, "", fmt.Errorf("pack archive changed since install: %w", err)
+		}
+		return modpack, &sources.BundleSource{Path: sources.BundlePackDir + filepath.Base(packPath), FilePath: packPath}, locked.URL, nil
+	}
+
+	// Recipe archives are installed as modpack.mrpack whatever their URL
+	packPath := filepath.Join(workDir, "modpack.mrpack")
+	ui.PrintInfo(fmt.Sprintf("Downloading pack archive from: %s", locked.URL))
+	if err := cache.NewDefaultDownloader(i.httpClient).Download(ctx, locked.URL, packPath, locked.Checksums(), nil); err != nil {
+		return nil, nil, "", fmt.Errorf("failed to download pack archive: %w", err)
+	}
+
+	return modpack, &sources.BundleSource{Path: sources.BundlePackDir + "modpack.mrpack", FilePath: packPath}, locked.URL, nil
+}
+
+// bundleModpack resolves a modpack the way chunk install would
+func (i *Installer) bundleModpack(ctx context.Context, identifier, workDir string) (*sources.Modpack, *sources.BundleSource, string, error) {
+	sourceType := sources.DetectSource(identifier)
+	if sourceType == "bundle" {
+		return nil, nil, "", fmt.Errorf("%s is already a bundle", identifier)
+	}
+
+	modpack, err := i.fetchModpack(ctx, identifier)
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("failed to fetch modpack: %w", err)
+	}
+	ui.PrintSuccess(fmt.Sprintf("Found modpack: %s", modpack.Name))
+
+	var pack *sources.BundleSource
+	packURL := ""
+
+	switch sourceType {
+	case "recipe":
+		// Recipes that only list files have no archive
+		if modpack.ManifestURL == "" {
+			break
+		}
+
+		archivePath, cleanup, err := i.recipeArchive(ctx, identifier, modpack)
+		if err != nil {
+			return nil, nil, "", fmt.Errorf("failed to download modpack: %w", err)
+		}
+		// Keep the archive even when it is a temporary download
+		packPath := filepath.Join(workDir, "modpack.mrpack")
+		err = copyFile(archivePath, packPath)
+		cleanup()
+		if err != nil {
+			return nil, nil, "", err
+		}
+
+		archivePack, err := sources.NewLocalClient().ParseArchive(ctx, packPath)
+		if err != nil {
+			return nil, nil, "", fmt.Errorf("failed to read modpack manifest: %w", err)
+		}
+		modpack.Mods = mergeMods(archivePack.Mods, modpack.Mods)
+		pack = &sources.BundleSource{Path: sources.BundlePackDir + "modpack.mrpack", FilePath: packPath}
+		packURL = modpack.ManifestURL
+	case "local":
+		absPath, err := filepath.Abs(identifier)
+		if err != nil {
+			return nil, nil, "", fmt.Errorf("failed to resolve modpack path: %w", err)
+		}
+		pack = &sources.BundleSource{Path: sources.BundlePackDir + filepath.Base(absPath), FilePath: absPath}
+		packURL = "file://" + filepath.ToSlash(absPath)
+	}
+
+	if err := resolveLoaderVersion(ctx, modpack); err != nil {
+		return nil, nil, "", err
+	}
+
+	return modpack, pack, packURL, nil
+}
+
+// bundleLoader installs the loader into an empty directory and collects the
+// result: the installer or jar itself, and every file the installer wrote,
+// which an offline install copies instead of running the installer
+func (i *Installer) bundleLoader(ctx context.Context, modpack *sources.Modpack, loaderDir string) ([]sources.BundleSource, *sources.BundleEntry, error) {
+	if err := os.MkdirAll(loaderDir, 0755); err != nil {
+		return nil, nil, fmt.Errorf("failed to create loader directory: %w", err)
+	}
+
+	opts := &converter.ConversionOptions{
+		DestDir:       loaderDir,
+		ModpackName:   modpack.Name,
+		MCVersion:     modpack.MCVersion,
+		Loader:        modpack.Loader,
+		LoaderVersion: modpack.LoaderVersion,
+		MinJava:       modpack.MinJava,
+		MaxJava:       modpack.MaxJava,
+	}
+
+	loaderInstaller := converter.NewLoaderInstaller()
+	artifact, err := loaderInstaller.Artifact(ctx, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if i.frozenLock != nil {
+		locked := i.frozenLock.Find(lockfile.KindLoader, string(modpack.Loader))
+		if locked == nil {
+			return nil, nil, fmt.Errorf("%w: %s loader is not in %s", lockfile.ErrDeviation, modpack.Loader, lockfile.FileName)
+		}
+		if locked.URL != artifact.URL {
+			return nil, nil, lockfile.Deviation("loader url", artifact.URL, locked.URL)
+		}
+	}
+
+	if err := loaderInstaller.Install(ctx, opts); err != nil {
+		return nil, nil, err
+	}
+
+	artifactPath := filepath.Join(loaderDir, artifact.FileName)
+	if i.frozenLock != nil {
+		if err := i.frozenLock.Find(lockfile.KindLoader, string(modpack.Loader)).Match(artifactPath); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	entry := &sources.BundleEntry{Path: sources.BundleLoaderDir + artifact.FileName, URL: artifact.URL}
+	files := []sources.BundleSource{{Path: entry.Path, FilePath: artifactPath}}
+
+	err = filepath.WalkDir(loaderDir, func(filePath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(loaderDir, filePath)
+		if err != nil {
+			return err
+		}
+		// Installer logs describe this machine, not the server
+		if d.IsDir() {
+			if rel == "logs" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if rel == artifact.FileName || strings.HasSuffix(rel, ".log") {
+			return nil
+		}
+		files = append(files, sources.BundleSource{Path: sources.BundleServerDir + filepath.ToSlash(rel), FilePath: filePath})
+		return nil
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to collect loader files: %w", err)
+	}
+
+	return files, entry, nil
+}
+
+// bundleMods collects every server mod. Mods in serverDir are used as they
+// are when they match their checksums; the rest are downloaded.
+func (i *Installer) bundleMods(ctx context.Context, modpack *sources.Modpack, workDir, serverDir string) ([]sources.BundleSource, []sources.BundleMod, error) {
+	modManager := converter.NewModManager()
+	if cacheManager, err := cache.NewManager(); err == nil {
+		modManager.Cache = cacheManager
+	}
+	serverMods := modManager.FilterServerMods(modpack.Mods)
+
+	var files []sources.BundleSource
+	var mods []sources.BundleMod
+	var missing []*sources.Mod
+
+	for _, mod := range serverMods {
+		entry := sources.BundleModsDir + filepath.ToSlash(mod.FileName)
+		mods = append(mods, sources.BundleMod{
+			BundleEntry: sources.BundleEntry{Path: entry, URL: mod.DownloadURL},
+			FileName:    mod.FileName,
+			Name:        mod.Name,
+			Version:     mod.Version,
+			Side:        mod.Side,
+		})
+
+		installed := filepath.Join(serverDir, "mods", mod.FileName)
+		sums := &checksum.Checksums{SHA256: mod.SHA256, SHA512: mod.SHA512, SHA1: mod.SHA1}
+		if serverDir != "" && sums.HasAny() && checksum.VerifyFile(installed, sums) == nil {
+			files = append(files, sources.BundleSource{Path: entry, FilePath: installed})
+			continue
+		}
+
+		missing = append(missing, mod)
+		files = append(files, sources.BundleSource{Path: entry, FilePath: filepath.Join(workDir, "mods", mod.FileName)})
+	}
+
+	if len(missing) > 0 {
+		ui.PrintInfo(fmt.Sprintf("Downloading %d mods...", len(missing)))
+		if err := modManager.DownloadMods(ctx, missing, workDir); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return files, mods, nil
+}
+
+// bundleServerFiles collects the recipe's server files under server/. Files
+// in serverDir are used as they are when they match their checksums; the
+// rest are downloaded and verified.
+func (i *Installer) bundleServerFiles(ctx context.Context, serverFiles []*sources.ServerFile, workDir, serverDir string) ([]sources.BundleSource, []sources.BundleEntry, error) {
+	var files []sources.BundleSource
+	var entries []sources.BundleEntry
+	recipeClient := sources.NewRecipeClient()
+
+	for _, file := range serverFiles {
+		relPath, err := serverFilePath(file.Path)
+		if err != nil {
+			return nil, nil, err
+		}
+		sums := &checksum.Checksums{SHA256: file.SHA256, SHA512: file.SHA512}
+		if !sums.HasAny() {
+			return nil, nil, fmt.Errorf("server file %s has no checksum", file.Path)
+		}
+
+		entry := sources.BundleServerDir + filepath.ToSlash(relPath)
+		entries = append(entries, sources.BundleEntry{Path: entry, URL: file.DownloadURL})
+
+		installed := filepath.Join(serverDir, relPath)
+		if serverDir != "" && checksum.VerifyFile(installed, sums) == nil {
+			files = append(files, sources.BundleSource{Path: entry, FilePath: installed})
+			continue
+		}
+
+		filePath := filepath.Join(workDir, "server", relPath)
+		if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+			return nil, nil, fmt.Errorf("failed to create directory for %s: %w", file.Path, err)
+		}
+		ui.PrintInfo(fmt.Sprintf("Downloading server file %s", file.Path))
+		if err := recipeClient.DownloadToFile(ctx, file.DownloadURL, filePath, sums, nil); err != nil {
+			return nil, nil, fmt.Errorf("failed to download %s: %w", file.Path, err)
+		}
+		files = append(files, sources.BundleSource{Path: entry, FilePath: filePath})
+	}
+
+	return files, entries, nil
+}
+
+// openBundle opens and verifies the bundle being installed
+func (i *Installer) openBundle(bundlePath string) error {
+	bundle, err := sources.OpenBundle(bundlePath)
+	if err != nil {
+		return err
+	}
+	if err := bundle.Verify(); err != nil {
+		bundle.Close()
+		return err
+	}
+	i.bundle = bundle
+	return nil
+}
+
+// extractBundlePack extracts the bundled pack archive over the server root
+func (i *Installer) extractBundlePack(modpack *sources.Modpack, destDir string) error {
+	pack := i.bundle.Manifest.Pack
+	if pack == nil {
+		return nil
+	}
+
+	tmpDir, err := os.MkdirTemp("", "chunk-bundle-pack-")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	// The archive keeps its name; extraction goes by its extension
+	packPath := filepath.Join(tmpDir, path.Base(pack.Path))
+	if err := i.bundle.ExtractFile(pack.Path, packPath); err != nil {
+		return err
+	}
+
+	if err := i.lockArtifact(lockfile.KindPack, modpack.Identifier, pack.URL, packPath, ""); err != nil {
+		return err
+	}
+
+	return sources.ExtractArchive(packPath, destDir)
+}
+
+// installBundleLoader copies the bundled loader into place; the installer
+// already ran when the bundle was exported. Bundled server files come along
+// and are placed again after the server is configured.
+func (i *Installer) installBundleLoader(modpack *sources.Modpack, destDir string) error {
+	if err := i.bundle.ExtractDir(sources.BundleServerDir, destDir); err != nil {
+		return err
+	}
+
+	loaderFile := i.bundle.Manifest.LoaderFile
+	fileName := path.Base(loaderFile.Path)
+	filePath := filepath.Join(destDir, fileName)
+	if err := i.bundle.ExtractFile(loaderFile.Path, filePath); err != nil {
+		return err
+	}
+
+	if _, err := converter.DetectLaunchLayout(destDir, modpack.Loader, modpack.LoaderVersion); err != nil {
+		return err
+	}
+
+	return i.lockArtifact(lockfile.KindLoader, string(modpack.Loader), loaderFile.URL, filePath, fileName)
+}
+
+// installBundleMods copies the bundled server mods into mods/
+func (i *Installer) installBundleMods(destDir string) (int, error) {
+	if err := i.bundle.ExtractDir(sources.BundleModsDir, filepath.Join(destDir, "mods")); err != nil {
+		return 0, err
+	}
+
+	for _, mod := range i.bundle.Manifest.Mods {
+		relPath := filepath.Join("mods", mod.FileName)
+		if err := i.lockArtifact(lockfile.KindMod, mod.FileName, mod.URL, filepath.Join(destDir, relPath), relPath); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(i.bundle.Manifest.Mods), nil
+}
+
+// snapshotModpack is the part of a tracked recipe snapshot a bundle is built from
+type snapshotModpack struct {
+	Name           string `json:"name"`
+	Identifier     string `json:"identifier"`
+	Description    string `json:"description"`
+	MCVersion      string `json:"mc_version"`
+	Loader         string `json:"loader"`
+	LoaderVersion  string `json:"loader_version"`
+	Author         string `json:"author"`
+	Source         string `json:"source"`
+	RecommendedRAM int    `json:"recommended_ram"`
+	ManifestURL    string `json:"manifest_url"`
+	Variant        string `json:"variant"`
+	Mods           []struct {
+		Name        string `json:"name"`
+		Version     string `json:"version"`
+		FileName    string `json:"filename"`
+		Side        string `json:"side"`
+		Required    bool   `json:"required"`
+		DownloadURL string `json:"download_url"`
+		SHA256      string `json:"sha256"`
+		SHA512      string `json:"sha512"`
+	} `json:"mods"`
+}
+
+// modpackFromSnapshot reverses createRecipeSnapshot
+func modpackFromSnapshot(snapshot map[string]interface{}) (*sources.Modpack, error) {
+	// Snapshots are maps in memory and decoded JSON on disk; round-trip to read both
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read recipe snapshot: %w", err)
+	}
+
+	var recipe snapshotModpack
+	if err := json.Unmarshal(data, &recipe); err != nil {
+		return nil, fmt.Errorf("failed to read recipe snapshot: %w", err)
+	}
+
+	modpack := &sources.Modpack{
+		Name:           recipe.Name,
+		Identifier:     recipe.Identifier,
+		Description:    recipe.Description,
+		MCVersion:      recipe.MCVersion,
+		Loader:         sources.LoaderType(recipe.Loader),
+		LoaderVersion:  recipe.LoaderVersion,
+		Author:         recipe.Author,
+		Source:         recipe.Source,
+		RecommendedRAM: recipe.RecommendedRAM,
+		ManifestURL:    recipe.ManifestURL,
+		Variant:        recipe.Variant,
+	}
+	for _, mod := range recipe.Mods {
+		modpack.Mods = append(modpack.Mods, &sources.Mod{
+			Name:        mod.Name,
+			Version:     mod.Version,
+			FileName:    mod.FileName,
+			DownloadURL: mod.DownloadURL,
+			Side:        sources.ModSide(mod.Side),
+			Required:    mod.Required,
+			SHA256:      mod.SHA256,
+			SHA512:      mod.SHA512,
+		})
+	}
+
+	return modpack, nil
+}
+
+// copyFile copies src to dst
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", src, err)
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", dst, err)
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return fmt.Errorf("failed to copy %s: %w", src, err)
+	}
+	return out.Close()
+}
@@ -0,0 +1,154 @@
+package install
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alexinslc/chunk/internal/lockfile"
+	"github.com/alexinslc/chunk/internal/sources"
+)
+
+func TestInstallFromBundle(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+
+	packPath, err := createTestMrpack(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to create test mrpack: %v", err)
+	}
+	for name, content := range map[string]string{"fabric-server-launch.jar": "launcher", "jei.jar": "jei", "bundled.properties": "motd=Bundled\n"} {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	bundlePath := filepath.Join(tmpDir, "test"+sources.BundleExtension)
+	err = sources.WriteBundle(bundlePath, &sources.BundleManifest{
+		Name:          "Test Modpack",
+		Identifier:    "test-modpack",
+		MCVersion:     "1.20.1",
+		Loader:        sources.LoaderFabric,
+		LoaderVersion: "0.15.0",
+		Pack:          &sources.BundleEntry{Path: sources.BundlePackDir + "test.mrpack", URL: "https://example.com/test.mrpack"},
+		LoaderFile:    sources.BundleEntry{Path: sources.BundleLoaderDir + "fabric-server-launch.jar", URL: "https://example.com/fabric.jar"},
+		Variant:       "lite",
+		JVMArgs:       []string{"-XX:+UseZGC"},
+		MinJava:       17,
+		Mods: []sources.BundleMod{{
+			BundleEntry: sources.BundleEntry{Path: sources.BundleModsDir + "jei.jar", URL: "https://example.com/jei.jar"},
+			FileName:    "jei.jar",
+			Side:        sources.SideBoth,
+		}},
+		ServerFiles: []sources.BundleEntry{{Path: sources.BundleServerDir + "server.properties", URL: "https://example.com/server.properties"}},
+	}, map[string]interface{}{"name": "Test Modpack"}, []sources.BundleSource{
+		{Path: sources.BundlePackDir + "test.mrpack", FilePath: packPath},
+		{Path: sources.BundleServerDir + "server.properties", FilePath: filepath.Join(tmpDir, "bundled.properties")},
+		{Path: sources.BundleLoaderDir + "fabric-server-launch.jar", FilePath: filepath.Join(tmpDir, "fabric-server-launch.jar")},
+		{Path: sources.BundleModsDir + "jei.jar", FilePath: filepath.Join(tmpDir, "jei.jar")},
+	})
+	if err != nil {
+		t.Fatalf("WriteBundle failed: %v", err)
+	}
+
+	destDir := filepath.Join(tmpDir, "server")
+	result, err := NewInstaller().Install(context.Background(), &Options{Identifier: bundlePath, DestDir: destDir})
+	if err != nil {
+		t.Fatalf("Install from bundle failed: %v", err)
+	}
+
+	if result.ModsInstalled != 1 || result.LoaderVersion != "0.15.0" || result.Modpack.Variant != "lite" {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+	for _, name := range []string{"fabric-server-launch.jar", filepath.Join("mods", "jei.jar"), "start.sh"} {
+		if _, err := os.Stat(filepath.Join(destDir, name)); err != nil {
+			t.Errorf("Expected %s to be installed: %v", name, err)
+		}
+	}
+
+	lock, err := lockfile.Load(lockfile.Path(destDir))
+	if err != nil {
+		t.Fatalf("Failed to load lock: %v", err)
+	}
+	if lock.Variant != "lite" {
+		t.Errorf("Expected the lock to record variant lite, got %q", lock.Variant)
+	}
+	if len(lock.Artifacts) != 4 {
+		t.Errorf("Expected pack, loader, mod and server file in the lock, got %d artifacts", len(lock.Artifacts))
+	}
+	// The bundled server file replaces the generated one
+	if data, err := os.ReadFile(filepath.Join(destDir, "server.properties")); err != nil || string(data) != "motd=Bundled\n" {
+		t.Errorf("Expected the bundled server.properties, got %q (%v)", data, err)
+	}
+	if file := lock.Find(lockfile.KindFile, "server.properties"); file == nil || file.URL != "https://example.com/server.properties" {
+		t.Errorf("Expected server.properties locked with its original URL, got %+v", file)
+	}
+	if script, err := os.ReadFile(filepath.Join(destDir, "start.sh")); err != nil || !strings.Contains(string(script), "-XX:+UseZGC") {
+		t.Errorf("Expected start.sh to use the bundled JVM arguments: %v", err)
+	}
+	if mod := lock.Find(lockfile.KindMod, "jei.jar"); mod == nil || mod.URL != "https://example.com/jei.jar" {
+		t.Errorf("Expected jei.jar locked with its original URL, got %+v", mod)
+	}
+	if _, err := os.Stat(filepath.Join(destDir, ".chunk.json")); err != nil {
+		t.Errorf("Expected installed manifest: %v", err)
+	}
+
+	// Reinstalling over the server keeps its world and the previous
+	// installation, and leaves no staging directory behind
+	levelPath := filepath.Join(destDir, "world", "level.dat")
+	if err := os.MkdirAll(filepath.Dir(levelPath), 0755); err != nil {
+		t.Fatalf("Failed to create world: %v", err)
+	}
+	if err := os.WriteFile(levelPath, []byte("level"), 0644); err != nil {
+		t.Fatalf("Failed to write world: %v", err)
+	}
+	if _, err := NewInstaller().Install(context.Background(), &Options{Identifier: bundlePath, DestDir: destDir}); err != nil {
+		t.Fatalf("Reinstall from bundle failed: %v", err)
+	}
+	if data, err := os.ReadFile(levelPath); err != nil || string(data) != "level" {
+		t.Errorf("Expected world to be preserved, got %q (%v)", data, err)
+	}
+	entries, err := os.ReadDir(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", tmpDir, err)
+	}
+	backups := 0
+	for _, entry := range entries {
+		switch {
+		case strings.HasPrefix(entry.Name(), "server.backup."):
+			backups++
+		case strings.HasPrefix(entry.Name(), "server."):
+			t.Errorf("Expected no leftover directory, found %s", entry.Name())
+		}
+	}
+	if backups != 1 {
+		t.Errorf("Expected the previous installation to be kept, found %d backups", backups)
+	}
+}
+
+func TestModpackFromSnapshot(t *testing.T) {
+	modpack := &sources.Modpack{
+		Name:          "ATM9",
+		Identifier:    "atm9",
+		MCVersion:     "1.20.1",
+		Loader:        sources.LoaderForge,
+		LoaderVersion: "47.2.0",
+		Variant:       "lite",
+		Mods: []*sources.Mod{
+			{Name: "JEI", FileName: "jei.jar", DownloadURL: "https://example.com/jei.jar", Side: sources.SideBoth, SHA512: "abc"},
+		},
+	}
+
+	restored, err := modpackFromSnapshot(createRecipeSnapshot(modpack))
+	if err != nil {
+		t.Fatalf("modpackFromSnapshot failed: %v", err)
+	}
+	if restored.Identifier != "atm9" || restored.Loader != sources.LoaderForge || restored.LoaderVersion != "47.2.0" || restored.Variant != "lite" {
+		t.Errorf("Unexpected modpack: %+v", restored)
+	}
+	if len(restored.Mods) != 1 || restored.Mods[0].DownloadURL != "https://example.com/jei.jar" || restored.Mods[0].SHA512 != "abc" {
+		t.Errorf("Unexpected mods: %+v", restored.Mods)
+	}
+}
@@ -1,17 +1,29 @@
 package install
 
 import (
+	"context"
 	"fmt"
+	"io/fs"
 	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/alexinslc/chunk/internal/cache"
+	"github.com/alexinslc/chunk/internal/checksum"
+	"github.com/alexinslc/chunk/internal/config"
 	"github.com/alexinslc/chunk/internal/converter"
+	"github.com/alexinslc/chunk/internal/lockfile"
+	"github.com/alexinslc/chunk/internal/mirror"
+	"github.com/alexinslc/chunk/internal/preserve"
+	"github.com/alexinslc/chunk/internal/rcon"
+	"github.com/alexinslc/chunk/internal/search"
 	"github.com/alexinslc/chunk/internal/sources"
+	"github.com/alexinslc/chunk/internal/supervisor"
 	"github.com/alexinslc/chunk/internal/tracking"
 	"github.com/alexinslc/chunk/internal/ui"
+	"github.com/alexinslc/chunk/internal/validation"
 )
 
 // Installer handles the complete installation workflow for modpacks
@@ -19,18 +31,33 @@ type Installer struct {
 	sourceManager    *sources.SourceManager
 	conversionEngine *converter.ConversionEngine
 	httpClient       *http.Client
-	backupDir        string
+	backupDir        string // Previous installation, moved aside by the swap
+	stagingDir       string // Sibling directory the new installation is built in
 	absDestDir       string
+	destWasEmpty     bool // Destination existed but was empty
 	skipVerify       bool
+	variant          string             // Recipe variant being installed
+	frozenLock       *lockfile.Lockfile // Lock being installed from in --frozen mode
+	lock             *lockfile.Lockfile // Lock recorded for this installation
+	bundle           *sources.Bundle    // Bundle being installed from, once verified
+	rcon             *tracking.RCON     // RCON console enabled in server.properties
 }
 
+// stagingSuffix names the sibling directory a server is built in
+const stagingSuffix = ".staging"
+
+// stagingMarker marks a staging directory as created by chunk, so a leftover
+// one can be cleared without touching an unrelated directory of the same name
+const stagingMarker = ".chunk-staging"
+
 // NewInstaller creates a new Installer instance
 func NewInstaller() *Installer {
 	return &Installer{
 		sourceManager:    sources.NewSourceManager(),
 		conversionEngine: converter.NewConversionEngine(),
 		httpClient: &http.Client{
-			Timeout: 10 * time.Minute,
+			Timeout:   10 * time.Minute,
+			Transport: &mirror.Transport{},
 		},
 	}
 }
@@ -41,6 +68,10 @@ type Options struct {
 	DestDir      string
 	PreserveData bool
 	SkipVerify   bool
+	Frozen       bool   // Install only the artifacts pinned in the existing chunk.lock
+	Name         string // Instance name to track the installation under; must be unique
+	Variant      string // Recipe variant to install, such as "lite"
+	RCON         bool   // Enable RCON on a free port with a generated password
 }
 
 // Result contains the outcome of an installation
@@ -53,6 +84,10 @@ type Result struct {
 	DestDir       string
 	ModpackInfo   *ModpackDisplayInfo
 	Modpack       *sources.Modpack // Full modpack info for tracking
+	LockPath      string
+	Name          string         // Instance name requested in Options
+	RCON          *tracking.RCON // RCON console enabled with Options.RCON
+	PreservedData bool           // Server settings were carried over from the previous install
 }
 
 // ModpackDisplayInfo contains modpack details for display
@@ -69,7 +104,7 @@ type ModpackDisplayInfo struct {
 }
 
 // Install performs the complete installation workflow
-func (i *Installer) Install(opts *Options) (*Result, error) {
+func (i *Installer) Install(ctx context.Context, opts *Options) (*Result, error) {
 	// Normalize destination directory
 	destDir := opts.DestDir
 	if destDir == "" {
@@ -85,9 +120,63 @@ func (i *Installer) Install(opts *Options) (*Result, error) {
 	// Store options for later use
 	i.absDestDir = absDestDir
 	i.skipVerify = opts.SkipVerify
+	i.variant = opts.Variant
+
+	// A taken instance name fails the install before anything is written
+	if opts.Name != "" {
+		tracker, err := tracking.NewTracker()
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize tracker: %w", err)
+		}
+		if err := tracker.CheckName(opts.Name, absDestDir); err != nil {
+			return nil, err
+		}
+	}
+
+	// Replacing the files of a running server corrupts its worlds
+	if err := supervisor.EnsureStopped(absDestDir); err != nil {
+		return nil, err
+	}
 
 	ui.PrintInfo(fmt.Sprintf("Installing to: %s", absDestDir))
 
+	if opts.RCON {
+		credentials, err := provisionRCON(absDestDir)
+		if err != nil {
+			return nil, err
+		}
+		i.rcon = credentials
+		ui.PrintInfo(fmt.Sprintf("Enabling RCON on port %d", credentials.Port))
+	}
+
+	// Frozen installs read the lock before the destination is backed up
+	if opts.Frozen {
+		if opts.SkipVerify {
+			return nil, fmt.Errorf("--frozen cannot be combined with --skip-verify")
+		}
+		frozenLock, err := lockfile.Load(lockfile.Path(absDestDir))
+		if err != nil {
+			return nil, fmt.Errorf("frozen install requires a lock file: %w", err)
+		}
+		// The lock pins the modpack it was recorded for
+		if err := checkFrozenIdentifier(opts.Identifier, frozenLock); err != nil {
+			return nil, err
+		}
+		i.frozenLock = frozenLock
+		ui.PrintInfo(fmt.Sprintf("Installing from %s (frozen)", lockfile.FileName))
+
+		// The lock pins the variant it was recorded with
+		if i.variant == "" {
+			i.variant = frozenLock.Variant
+		} else if i.variant != frozenLock.Variant {
+			return nil, lockfile.Deviation("variant", i.variant, frozenLock.Variant)
+		}
+	}
+
+	if i.variant != "" {
+		ui.PrintInfo(fmt.Sprintf("Variant: %s", i.variant))
+	}
+
 	// Detect source type
 	sourceType := sources.DetectSource(opts.Identifier)
 	ui.PrintInfo(fmt.Sprintf("Source: %s", sourceType))
@@ -96,13 +185,41 @@ func (i *Installer) Install(opts *Options) (*Result, error) {
 	spinner := ui.NewSpinner("Fetching modpack information...")
 	spinner.Start()
 
-	modpack, err := i.fetchModpack(opts.Identifier)
+	modpack, err := i.fetchModpack(ctx, opts.Identifier)
 	if err != nil {
 		spinner.Error(fmt.Sprintf("Failed to fetch modpack: %v", err))
 		return nil, fmt.Errorf("failed to fetch modpack: %w", err)
 	}
 	spinner.Success(fmt.Sprintf("Found modpack: %s", modpack.Name))
 
+	// Bundles carry every artifact; all of them are verified before anything is written
+	if sourceType == "bundle" {
+		spinner = ui.NewSpinner("Verifying bundle...")
+		spinner.Start()
+		if err := i.openBundle(opts.Identifier); err != nil {
+			spinner.Error(fmt.Sprintf("Bundle verification failed: %v", err))
+			return nil, err
+		}
+		defer i.bundle.Close()
+		spinner.Success(fmt.Sprintf("Verified %d bundled files", len(i.bundle.Manifest.Files)))
+	}
+
+	if i.frozenLock != nil {
+		if err := applyLock(i.frozenLock, modpack); err != nil {
+			return nil, err
+		}
+		i.lock = i.frozenLock
+	} else {
+		// Bundles pin the loader version they were exported with
+		if sourceType != "bundle" {
+			if err := resolveLoaderVersion(ctx, modpack); err != nil {
+				return nil, err
+			}
+		}
+		i.lock = lockfile.New(opts.Identifier, modpack.Name, modpack.MCVersion, string(modpack.Loader), modpack.LoaderVersion)
+		i.lock.Variant = modpack.Variant
+	}
+
 	// Build modpack display info for the command layer to display
 	modpackInfo := &ModpackDisplayInfo{
 		Name:           modpack.Name,
@@ -116,15 +233,17 @@ func (i *Installer) Install(opts *Options) (*Result, error) {
 		RecommendedRAM: modpack.RecommendedRAM,
 	}
 
-	// Create backup if directory exists and has content
-	if err := i.createBackup(absDestDir); err != nil {
-		ui.PrintWarning(fmt.Sprintf("Could not create backup: %v", err))
+	// Build the server in a sibling staging directory; the destination is
+	// only touched by the final swap
+	stagingDir, err := i.createStaging(absDestDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create staging directory: %w", err)
 	}
 
 	// Prepare installation directory
 	spinner = ui.NewSpinner("Preparing installation directory...")
 	spinner.Start()
-	if err := i.prepareDirectory(absDestDir, opts.PreserveData); err != nil {
+	if err := i.prepareDirectory(stagingDir, opts.PreserveData); err != nil {
 		spinner.Error(fmt.Sprintf("Failed to prepare directory: %v", err))
 		return nil, fmt.Errorf("failed to prepare directory: %w", err)
 	}
@@ -134,7 +253,7 @@ func (i *Installer) Install(opts *Options) (*Result, error) {
 	if sourceType == "recipe" {
 		spinner = ui.NewSpinner("Downloading modpack from recipe...")
 		spinner.Start()
-		if err := i.downloadAndExtractRecipe(opts.Identifier, modpack, absDestDir); err != nil {
+		if err := i.downloadAndExtractRecipe(ctx, opts.Identifier, modpack, stagingDir); err != nil {
 			spinner.Error(fmt.Sprintf("Failed to download modpack: %v", err))
 			return nil, fmt.Errorf("failed to download modpack: %w", err)
 		}
@@ -145,7 +264,22 @@ func (i *Installer) Install(opts *Options) (*Result, error) {
 	if sourceType == "local" {
 		spinner = ui.NewSpinner("Extracting modpack files...")
 		spinner.Start()
-		if err := i.extractLocalModpack(opts.Identifier, absDestDir); err != nil {
+		if err := i.lockLocalModpack(opts.Identifier); err != nil {
+			spinner.Error(fmt.Sprintf("Failed to verify modpack: %v", err))
+			return nil, err
+		}
+		if err := i.extractLocalModpack(opts.Identifier, stagingDir); err != nil {
+			spinner.Error(fmt.Sprintf("Failed to extract modpack: %v", err))
+			return nil, fmt.Errorf("failed to extract modpack: %w", err)
+		}
+		spinner.Success("Modpack files extracted")
+	}
+
+	// Bundles are installed from the bundle alone
+	if sourceType == "bundle" {
+		spinner = ui.NewSpinner("Extracting modpack files...")
+		spinner.Start()
+		if err := i.extractBundlePack(modpack, stagingDir); err != nil {
 			spinner.Error(fmt.Sprintf("Failed to extract modpack: %v", err))
 			return nil, fmt.Errorf("failed to extract modpack: %w", err)
 		}
@@ -155,17 +289,23 @@ func (i *Installer) Install(opts *Options) (*Result, error) {
 	// Install mod loader
 	spinner = ui.NewSpinner(fmt.Sprintf("Installing %s loader...", modpack.Loader))
 	spinner.Start()
-	if err := i.installLoader(modpack, absDestDir); err != nil {
+	if err := i.installLoader(ctx, modpack, stagingDir); err != nil {
 		spinner.Error(fmt.Sprintf("Failed to install loader: %v", err))
 		return nil, fmt.Errorf("failed to install mod loader: %w", err)
 	}
 	spinner.Success(fmt.Sprintf("%s loader installed", modpack.Loader))
 
 	// Download mods
+	if i.frozenLock != nil {
+		if err := applyLockedMods(i.frozenLock, modpack); err != nil {
+			return nil, err
+		}
+	}
+
 	modsInstalled := 0
 	if len(modpack.Mods) > 0 {
 		ui.PrintInfo(fmt.Sprintf("Downloading %d mods (filtering server-side only)...", len(modpack.Mods)))
-		installed, err := i.downloadMods(modpack.Mods, absDestDir)
+		installed, err := i.downloadMods(ctx, modpack.Mods, stagingDir)
 		if err != nil {
 			return nil, fmt.Errorf("failed to download mods: %w", err)
 		}
@@ -178,7 +318,7 @@ func (i *Installer) Install(opts *Options) (*Result, error) {
 	// Generate configuration files
 	spinner = ui.NewSpinner("Generating server configuration...")
 	spinner.Start()
-	if err := i.generateConfigs(modpack, absDestDir); err != nil {
+	if err := i.generateConfigs(modpack, stagingDir); err != nil {
 		spinner.Error(fmt.Sprintf("Failed to generate configs: %v", err))
 		return nil, fmt.Errorf("failed to generate configs: %w", err)
 	}
@@ -187,17 +327,62 @@ func (i *Installer) Install(opts *Options) (*Result, error) {
 	// Generate start scripts
 	spinner = ui.NewSpinner("Creating start scripts...")
 	spinner.Start()
-	if err := i.generateScripts(modpack, absDestDir); err != nil {
+	if err := i.generateScripts(modpack, stagingDir); err != nil {
 		spinner.Error(fmt.Sprintf("Failed to generate scripts: %v", err))
 		return nil, fmt.Errorf("failed to generate scripts: %w", err)
 	}
 	spinner.Success("Start scripts created")
 
-	// Clean up backup if successful
-	if i.backupDir != "" {
-		if err := os.RemoveAll(i.backupDir); err != nil {
-			ui.PrintWarning(fmt.Sprintf("Failed to clean up backup: %v", err))
+	// Recipe overrides replace generated and extracted files
+	if len(modpack.ServerFiles) > 0 {
+		ui.PrintInfo(fmt.Sprintf("Downloading %d server files...", len(modpack.ServerFiles)))
+		if err := i.downloadServerFiles(ctx, modpack.ServerFiles, stagingDir); err != nil {
+			return nil, fmt.Errorf("failed to download server files: %w", err)
 		}
+		ui.PrintSuccess(fmt.Sprintf("Placed %d server files", len(modpack.ServerFiles)))
+	}
+
+	if err := i.generateManifest(modpack, stagingDir); err != nil {
+		return nil, err
+	}
+
+	// Write the lock file
+	if err := i.lock.Save(lockfile.Path(stagingDir)); err != nil {
+		return nil, fmt.Errorf("failed to write lock file: %w", err)
+	}
+	ui.PrintSuccess(fmt.Sprintf("Locked %d artifacts in %s", len(i.lock.Artifacts), lockfile.FileName))
+
+	// Installing over an existing server keeps its worlds, server settings
+	// and edited pack files, whether it is an upgrade or a reinstall
+	preserved, err := i.carryPreservedData(absDestDir, stagingDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to carry over preserved data: %w", err)
+	}
+	// A requested RCON console replaces the settings of a carried server.properties
+	if preserved && i.rcon != nil {
+		if err := converter.EnableRCON(stagingDir, i.rcon.Port, i.rcon.Password); err != nil {
+			return nil, fmt.Errorf("failed to enable RCON: %w", err)
+		}
+	}
+
+	// Only a server that passes the smoke test replaces the destination
+	if err := smokeTest(stagingDir); err != nil {
+		return nil, err
+	}
+
+	spinner = ui.NewSpinner("Swapping in the new installation...")
+	spinner.Start()
+	if err := i.swap(absDestDir); err != nil {
+		spinner.Error(fmt.Sprintf("Failed to swap in the new installation: %v", err))
+		return nil, err
+	}
+	spinner.Success("Installation swapped in")
+
+	// The previous installation is kept next to the new one, since not
+	// everything in it may have been carried over
+	if i.backupDir != "" {
+		ui.PrintInfo(fmt.Sprintf("Previous installation kept at %s", i.backupDir))
+		i.backupDir = ""
 	}
 
 	return &Result{
@@ -209,18 +394,28 @@ func (i *Installer) Install(opts *Options) (*Result, error) {
 		DestDir:       absDestDir,
 		ModpackInfo:   modpackInfo,
 		Modpack:       modpack,
+		LockPath:      lockfile.Path(absDestDir),
+		Name:          opts.Name,
+		RCON:          i.rcon,
+		PreservedData: preserved,
 	}, nil
 }
 
-// Rollback restores the previous state after a failed installation
+// Rollback restores the destination to its state before a failed or
+// cancelled installation. Before the swap the destination is untouched and
+// only the staging directory is discarded; a failed swap moves the previous
+// installation back.
 func (i *Installer) Rollback() error {
-	if i.backupDir == "" {
-		return nil
+	if i.stagingDir != "" {
+		if err := os.RemoveAll(i.stagingDir); err != nil {
+			return fmt.Errorf("failed to remove staging directory: %w", err)
+		}
+		i.stagingDir = ""
 	}
 
 	// Use the stored absolute path to ensure correct rollback
 	destDir := i.absDestDir
-	if destDir == "" {
+	if destDir == "" || (i.backupDir == "" && !i.destWasEmpty) {
 		return nil
 	}
 
@@ -231,17 +426,157 @@ func (i *Installer) Rollback() error {
 		return fmt.Errorf("failed to remove failed installation: %w", err)
 	}
 
-	// Restore backup
-	if err := os.Rename(i.backupDir, destDir); err != nil {
-		return fmt.Errorf("failed to restore backup: %w", err)
+	if i.backupDir != "" {
+		if err := os.Rename(i.backupDir, destDir); err != nil {
+			return fmt.Errorf("failed to restore backup: %w", err)
+		}
+		i.backupDir = ""
+	} else if err := os.Mkdir(destDir, 0755); err != nil {
+		return fmt.Errorf("failed to restore empty destination: %w", err)
 	}
 
 	ui.PrintSuccess("Rollback complete")
 	return nil
 }
 
-func (i *Installer) fetchModpack(identifier string) (*sources.Modpack, error) {
-	return i.sourceManager.Fetch(identifier)
+func (i *Installer) fetchModpack(ctx context.Context, identifier string) (*sources.Modpack, error) {
+	return i.sourceManager.FetchVariant(ctx, identifier, i.variant)
+}
+
+// findRecipe finds the recipe being installed, with the variant applied
+func (i *Installer) findRecipe(identifier string) (*search.Recipe, error) {
+	benchName, recipeName := sources.ParseRecipeIdentifier(identifier)
+	recipe, err := sources.NewRecipeClient().FindRecipeVariant(recipeName, benchName, i.variant)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find recipe: %w", err)
+	}
+	return recipe, nil
+}
+
+// FetchModpack fetches a modpack without installing it. Recipes that point at
+// an archive have it downloaded (or taken from the download cache) to read its
+// mod list. Nothing is printed, so callers can emit JSON.
+func (i *Installer) FetchModpack(ctx context.Context, identifier string) (*sources.Modpack, error) {
+	modpack, err := i.fetchModpack(ctx, identifier)
+	if err != nil {
+		return nil, err
+	}
+	if sources.DetectSource(identifier) != "recipe" || modpack.ManifestURL == "" {
+		return modpack, nil
+	}
+
+	archivePath, cleanup, err := i.recipeArchive(ctx, identifier, modpack)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	archivePack, err := sources.NewLocalClient().ParseArchive(ctx, archivePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read modpack manifest: %w", err)
+	}
+	modpack.Mods = mergeMods(archivePack.Mods, modpack.Mods)
+
+	return modpack, nil
+}
+
+// FetchVariant is FetchModpack with a recipe variant applied
+func (i *Installer) FetchVariant(ctx context.Context, identifier, variant string) (*sources.Modpack, error) {
+	i.variant = variant
+	return i.FetchModpack(ctx, identifier)
+}
+
+// mergeMods adds the mods a recipe lists to those of its pack archive. A
+// listed mod replaces an archive mod with the same file name.
+func mergeMods(archiveMods, listed []*sources.Mod) []*sources.Mod {
+	replaced := make(map[string]bool, len(listed))
+	for _, mod := range listed {
+		replaced[strings.ToLower(mod.FileName)] = true
+	}
+
+	merged := make([]*sources.Mod, 0, len(archiveMods)+len(listed))
+	for _, mod := range archiveMods {
+		if !replaced[strings.ToLower(mod.FileName)] {
+			merged = append(merged, mod)
+		}
+	}
+	return append(merged, listed...)
+}
+
+// recipeArchive returns the verified pack archive of a recipe, downloading it
+// into the download cache unless it is already there. Without a cache the
+// archive is a temporary file that cleanup removes.
+func (i *Installer) recipeArchive(ctx context.Context, identifier string, modpack *sources.Modpack) (string, func(), error) {
+	recipeClient := sources.NewRecipeClient()
+	recipe, err := i.findRecipe(identifier)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var expected *checksum.Checksums
+	if !i.skipVerify && recipe.SHA256 != "" {
+		expected = &checksum.Checksums{SHA256: recipe.SHA256}
+	}
+
+	version := recipeCacheVersion(recipe)
+	cacheManager, err := cache.NewManager()
+	if err != nil {
+		cacheManager = nil
+	}
+
+	var archivePath string
+	cleanup := func() {}
+	cached := false
+	if cacheManager != nil {
+		archivePath = cacheManager.GetCachePath(recipe.Slug, version, "modpack.mrpack")
+		_, err := os.Stat(archivePath)
+		cached = err == nil
+	} else {
+		archivePath = filepath.Join(os.TempDir(), fmt.Sprintf("chunk-download-%s-%s.mrpack", recipe.Slug, version))
+		cleanup = func() { os.Remove(archivePath) }
+	}
+
+	if cached {
+		if expected != nil {
+			if err := checksum.VerifyFile(archivePath, expected); err != nil {
+				return "", nil, fmt.Errorf("cached modpack failed verification: %w", err)
+			}
+		}
+		_ = cacheManager.UpdateLastUsed(archivePath)
+		return archivePath, cleanup, nil
+	}
+
+	if err := recipeClient.DownloadToFile(ctx, modpack.ManifestURL, archivePath, expected, nil); err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("download failed: %w", err)
+	}
+	if info, err := os.Stat(archivePath); err == nil && cacheManager != nil {
+		_ = cacheManager.SaveMetadata(archivePath, &cache.DownloadMetadata{
+			Slug:         recipe.Slug,
+			Version:      version,
+			Filename:     "modpack.mrpack",
+			Size:         info.Size(),
+			DownloadURL:  modpack.ManifestURL,
+			SHA256:       recipe.SHA256,
+			DownloadedAt: time.Now(),
+			LastUsedAt:   time.Now(),
+		})
+	}
+
+	return archivePath, cleanup, nil
+}
+
+// recipeCacheVersion is the version a recipe's archive is cached under.
+// Variants have their own archives, so they are cached separately.
+func recipeCacheVersion(recipe *search.Recipe) string {
+	version := recipe.MCVersion
+	if recipe.Version != "" {
+		version = recipe.Version
+	}
+	if recipe.Variant != "" {
+		version += "-" + recipe.Variant
+	}
+	return version
 }
 
 func (i *Installer) createBackup(destDir string) error {
@@ -251,13 +586,240 @@ func (i *Installer) createBackup(destDir string) error {
 
 	// Check if directory has content
 	entries, err := os.ReadDir(destDir)
-	if err != nil || len(entries) == 0 {
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		i.destWasEmpty = true
+		return nil
+	}
+
+	// Create backup directory; only a completed move may be restored by Rollback
+	backupDir := fmt.Sprintf("%s.backup.%d", destDir, time.Now().Unix())
+	if err := os.Rename(destDir, backupDir); err != nil {
+		return err
+	}
+	i.backupDir = backupDir
+	return nil
+}
+
+// createStaging creates the sibling directory a new installation is built in.
+// Its name is the same on every run, since interrupted downloads are resumed
+// from .part files keyed by their destination. A leftover staging directory
+// is only removed if it carries the staging marker.
+func (i *Installer) createStaging(destDir string) (string, error) {
+	if err := os.MkdirAll(filepath.Dir(destDir), 0755); err != nil {
+		return "", err
+	}
+	stagingDir := destDir + stagingSuffix
+	if _, err := os.Lstat(stagingDir); err == nil {
+		if _, err := os.Stat(filepath.Join(stagingDir, stagingMarker)); err != nil {
+			return "", fmt.Errorf("%s already exists and was not created by chunk; move it out of the way", stagingDir)
+		}
+		if err := os.RemoveAll(stagingDir); err != nil {
+			return "", err
+		}
+	} else if !os.IsNotExist(err) {
+		return "", err
+	}
+	if err := os.Mkdir(stagingDir, 0755); err != nil {
+		return "", err
+	}
+	i.stagingDir = stagingDir
+	if err := os.WriteFile(filepath.Join(stagingDir, stagingMarker), nil, 0644); err != nil {
+		return "", err
+	}
+	return stagingDir, nil
+}
+
+// swap replaces destDir with the staged installation. The previous
+// installation is moved aside first and kept until Install succeeds, so a
+// failed swap can be rolled back.
+func (i *Installer) swap(destDir string) error {
+	if err := i.createBackup(destDir); err != nil {
+		return fmt.Errorf("failed to move previous installation aside: %w", err)
+	}
+	if i.destWasEmpty {
+		if err := os.Remove(destDir); err != nil {
+			return fmt.Errorf("failed to replace empty destination: %w", err)
+		}
+	}
+	if err := os.Remove(filepath.Join(i.stagingDir, stagingMarker)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove staging marker: %w", err)
+	}
+	if err := os.Rename(i.stagingDir, destDir); err != nil {
+		return fmt.Errorf("failed to move new installation into place: %w", err)
+	}
+	i.stagingDir = ""
+	return nil
+}
+
+// carryPreservedData copies the data an installation keeps from the server
+// already in destDir into the staged one. Worlds, server settings and edited
+// pack files replace what the new pack generated; every other file the
+// previous pack did not install is carried over unless the new pack ships a
+// file at the same path. It reports whether destDir held anything to carry over.
+func (i *Installer) carryPreservedData(destDir, stagingDir string) (bool, error) {
+	if entries, err := os.ReadDir(destDir); err != nil || len(entries) == 0 {
+		return false, nil
+	}
+
+	preserver := preserve.NewDataPreserver()
+	paths := append(preserver.GetCriticalFiles(destDir), preservedExtras...)
+	replaced := make(map[string]bool, len(paths))
+	carried := 0
+	for _, path := range paths {
+		replaced[filepath.ToSlash(path)] = true
+		src := filepath.Join(destDir, path)
+		info, err := os.Stat(src)
+		if err != nil {
+			continue
+		}
+
+		dst := filepath.Join(stagingDir, path)
+		if info.IsDir() {
+			if err := os.RemoveAll(dst); err != nil {
+				return false, err
+			}
+			err = preserver.CopyDir(src, dst)
+		} else {
+			err = preserver.CopyFile(src, dst)
+		}
+		if err != nil {
+			return false, fmt.Errorf("failed to copy %s: %w", path, err)
+		}
+		carried++
+	}
+
+	owned := packOwnedPaths(destDir)
+	err := filepath.WalkDir(destDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(destDir, path)
+		if err != nil || relPath == "." {
+			return err
+		}
+		if key := filepath.ToSlash(relPath); replaced[key] || owned[key] {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if entry.IsDir() {
+			return nil
+		}
+
+		dst := filepath.Join(stagingDir, relPath)
+		if _, err := os.Lstat(dst); err == nil {
+			return nil
+		}
+		if err := carryFile(path, dst, entry); err != nil {
+			return fmt.Errorf("failed to copy %s: %w", relPath, err)
+		}
+		carried++
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	if carried > 0 {
+		ui.PrintSuccess(fmt.Sprintf("Carried over %d preserved files and directories", carried))
+	}
+	return true, nil
+}
+
+// generatedFiles are written by every install, so the new installation's
+// copies are kept
+var generatedFiles = []string{
+	"start.sh", "start.bat", "stop.sh", lockfile.FileName, config.ChunkManifestFile,
+	sources.OverridesRecordFile, ".chunk-recipe.json",
+}
+
+// packOwnedPaths returns the slash-separated paths the installation in
+// serverDir placed there itself: generated files, the loader's libraries,
+// the files locked in its chunk.lock and the unedited files of its override
+// layers. Without a chunk.lock, nothing tells hand-added mods from the pack's,
+// so mods/ is treated as the pack's.
+func packOwnedPaths(serverDir string) map[string]bool {
+	owned := map[string]bool{"libraries": true}
+	for _, name := range generatedFiles {
+		owned[name] = true
+	}
+
+	if lock, err := lockfile.Load(lockfile.Path(serverDir)); err == nil {
+		for _, artifact := range lock.Artifacts {
+			if artifact.Path != "" {
+				owned[artifact.Path] = true
+			}
+		}
+	} else {
+		owned["mods"] = true
+	}
+
+	if record, err := sources.LoadOverridesRecord(serverDir); err == nil && record != nil {
+		edited := make(map[string]bool)
+		if paths, err := record.ModifiedFiles(serverDir); err == nil {
+			for _, path := range paths {
+				edited[path] = true
+			}
+		}
+		for _, file := range record.Files {
+			if !edited[file.Path] {
+				owned[file.Path] = true
+			}
+		}
+	}
+	return owned
+}
+
+// carryFile copies a file or symlink into the staged installation, keeping
+// its permissions
+func carryFile(src, dst string, entry fs.DirEntry) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	if entry.Type()&fs.ModeSymlink != 0 {
+		target, err := os.Readlink(src)
+		if err != nil {
+			return err
+		}
+		return os.Symlink(target, dst)
+	}
+	if !entry.Type().IsRegular() {
+		return nil
+	}
+
+	info, err := entry.Info()
+	if err != nil {
+		return err
+	}
+	if err := preserve.NewDataPreserver().CopyFile(src, dst); err != nil {
+		return err
+	}
+	return os.Chmod(dst, info.Mode().Perm())
+}
+
+// preservedExtras are carried over on top of the preserver's critical files:
+// an accepted EULA stays accepted, the upgrade backup stays available and
+// chunk status still reports how the server last exited
+var preservedExtras = []string{"eula.txt", preserve.BackupDir, supervisor.StateFile}
+
+// smokeTest fails unless every check of validation.SmokeTest passes
+func smokeTest(serverDir string) error {
+	report := validation.NewSmokeTest().RunAll(serverDir)
+	if report.Failed == 0 {
 		return nil
 	}
 
-	// Create backup directory
-	i.backupDir = fmt.Sprintf("%s.backup.%d", destDir, time.Now().Unix())
-	return os.Rename(destDir, i.backupDir)
+	var failed []string
+	for _, result := range report.Results {
+		if !result.Passed {
+			failed = append(failed, fmt.Sprintf("%s: %s", result.Name, result.Message))
+		}
+	}
+	return fmt.Errorf("new installation failed its smoke test: %s", strings.Join(failed, "; "))
 }
 
 func (i *Installer) prepareDirectory(destDir string, preserveData bool) error {
@@ -283,17 +845,22 @@ func (i *Installer) extractLocalModpack(filePath, destDir string) error {
 	return localClient.Extract(filePath, destDir)
 }
 
-func (i *Installer) downloadAndExtractRecipe(identifier string, modpack *sources.Modpack, destDir string) error {
+func (i *Installer) downloadAndExtractRecipe(ctx context.Context, identifier string, modpack *sources.Modpack, destDir string) error {
 	// Get the recipe client
 	recipeClient := sources.NewRecipeClient()
 
-	// Parse identifier to get recipe info
-	benchName, recipeName := sources.ParseRecipeIdentifier(identifier)
-
 	// Find the recipe to get checksum
-	recipe, err := recipeClient.FindRecipe(recipeName, benchName)
+	recipe, err := i.findRecipe(identifier)
 	if err != nil {
-		return fmt.Errorf("failed to find recipe: %w", err)
+		return err
+	}
+
+	// Recipes that only list files have no archive
+	if modpack.ManifestURL == "" {
+		if err := sources.SaveRecipeSnapshot(recipe, destDir); err != nil {
+			ui.PrintWarning(fmt.Sprintf("Failed to save recipe snapshot: %v", err))
+		}
+		return nil
 	}
 
 	// Initialize cache manager
@@ -305,10 +872,7 @@ func (i *Installer) downloadAndExtractRecipe(identifier string, modpack *sources
 	}
 
 	// Determine version for cache key (use recipe version or MC version)
-	version := recipe.Version
-	if version == "" {
-		version = recipe.MCVersion
-	}
+	version := recipeCacheVersion(recipe)
 
 	var downloadPath string
 	var shouldCleanup bool
@@ -330,48 +894,30 @@ func (i *Installer) downloadAndExtractRecipe(identifier string, modpack *sources
 		}
 	}
 
-	// If not in cache, download to cache directory or temp
+	// If not in cache, download to cache directory or temp.
+	// Interrupted downloads resume from the partial download area.
+	verified := false
 	if downloadPath == "" {
-		var downloadFile *os.File
-
 		if cacheManager != nil {
-			// Download to cache
 			downloadPath = cacheManager.GetCachePath(recipe.Slug, version, "modpack.mrpack")
-			downloadFile, err = os.Create(downloadPath)
-			if err != nil {
-				ui.PrintWarning(fmt.Sprintf("Failed to create cache file: %v, using temp file", err))
-				// Fallback to temp file
-				downloadFile, err = os.CreateTemp("", "chunk-download-*.mrpack")
-				if err != nil {
-					return fmt.Errorf("failed to create temp file: %w", err)
-				}
-				downloadPath = downloadFile.Name()
-				shouldCleanup = true
-			} else {
-				shouldCleanup = false
-			}
 		} else {
-			// No cache manager, use temp file
-			downloadFile, err = os.CreateTemp("", "chunk-download-*.mrpack")
-			if err != nil {
-				return fmt.Errorf("failed to create temp file: %w", err)
-			}
-			downloadPath = downloadFile.Name()
+			downloadPath = filepath.Join(os.TempDir(), fmt.Sprintf("chunk-download-%s-%s.mrpack", recipe.Slug, version))
 			shouldCleanup = true
-		}
-
-		if shouldCleanup {
 			defer os.Remove(downloadPath)
 		}
-		defer downloadFile.Close()
 
 		// Download with progress
 		ui.PrintInfo(fmt.Sprintf("Downloading from: %s", modpack.ManifestURL))
 
+		var expected *checksum.Checksums
+		if !i.skipVerify && recipe.SHA256 != "" {
+			expected = &checksum.Checksums{SHA256: recipe.SHA256}
+		}
+
 		// Track progress
 		var lastPercent int
 		var downloadSize int64
-		err = recipeClient.DownloadFile(modpack.ManifestURL, downloadFile, func(downloaded, total int64) {
+		err = recipeClient.DownloadToFile(ctx, modpack.ManifestURL, downloadPath, expected, func(downloaded, total int64) {
 			downloadSize = downloaded // Track for metadata
 			if total > 0 {
 				percent := int(float64(downloaded) / float64(total) * 100)
@@ -386,6 +932,10 @@ func (i *Installer) downloadAndExtractRecipe(identifier string, modpack *sources
 		if err != nil {
 			return fmt.Errorf("download failed: %w", err)
 		}
+		if expected != nil {
+			verified = true
+			ui.PrintSuccess("Checksum verified")
+		}
 
 		// Save metadata if using cache
 		if cacheManager != nil && !shouldCleanup {
@@ -407,17 +957,31 @@ func (i *Installer) downloadAndExtractRecipe(identifier string, modpack *sources
 		}
 	}
 
-	// Verify checksum if not skipped
+	// Verify checksum of cached downloads if not skipped
 	if !i.skipVerify && recipe.SHA256 != "" {
-		ui.PrintInfo("Verifying checksum...")
-		if err := sources.VerifyChecksum(downloadPath, recipe.SHA256); err != nil {
-			return fmt.Errorf("checksum verification failed: %w", err)
+		if !verified {
+			ui.PrintInfo("Verifying checksum...")
+			if err := sources.VerifyChecksum(downloadPath, recipe.SHA256); err != nil {
+				return fmt.Errorf("checksum verification failed: %w", err)
+			}
+			ui.PrintSuccess("Checksum verified")
 		}
-		ui.PrintSuccess("Checksum verified")
 	} else if recipe.SHA256 == "" {
 		ui.PrintWarning("No checksum provided in recipe, skipping verification")
 	}
 
+	if err := i.lockArtifact(lockfile.KindPack, recipe.Slug, modpack.ManifestURL, downloadPath, ""); err != nil {
+		return err
+	}
+
+	// Recipes point at an archive; Modrinth and CurseForge archives list their mods in a manifest
+	archivePack, err := sources.NewLocalClient().ParseArchive(ctx, downloadPath)
+	if err != nil {
+		return fmt.Errorf("failed to read modpack manifest: %w", err)
+	}
+	modpack.Mods = mergeMods(archivePack.Mods, modpack.Mods)
+	modpack.OverridesDir = archivePack.OverridesDir
+
 	// Extract the archive
 	ui.PrintInfo("Extracting modpack...")
 	if err := sources.ExtractArchive(downloadPath, destDir); err != nil {
@@ -433,7 +997,28 @@ func (i *Installer) downloadAndExtractRecipe(identifier string, modpack *sources
 	return nil
 }
 
-func (i *Installer) installLoader(modpack *sources.Modpack, destDir string) error {
+// resolveLoaderVersion pins the modpack's loader version against the loader's
+// published versions, so the lock records the version that gets installed
+func resolveLoaderVersion(ctx context.Context, modpack *sources.Modpack) error {
+	requested := modpack.LoaderVersion
+
+	version, err := converter.NewLoaderInstaller().ResolveLoaderVersion(ctx, modpack.Loader, modpack.MCVersion, requested)
+	if err != nil {
+		return fmt.Errorf("failed to select %s version: %w", modpack.Loader, err)
+	}
+	modpack.LoaderVersion = version
+
+	if requested == "" || requested == "latest" {
+		ui.PrintInfo(fmt.Sprintf("Using %s %s for Minecraft %s", modpack.Loader, version, modpack.MCVersion))
+	}
+	return nil
+}
+
+func (i *Installer) installLoader(ctx context.Context, modpack *sources.Modpack, destDir string) error {
+	if i.bundle != nil {
+		return i.installBundleLoader(modpack, destDir)
+	}
+
 	opts := &converter.ConversionOptions{
 		DestDir:        destDir,
 		ModpackName:    modpack.Name,
@@ -442,28 +1027,254 @@ func (i *Installer) installLoader(modpack *sources.Modpack, destDir string) erro
 		LoaderVersion:  modpack.LoaderVersion,
 		RecommendedRAM: modpack.RecommendedRAM,
 		PreserveData:   false,
+		MinJava:        modpack.MinJava,
+		MaxJava:        modpack.MaxJava,
 	}
 
 	loaderInstaller := converter.NewLoaderInstaller()
-	return loaderInstaller.Install(opts)
+	artifact, err := loaderInstaller.Artifact(ctx, opts)
+	if err != nil {
+		return err
+	}
+
+	if i.frozenLock != nil {
+		locked := i.frozenLock.Find(lockfile.KindLoader, string(modpack.Loader))
+		if locked == nil {
+			return fmt.Errorf("%w: %s loader is not in %s", lockfile.ErrDeviation, modpack.Loader, lockfile.FileName)
+		}
+		if locked.URL != artifact.URL {
+			return lockfile.Deviation("loader url", artifact.URL, locked.URL)
+		}
+		// Installers are run as soon as they are downloaded, so they are
+		// verified against the lock during the download
+		opts.LoaderChecksums = locked.Checksums()
+	}
+
+	if err := loaderInstaller.Install(ctx, opts); err != nil {
+		return err
+	}
+
+	return i.lockArtifact(lockfile.KindLoader, string(modpack.Loader), artifact.URL,
+		filepath.Join(destDir, artifact.FileName), artifact.FileName)
 }
 
-func (i *Installer) downloadMods(mods []*sources.Mod, destDir string) (int, error) {
+func (i *Installer) downloadMods(ctx context.Context, mods []*sources.Mod, destDir string) (int, error) {
+	if i.bundle != nil {
+		return i.installBundleMods(destDir)
+	}
+
 	modManager := converter.NewModManager()
 	modManager.SkipVerify = i.skipVerify
+	if cacheManager, err := cache.NewManager(); err == nil {
+		modManager.Cache = cacheManager
+	} else {
+		ui.PrintWarning(fmt.Sprintf("Shared mod cache unavailable: %v", err))
+	}
 	serverMods := modManager.FilterServerMods(mods)
 
 	if len(serverMods) == 0 {
 		return 0, nil
 	}
 
-	if err := modManager.DownloadMods(serverMods, destDir); err != nil {
+	if err := modManager.DownloadMods(ctx, serverMods, destDir); err != nil {
 		return 0, err
 	}
 
+	for _, mod := range serverMods {
+		relPath := filepath.Join("mods", mod.FileName)
+		if err := i.lockArtifact(lockfile.KindMod, mod.FileName, mod.DownloadURL, filepath.Join(destDir, relPath), relPath); err != nil {
+			return 0, err
+		}
+	}
+
 	return len(serverMods), nil
 }
 
+// downloadServerFiles places recipe server overrides in the server directory,
+// verifying each against its checksum. Bundle installs take them from the
+// bundle, which was verified when it was opened.
+func (i *Installer) downloadServerFiles(ctx context.Context, files []*sources.ServerFile, destDir string) error {
+	recipeClient := sources.NewRecipeClient()
+	for _, file := range files {
+		relPath, err := serverFilePath(file.Path)
+		if err != nil {
+			return err
+		}
+		filePath := filepath.Join(destDir, relPath)
+		if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+			return fmt.Errorf("failed to create directory for %s: %w", file.Path, err)
+		}
+
+		if i.bundle != nil {
+			if err := i.bundle.ExtractFile(sources.BundleServerDir+filepath.ToSlash(relPath), filePath); err != nil {
+				return err
+			}
+		} else {
+			var expected *checksum.Checksums
+			if !i.skipVerify {
+				expected = &checksum.Checksums{SHA256: file.SHA256, SHA512: file.SHA512}
+				if !expected.HasAny() {
+					return fmt.Errorf("server file %s has no checksum", file.Path)
+				}
+			}
+
+			if err := recipeClient.DownloadToFile(ctx, file.DownloadURL, filePath, expected, nil); err != nil {
+				return fmt.Errorf("failed to download %s: %w", file.Path, err)
+			}
+		}
+
+		if err := i.lockArtifact(lockfile.KindFile, filepath.ToSlash(relPath), file.DownloadURL, filePath, relPath); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// serverFilePath returns the cleaned path of a server override, rejecting
+// paths that would leave the server directory
+func serverFilePath(path string) (string, error) {
+	if path == "" || strings.HasPrefix(path, "/") || filepath.IsAbs(path) {
+		return "", fmt.Errorf("invalid server file path %q", path)
+	}
+	clean := filepath.Clean(filepath.FromSlash(path))
+	if clean == "." || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("server file path %q leaves the server directory", path)
+	}
+	return clean, nil
+}
+
+// lockArtifact records a downloaded file in the lock, or in --frozen mode
+// checks that it is byte-for-byte the locked artifact
+func (i *Installer) lockArtifact(kind lockfile.ArtifactKind, name, url, filePath, relPath string) error {
+	if i.lock == nil {
+		return nil
+	}
+
+	if i.frozenLock != nil {
+		locked := i.frozenLock.Find(kind, name)
+		if locked == nil {
+			return fmt.Errorf("%w: %s %q is not in %s", lockfile.ErrDeviation, kind, name, lockfile.FileName)
+		}
+		return locked.Match(filePath)
+	}
+
+	artifact, err := lockfile.NewArtifact(kind, name, url, filePath, relPath)
+	if err != nil {
+		return fmt.Errorf("failed to lock %s %q: %w", kind, name, err)
+	}
+	i.lock.Add(artifact)
+	return nil
+}
+
+// lockLocalModpack pins a local pack archive by content; its path may differ between hosts
+func (i *Installer) lockLocalModpack(filePath string) error {
+	// A frozen install already matched the archive by content, whatever its name
+	if i.frozenLock != nil {
+		return nil
+	}
+	absPath, err := filepath.Abs(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to resolve modpack path: %w", err)
+	}
+	return i.lockArtifact(lockfile.KindPack, filepath.Base(absPath), "file://"+filepath.ToSlash(absPath), absPath, "")
+}
+
+// checkFrozenIdentifier checks that identifier names the modpack a lock was
+// recorded for. Paths differ between hosts, so a local pack archive is
+// matched by content and a bundle by the artifacts it carries; other
+// identifiers are compared in normalized form.
+func checkFrozenIdentifier(identifier string, lock *lockfile.Lockfile) error {
+	sourceType := sources.DetectSource(identifier)
+	if lockedType := sources.DetectSource(lock.Identifier); sourceType != lockedType {
+		return lockfile.Deviation("source", sourceType, lockedType)
+	}
+
+	switch sourceType {
+	case "local":
+		packs := lock.ByKind(lockfile.KindPack)
+		if len(packs) == 0 {
+			return fmt.Errorf("%w: %s pins no pack archive", lockfile.ErrDeviation, lockfile.FileName)
+		}
+		return packs[0].Match(identifier)
+	case "bundle":
+		return nil
+	}
+
+	if normalizeIdentifier(identifier) != normalizeIdentifier(lock.Identifier) {
+		return lockfile.Deviation("identifier", identifier, lock.Identifier)
+	}
+	return nil
+}
+
+// normalizeIdentifier returns the form of a modpack identifier that is the
+// same however it was typed. Modrinth slugs and GitHub repositories are
+// case-insensitive.
+func normalizeIdentifier(identifier string) string {
+	identifier = strings.TrimSpace(identifier)
+	switch sources.DetectSource(identifier) {
+	case "modrinth":
+		return "modrinth:" + strings.ToLower(strings.TrimSpace(strings.TrimPrefix(identifier, "modrinth:")))
+	case "github":
+		return strings.ToLower(identifier)
+	}
+	return identifier
+}
+
+// applyLock checks a freshly resolved modpack against a lock and pins it to
+// the locked loader version. Any difference is an error.
+func applyLock(lock *lockfile.Lockfile, modpack *sources.Modpack) error {
+	if modpack.MCVersion != lock.MCVersion {
+		return lockfile.Deviation("minecraft version", modpack.MCVersion, lock.MCVersion)
+	}
+	if string(modpack.Loader) != lock.Loader {
+		return lockfile.Deviation("loader", string(modpack.Loader), lock.Loader)
+	}
+	if modpack.LoaderVersion != "" && modpack.LoaderVersion != lock.LoaderVersion {
+		return lockfile.Deviation("loader version", modpack.LoaderVersion, lock.LoaderVersion)
+	}
+	modpack.LoaderVersion = lock.LoaderVersion
+
+	if pack := lock.ByKind(lockfile.KindPack); len(pack) > 0 && modpack.ManifestURL != "" && modpack.ManifestURL != pack[0].URL {
+		return lockfile.Deviation("pack url", modpack.ManifestURL, pack[0].URL)
+	}
+
+	return nil
+}
+
+// applyLockedMods checks the modpack's server mods against a lock and pins
+// their checksums to the locked values. Any difference is an error.
+func applyLockedMods(lock *lockfile.Lockfile, modpack *sources.Modpack) error {
+	serverMods := converter.NewModManager().FilterServerMods(modpack.Mods)
+	lockedMods := lock.ByKind(lockfile.KindMod)
+	if len(serverMods) != len(lockedMods) {
+		return lockfile.Deviation("server mod count", fmt.Sprint(len(serverMods)), fmt.Sprint(len(lockedMods)))
+	}
+
+	for _, mod := range serverMods {
+		locked := lock.Find(lockfile.KindMod, mod.FileName)
+		if locked == nil {
+			return fmt.Errorf("%w: mod %q is not in %s", lockfile.ErrDeviation, mod.FileName, lockfile.FileName)
+		}
+		if mod.DownloadURL != locked.URL {
+			return lockfile.Deviation(fmt.Sprintf("url of mod %q", mod.FileName), mod.DownloadURL, locked.URL)
+		}
+		mod.SHA256 = locked.SHA256
+		mod.SHA512 = locked.SHA512
+	}
+
+	return nil
+}
+
+// generateManifest writes the installed manifest (.chunk.json)
+func (i *Installer) generateManifest(modpack *sources.Modpack, destDir string) error {
+	manifest := preserve.ManifestFromModpack(modpack)
+	if err := config.SaveChunkManifest(filepath.Join(destDir, config.ChunkManifestFile), manifest); err != nil {
+		return fmt.Errorf("failed to write installed manifest: %w", err)
+	}
+	return nil
+}
+
 func (i *Installer) generateConfigs(modpack *sources.Modpack, destDir string) error {
 	opts := &converter.ConversionOptions{
 		DestDir:        destDir,
@@ -473,6 +1284,10 @@ func (i *Installer) generateConfigs(modpack *sources.Modpack, destDir string) er
 		LoaderVersion:  modpack.LoaderVersion,
 		RecommendedRAM: modpack.RecommendedRAM,
 	}
+	if i.rcon != nil {
+		opts.RCONPort = i.rcon.Port
+		opts.RCONPassword = i.rcon.Password
+	}
 
 	configGen := converter.NewConfigGenerator()
 	return configGen.Generate(opts)
@@ -486,12 +1301,46 @@ func (i *Installer) generateScripts(modpack *sources.Modpack, destDir string) er
 		Loader:         modpack.Loader,
 		LoaderVersion:  modpack.LoaderVersion,
 		RecommendedRAM: modpack.RecommendedRAM,
+		JVMArgs:        modpack.JVMArgs,
+		Env:            modpack.Env,
+		MinJava:        modpack.MinJava,
+		MaxJava:        modpack.MaxJava,
 	}
 
 	scriptGen := converter.NewScriptGenerator()
 	return scriptGen.Generate(opts)
 }
 
+// provisionRCON picks a free RCON port, skipping those of other tracked
+// installations, and generates a password
+func provisionRCON(destDir string) (*tracking.RCON, error) {
+	tracker, err := tracking.NewTracker()
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize tracker: %w", err)
+	}
+	installations, err := tracker.ListInstallations()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list installations: %w", err)
+	}
+
+	taken := make(map[int]bool)
+	for _, installation := range installations {
+		if installation.RCON != nil && installation.Path != destDir {
+			taken[installation.RCON.Port] = true
+		}
+	}
+
+	port, err := rcon.FreePort(rcon.DefaultPort, taken)
+	if err != nil {
+		return nil, err
+	}
+	password, err := rcon.GeneratePassword()
+	if err != nil {
+		return nil, err
+	}
+	return &tracking.RCON{Port: port, Password: password}, nil
+}
+
 // createRecipeSnapshot converts modpack data to a recipe snapshot for tracking
 func createRecipeSnapshot(modpack *sources.Modpack) map[string]interface{} {
 	snapshot := map[string]interface{}{
@@ -507,6 +1356,10 @@ func createRecipeSnapshot(modpack *sources.Modpack) map[string]interface{} {
 		"manifest_url":    modpack.ManifestURL,
 	}
 
+	if modpack.Variant != "" {
+		snapshot["variant"] = modpack.Variant
+	}
+
 	if len(modpack.Dependencies) > 0 {
 		snapshot["dependencies"] = modpack.Dependencies
 	}
@@ -569,14 +1422,29 @@ func TrackInstallation(result *Result, identifier string) error {
 	}
 
 	installation := &tracking.Installation{
+		Name:           result.Name,
 		Slug:           slug,
 		Version:        version,
 		Bench:          bench,
 		Path:           result.DestDir,
+		Variant:        result.Modpack.Variant,
+		RCON:           result.RCON,
 		InstalledAt:    time.Now().UTC(),
 		RecipeSnapshot: createRecipeSnapshot(result.Modpack),
 	}
 
+	// The RCON console only survives when server.properties was carried over;
+	// a fresh install writes a server.properties without it
+	if installation.RCON == nil && result.PreservedData {
+		existing, err := tracker.GetInstallation(installation.Path)
+		if err != nil {
+			return fmt.Errorf("failed to load installation: %w", err)
+		}
+		if existing != nil {
+			installation.RCON = existing.RCON
+		}
+	}
+
 	if err := tracker.AddInstallation(installation); err != nil {
 		return fmt.Errorf("failed to track installation: %w", err)
 	}
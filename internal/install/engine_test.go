@@ -2,10 +2,19 @@ package install
 
 import (
 	"archive/zip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/alexinslc/chunk/internal/lockfile"
 	"github.com/alexinslc/chunk/internal/sources"
 	"github.com/alexinslc/chunk/internal/tracking"
 )
@@ -218,6 +227,294 @@ func TestInstallerRollbackNoBackup(t *testing.T) {
 	}
 }
 
+func TestInstallerRollbackDiscardsStaging(t *testing.T) {
+	destDir := filepath.Join(t.TempDir(), "server")
+	originalFile := filepath.Join(destDir, "original.txt")
+	if err := os.MkdirAll(destDir, 0755); err != nil {
+		t.Fatalf("Failed to create destination: %v", err)
+	}
+	if err := os.WriteFile(originalFile, []byte("original content"), 0644); err != nil {
+		t.Fatalf("Failed to create original file: %v", err)
+	}
+
+	installer := NewInstaller()
+	installer.absDestDir = destDir
+	stagingDir, err := installer.createStaging(destDir)
+	if err != nil {
+		t.Fatalf("createStaging failed: %v", err)
+	}
+	if err := installer.prepareDirectory(stagingDir, false); err != nil {
+		t.Fatalf("prepareDirectory failed: %v", err)
+	}
+
+	if err := installer.Rollback(); err != nil {
+		t.Fatalf("Rollback failed: %v", err)
+	}
+
+	if _, err := os.Stat(stagingDir); !os.IsNotExist(err) {
+		t.Error("Expected staging directory to be removed")
+	}
+	if data, err := os.ReadFile(originalFile); err != nil || string(data) != "original content" {
+		t.Errorf("Expected destination to be untouched, got %q (%v)", data, err)
+	}
+}
+
+func TestInstallerSwap(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	tests := []struct {
+		name    string
+		dir     string
+		setup   func(destDir string) error
+		wantOld bool
+	}{
+		{name: "missing destination", dir: "missing", setup: func(string) error { return nil }},
+		{name: "empty destination", dir: "empty", setup: func(destDir string) error { return os.Mkdir(destDir, 0755) }},
+		{name: "existing installation", dir: "existing", wantOld: true, setup: func(destDir string) error {
+			if err := os.Mkdir(destDir, 0755); err != nil {
+				return err
+			}
+			return os.WriteFile(filepath.Join(destDir, "old.txt"), []byte("old"), 0644)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			destDir := filepath.Join(tmpDir, tt.dir)
+			if err := tt.setup(destDir); err != nil {
+				t.Fatalf("Failed to set up destination: %v", err)
+			}
+
+			installer := NewInstaller()
+			installer.absDestDir = destDir
+			stagingDir, err := installer.createStaging(destDir)
+			if err != nil {
+				t.Fatalf("createStaging failed: %v", err)
+			}
+			if err := installer.prepareDirectory(stagingDir, false); err != nil {
+				t.Fatalf("prepareDirectory failed: %v", err)
+			}
+
+			if err := installer.swap(destDir); err != nil {
+				t.Fatalf("swap failed: %v", err)
+			}
+			if _, err := os.Stat(filepath.Join(destDir, "mods")); err != nil {
+				t.Errorf("Expected staged installation in place: %v", err)
+			}
+			if _, err := os.Stat(stagingDir); !os.IsNotExist(err) {
+				t.Error("Expected staging directory to be moved")
+			}
+			if _, err := os.Stat(filepath.Join(destDir, stagingMarker)); !os.IsNotExist(err) {
+				t.Error("Expected staging marker to be removed from the installation")
+			}
+			if tt.wantOld {
+				if _, err := os.Stat(filepath.Join(installer.backupDir, "old.txt")); err != nil {
+					t.Errorf("Expected previous installation to be kept aside: %v", err)
+				}
+			}
+
+			if tt.dir == "missing" {
+				return
+			}
+
+			// Until Install cleans up, a rollback can still put the previous state back
+			if err := installer.Rollback(); err != nil {
+				t.Fatalf("Rollback failed: %v", err)
+			}
+			if tt.wantOld {
+				if _, err := os.Stat(filepath.Join(destDir, "old.txt")); err != nil {
+					t.Errorf("Expected previous installation to be restored: %v", err)
+				}
+			} else if entries, err := os.ReadDir(destDir); err != nil || len(entries) != 0 {
+				t.Errorf("Expected empty destination, got %d entries (%v)", len(entries), err)
+			}
+		})
+	}
+}
+
+func TestCreateStaging(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// A leftover staging directory from an interrupted install is replaced
+	destDir := filepath.Join(tmpDir, "server")
+	leftover, err := NewInstaller().createStaging(destDir)
+	if err != nil {
+		t.Fatalf("createStaging failed: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(leftover, "stale.txt"), []byte("stale"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	stagingDir, err := NewInstaller().createStaging(destDir)
+	if err != nil {
+		t.Fatalf("createStaging over a leftover staging directory failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(stagingDir, "stale.txt")); !os.IsNotExist(err) {
+		t.Error("Expected leftover staging directory to be cleared")
+	}
+
+	// A directory chunk did not create is never removed
+	otherDest := filepath.Join(tmpDir, "other")
+	unrelated := otherDest + stagingSuffix
+	if err := os.Mkdir(unrelated, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(unrelated, "notes.txt"), []byte("keep"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewInstaller().createStaging(otherDest); err == nil {
+		t.Error("Expected createStaging to refuse an unrelated directory")
+	}
+	if data, err := os.ReadFile(filepath.Join(unrelated, "notes.txt")); err != nil || string(data) != "keep" {
+		t.Errorf("Expected unrelated directory to be untouched, got %q (%v)", data, err)
+	}
+}
+
+func TestCarryPreservedData(t *testing.T) {
+	tmpDir := t.TempDir()
+	destDir := filepath.Join(tmpDir, "server")
+	stagingDir := filepath.Join(tmpDir, "staging")
+
+	files := map[string]string{
+		filepath.Join("world", "level.dat"): "world",
+		"server.properties":                 "motd=kept",
+		"eula.txt":                          "eula=true",
+		filepath.Join("mods", "old.jar"):    "old mod",
+	}
+	for name, content := range files {
+		path := filepath.Join(destDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	if err := os.MkdirAll(stagingDir, 0755); err != nil {
+		t.Fatalf("Failed to create staging dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(stagingDir, "server.properties"), []byte("motd=generated"), 0644); err != nil {
+		t.Fatalf("Failed to write server.properties: %v", err)
+	}
+
+	preserved, err := NewInstaller().carryPreservedData(destDir, stagingDir)
+	if err != nil {
+		t.Fatalf("carryPreservedData failed: %v", err)
+	}
+	if !preserved {
+		t.Error("Expected carryPreservedData to report the previous installation")
+	}
+
+	for name, want := range map[string]string{
+		filepath.Join("world", "level.dat"): "world",
+		"server.properties":                 "motd=kept",
+		"eula.txt":                          "eula=true",
+	} {
+		if data, err := os.ReadFile(filepath.Join(stagingDir, name)); err != nil || string(data) != want {
+			t.Errorf("Expected %s to be carried over as %q, got %q (%v)", name, want, data, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(stagingDir, "mods", "old.jar")); !os.IsNotExist(err) {
+		t.Error("Expected old mods not to be carried over")
+	}
+}
+
+func TestCarryPreservedDataUserFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	destDir := filepath.Join(tmpDir, "server")
+	stagingDir := filepath.Join(tmpDir, "staging")
+
+	files := map[string]string{
+		"server.properties":                       "level-name=survival\n",
+		filepath.Join("survival", "level.dat"):    "world",
+		filepath.Join("survival_nether", "DIM-1"): "nether",
+		filepath.Join("mods", "pack.jar"):         "pack mod",
+		filepath.Join("mods", "extra.jar"):        "hand-added mod",
+		filepath.Join("config", "runtime.toml"):   "old runtime config",
+		filepath.Join("config", "shipped.toml"):   "old shipped config",
+		filepath.Join("libraries", "old.jar"):     "old library",
+		"backup.sh":                               "#!/bin/sh",
+		"start.sh":                                "old start script",
+	}
+	for name, content := range files {
+		path := filepath.Join(destDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	if err := os.Chmod(filepath.Join(destDir, "backup.sh"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	lock := lockfile.New("pack", "Pack", "1.20.1", "fabric", "0.15.0")
+	artifact, err := lockfile.NewArtifact(lockfile.KindMod, "pack.jar", "https://example.com/pack.jar", filepath.Join(destDir, "mods", "pack.jar"), "mods/pack.jar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lock.Add(artifact)
+	if err := lock.Save(lockfile.Path(destDir)); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, content := range map[string]string{
+		filepath.Join("config", "shipped.toml"): "new shipped config",
+		"start.sh":                              "new start script",
+	} {
+		path := filepath.Join(stagingDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := NewInstaller().carryPreservedData(destDir, stagingDir); err != nil {
+		t.Fatalf("carryPreservedData failed: %v", err)
+	}
+
+	for name, want := range map[string]string{
+		filepath.Join("survival", "level.dat"):    "world",
+		filepath.Join("survival_nether", "DIM-1"): "nether",
+		filepath.Join("mods", "extra.jar"):        "hand-added mod",
+		filepath.Join("config", "runtime.toml"):   "old runtime config",
+		filepath.Join("config", "shipped.toml"):   "new shipped config",
+		"backup.sh":                               "#!/bin/sh",
+		"start.sh":                                "new start script",
+	} {
+		if data, err := os.ReadFile(filepath.Join(stagingDir, name)); err != nil || string(data) != want {
+			t.Errorf("Expected %s to be %q, got %q (%v)", name, want, data, err)
+		}
+	}
+	for _, name := range []string{filepath.Join("mods", "pack.jar"), filepath.Join("libraries", "old.jar")} {
+		if _, err := os.Stat(filepath.Join(stagingDir, name)); !os.IsNotExist(err) {
+			t.Errorf("Expected pack-owned %s not to be carried over", name)
+		}
+	}
+	if info, err := os.Stat(filepath.Join(stagingDir, "backup.sh")); err == nil && info.Mode().Perm() != 0755 {
+		t.Errorf("Expected backup.sh to stay executable, got %v", info.Mode())
+	}
+}
+
+func TestInstallCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	destDir := filepath.Join(t.TempDir(), "server")
+	installer := NewInstaller()
+	_, err := installer.Install(ctx, &Options{Identifier: "modrinth:some-modpack", DestDir: destDir})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+	if err := installer.Rollback(); err != nil {
+		t.Fatalf("Rollback failed: %v", err)
+	}
+	if _, err := os.Stat(destDir); !os.IsNotExist(err) {
+		t.Error("Expected a cancelled install to leave no destination")
+	}
+}
+
 func TestResultFields(t *testing.T) {
 	result := &Result{
 		ModpackName:   "Test Modpack",
@@ -392,6 +689,44 @@ func TestTrackInstallation(t *testing.T) {
 	}
 }
 
+func TestTrackInstallationRCON(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	modpack := &sources.Modpack{Name: "Test", Identifier: "test", MCVersion: "1.20.1", Loader: sources.LoaderFabric}
+	result := &Result{
+		DestDir: "/opt/minecraft/test",
+		Modpack: modpack,
+		RCON:    &tracking.RCON{Port: 25575, Password: "secret"},
+	}
+	if err := TrackInstallation(result, "test"); err != nil {
+		t.Fatalf("TrackInstallation failed: %v", err)
+	}
+	tracker, err := tracking.NewTracker()
+	if err != nil {
+		t.Fatalf("Failed to create tracker: %v", err)
+	}
+
+	// Upgrades keep server.properties, and with it the RCON console
+	upgrade := &Result{DestDir: "/opt/minecraft/test", Modpack: modpack, PreservedData: true}
+	if err := TrackInstallation(upgrade, "test"); err != nil {
+		t.Fatalf("TrackInstallation failed: %v", err)
+	}
+	installation, _ := tracker.GetInstallation("/opt/minecraft/test")
+	if installation == nil || installation.RCON == nil || installation.RCON.Port != 25575 {
+		t.Errorf("Expected upgrade to keep the RCON console, got %+v", installation)
+	}
+
+	// A fresh install writes server.properties without it
+	reinstall := &Result{DestDir: "/opt/minecraft/test", Modpack: modpack}
+	if err := TrackInstallation(reinstall, "test"); err != nil {
+		t.Fatalf("TrackInstallation failed: %v", err)
+	}
+	installation, _ = tracker.GetInstallation("/opt/minecraft/test")
+	if installation == nil || installation.RCON != nil {
+		t.Errorf("Expected reinstall to clear the RCON console, got %+v", installation)
+	}
+}
+
 func TestTrackInstallationNilResult(t *testing.T) {
 	err := TrackInstallation(nil, "test")
 	if err == nil {
@@ -520,3 +855,289 @@ func (z *zipWriterHelper) writeFile(name string, content []byte) error {
 func (z *zipWriterHelper) close() error {
 	return z.writer.Close()
 }
+
+func TestApplyLock(t *testing.T) {
+	newModpack := func() *sources.Modpack {
+		return &sources.Modpack{
+			MCVersion: "1.20.1",
+			Loader:    sources.LoaderForge,
+			Mods: []*sources.Mod{
+				{FileName: "jei.jar", DownloadURL: "https://example.com/jei.jar", Side: sources.SideBoth},
+				{FileName: "optifine.jar", DownloadURL: "https://example.com/optifine.jar", Side: sources.SideClient},
+			},
+		}
+	}
+
+	newLock := func() *lockfile.Lockfile {
+		lock := lockfile.New("pack", "Pack", "1.20.1", "forge", "47.2.0")
+		lock.Add(&lockfile.Artifact{Kind: lockfile.KindMod, Name: "jei.jar", URL: "https://example.com/jei.jar", SHA256: "abc", SHA512: "def"})
+		return lock
+	}
+
+	tests := []struct {
+		name    string
+		mutate  func(*sources.Modpack)
+		wantErr bool
+	}{
+		{name: "matches lock", mutate: func(m *sources.Modpack) {}, wantErr: false},
+		{name: "different mc version", mutate: func(m *sources.Modpack) { m.MCVersion = "1.20.2" }, wantErr: true},
+		{name: "different loader", mutate: func(m *sources.Modpack) { m.Loader = sources.LoaderFabric }, wantErr: true},
+		{name: "different loader version", mutate: func(m *sources.Modpack) { m.LoaderVersion = "47.3.0" }, wantErr: true},
+		{name: "mod url changed", mutate: func(m *sources.Modpack) { m.Mods[0].DownloadURL = "https://evil.example.com/jei.jar" }, wantErr: true},
+		{
+			name: "mod added",
+			mutate: func(m *sources.Modpack) {
+				m.Mods = append(m.Mods, &sources.Mod{FileName: "new.jar", DownloadURL: "https://example.com/new.jar"})
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			modpack := newModpack()
+			tt.mutate(modpack)
+
+			lock := newLock()
+			err := applyLock(lock, modpack)
+			if err == nil {
+				err = applyLockedMods(lock, modpack)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("applyLock() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if !errors.Is(err, lockfile.ErrDeviation) {
+					t.Errorf("Expected ErrDeviation, got %v", err)
+				}
+				return
+			}
+
+			if modpack.LoaderVersion != "47.2.0" {
+				t.Errorf("Expected loader version pinned to '47.2.0', got '%s'", modpack.LoaderVersion)
+			}
+			if modpack.Mods[0].SHA512 != "def" {
+				t.Errorf("Expected mod checksum pinned from lock, got '%s'", modpack.Mods[0].SHA512)
+			}
+		})
+	}
+}
+
+func TestLockArtifact(t *testing.T) {
+	tmpDir := t.TempDir()
+	modPath := filepath.Join(tmpDir, "jei.jar")
+	if err := os.WriteFile(modPath, []byte("jei"), 0644); err != nil {
+		t.Fatalf("Failed to write mod: %v", err)
+	}
+
+	// Recording mode adds the artifact to the lock
+	installer := NewInstaller()
+	installer.lock = lockfile.New("pack", "Pack", "1.20.1", "forge", "47.2.0")
+	if err := installer.lockArtifact(lockfile.KindMod, "jei.jar", "https://example.com/jei.jar", modPath, "mods/jei.jar"); err != nil {
+		t.Fatalf("lockArtifact failed: %v", err)
+	}
+	if installer.lock.Find(lockfile.KindMod, "jei.jar") == nil {
+		t.Fatal("Expected jei.jar to be recorded")
+	}
+
+	// Frozen mode checks files against the lock instead
+	frozen := NewInstaller()
+	frozen.frozenLock = installer.lock
+	frozen.lock = installer.lock
+	if err := frozen.lockArtifact(lockfile.KindMod, "jei.jar", "https://example.com/jei.jar", modPath, "mods/jei.jar"); err != nil {
+		t.Errorf("Expected identical file to match lock: %v", err)
+	}
+
+	if err := os.WriteFile(modPath, []byte("tampered"), 0644); err != nil {
+		t.Fatalf("Failed to write mod: %v", err)
+	}
+	if err := frozen.lockArtifact(lockfile.KindMod, "jei.jar", "https://example.com/jei.jar", modPath, "mods/jei.jar"); !errors.Is(err, lockfile.ErrDeviation) {
+		t.Errorf("Expected ErrDeviation for tampered file, got %v", err)
+	}
+	if err := frozen.lockArtifact(lockfile.KindMod, "other.jar", "https://example.com/other.jar", modPath, "mods/other.jar"); !errors.Is(err, lockfile.ErrDeviation) {
+		t.Errorf("Expected ErrDeviation for unlocked file, got %v", err)
+	}
+}
+
+func TestMergeMods(t *testing.T) {
+	archiveMods := []*sources.Mod{
+		{FileName: "jei.jar", DownloadURL: "https://example.com/archive/jei.jar"},
+		{FileName: "Lithium.jar", DownloadURL: "https://example.com/archive/lithium.jar"},
+	}
+	listed := []*sources.Mod{
+		{FileName: "lithium.jar", DownloadURL: "https://example.com/recipe/lithium.jar"},
+		{FileName: "ferritecore.jar", DownloadURL: "https://example.com/recipe/ferritecore.jar"},
+	}
+
+	merged := mergeMods(archiveMods, listed)
+	if len(merged) != 3 {
+		t.Fatalf("Expected 3 mods, got %d", len(merged))
+	}
+	for _, mod := range merged {
+		if strings.EqualFold(mod.FileName, "lithium.jar") && mod.DownloadURL != "https://example.com/recipe/lithium.jar" {
+			t.Errorf("Expected the recipe's lithium.jar to replace the archive's, got %s", mod.DownloadURL)
+		}
+	}
+}
+
+func TestDownloadServerFiles(t *testing.T) {
+	content := []byte("max-tick-time=-1\n")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(content)
+	}))
+	defer server.Close()
+
+	sum := sha256.Sum256(content)
+	goodSum := hex.EncodeToString(sum[:])
+
+	destDir := t.TempDir()
+	installer := NewInstaller()
+	installer.lock = lockfile.New("pack", "Pack", "1.20.1", "fabric", "0.15.0")
+
+	files := []*sources.ServerFile{{Path: "config/server.toml", DownloadURL: server.URL + "/server.toml", SHA256: goodSum}}
+	if err := installer.downloadServerFiles(context.Background(), files, destDir); err != nil {
+		t.Fatalf("downloadServerFiles failed: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(destDir, "config", "server.toml"))
+	if err != nil || string(data) != string(content) {
+		t.Errorf("Expected server file to be written, got %q (%v)", data, err)
+	}
+	if installer.lock.Find(lockfile.KindFile, "config/server.toml") == nil {
+		t.Error("Expected the server file to be locked")
+	}
+
+	tests := []struct {
+		name string
+		file *sources.ServerFile
+	}{
+		{name: "checksum mismatch", file: &sources.ServerFile{Path: "config/bad.toml", DownloadURL: server.URL, SHA256: strings.Repeat("0", 64)}},
+		{name: "missing checksum", file: &sources.ServerFile{Path: "config/bad.toml", DownloadURL: server.URL}},
+		{name: "escaping path", file: &sources.ServerFile{Path: "../outside.toml", DownloadURL: server.URL, SHA256: goodSum}},
+		{name: "absolute path", file: &sources.ServerFile{Path: "/tmp/outside.toml", DownloadURL: server.URL, SHA256: goodSum}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := installer.downloadServerFiles(context.Background(), []*sources.ServerFile{tt.file}, destDir); err == nil {
+				t.Error("Expected downloadServerFiles to fail")
+			}
+		})
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(destDir), "outside.toml")); err == nil {
+		t.Error("Expected nothing to be written outside the server directory")
+	}
+}
+
+func TestInstallFrozenRequiresLock(t *testing.T) {
+	installer := NewInstaller()
+	_, err := installer.Install(context.Background(), &Options{
+		Identifier: "./missing.mrpack",
+		DestDir:    t.TempDir(),
+		Frozen:     true,
+	})
+	if !errors.Is(err, lockfile.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound without chunk.lock, got %v", err)
+	}
+}
+
+func TestInstallFrozenIdentifierDeviation(t *testing.T) {
+	destDir := t.TempDir()
+	lock := lockfile.New("atm9", "All the Mods 9", "1.20.1", "forge", "47.2.0")
+	if err := lock.Save(lockfile.Path(destDir)); err != nil {
+		t.Fatalf("Failed to save lock: %v", err)
+	}
+
+	installer := NewInstaller()
+	_, err := installer.Install(context.Background(), &Options{
+		Identifier: "./other.mrpack",
+		DestDir:    destDir,
+		Frozen:     true,
+	})
+	if !errors.Is(err, lockfile.ErrDeviation) {
+		t.Errorf("Expected ErrDeviation for another modpack, got %v", err)
+	}
+}
+
+func TestCheckFrozenIdentifier(t *testing.T) {
+	tmpDir := t.TempDir()
+	packPath := filepath.Join(tmpDir, "pack.mrpack")
+	if err := os.WriteFile(packPath, []byte("pack archive"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	localLock := lockfile.New("./pack.mrpack", "Pack", "1.20.1", "fabric", "0.15.0")
+	artifact, err := lockfile.NewArtifact(lockfile.KindPack, "pack.mrpack", "file://"+filepath.ToSlash(packPath), packPath, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	localLock.Add(artifact)
+
+	// The same archive under another name and path, as on another host
+	copyPath := filepath.Join(tmpDir, "elsewhere", "renamed.mrpack")
+	if err := os.MkdirAll(filepath.Dir(copyPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(copyPath, []byte("pack archive"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	otherPath := filepath.Join(tmpDir, "other.mrpack")
+	if err := os.WriteFile(otherPath, []byte("other archive"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		identifier string
+		lock       *lockfile.Lockfile
+		wantErr    bool
+	}{
+		{name: "same recipe", identifier: "atm9", lock: lockfile.New("atm9", "", "", "", "")},
+		{name: "modrinth slug case", identifier: "modrinth:ATM9", lock: lockfile.New("modrinth:atm9", "", "", "", "")},
+		{name: "github repository case", identifier: "Owner/Pack", lock: lockfile.New("owner/pack", "", "", "", "")},
+		{name: "local archive moved", identifier: copyPath, lock: localLock},
+		{name: "other recipe", identifier: "atm10", lock: lockfile.New("atm9", "", "", "", ""), wantErr: true},
+		{name: "other source", identifier: "./other.mrpack", lock: lockfile.New("atm9", "", "", "", ""), wantErr: true},
+		{name: "other local archive", identifier: otherPath, lock: localLock, wantErr: true},
+		{name: "local lock without archive", identifier: packPath, lock: lockfile.New("./pack.mrpack", "", "", "", ""), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkFrozenIdentifier(tt.identifier, tt.lock)
+			if tt.wantErr && !errors.Is(err, lockfile.ErrDeviation) {
+				t.Errorf("Expected ErrDeviation, got %v", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("Expected %s to match the lock, got %v", tt.identifier, err)
+			}
+		})
+	}
+}
+
+func TestInstallRejectsTakenName(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+
+	tracker, err := tracking.NewTracker()
+	if err != nil {
+		t.Fatalf("Failed to create tracker: %v", err)
+	}
+	if err := tracker.AddInstallation(&tracking.Installation{
+		Name:        "survival-eu",
+		Slug:        "atm9",
+		Version:     "1.0.0",
+		Bench:       "test",
+		Path:        filepath.Join(tmpDir, "existing"),
+		InstalledAt: time.Now(),
+	}); err != nil {
+		t.Fatalf("Failed to track installation: %v", err)
+	}
+
+	installer := NewInstaller()
+	_, err = installer.Install(context.Background(), &Options{
+		Identifier: "./missing.mrpack",
+		DestDir:    filepath.Join(tmpDir, "other"),
+		Name:       "survival-eu",
+	})
+	if !errors.Is(err, tracking.ErrNameTaken) {
+		t.Errorf("Expected ErrNameTaken, got %v", err)
+	}
+}
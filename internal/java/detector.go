@@ -226,19 +226,31 @@ func (d *JavaDetector) deduplicate(installations []*JavaInstallation) []*JavaIns
 }
 
 func (d *JavaDetector) FindCompatible(mcVersion string) (*JavaInstallation, error) {
+	return d.FindInRange(mcVersion, 0, 0)
+}
+
+// FindInRange returns an installation new enough for mcVersion whose major
+// version is also within [minJava, maxJava]. Zero bounds are unconstrained.
+func (d *JavaDetector) FindInRange(mcVersion string, minJava, maxJava int) (*JavaInstallation, error) {
 	installations, err := d.DetectAll()
 	if err != nil {
 		return nil, err
 	}
 
 	requiredJava := GetRequiredJavaVersion(mcVersion)
+	if minJava > requiredJava {
+		requiredJava = minJava
+	}
 
 	for _, install := range installations {
-		if install.Major >= requiredJava {
+		if install.Major >= requiredJava && (maxJava == 0 || install.Major <= maxJava) {
 			return install, nil
 		}
 	}
 
+	if maxJava != 0 {
+		return nil, fmt.Errorf("no compatible Java installation found (need Java %d to %d)", requiredJava, maxJava)
+	}
 	return nil, fmt.Errorf("no compatible Java installation found (need Java %d+)", requiredJava)
 }
 
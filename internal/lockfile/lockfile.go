@@ -0,0 +1,223 @@
+// Package lockfile records the exact artifacts of an installation in chunk.lock
+// so the same server can be rebuilt byte-for-byte later.
+package lockfile
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/alexinslc/chunk/internal/checksum"
+)
+
+const (
+	// FileName is the name of the lock file written into the server directory
+	FileName = "chunk.lock"
+	// CurrentVersion is the lock file format version
+	CurrentVersion = 1
+)
+
+var (
+	// ErrNotFound is returned when no chunk.lock exists in the server directory
+	ErrNotFound = errors.New("chunk.lock not found")
+	// ErrDeviation is returned when an installation differs from its lock file
+	ErrDeviation = errors.New("installation deviates from chunk.lock")
+)
+
+// ArtifactKind identifies what a locked artifact is used for
+type ArtifactKind string
+
+const (
+	KindLoader ArtifactKind = "loader"
+	KindPack   ArtifactKind = "pack"
+	KindMod    ArtifactKind = "mod"
+	KindFile   ArtifactKind = "file" // A recipe server override, named by its path
+)
+
+// Artifact is a single downloaded file pinned by URL, size and checksums
+type Artifact struct {
+	Kind   ArtifactKind `json:"kind"`
+	Name   string       `json:"name"`
+	URL    string       `json:"url"`
+	Path   string       `json:"path,omitempty"` // Relative to the server directory
+	Size   int64        `json:"size"`
+	SHA256 string       `json:"sha256"`
+	SHA512 string       `json:"sha512"`
+}
+
+// Checksums returns the artifact's checksums for verification
+func (a *Artifact) Checksums() *checksum.Checksums {
+	return &checksum.Checksums{
+		SHA256: a.SHA256,
+		SHA512: a.SHA512,
+	}
+}
+
+// Lockfile pins every artifact of an installation
+type Lockfile struct {
+	LockVersion   int         `json:"lock_version"`
+	Identifier    string      `json:"identifier"`
+	Name          string      `json:"name"`
+	MCVersion     string      `json:"mc_version"`
+	Loader        string      `json:"loader"`
+	LoaderVersion string      `json:"loader_version,omitempty"`
+	Variant       string      `json:"variant,omitempty"` // Recipe variant, if one was selected
+	GeneratedAt   time.Time   `json:"generated_at"`
+	Artifacts     []*Artifact `json:"artifacts"`
+}
+
+// New creates an empty lock file for the given modpack
+func New(identifier, name, mcVersion, loader, loaderVersion string) *Lockfile {
+	return &Lockfile{
+		LockVersion:   CurrentVersion,
+		Identifier:    identifier,
+		Name:          name,
+		MCVersion:     mcVersion,
+		Loader:        loader,
+		LoaderVersion: loaderVersion,
+		GeneratedAt:   time.Now().UTC(),
+		Artifacts:     []*Artifact{},
+	}
+}
+
+// Path returns the location of chunk.lock inside a server directory
+func Path(serverDir string) string {
+	return filepath.Join(serverDir, FileName)
+}
+
+// Load reads a lock file from disk
+func Load(path string) (*Lockfile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: %s", ErrNotFound, path)
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", FileName, err)
+	}
+
+	var lock Lockfile
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", FileName, err)
+	}
+
+	if lock.LockVersion > CurrentVersion {
+		return nil, fmt.Errorf("unsupported %s version %d (this chunk supports up to %d)", FileName, lock.LockVersion, CurrentVersion)
+	}
+
+	for _, artifact := range lock.Artifacts {
+		if artifact.URL == "" || (artifact.SHA256 == "" && artifact.SHA512 == "") {
+			return nil, fmt.Errorf("invalid %s: artifact %q is missing url or checksum", FileName, artifact.Name)
+		}
+	}
+
+	return &lock, nil
+}
+
+// Save writes the lock file to disk
+func (l *Lockfile) Save(path string) error {
+	if l.Artifacts == nil {
+		l.Artifacts = []*Artifact{}
+	}
+
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", FileName, err)
+	}
+
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", FileName, err)
+	}
+
+	return nil
+}
+
+// Add records an artifact, replacing any existing entry of the same kind and name
+func (l *Lockfile) Add(artifact *Artifact) {
+	for i, existing := range l.Artifacts {
+		if existing.Kind == artifact.Kind && existing.Name == artifact.Name {
+			l.Artifacts[i] = artifact
+			return
+		}
+	}
+	l.Artifacts = append(l.Artifacts, artifact)
+}
+
+// Find returns the artifact of the given kind and name, or nil if not locked
+func (l *Lockfile) Find(kind ArtifactKind, name string) *Artifact {
+	for _, artifact := range l.Artifacts {
+		if artifact.Kind == kind && artifact.Name == name {
+			return artifact
+		}
+	}
+	return nil
+}
+
+// ByKind returns all artifacts of the given kind in lock order
+func (l *Lockfile) ByKind(kind ArtifactKind) []*Artifact {
+	var artifacts []*Artifact
+	for _, artifact := range l.Artifacts {
+		if artifact.Kind == kind {
+			artifacts = append(artifacts, artifact)
+		}
+	}
+	return artifacts
+}
+
+// NewArtifact builds an artifact by measuring a downloaded file on disk.
+// relPath is stored as-is and may be empty for files outside the server directory.
+func NewArtifact(kind ArtifactKind, name, url, filePath, relPath string) (*Artifact, error) {
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat %s: %w", filePath, err)
+	}
+
+	sums, err := checksum.CalculateFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Artifact{
+		Kind:   kind,
+		Name:   name,
+		URL:    url,
+		Path:   filepath.ToSlash(relPath),
+		Size:   info.Size(),
+		SHA256: sums.SHA256,
+		SHA512: sums.SHA512,
+	}, nil
+}
+
+// Match checks that a file on disk is byte-for-byte the locked artifact
+func (a *Artifact) Match(filePath string) error {
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return fmt.Errorf("%w: %s %q: %v", ErrDeviation, a.Kind, a.Name, err)
+	}
+
+	if info.Size() != a.Size {
+		return fmt.Errorf("%w: %s %q size is %d bytes, locked %d", ErrDeviation, a.Kind, a.Name, info.Size(), a.Size)
+	}
+
+	sums, err := checksum.CalculateFile(filePath)
+	if err != nil {
+		return err
+	}
+
+	if a.SHA512 != "" && !strings.EqualFold(sums.SHA512, a.SHA512) {
+		return fmt.Errorf("%w: %s %q sha512 is %s, locked %s", ErrDeviation, a.Kind, a.Name, sums.SHA512, a.SHA512)
+	}
+	if a.SHA256 != "" && !strings.EqualFold(sums.SHA256, a.SHA256) {
+		return fmt.Errorf("%w: %s %q sha256 is %s, locked %s", ErrDeviation, a.Kind, a.Name, sums.SHA256, a.SHA256)
+	}
+
+	return nil
+}
+
+// Deviation builds an ErrDeviation error describing a mismatched field
+func Deviation(field, got, locked string) error {
+	return fmt.Errorf("%w: %s is %q, locked %q", ErrDeviation, field, got, locked)
+}
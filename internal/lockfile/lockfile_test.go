@@ -0,0 +1,134 @@
+package lockfile
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("Failed to create dir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	tmpDir := t.TempDir()
+	modPath := filepath.Join(tmpDir, "mods", "jei.jar")
+	writeFile(t, modPath, "jei content")
+
+	lock := New("atm9", "All the Mods 9", "1.20.1", "forge", "47.2.0")
+	artifact, err := NewArtifact(KindMod, "jei.jar", "https://example.com/jei.jar", modPath, filepath.Join("mods", "jei.jar"))
+	if err != nil {
+		t.Fatalf("NewArtifact failed: %v", err)
+	}
+	lock.Add(artifact)
+
+	if err := lock.Save(Path(tmpDir)); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	loaded, err := Load(Path(tmpDir))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if loaded.Identifier != "atm9" || loaded.LoaderVersion != "47.2.0" {
+		t.Errorf("Unexpected lock header: %+v", loaded)
+	}
+
+	got := loaded.Find(KindMod, "jei.jar")
+	if got == nil {
+		t.Fatal("Expected jei.jar to be locked")
+	}
+	if got.Size != int64(len("jei content")) {
+		t.Errorf("Expected size %d, got %d", len("jei content"), got.Size)
+	}
+	if got.Path != "mods/jei.jar" {
+		t.Errorf("Expected path 'mods/jei.jar', got '%s'", got.Path)
+	}
+	if got.SHA256 == "" || got.SHA512 == "" {
+		t.Error("Expected both checksums to be recorded")
+	}
+}
+
+func TestLoadMissing(t *testing.T) {
+	_, err := Load(Path(t.TempDir()))
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestLoadRejectsArtifactWithoutChecksum(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFile(t, Path(tmpDir), `{"lock_version":1,"artifacts":[{"kind":"mod","name":"a.jar","url":"https://example.com/a.jar"}]}`)
+
+	if _, err := Load(Path(tmpDir)); err == nil {
+		t.Error("Expected error for artifact without checksum")
+	}
+}
+
+func TestLoadRejectsNewerVersion(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFile(t, Path(tmpDir), `{"lock_version":99,"artifacts":[]}`)
+
+	if _, err := Load(Path(tmpDir)); err == nil {
+		t.Error("Expected error for unsupported lock version")
+	}
+}
+
+func TestAddReplacesExisting(t *testing.T) {
+	lock := New("pack", "Pack", "1.20.1", "fabric", "0.15.0")
+	lock.Add(&Artifact{Kind: KindMod, Name: "a.jar", URL: "https://one"})
+	lock.Add(&Artifact{Kind: KindMod, Name: "a.jar", URL: "https://two"})
+	lock.Add(&Artifact{Kind: KindLoader, Name: "fabric", URL: "https://loader"})
+
+	if len(lock.Artifacts) != 2 {
+		t.Fatalf("Expected 2 artifacts, got %d", len(lock.Artifacts))
+	}
+	if got := lock.Find(KindMod, "a.jar").URL; got != "https://two" {
+		t.Errorf("Expected replaced URL 'https://two', got '%s'", got)
+	}
+	if len(lock.ByKind(KindMod)) != 1 {
+		t.Errorf("Expected 1 mod artifact, got %d", len(lock.ByKind(KindMod)))
+	}
+}
+
+func TestArtifactMatch(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "file.jar")
+	writeFile(t, path, "original")
+
+	artifact, err := NewArtifact(KindMod, "file.jar", "https://example.com/file.jar", path, "")
+	if err != nil {
+		t.Fatalf("NewArtifact failed: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		content string
+		wantErr bool
+	}{
+		{name: "identical", content: "original", wantErr: false},
+		{name: "different size", content: "changed content", wantErr: true},
+		{name: "same size different bytes", content: "ORIGINAL", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writeFile(t, path, tt.content)
+			err := artifact.Match(path)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Match() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrDeviation) {
+				t.Errorf("Expected ErrDeviation, got %v", err)
+			}
+		})
+	}
+}
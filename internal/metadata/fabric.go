@@ -1,10 +1,13 @@
 package metadata
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"time"
+
+	"github.com/alexinslc/chunk/internal/mirror"
 )
 
 const (
@@ -26,7 +29,8 @@ type FabricClient struct {
 func NewFabricClient(cache *Cache) *FabricClient {
 	return &FabricClient{
 		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout:   30 * time.Second,
+			Transport: &mirror.Transport{},
 		},
 		cache: cache,
 	}
@@ -48,8 +52,8 @@ type fabricGameResponse struct {
 }
 
 // GetVersions returns all available Fabric loader versions.
-func (f *FabricClient) GetVersions() ([]LoaderVersion, error) {
-	loaders, err := f.getLoaderVersions()
+func (f *FabricClient) GetVersions(ctx context.Context) ([]LoaderVersion, error) {
+	loaders, err := f.getLoaderVersions(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -68,9 +72,9 @@ func (f *FabricClient) GetVersions() ([]LoaderVersion, error) {
 
 // GetVersionsForMC returns Fabric loader versions compatible with a specific Minecraft version.
 // Note: Fabric loader versions are generally compatible with all supported MC versions.
-func (f *FabricClient) GetVersionsForMC(mcVersion string) ([]LoaderVersion, error) {
+func (f *FabricClient) GetVersionsForMC(ctx context.Context, mcVersion string) ([]LoaderVersion, error) {
 	// First check if the MC version is supported by Fabric
-	supported, err := f.IsMCVersionSupported(mcVersion)
+	supported, err := f.IsMCVersionSupported(ctx, mcVersion)
 	if err != nil {
 		return nil, err
 	}
@@ -79,13 +83,13 @@ func (f *FabricClient) GetVersionsForMC(mcVersion string) ([]LoaderVersion, erro
 	}
 
 	// Return all loader versions as they are generally compatible
-	return f.GetVersions()
+	return f.GetVersions(ctx)
 }
 
 // GetLatestVersion returns the latest stable Fabric loader version.
-func (f *FabricClient) GetLatestVersion(mcVersion string) (*LoaderVersion, error) {
+func (f *FabricClient) GetLatestVersion(ctx context.Context, mcVersion string) (*LoaderVersion, error) {
 	// Check MC version support
-	supported, err := f.IsMCVersionSupported(mcVersion)
+	supported, err := f.IsMCVersionSupported(ctx, mcVersion)
 	if err != nil {
 		return nil, err
 	}
@@ -93,7 +97,7 @@ func (f *FabricClient) GetLatestVersion(mcVersion string) (*LoaderVersion, error
 		return nil, ErrIncompatibleVersion
 	}
 
-	loaders, err := f.getLoaderVersions()
+	loaders, err := f.getLoaderVersions(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -124,9 +128,9 @@ func (f *FabricClient) GetLatestVersion(mcVersion string) (*LoaderVersion, error
 }
 
 // IsVersionCompatible checks if a Fabric loader version is compatible with a Minecraft version.
-func (f *FabricClient) IsVersionCompatible(loaderVersion, mcVersion string) (bool, error) {
+func (f *FabricClient) IsVersionCompatible(ctx context.Context, loaderVersion, mcVersion string) (bool, error) {
 	// Check if MC version is supported
-	supported, err := f.IsMCVersionSupported(mcVersion)
+	supported, err := f.IsMCVersionSupported(ctx, mcVersion)
 	if err != nil {
 		return false, err
 	}
@@ -135,7 +139,7 @@ func (f *FabricClient) IsVersionCompatible(loaderVersion, mcVersion string) (boo
 	}
 
 	// Check if loader version exists
-	loaders, err := f.getLoaderVersions()
+	loaders, err := f.getLoaderVersions(ctx)
 	if err != nil {
 		return false, err
 	}
@@ -150,8 +154,8 @@ func (f *FabricClient) IsVersionCompatible(loaderVersion, mcVersion string) (boo
 }
 
 // IsMCVersionSupported checks if a Minecraft version is supported by Fabric.
-func (f *FabricClient) IsMCVersionSupported(mcVersion string) (bool, error) {
-	games, err := f.getGameVersions()
+func (f *FabricClient) IsMCVersionSupported(ctx context.Context, mcVersion string) (bool, error) {
+	games, err := f.getGameVersions(ctx)
 	if err != nil {
 		return false, err
 	}
@@ -166,8 +170,8 @@ func (f *FabricClient) IsMCVersionSupported(mcVersion string) (bool, error) {
 }
 
 // GetSupportedMCVersions returns all Minecraft versions supported by Fabric.
-func (f *FabricClient) GetSupportedMCVersions() ([]string, error) {
-	games, err := f.getGameVersions()
+func (f *FabricClient) GetSupportedMCVersions(ctx context.Context) ([]string, error) {
+	games, err := f.getGameVersions(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -181,7 +185,7 @@ func (f *FabricClient) GetSupportedMCVersions() ([]string, error) {
 }
 
 // getLoaderVersions fetches Fabric loader versions.
-func (f *FabricClient) getLoaderVersions() ([]fabricLoaderResponse, error) {
+func (f *FabricClient) getLoaderVersions(ctx context.Context) ([]fabricLoaderResponse, error) {
 	// Try cache first
 	if f.cache != nil {
 		if data, err := f.cache.Get(CacheKeyFabricLoader); err == nil {
@@ -194,7 +198,7 @@ func (f *FabricClient) getLoaderVersions() ([]fabricLoaderResponse, error) {
 
 	// Fetch from API
 	url := FabricMetaURL + "/loader"
-	resp, err := f.httpClient.Get(url)
+	resp, err := httpGet(ctx, f.httpClient, url)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %v", ErrNetworkError, err)
 	}
@@ -220,7 +224,7 @@ func (f *FabricClient) getLoaderVersions() ([]fabricLoaderResponse, error) {
 }
 
 // getGameVersions fetches Fabric-supported game versions.
-func (f *FabricClient) getGameVersions() ([]fabricGameResponse, error) {
+func (f *FabricClient) getGameVersions(ctx context.Context) ([]fabricGameResponse, error) {
 	// Try cache first
 	if f.cache != nil {
 		if data, err := f.cache.Get(CacheKeyFabricGame); err == nil {
@@ -233,7 +237,7 @@ func (f *FabricClient) getGameVersions() ([]fabricGameResponse, error) {
 
 	// Fetch from API
 	url := FabricMetaURL + "/game"
-	resp, err := f.httpClient.Get(url)
+	resp, err := httpGet(ctx, f.httpClient, url)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %v", ErrNetworkError, err)
 	}
@@ -1,19 +1,27 @@
 package metadata
 
 import (
+	"context"
 	"encoding/json"
+	"encoding/xml"
 	"fmt"
 	"net/http"
 	"regexp"
 	"strings"
 	"time"
+
+	"github.com/alexinslc/chunk/internal/mirror"
 )
 
 const (
 	// ForgePromotionsURL is the URL for Forge promotions metadata.
 	ForgePromotionsURL = "https://files.minecraftforge.net/net/minecraftforge/forge/promotions_slim.json"
+	// ForgeMavenMetadataURL lists every Forge build published to Maven.
+	ForgeMavenMetadataURL = "https://maven.minecraftforge.net/net/minecraftforge/forge/maven-metadata.xml"
 	// CacheKeyForgeVersions is the cache key for Forge versions.
 	CacheKeyForgeVersions = "forge_versions"
+	// CacheKeyForgeMavenVersions is the cache key for the full Forge build list.
+	CacheKeyForgeMavenVersions = "forge_maven_versions"
 )
 
 // forgePromoPattern is a pre-compiled regex for parsing Forge promotion keys.
@@ -29,7 +37,8 @@ type ForgeClient struct {
 func NewForgeClient(cache *Cache) *ForgeClient {
 	return &ForgeClient{
 		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout:   30 * time.Second,
+			Transport: &mirror.Transport{},
 		},
 		cache: cache,
 	}
@@ -42,8 +51,8 @@ type forgePromotions struct {
 }
 
 // GetVersions returns all available Forge versions.
-func (f *ForgeClient) GetVersions() ([]LoaderVersion, error) {
-	promos, err := f.getPromotions()
+func (f *ForgeClient) GetVersions(ctx context.Context) ([]LoaderVersion, error) {
+	promos, err := f.getPromotions(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -51,13 +60,29 @@ func (f *ForgeClient) GetVersions() ([]LoaderVersion, error) {
 	return f.parsePromotions(promos)
 }
 
-// GetVersionsForMC returns Forge versions compatible with a specific Minecraft version.
-func (f *ForgeClient) GetVersionsForMC(mcVersion string) ([]LoaderVersion, error) {
-	promos, err := f.getPromotions()
+// forgeMavenMetadata represents the Forge maven-metadata.xml structure.
+type forgeMavenMetadata struct {
+	Versions []string `xml:"versioning>versions>version" json:"versions"`
+}
+
+// GetVersionsForMC returns Forge versions compatible with a specific Minecraft version,
+// newest first. Every published build is listed when the Maven metadata is reachable;
+// otherwise only the promoted (recommended and latest) builds are returned.
+func (f *ForgeClient) GetVersionsForMC(ctx context.Context, mcVersion string) ([]LoaderVersion, error) {
+	promos, err := f.getPromotions(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	if maven, err := f.getMavenVersions(ctx); err == nil {
+		return f.parseMavenVersions(maven, promos, mcVersion), nil
+	}
+
+	return f.getPromotedVersionsForMC(promos, mcVersion)
+}
+
+// getPromotedVersionsForMC returns the promoted Forge versions for a Minecraft version.
+func (f *ForgeClient) getPromotedVersionsForMC(promos *forgePromotions, mcVersion string) ([]LoaderVersion, error) {
 	allVersions, err := f.parsePromotions(promos)
 	if err != nil {
 		return nil, err
@@ -74,8 +99,8 @@ func (f *ForgeClient) GetVersionsForMC(mcVersion string) ([]LoaderVersion, error
 }
 
 // GetLatestVersion returns the latest stable (recommended) Forge version for a Minecraft version.
-func (f *ForgeClient) GetLatestVersion(mcVersion string) (*LoaderVersion, error) {
-	promos, err := f.getPromotions()
+func (f *ForgeClient) GetLatestVersion(ctx context.Context, mcVersion string) (*LoaderVersion, error) {
+	promos, err := f.getPromotions(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -106,8 +131,8 @@ func (f *ForgeClient) GetLatestVersion(mcVersion string) (*LoaderVersion, error)
 }
 
 // IsVersionCompatible checks if a Forge version is compatible with a Minecraft version.
-func (f *ForgeClient) IsVersionCompatible(forgeVersion, mcVersion string) (bool, error) {
-	versions, err := f.GetVersionsForMC(mcVersion)
+func (f *ForgeClient) IsVersionCompatible(ctx context.Context, forgeVersion, mcVersion string) (bool, error) {
+	versions, err := f.GetVersionsForMC(ctx, mcVersion)
 	if err != nil {
 		return false, err
 	}
@@ -128,7 +153,7 @@ func (f *ForgeClient) IsVersionCompatible(forgeVersion, mcVersion string) (bool,
 }
 
 // getPromotions fetches the Forge promotions data.
-func (f *ForgeClient) getPromotions() (*forgePromotions, error) {
+func (f *ForgeClient) getPromotions(ctx context.Context) (*forgePromotions, error) {
 	// Try to get from cache first
 	if f.cache != nil {
 		if data, err := f.cache.Get(CacheKeyForgeVersions); err == nil {
@@ -140,7 +165,7 @@ func (f *ForgeClient) getPromotions() (*forgePromotions, error) {
 	}
 
 	// Fetch from API
-	promos, err := f.fetchPromotions()
+	promos, err := f.fetchPromotions(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -156,8 +181,8 @@ func (f *ForgeClient) getPromotions() (*forgePromotions, error) {
 }
 
 // fetchPromotions fetches promotions from the Forge API.
-func (f *ForgeClient) fetchPromotions() (*forgePromotions, error) {
-	resp, err := f.httpClient.Get(ForgePromotionsURL)
+func (f *ForgeClient) fetchPromotions(ctx context.Context) (*forgePromotions, error) {
+	resp, err := httpGet(ctx, f.httpClient, ForgePromotionsURL)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %v", ErrNetworkError, err)
 	}
@@ -175,6 +200,72 @@ func (f *ForgeClient) fetchPromotions() (*forgePromotions, error) {
 	return &promos, nil
 }
 
+// getMavenVersions fetches the full Forge build list, using the cache when possible.
+func (f *ForgeClient) getMavenVersions(ctx context.Context) (*forgeMavenMetadata, error) {
+	if f.cache != nil {
+		if data, err := f.cache.Get(CacheKeyForgeMavenVersions); err == nil {
+			var maven forgeMavenMetadata
+			if err := json.Unmarshal(data, &maven); err == nil {
+				return &maven, nil
+			}
+		}
+	}
+
+	resp, err := httpGet(ctx, f.httpClient, ForgeMavenMetadataURL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNetworkError, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: status %d", ErrNetworkError, resp.StatusCode)
+	}
+
+	var maven forgeMavenMetadata
+	if err := xml.NewDecoder(resp.Body).Decode(&maven); err != nil {
+		return nil, fmt.Errorf("failed to decode forge maven metadata: %w", err)
+	}
+
+	if f.cache != nil {
+		if data, err := json.Marshal(maven); err == nil {
+			_ = f.cache.Set(CacheKeyForgeMavenVersions, data)
+		}
+	}
+
+	return &maven, nil
+}
+
+// parseMavenVersions returns the Forge builds for a Minecraft version, newest first.
+// Maven entries have the form <mcVersion>-<forgeVersion>; the returned versions
+// carry only the Forge part and are marked stable when they are the recommended build.
+func (f *ForgeClient) parseMavenVersions(maven *forgeMavenMetadata, promos *forgePromotions, mcVersion string) []LoaderVersion {
+	recommended := ""
+	if promos != nil {
+		recommended = promos.Promos[mcVersion+"-recommended"]
+	}
+
+	var versions []LoaderVersion
+	prefix := mcVersion + "-"
+
+	// Maven lists builds oldest first
+	for i := len(maven.Versions) - 1; i >= 0; i-- {
+		entry := maven.Versions[i]
+		if !strings.HasPrefix(entry, prefix) {
+			continue
+		}
+
+		version := strings.TrimPrefix(entry, prefix)
+		versions = append(versions, LoaderVersion{
+			Version:          version,
+			MinecraftVersion: mcVersion,
+			Stable:           version == recommended,
+			LoaderType:       LoaderForge,
+		})
+	}
+
+	return versions
+}
+
 // parsePromotions converts Forge promotions data to LoaderVersion slice.
 func (f *ForgeClient) parsePromotions(promos *forgePromotions) ([]LoaderVersion, error) {
 	var versions []LoaderVersion
@@ -211,8 +302,8 @@ func (f *ForgeClient) parsePromotions(promos *forgePromotions) ([]LoaderVersion,
 }
 
 // GetSupportedMCVersions returns all Minecraft versions that have Forge support.
-func (f *ForgeClient) GetSupportedMCVersions() ([]string, error) {
-	promos, err := f.getPromotions()
+func (f *ForgeClient) GetSupportedMCVersions(ctx context.Context) ([]string, error) {
+	promos, err := f.getPromotions(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -239,6 +330,9 @@ func (f *ForgeClient) GetSupportedMCVersions() ([]string, error) {
 // RefreshCache clears the cached Forge data.
 func (f *ForgeClient) RefreshCache() error {
 	if f.cache != nil {
+		if err := f.cache.Delete(CacheKeyForgeMavenVersions); err != nil {
+			return err
+		}
 		return f.cache.Delete(CacheKeyForgeVersions)
 	}
 	return nil
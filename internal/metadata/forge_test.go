@@ -1,6 +1,7 @@
 package metadata
 
 import (
+	"encoding/xml"
 	"testing"
 )
 
@@ -72,6 +73,50 @@ func TestForgeClient_parsePromotions(t *testing.T) {
 	}
 }
 
+func TestForgeClient_parseMavenVersions(t *testing.T) {
+	client := NewForgeClient(nil)
+
+	data := `<?xml version="1.0" encoding="UTF-8"?>
+<metadata>
+  <groupId>net.minecraftforge</groupId>
+  <artifactId>forge</artifactId>
+  <versioning>
+    <versions>
+      <version>1.7.10-10.13.4.1614-1.7.10</version>
+      <version>1.20-46.0.14</version>
+      <version>1.20.1-47.1.0</version>
+      <version>1.20.1-47.2.0</version>
+      <version>1.20.1-47.2.5</version>
+    </versions>
+  </versioning>
+</metadata>`
+
+	var maven forgeMavenMetadata
+	if err := xml.Unmarshal([]byte(data), &maven); err != nil {
+		t.Fatalf("Failed to parse maven metadata: %v", err)
+	}
+
+	promos := &forgePromotions{Promos: map[string]string{"1.20.1-recommended": "47.2.0"}}
+
+	versions := client.parseMavenVersions(&maven, promos, "1.20.1")
+	if len(versions) != 3 {
+		t.Fatalf("parseMavenVersions() returned %d versions, want 3", len(versions))
+	}
+	if versions[0].Version != "47.2.5" {
+		t.Errorf("First version = %s, want newest 47.2.5", versions[0].Version)
+	}
+	for _, v := range versions {
+		if v.Stable != (v.Version == "47.2.0") {
+			t.Errorf("Version %s Stable = %v, want only the recommended build stable", v.Version, v.Stable)
+		}
+	}
+
+	legacy := client.parseMavenVersions(&maven, promos, "1.7.10")
+	if len(legacy) != 1 || legacy[0].Version != "10.13.4.1614-1.7.10" {
+		t.Errorf("Unexpected 1.7.10 versions: %+v", legacy)
+	}
+}
+
 func TestForgeClient_RefreshCache(t *testing.T) {
 	// Without cache
 	client := NewForgeClient(nil)
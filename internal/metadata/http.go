@@ -0,0 +1,15 @@
+package metadata
+
+import (
+	"context"
+	"net/http"
+)
+
+// httpGet performs a GET request that is aborted when ctx is cancelled.
+func httpGet(ctx context.Context, client *http.Client, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return client.Do(req)
+}
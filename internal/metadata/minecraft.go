@@ -1,11 +1,14 @@
 package metadata
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"strings"
 	"time"
+
+	"github.com/alexinslc/chunk/internal/mirror"
 )
 
 const (
@@ -25,14 +28,15 @@ type MinecraftClient struct {
 func NewMinecraftClient(cache *Cache) *MinecraftClient {
 	return &MinecraftClient{
 		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout:   30 * time.Second,
+			Transport: &mirror.Transport{},
 		},
 		cache: cache,
 	}
 }
 
 // GetVersionManifest fetches the Minecraft version manifest from Mojang.
-func (m *MinecraftClient) GetVersionManifest() (*MinecraftVersionManifest, error) {
+func (m *MinecraftClient) GetVersionManifest(ctx context.Context) (*MinecraftVersionManifest, error) {
 	// Try to get from cache first
 	if m.cache != nil {
 		if data, err := m.cache.Get(CacheKeyMinecraftManifest); err == nil {
@@ -44,7 +48,7 @@ func (m *MinecraftClient) GetVersionManifest() (*MinecraftVersionManifest, error
 	}
 
 	// Fetch from API
-	manifest, err := m.fetchVersionManifest()
+	manifest, err := m.fetchVersionManifest(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -60,8 +64,8 @@ func (m *MinecraftClient) GetVersionManifest() (*MinecraftVersionManifest, error
 }
 
 // fetchVersionManifest fetches the manifest from the Mojang API.
-func (m *MinecraftClient) fetchVersionManifest() (*MinecraftVersionManifest, error) {
-	resp, err := m.httpClient.Get(MojangVersionManifestURL)
+func (m *MinecraftClient) fetchVersionManifest(ctx context.Context) (*MinecraftVersionManifest, error) {
+	resp, err := httpGet(ctx, m.httpClient, MojangVersionManifestURL)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %v", ErrNetworkError, err)
 	}
@@ -80,8 +84,8 @@ func (m *MinecraftClient) fetchVersionManifest() (*MinecraftVersionManifest, err
 }
 
 // GetVersion returns details for a specific Minecraft version.
-func (m *MinecraftClient) GetVersion(version string) (*MinecraftVersion, error) {
-	manifest, err := m.GetVersionManifest()
+func (m *MinecraftClient) GetVersion(ctx context.Context, version string) (*MinecraftVersion, error) {
+	manifest, err := m.GetVersionManifest(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -96,28 +100,28 @@ func (m *MinecraftClient) GetVersion(version string) (*MinecraftVersion, error)
 }
 
 // GetLatestRelease returns the latest stable release version.
-func (m *MinecraftClient) GetLatestRelease() (*MinecraftVersion, error) {
-	manifest, err := m.GetVersionManifest()
+func (m *MinecraftClient) GetLatestRelease(ctx context.Context) (*MinecraftVersion, error) {
+	manifest, err := m.GetVersionManifest(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	return m.GetVersion(manifest.Latest.Release)
+	return m.GetVersion(ctx, manifest.Latest.Release)
 }
 
 // GetLatestSnapshot returns the latest snapshot version.
-func (m *MinecraftClient) GetLatestSnapshot() (*MinecraftVersion, error) {
-	manifest, err := m.GetVersionManifest()
+func (m *MinecraftClient) GetLatestSnapshot(ctx context.Context) (*MinecraftVersion, error) {
+	manifest, err := m.GetVersionManifest(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	return m.GetVersion(manifest.Latest.Snapshot)
+	return m.GetVersion(ctx, manifest.Latest.Snapshot)
 }
 
 // GetVersions returns all release versions.
-func (m *MinecraftClient) GetVersions() ([]*MinecraftVersion, error) {
-	manifest, err := m.GetVersionManifest()
+func (m *MinecraftClient) GetVersions(ctx context.Context) ([]*MinecraftVersion, error) {
+	manifest, err := m.GetVersionManifest(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -133,8 +137,8 @@ func (m *MinecraftClient) GetVersions() ([]*MinecraftVersion, error) {
 }
 
 // GetAllVersions returns all versions including snapshots.
-func (m *MinecraftClient) GetAllVersions() ([]*MinecraftVersion, error) {
-	manifest, err := m.GetVersionManifest()
+func (m *MinecraftClient) GetAllVersions(ctx context.Context) ([]*MinecraftVersion, error) {
+	manifest, err := m.GetVersionManifest(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -143,9 +147,9 @@ func (m *MinecraftClient) GetAllVersions() ([]*MinecraftVersion, error) {
 }
 
 // GetJavaVersion returns the required Java version for a Minecraft version.
-func (m *MinecraftClient) GetJavaVersion(mcVersion string) (int, error) {
+func (m *MinecraftClient) GetJavaVersion(ctx context.Context, mcVersion string) (int, error) {
 	// First check the version manifest for Java version info
-	v, err := m.GetVersion(mcVersion)
+	v, err := m.GetVersion(ctx, mcVersion)
 	if err == nil && v.JavaVersion > 0 {
 		return v.JavaVersion, nil
 	}
@@ -190,8 +194,8 @@ func getJavaVersionFromMapping(mcVersion string) (int, error) {
 }
 
 // IsVersionValid checks if a Minecraft version string is valid.
-func (m *MinecraftClient) IsVersionValid(version string) (bool, error) {
-	_, err := m.GetVersion(version)
+func (m *MinecraftClient) IsVersionValid(ctx context.Context, version string) (bool, error) {
+	_, err := m.GetVersion(ctx, version)
 	if err == ErrNotFound {
 		return false, nil
 	}
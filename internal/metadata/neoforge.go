@@ -1,19 +1,29 @@
 package metadata
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"regexp"
 	"strings"
 	"time"
+
+	"github.com/alexinslc/chunk/internal/mirror"
 )
 
 const (
 	// NeoForgeMavenURL is the base URL for NeoForge Maven repository.
 	NeoForgeMavenURL = "https://maven.neoforged.net/api/maven/versions/releases/net/neoforged/neoforge"
+	// NeoForgeLegacyMavenURL lists the NeoForge builds for Minecraft 1.20.1, which
+	// were published under the net.neoforged:forge coordinates.
+	NeoForgeLegacyMavenURL = "https://maven.neoforged.net/api/maven/versions/releases/net/neoforged/forge"
+	// NeoForgeLegacyMCVersion is the only Minecraft version served by the legacy artifact.
+	NeoForgeLegacyMCVersion = "1.20.1"
 	// CacheKeyNeoForgeVersions is the cache key for NeoForge versions.
 	CacheKeyNeoForgeVersions = "neoforge_versions"
+	// CacheKeyNeoForgeLegacyVersions is the cache key for legacy NeoForge versions.
+	CacheKeyNeoForgeLegacyVersions = "neoforge_legacy_versions"
 )
 
 // neoForgeVersionPattern is a pre-compiled regex for parsing NeoForge version strings.
@@ -29,7 +39,8 @@ type NeoForgeClient struct {
 func NewNeoForgeClient(cache *Cache) *NeoForgeClient {
 	return &NeoForgeClient{
 		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout:   30 * time.Second,
+			Transport: &mirror.Transport{},
 		},
 		cache: cache,
 	}
@@ -42,8 +53,8 @@ type neoForgeMavenResponse struct {
 }
 
 // GetVersions returns all available NeoForge versions.
-func (n *NeoForgeClient) GetVersions() ([]LoaderVersion, error) {
-	mavenData, err := n.getMavenVersions()
+func (n *NeoForgeClient) GetVersions(ctx context.Context) ([]LoaderVersion, error) {
+	mavenData, err := n.getMavenVersions(ctx, NeoForgeMavenURL, CacheKeyNeoForgeVersions)
 	if err != nil {
 		return nil, err
 	}
@@ -52,15 +63,24 @@ func (n *NeoForgeClient) GetVersions() ([]LoaderVersion, error) {
 }
 
 // GetVersionsForMC returns NeoForge versions compatible with a specific Minecraft version.
-func (n *NeoForgeClient) GetVersionsForMC(mcVersion string) ([]LoaderVersion, error) {
-	allVersions, err := n.GetVersions()
+func (n *NeoForgeClient) GetVersionsForMC(ctx context.Context, mcVersion string) ([]LoaderVersion, error) {
+	if mcVersion == NeoForgeLegacyMCVersion {
+		mavenData, err := n.getMavenVersions(ctx, NeoForgeLegacyMavenURL, CacheKeyNeoForgeLegacyVersions)
+		if err != nil {
+			return nil, err
+		}
+		return n.parseLegacyVersions(mavenData.Versions), nil
+	}
+
+	allVersions, err := n.GetVersions(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	var compatible []LoaderVersion
 	for _, v := range allVersions {
-		if v.MinecraftVersion == mcVersion {
+		// NeoForge 21.0.x targets Minecraft 1.21, which inferMCVersion reports as 1.21.0
+		if v.MinecraftVersion == mcVersion || v.MinecraftVersion == mcVersion+".0" {
 			compatible = append(compatible, v)
 		}
 	}
@@ -69,8 +89,8 @@ func (n *NeoForgeClient) GetVersionsForMC(mcVersion string) ([]LoaderVersion, er
 }
 
 // GetLatestVersion returns the latest NeoForge version for a Minecraft version.
-func (n *NeoForgeClient) GetLatestVersion(mcVersion string) (*LoaderVersion, error) {
-	versions, err := n.GetVersionsForMC(mcVersion)
+func (n *NeoForgeClient) GetLatestVersion(ctx context.Context, mcVersion string) (*LoaderVersion, error) {
+	versions, err := n.GetVersionsForMC(ctx, mcVersion)
 	if err != nil {
 		return nil, err
 	}
@@ -84,8 +104,8 @@ func (n *NeoForgeClient) GetLatestVersion(mcVersion string) (*LoaderVersion, err
 }
 
 // IsVersionCompatible checks if a NeoForge version is compatible with a Minecraft version.
-func (n *NeoForgeClient) IsVersionCompatible(neoforgeVersion, mcVersion string) (bool, error) {
-	versions, err := n.GetVersionsForMC(mcVersion)
+func (n *NeoForgeClient) IsVersionCompatible(ctx context.Context, neoforgeVersion, mcVersion string) (bool, error) {
+	versions, err := n.GetVersionsForMC(ctx, mcVersion)
 	if err != nil {
 		return false, err
 	}
@@ -100,8 +120,8 @@ func (n *NeoForgeClient) IsVersionCompatible(neoforgeVersion, mcVersion string)
 }
 
 // GetSupportedMCVersions returns all Minecraft versions that have NeoForge support.
-func (n *NeoForgeClient) GetSupportedMCVersions() ([]string, error) {
-	allVersions, err := n.GetVersions()
+func (n *NeoForgeClient) GetSupportedMCVersions(ctx context.Context) ([]string, error) {
+	allVersions, err := n.GetVersions(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -120,10 +140,10 @@ func (n *NeoForgeClient) GetSupportedMCVersions() ([]string, error) {
 }
 
 // getMavenVersions fetches version data from NeoForge Maven.
-func (n *NeoForgeClient) getMavenVersions() (*neoForgeMavenResponse, error) {
+func (n *NeoForgeClient) getMavenVersions(ctx context.Context, url, cacheKey string) (*neoForgeMavenResponse, error) {
 	// Try cache first
 	if n.cache != nil {
-		if data, err := n.cache.Get(CacheKeyNeoForgeVersions); err == nil {
+		if data, err := n.cache.Get(cacheKey); err == nil {
 			var mavenData neoForgeMavenResponse
 			if err := json.Unmarshal(data, &mavenData); err == nil {
 				return &mavenData, nil
@@ -132,7 +152,7 @@ func (n *NeoForgeClient) getMavenVersions() (*neoForgeMavenResponse, error) {
 	}
 
 	// Fetch from API
-	resp, err := n.httpClient.Get(NeoForgeMavenURL)
+	resp, err := httpGet(ctx, n.httpClient, url)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %v", ErrNetworkError, err)
 	}
@@ -150,7 +170,7 @@ func (n *NeoForgeClient) getMavenVersions() (*neoForgeMavenResponse, error) {
 	// Cache the result
 	if n.cache != nil {
 		if data, err := json.Marshal(mavenData); err == nil {
-			_ = n.cache.Set(CacheKeyNeoForgeVersions, data)
+			_ = n.cache.Set(cacheKey, data)
 		}
 	}
 
@@ -200,6 +220,27 @@ func (n *NeoForgeClient) parseVersions(versionStrings []string) ([]LoaderVersion
 	return versions, nil
 }
 
+// parseLegacyVersions converts legacy NeoForge versions (e.g., "1.20.1-47.1.106") to
+// a LoaderVersion slice, newest first. Like Forge, the MC prefix is dropped.
+func (n *NeoForgeClient) parseLegacyVersions(versionStrings []string) []LoaderVersion {
+	var versions []LoaderVersion
+	prefix := NeoForgeLegacyMCVersion + "-"
+
+	for i := len(versionStrings) - 1; i >= 0; i-- {
+		if !strings.HasPrefix(versionStrings[i], prefix) {
+			continue
+		}
+		versions = append(versions, LoaderVersion{
+			Version:          strings.TrimPrefix(versionStrings[i], prefix),
+			MinecraftVersion: NeoForgeLegacyMCVersion,
+			Stable:           true,
+			LoaderType:       LoaderNeoForge,
+		})
+	}
+
+	return versions
+}
+
 // inferMCVersion attempts to determine the Minecraft version from NeoForge version numbers.
 func (n *NeoForgeClient) inferMCVersion(major, minor string) string {
 	// NeoForge uses a versioning scheme where:
@@ -211,6 +252,9 @@ func (n *NeoForgeClient) inferMCVersion(major, minor string) string {
 // RefreshCache clears the cached NeoForge data.
 func (n *NeoForgeClient) RefreshCache() error {
 	if n.cache != nil {
+		if err := n.cache.Delete(CacheKeyNeoForgeLegacyVersions); err != nil {
+			return err
+		}
 		return n.cache.Delete(CacheKeyNeoForgeVersions)
 	}
 	return nil
@@ -138,6 +138,18 @@ func TestNeoForgeClient_parseVersions(t *testing.T) {
 	}
 }
 
+func TestNeoForgeClient_parseLegacyVersions(t *testing.T) {
+	client := NewNeoForgeClient(nil)
+
+	versions := client.parseLegacyVersions([]string{"1.20.1-47.1.3", "1.20.1-47.1.106", "47.1.0"})
+	if len(versions) != 2 {
+		t.Fatalf("parseLegacyVersions() returned %d versions, want 2", len(versions))
+	}
+	if versions[0].Version != "47.1.106" || versions[0].MinecraftVersion != "1.20.1" {
+		t.Errorf("First version = %+v, want 47.1.106 for MC 1.20.1", versions[0])
+	}
+}
+
 func TestNeoForgeMavenResponse_Struct(t *testing.T) {
 	resp := neoForgeMavenResponse{
 		IsSnapshot: false,
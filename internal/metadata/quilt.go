@@ -1,10 +1,13 @@
 package metadata
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"time"
+
+	"github.com/alexinslc/chunk/internal/mirror"
 )
 
 const (
@@ -28,7 +31,8 @@ type QuiltClient struct {
 func NewQuiltClient(cache *Cache) *QuiltClient {
 	return &QuiltClient{
 		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout:   30 * time.Second,
+			Transport: &mirror.Transport{},
 		},
 		cache: cache,
 	}
@@ -49,8 +53,8 @@ type quiltGameResponse struct {
 }
 
 // GetVersions returns all available Quilt loader versions.
-func (q *QuiltClient) GetVersions() ([]LoaderVersion, error) {
-	loaders, err := q.getLoaderVersions()
+func (q *QuiltClient) GetVersions(ctx context.Context) ([]LoaderVersion, error) {
+	loaders, err := q.getLoaderVersions(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -69,9 +73,9 @@ func (q *QuiltClient) GetVersions() ([]LoaderVersion, error) {
 
 // GetVersionsForMC returns Quilt loader versions for a specific Minecraft version.
 // Note: Quilt loader versions are generally compatible with all supported MC versions.
-func (q *QuiltClient) GetVersionsForMC(mcVersion string) ([]LoaderVersion, error) {
+func (q *QuiltClient) GetVersionsForMC(ctx context.Context, mcVersion string) ([]LoaderVersion, error) {
 	// First check if the MC version is supported by Quilt
-	supported, err := q.IsMCVersionSupported(mcVersion)
+	supported, err := q.IsMCVersionSupported(ctx, mcVersion)
 	if err != nil {
 		return nil, err
 	}
@@ -80,13 +84,13 @@ func (q *QuiltClient) GetVersionsForMC(mcVersion string) ([]LoaderVersion, error
 	}
 
 	// Return all loader versions as they are generally compatible
-	return q.GetVersions()
+	return q.GetVersions(ctx)
 }
 
 // GetLatestVersion returns the latest Quilt loader version.
-func (q *QuiltClient) GetLatestVersion(mcVersion string) (*LoaderVersion, error) {
+func (q *QuiltClient) GetLatestVersion(ctx context.Context, mcVersion string) (*LoaderVersion, error) {
 	// Check MC version support
-	supported, err := q.IsMCVersionSupported(mcVersion)
+	supported, err := q.IsMCVersionSupported(ctx, mcVersion)
 	if err != nil {
 		return nil, err
 	}
@@ -94,7 +98,7 @@ func (q *QuiltClient) GetLatestVersion(mcVersion string) (*LoaderVersion, error)
 		return nil, ErrIncompatibleVersion
 	}
 
-	loaders, err := q.getLoaderVersions()
+	loaders, err := q.getLoaderVersions(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -113,9 +117,9 @@ func (q *QuiltClient) GetLatestVersion(mcVersion string) (*LoaderVersion, error)
 }
 
 // IsVersionCompatible checks if a Quilt loader version is compatible with a Minecraft version.
-func (q *QuiltClient) IsVersionCompatible(loaderVersion, mcVersion string) (bool, error) {
+func (q *QuiltClient) IsVersionCompatible(ctx context.Context, loaderVersion, mcVersion string) (bool, error) {
 	// Check if MC version is supported
-	supported, err := q.IsMCVersionSupported(mcVersion)
+	supported, err := q.IsMCVersionSupported(ctx, mcVersion)
 	if err != nil {
 		return false, err
 	}
@@ -124,7 +128,7 @@ func (q *QuiltClient) IsVersionCompatible(loaderVersion, mcVersion string) (bool
 	}
 
 	// Check if loader version exists
-	loaders, err := q.getLoaderVersions()
+	loaders, err := q.getLoaderVersions(ctx)
 	if err != nil {
 		return false, err
 	}
@@ -139,8 +143,8 @@ func (q *QuiltClient) IsVersionCompatible(loaderVersion, mcVersion string) (bool
 }
 
 // IsMCVersionSupported checks if a Minecraft version is supported by Quilt.
-func (q *QuiltClient) IsMCVersionSupported(mcVersion string) (bool, error) {
-	games, err := q.getGameVersions()
+func (q *QuiltClient) IsMCVersionSupported(ctx context.Context, mcVersion string) (bool, error) {
+	games, err := q.getGameVersions(ctx)
 	if err != nil {
 		return false, err
 	}
@@ -155,8 +159,8 @@ func (q *QuiltClient) IsMCVersionSupported(mcVersion string) (bool, error) {
 }
 
 // GetSupportedMCVersions returns all Minecraft versions supported by Quilt.
-func (q *QuiltClient) GetSupportedMCVersions() ([]string, error) {
-	games, err := q.getGameVersions()
+func (q *QuiltClient) GetSupportedMCVersions(ctx context.Context) ([]string, error) {
+	games, err := q.getGameVersions(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -170,7 +174,7 @@ func (q *QuiltClient) GetSupportedMCVersions() ([]string, error) {
 }
 
 // getLoaderVersions fetches Quilt loader versions.
-func (q *QuiltClient) getLoaderVersions() ([]quiltLoaderResponse, error) {
+func (q *QuiltClient) getLoaderVersions(ctx context.Context) ([]quiltLoaderResponse, error) {
 	// Try cache first
 	if q.cache != nil {
 		if data, err := q.cache.Get(CacheKeyQuiltLoader); err == nil {
@@ -183,7 +187,7 @@ func (q *QuiltClient) getLoaderVersions() ([]quiltLoaderResponse, error) {
 
 	// Fetch from API
 	url := QuiltMetaURL + "/loader"
-	resp, err := q.httpClient.Get(url)
+	resp, err := httpGet(ctx, q.httpClient, url)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %v", ErrNetworkError, err)
 	}
@@ -209,7 +213,7 @@ func (q *QuiltClient) getLoaderVersions() ([]quiltLoaderResponse, error) {
 }
 
 // getGameVersions fetches Quilt-supported game versions.
-func (q *QuiltClient) getGameVersions() ([]quiltGameResponse, error) {
+func (q *QuiltClient) getGameVersions(ctx context.Context) ([]quiltGameResponse, error) {
 	// Try cache first
 	if q.cache != nil {
 		if data, err := q.cache.Get(CacheKeyQuiltGame); err == nil {
@@ -222,7 +226,7 @@ func (q *QuiltClient) getGameVersions() ([]quiltGameResponse, error) {
 
 	// Fetch from API
 	url := QuiltMetaURL + "/game"
-	resp, err := q.httpClient.Get(url)
+	resp, err := httpGet(ctx, q.httpClient, url)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %v", ErrNetworkError, err)
 	}
@@ -0,0 +1,124 @@
+package metadata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// maxListedVersions is how many valid versions an UnknownVersionError names.
+const maxListedVersions = 10
+
+// UnknownVersionError is returned when a requested loader version is not
+// published for the Minecraft version.
+type UnknownVersionError struct {
+	Loader    LoaderType
+	MCVersion string
+	Version   string
+	// Valid lists the published versions, newest first.
+	Valid []string
+}
+
+func (e *UnknownVersionError) Error() string {
+	if len(e.Valid) == 0 {
+		return fmt.Sprintf("%s version %q is not available for Minecraft %s: no %s versions are published for it",
+			e.Loader, e.Version, e.MCVersion, e.Loader)
+	}
+
+	listed := e.Valid
+	more := ""
+	if len(listed) > maxListedVersions {
+		more = fmt.Sprintf(" (and %d more)", len(listed)-maxListedVersions)
+		listed = listed[:maxListedVersions]
+	}
+
+	return fmt.Sprintf("%s version %q is not available for Minecraft %s; valid versions: %s%s",
+		e.Loader, e.Version, e.MCVersion, strings.Join(listed, ", "), more)
+}
+
+// Unwrap lets callers match the error with errors.Is(err, ErrInvalidVersion).
+func (e *UnknownVersionError) Unwrap() error {
+	return ErrInvalidVersion
+}
+
+// LoaderResolver selects mod loader versions for a Minecraft version.
+type LoaderResolver struct {
+	providers map[LoaderType]VersionProvider
+}
+
+// NewLoaderResolver creates a resolver backed by the Forge, NeoForge, Fabric
+// and Quilt metadata clients, sharing an optional cache.
+func NewLoaderResolver(cache *Cache) *LoaderResolver {
+	return NewLoaderResolverWithProviders(map[LoaderType]VersionProvider{
+		LoaderForge:    NewForgeClient(cache),
+		LoaderNeoForge: NewNeoForgeClient(cache),
+		LoaderFabric:   NewFabricClient(cache),
+		LoaderQuilt:    NewQuiltClient(cache),
+	})
+}
+
+// NewLoaderResolverWithProviders creates a resolver with custom version providers.
+func NewLoaderResolverWithProviders(providers map[LoaderType]VersionProvider) *LoaderResolver {
+	return &LoaderResolver{providers: providers}
+}
+
+// Resolve returns the loader version to install for a Minecraft version.
+// An empty or "latest" request selects the recommended (or newest) version.
+// A named version must be published for mcVersion, otherwise an
+// *UnknownVersionError listing the valid versions is returned. If the
+// metadata cannot be fetched, a named version is returned unverified,
+// unless ctx was cancelled.
+func (r *LoaderResolver) Resolve(ctx context.Context, loader LoaderType, mcVersion, requested string) (string, error) {
+	provider, ok := r.providers[loader]
+	if !ok {
+		return "", fmt.Errorf("no version metadata for loader %q", loader)
+	}
+
+	requested = normalizeLoaderVersion(loader, mcVersion, requested)
+
+	if requested == "" || requested == "latest" {
+		latest, err := provider.GetLatestVersion(ctx, mcVersion)
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve %s version for Minecraft %s: %w", loader, mcVersion, err)
+		}
+		return latest.Version, nil
+	}
+
+	versions, err := provider.GetVersionsForMC(ctx, mcVersion)
+	if err != nil {
+		if errors.Is(err, ErrIncompatibleVersion) {
+			return "", fmt.Errorf("%s does not support Minecraft %s: %w", loader, mcVersion, err)
+		}
+		// A cancelled request is not an offline fallback
+		if errors.Is(err, ErrNetworkError) && ctx.Err() == nil {
+			return requested, nil
+		}
+		return "", fmt.Errorf("failed to list %s versions for Minecraft %s: %w", loader, mcVersion, err)
+	}
+
+	valid := make([]string, 0, len(versions))
+	for _, v := range versions {
+		if v.Version == requested {
+			return requested, nil
+		}
+		valid = append(valid, v.Version)
+	}
+
+	return "", &UnknownVersionError{
+		Loader:    loader,
+		MCVersion: mcVersion,
+		Version:   requested,
+		Valid:     valid,
+	}
+}
+
+// normalizeLoaderVersion drops the "<mcVersion>-" prefix Forge-style
+// versions are sometimes written with (e.g., "1.20.1-47.2.0").
+func normalizeLoaderVersion(loader LoaderType, mcVersion, version string) string {
+	version = strings.TrimSpace(version)
+	if loader == LoaderForge || loader == LoaderNeoForge {
+		version = strings.TrimPrefix(version, mcVersion+"-")
+	}
+	return version
+}
@@ -0,0 +1,132 @@
+package metadata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// fakeProvider serves a fixed version list, newest first
+type fakeProvider struct {
+	versions []LoaderVersion
+	err      error
+}
+
+func (f *fakeProvider) GetVersions(ctx context.Context) ([]LoaderVersion, error) {
+	return f.versions, f.err
+}
+
+func (f *fakeProvider) GetVersionsForMC(ctx context.Context, mcVersion string) ([]LoaderVersion, error) {
+	return f.versions, f.err
+}
+
+func (f *fakeProvider) GetLatestVersion(ctx context.Context, mcVersion string) (*LoaderVersion, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	for _, v := range f.versions {
+		if v.Stable {
+			return &v, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (f *fakeProvider) IsVersionCompatible(ctx context.Context, loaderVersion, mcVersion string) (bool, error) {
+	return false, nil
+}
+
+func TestLoaderResolver_Resolve(t *testing.T) {
+	forge := &fakeProvider{versions: []LoaderVersion{
+		{Version: "47.2.5", MinecraftVersion: "1.20.1"},
+		{Version: "47.2.0", MinecraftVersion: "1.20.1", Stable: true},
+		{Version: "47.1.0", MinecraftVersion: "1.20.1"},
+	}}
+
+	resolver := NewLoaderResolverWithProviders(map[LoaderType]VersionProvider{
+		LoaderForge:  forge,
+		LoaderFabric: &fakeProvider{err: ErrIncompatibleVersion},
+		LoaderQuilt:  &fakeProvider{err: fmt.Errorf("%w: status 503", ErrNetworkError)},
+	})
+
+	tests := []struct {
+		name      string
+		loader    LoaderType
+		requested string
+		want      string
+		wantErr   error
+	}{
+		{name: "empty selects recommended", loader: LoaderForge, want: "47.2.0"},
+		{name: "latest selects recommended", loader: LoaderForge, requested: "latest", want: "47.2.0"},
+		{name: "named version", loader: LoaderForge, requested: "47.1.0", want: "47.1.0"},
+		{name: "mc prefixed version", loader: LoaderForge, requested: "1.20.1-47.2.5", want: "47.2.5"},
+		{name: "unknown version", loader: LoaderForge, requested: "99.0.0", wantErr: ErrInvalidVersion},
+		{name: "unsupported mc version", loader: LoaderFabric, requested: "0.15.0", wantErr: ErrIncompatibleVersion},
+		{name: "offline keeps named version", loader: LoaderQuilt, requested: "0.23.1", want: "0.23.1"},
+		{name: "offline cannot pick latest", loader: LoaderQuilt, wantErr: ErrNetworkError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolver.Resolve(context.Background(), tt.loader, "1.20.1", tt.requested)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Resolve() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Resolve() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Resolve() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnknownVersionError_ListsValidVersions(t *testing.T) {
+	var versions []LoaderVersion
+	for i := 12; i > 0; i-- {
+		versions = append(versions, LoaderVersion{Version: fmt.Sprintf("47.2.%d", i)})
+	}
+
+	resolver := NewLoaderResolverWithProviders(map[LoaderType]VersionProvider{
+		LoaderForge: &fakeProvider{versions: versions},
+	})
+
+	_, err := resolver.Resolve(context.Background(), LoaderForge, "1.20.1", "47.9.9")
+
+	var unknown *UnknownVersionError
+	if !errors.As(err, &unknown) {
+		t.Fatalf("Expected UnknownVersionError, got %v", err)
+	}
+	if len(unknown.Valid) != 12 {
+		t.Errorf("Expected 12 valid versions, got %d", len(unknown.Valid))
+	}
+
+	msg := err.Error()
+	for _, want := range []string{`forge version "47.9.9"`, "Minecraft 1.20.1", "47.2.12, 47.2.11", "(and 2 more)"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("Expected error to contain %q, got: %s", want, msg)
+		}
+	}
+	if strings.Contains(msg, "47.2.2") {
+		t.Errorf("Expected the oldest versions to be elided, got: %s", msg)
+	}
+}
+
+func TestLoaderResolver_ResolveCancelled(t *testing.T) {
+	resolver := NewLoaderResolverWithProviders(map[LoaderType]VersionProvider{
+		LoaderQuilt: &fakeProvider{err: fmt.Errorf("%w: %v", ErrNetworkError, context.Canceled)},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := resolver.Resolve(ctx, LoaderQuilt, "1.20.1", "0.23.1"); err == nil {
+		t.Fatal("Expected cancelled resolve to fail instead of falling back")
+	}
+}
@@ -3,6 +3,7 @@
 package metadata
 
 import (
+	"context"
 	"errors"
 	"time"
 )
@@ -111,13 +112,13 @@ type NeoForgeVersion struct {
 // VersionProvider defines the interface for fetching version information.
 type VersionProvider interface {
 	// GetVersions returns all available versions.
-	GetVersions() ([]LoaderVersion, error)
+	GetVersions(ctx context.Context) ([]LoaderVersion, error)
 	// GetVersionsForMC returns versions compatible with a specific Minecraft version.
-	GetVersionsForMC(mcVersion string) ([]LoaderVersion, error)
+	GetVersionsForMC(ctx context.Context, mcVersion string) ([]LoaderVersion, error)
 	// GetLatestVersion returns the latest stable version for a Minecraft version.
-	GetLatestVersion(mcVersion string) (*LoaderVersion, error)
+	GetLatestVersion(ctx context.Context, mcVersion string) (*LoaderVersion, error)
 	// IsVersionCompatible checks if a loader version is compatible with a MC version.
-	IsVersionCompatible(loaderVersion, mcVersion string) (bool, error)
+	IsVersionCompatible(ctx context.Context, loaderVersion, mcVersion string) (bool, error)
 }
 
 // MinecraftProvider defines the interface for fetching Minecraft version information.
@@ -0,0 +1,171 @@
+// Package mirror rewrites download URLs to configured mirrors, such as an
+// internal artifact repository standing in for the public loader and mod hosts.
+package mirror
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/alexinslc/chunk/internal/config"
+	"github.com/alexinslc/chunk/internal/ui"
+)
+
+// Rewriter maps URLs to their mirrors. The longest matching prefix wins.
+type Rewriter struct {
+	rules []config.MirrorRule
+}
+
+// NewRewriter creates a rewriter for the given rules
+func NewRewriter(rules []config.MirrorRule) *Rewriter {
+	sorted := make([]config.MirrorRule, len(rules))
+	copy(sorted, rules)
+	sort.SliceStable(sorted, func(a, b int) bool {
+		return len(sorted[a].Prefix) > len(sorted[b].Prefix)
+	})
+	return &Rewriter{rules: sorted}
+}
+
+// Rewrite returns the mirrored URL and the rule that produced it, or a nil
+// rule if no rule matches
+func (r *Rewriter) Rewrite(rawURL string) (string, *config.MirrorRule) {
+	if r == nil {
+		return rawURL, nil
+	}
+	for i := range r.rules {
+		rule := &r.rules[i]
+		if matchesPrefix(rawURL, rule.Prefix) {
+			return rule.Mirror + strings.TrimPrefix(rawURL, rule.Prefix), rule
+		}
+	}
+	return rawURL, nil
+}
+
+// matchesPrefix reports whether rawURL starts with prefix at a path boundary,
+// so https://cdn.example.com/data does not match https://cdn.example.com/database
+func matchesPrefix(rawURL, prefix string) bool {
+	rest, found := strings.CutPrefix(rawURL, prefix)
+	if !found {
+		return false
+	}
+	return strings.HasSuffix(prefix, "/") || rest == "" || rest[0] == '/' || rest[0] == '?'
+}
+
+// ValidateRule checks that a rule maps one http(s) URL prefix to another.
+// The prefix is matched at path boundaries, so it may not hold a query or
+// fragment, and prefix and mirror must agree on a trailing slash for the
+// rewritten path to join up.
+func ValidateRule(rule config.MirrorRule) error {
+	for _, field := range []struct{ name, value string }{{"prefix", rule.Prefix}, {"mirror", rule.Mirror}} {
+		parsed, err := url.Parse(field.value)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("invalid mirror %s %q: must be an http(s) URL", field.name, field.value)
+		}
+		if field.name == "prefix" && strings.ContainsAny(field.value, "?#") {
+			return fmt.Errorf("invalid mirror prefix %q: must not contain a query or fragment", field.value)
+		}
+	}
+	if strings.HasSuffix(rule.Prefix, "/") != strings.HasSuffix(rule.Mirror, "/") {
+		return fmt.Errorf("invalid mirror rule %q: prefix and mirror %q must both end in / or neither", rule.Prefix, rule.Mirror)
+	}
+	return nil
+}
+
+var (
+	defaultOnce     sync.Once
+	defaultRewriter *Rewriter
+)
+
+// Default returns the rewriter for the mirrors in the user's config.
+// Invalid rules are skipped with a warning.
+func Default() *Rewriter {
+	defaultOnce.Do(func() {
+		cfg, err := config.Load()
+		if err != nil {
+			defaultRewriter = NewRewriter(nil)
+			return
+		}
+
+		var rules []config.MirrorRule
+		for _, rule := range cfg.Mirrors {
+			if err := ValidateRule(rule); err != nil {
+				ui.PrintWarning(fmt.Sprintf("Ignoring mirror rule: %v", err))
+				continue
+			}
+			rules = append(rules, rule)
+		}
+		defaultRewriter = NewRewriter(rules)
+	})
+	return defaultRewriter
+}
+
+// Transport is an http.RoundTripper that sends requests to their mirror.
+// When a rule allows fallback and the mirror fails, the request is retried
+// against the original URL.
+type Transport struct {
+	// Base performs the requests; http.DefaultTransport when nil
+	Base http.RoundTripper
+	// Rewriter holds the rules; the configured mirrors (Default) when nil
+	Rewriter *Rewriter
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	rewriter := t.Rewriter
+	if rewriter == nil {
+		rewriter = Default()
+	}
+
+	mirrored, rule := rewriter.Rewrite(req.URL.String())
+	if rule == nil {
+		return base.RoundTrip(req)
+	}
+
+	mirrorURL, err := url.Parse(mirrored)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mirror URL for %s: %w", req.URL.Redacted(), err)
+	}
+	ui.PrintVerbose(fmt.Sprintf("Mirror: %s -> %s", req.URL.Redacted(), mirrorURL.Redacted()))
+
+	// A RoundTripper must not modify the caller's request
+	mirrorReq := req.Clone(req.Context())
+	mirrorReq.URL = mirrorURL
+	mirrorReq.Host = ""
+	if mirrorURL.User != nil {
+		password, _ := mirrorURL.User.Password()
+		mirrorReq.SetBasicAuth(mirrorURL.User.Username(), password)
+	}
+
+	resp, err := base.RoundTrip(mirrorReq)
+	// Only bodiless requests (downloads and metadata lookups) can be replayed
+	bodiless := req.Body == nil || req.Body == http.NoBody
+	if !rule.Fallback || !bodiless || !mirrorFailed(resp, err) {
+		return resp, err
+	}
+
+	reason := ""
+	if err != nil {
+		reason = err.Error()
+	} else {
+		reason = resp.Status
+		resp.Body.Close()
+	}
+	ui.PrintWarning(fmt.Sprintf("Mirror %s failed (%s), falling back to %s", mirrorURL.Host, reason, req.URL.Host))
+
+	return base.RoundTrip(req)
+}
+
+// mirrorFailed reports whether a mirror response warrants trying the origin.
+// 416 is left to the caller: resumed downloads handle it themselves.
+func mirrorFailed(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	return resp.StatusCode >= 400 && resp.StatusCode != http.StatusRequestedRangeNotSatisfiable
+}
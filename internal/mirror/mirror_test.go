@@ -0,0 +1,159 @@
+package mirror
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alexinslc/chunk/internal/config"
+)
+
+func TestRewriterRewrite(t *testing.T) {
+	rewriter := NewRewriter([]config.MirrorRule{
+		{Prefix: "https://maven.minecraftforge.net/", Mirror: "https://artifactory.example.com/forge/"},
+		{Prefix: "https://cdn.modrinth.com/", Mirror: "https://artifactory.example.com/modrinth/"},
+		{Prefix: "https://cdn.modrinth.com/data/special/", Mirror: "https://special.example.com/"},
+		{Prefix: "https://meta.fabricmc.net/v2", Mirror: "https://fabric.example.com/v2"},
+	})
+
+	tests := []struct {
+		name string
+		url  string
+		want string
+		hit  bool
+	}{
+		{
+			name: "forge installer",
+			url:  "https://maven.minecraftforge.net/net/minecraftforge/forge/1.20.1-47.2.0/forge-1.20.1-47.2.0-installer.jar",
+			want: "https://artifactory.example.com/forge/net/minecraftforge/forge/1.20.1-47.2.0/forge-1.20.1-47.2.0-installer.jar",
+			hit:  true,
+		},
+		{
+			name: "longest prefix wins",
+			url:  "https://cdn.modrinth.com/data/special/jei.jar",
+			want: "https://special.example.com/jei.jar",
+			hit:  true,
+		},
+		{
+			name: "prefix without trailing slash",
+			url:  "https://meta.fabricmc.net/v2/versions/loader",
+			want: "https://fabric.example.com/v2/versions/loader",
+			hit:  true,
+		},
+		{
+			name: "prefix matches whole path",
+			url:  "https://meta.fabricmc.net/v2?refresh=1",
+			want: "https://fabric.example.com/v2?refresh=1",
+			hit:  true,
+		},
+		{
+			name: "prefix stops mid-segment",
+			url:  "https://meta.fabricmc.net/v20/versions/loader",
+			want: "https://meta.fabricmc.net/v20/versions/loader",
+		},
+		{
+			name: "no matching rule",
+			url:  "https://maven.fabricmc.net/net/fabricmc/fabric-installer/maven-metadata.xml",
+			want: "https://maven.fabricmc.net/net/fabricmc/fabric-installer/maven-metadata.xml",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, rule := rewriter.Rewrite(tt.url)
+			if got != tt.want {
+				t.Errorf("Rewrite(%q) = %q, want %q", tt.url, got, tt.want)
+			}
+			if (rule != nil) != tt.hit {
+				t.Errorf("Rewrite(%q) matched rule %v, want match %v", tt.url, rule, tt.hit)
+			}
+		})
+	}
+}
+
+func TestValidateRule(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    config.MirrorRule
+		wantErr bool
+	}{
+		{name: "valid", rule: config.MirrorRule{Prefix: "https://cdn.modrinth.com/", Mirror: "http://mirror.local/modrinth/"}},
+		{name: "missing mirror", rule: config.MirrorRule{Prefix: "https://cdn.modrinth.com/"}, wantErr: true},
+		{name: "relative prefix", rule: config.MirrorRule{Prefix: "cdn.modrinth.com", Mirror: "https://mirror.local/"}, wantErr: true},
+		{name: "unsupported scheme", rule: config.MirrorRule{Prefix: "https://cdn.modrinth.com/", Mirror: "ftp://mirror.local/"}, wantErr: true},
+		{name: "no trailing slashes", rule: config.MirrorRule{Prefix: "https://meta.fabricmc.net/v2", Mirror: "https://mirror.local/v2"}},
+		{name: "mismatched trailing slash", rule: config.MirrorRule{Prefix: "https://cdn.modrinth.com/", Mirror: "https://mirror.local/modrinth"}, wantErr: true},
+		{name: "prefix with query", rule: config.MirrorRule{Prefix: "https://cdn.modrinth.com/?x=1", Mirror: "https://mirror.local/?x=1"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateRule(tt.rule); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateRule() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTransport(t *testing.T) {
+	origin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "origin:"+r.URL.Path)
+	}))
+	defer origin.Close()
+
+	var mirrorAuth string
+	mirror := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mirrorAuth = r.Header.Get("Authorization")
+		if strings.HasSuffix(r.URL.Path, "missing.jar") {
+			http.NotFound(w, r)
+			return
+		}
+		io.WriteString(w, "mirror:"+r.URL.Path)
+	}))
+	defer mirror.Close()
+
+	mirrorWithAuth := strings.Replace(mirror.URL, "http://", "http://ci:secret@", 1)
+
+	tests := []struct {
+		name     string
+		fallback bool
+		path     string
+		want     string
+		status   int
+	}{
+		{name: "mirrored", path: "/mods/jei.jar", want: "mirror:/repo/mods/jei.jar", status: http.StatusOK},
+		{name: "mirror miss without fallback", path: "/mods/missing.jar", status: http.StatusNotFound},
+		{name: "mirror miss with fallback", fallback: true, path: "/mods/missing.jar", want: "origin:/mods/missing.jar", status: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &http.Client{Transport: &Transport{
+				Rewriter: NewRewriter([]config.MirrorRule{
+					{Prefix: origin.URL + "/", Mirror: mirrorWithAuth + "/repo/", Fallback: tt.fallback},
+				}),
+			}}
+
+			resp, err := client.Get(origin.URL + tt.path)
+			if err != nil {
+				t.Fatalf("Get failed: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.status {
+				t.Fatalf("Status = %d, want %d", resp.StatusCode, tt.status)
+			}
+			if tt.want != "" {
+				body, _ := io.ReadAll(resp.Body)
+				if string(body) != tt.want {
+					t.Errorf("Body = %q, want %q", body, tt.want)
+				}
+			}
+			if mirrorAuth == "" {
+				t.Error("Expected mirror credentials to be sent as basic auth")
+			}
+		})
+	}
+}
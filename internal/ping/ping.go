@@ -0,0 +1,359 @@
+// Package ping queries the status of Minecraft servers with the Server List
+// Ping protocol, falling back to the legacy ping of servers before 1.7.
+package ping
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+
+	"github.com/alexinslc/chunk/internal/rcon"
+)
+
+// DefaultPort is the port servers listen on unless server-port says otherwise
+const DefaultPort = 25565
+
+// DefaultTimeout bounds each connection and exchange with the server
+const DefaultTimeout = 5 * time.Second
+
+const (
+	// handshakeProtocol is the protocol version clients send when they ping
+	// to find out which version a server runs
+	handshakeProtocol = -1
+	// nextStateStatus asks the server for its status after the handshake
+	nextStateStatus = 1
+	// maxResponseSize bounds the status JSON, which holds a favicon
+	maxResponseSize = 1 << 21
+	// legacyPrefix starts the responses of servers from 1.4 on
+	legacyPrefix = "§1\x00"
+)
+
+// Status is what a server reports about itself
+type Status struct {
+	Version  string `json:"version"`
+	Protocol int    `json:"protocol"`
+	Online   int    `json:"online"`
+	Max      int    `json:"max"`
+	// Sample holds some of the online players' names; servers may leave it
+	// out or fill it with text of their own
+	Sample []string `json:"sample,omitempty"`
+	MOTD   string   `json:"motd"`
+	// Latency is the round trip of a ping, or of the status query for
+	// servers that do not answer pings
+	Latency time.Duration `json:"-"`
+	// Legacy is set when the server only answered the legacy ping
+	Legacy bool `json:"legacy,omitempty"`
+}
+
+// Ping queries the status of the server at address, given as host:port. The
+// legacy ping is tried when the server does not answer the current protocol.
+func Ping(address string, timeout time.Duration) (*Status, error) {
+	status, err := pingModern(address, timeout)
+	if err == nil {
+		return status, nil
+	}
+
+	// A refused or unreachable connection fails the legacy ping as well
+	var netErr *net.OpError
+	if errors.As(err, &netErr) && netErr.Op == "dial" {
+		return nil, err
+	}
+	if legacy, legacyErr := pingLegacy(address, timeout); legacyErr == nil {
+		return legacy, nil
+	}
+	return nil, err
+}
+
+// statusResponse is the JSON of a status response
+type statusResponse struct {
+	Version struct {
+		Name     string `json:"name"`
+		Protocol int    `json:"protocol"`
+	} `json:"version"`
+	Players struct {
+		Max    int `json:"max"`
+		Online int `json:"online"`
+		Sample []struct {
+			Name string `json:"name"`
+		} `json:"sample"`
+	} `json:"players"`
+	Description json.RawMessage `json:"description"`
+}
+
+func pingModern(address string, timeout time.Duration) (*Status, error) {
+	host, port, err := splitAddress(address)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := net.DialTimeout("tcp", address, timeout)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(timeout))
+	reader := bufio.NewReader(conn)
+	start := time.Now()
+
+	// Handshake, then status request
+	var handshake bytes.Buffer
+	writeVarInt(&handshake, 0x00)
+	writeVarInt(&handshake, handshakeProtocol)
+	writeString(&handshake, host)
+	binary.Write(&handshake, binary.BigEndian, port)
+	writeVarInt(&handshake, nextStateStatus)
+	if err := writePacket(conn, handshake.Bytes(), []byte{0x00}); err != nil {
+		return nil, fmt.Errorf("failed to send status request: %w", err)
+	}
+
+	id, payload, err := readPacket(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read status response: %w", err)
+	}
+	if id != 0x00 {
+		return nil, fmt.Errorf("unexpected packet 0x%02x in place of a status response", id)
+	}
+	data, err := readString(bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("invalid status response: %w", err)
+	}
+
+	var response statusResponse
+	if err := json.Unmarshal([]byte(data), &response); err != nil {
+		return nil, fmt.Errorf("invalid status response: %w", err)
+	}
+	status := &Status{
+		Version:  response.Version.Name,
+		Protocol: response.Version.Protocol,
+		Online:   response.Players.Online,
+		Max:      response.Players.Max,
+		MOTD:     describe(response.Description),
+		Latency:  time.Since(start),
+	}
+	for _, player := range response.Players.Sample {
+		status.Sample = append(status.Sample, rcon.StripFormatting(player.Name))
+	}
+
+	// The ping round trip is a better measure of latency than the status
+	// query, but servers that do not answer it still count as online
+	var ping bytes.Buffer
+	writeVarInt(&ping, 0x01)
+	binary.Write(&ping, binary.BigEndian, time.Now().UnixMilli())
+	start = time.Now()
+	if err := writePacket(conn, ping.Bytes()); err == nil {
+		if id, _, err := readPacket(reader); err == nil && id == 0x01 {
+			status.Latency = time.Since(start)
+		}
+	}
+	return status, nil
+}
+
+// pingLegacy sends the ping of clients from 1.4 to 1.6, which servers from
+// 1.4 on answer with their version and older ones still answer with the
+// MOTD and player count
+func pingLegacy(address string, timeout time.Duration) (*Status, error) {
+	conn, err := net.DialTimeout("tcp", address, timeout)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(timeout))
+
+	start := time.Now()
+	if _, err := conn.Write([]byte{0xFE, 0x01}); err != nil {
+		return nil, fmt.Errorf("failed to send legacy ping: %w", err)
+	}
+
+	var header struct {
+		ID     byte
+		Length uint16
+	}
+	if err := binary.Read(conn, binary.BigEndian, &header); err != nil {
+		return nil, fmt.Errorf("failed to read legacy ping response: %w", err)
+	}
+	if header.ID != 0xFF {
+		return nil, fmt.Errorf("unexpected packet 0x%02x in place of a legacy ping response", header.ID)
+	}
+	units := make([]uint16, header.Length)
+	if err := binary.Read(conn, binary.BigEndian, units); err != nil {
+		return nil, fmt.Errorf("failed to read legacy ping response: %w", err)
+	}
+	latency := time.Since(start)
+
+	status, err := parseLegacy(string(utf16.Decode(units)))
+	if err != nil {
+		return nil, err
+	}
+	status.Latency = latency
+	status.Legacy = true
+	return status, nil
+}
+
+// parseLegacy parses a legacy ping response: §1, protocol, version, MOTD,
+// online and max players separated by NUL from 1.4 on, and MOTD, online and
+// max players separated by § before
+func parseLegacy(response string) (*Status, error) {
+	var fields []string
+	status := &Status{}
+	if rest, found := strings.CutPrefix(response, legacyPrefix); found {
+		fields = strings.Split(rest, "\x00")
+		if len(fields) != 5 {
+			return nil, fmt.Errorf("invalid legacy ping response %q", response)
+		}
+		status.Protocol, _ = strconv.Atoi(fields[0])
+		status.Version = fields[1]
+		fields = fields[2:]
+	} else {
+		fields = strings.Split(response, "§")
+		if len(fields) < 3 {
+			return nil, fmt.Errorf("invalid legacy ping response %q", response)
+		}
+		// The MOTD may itself contain §
+		n := len(fields)
+		fields = []string{strings.Join(fields[:n-2], "§"), fields[n-2], fields[n-1]}
+	}
+
+	status.MOTD = rcon.StripFormatting(fields[0])
+	var err error
+	if status.Online, err = strconv.Atoi(fields[1]); err != nil {
+		return nil, fmt.Errorf("invalid player count %q in legacy ping response", fields[1])
+	}
+	if status.Max, err = strconv.Atoi(fields[2]); err != nil {
+		return nil, fmt.Errorf("invalid player limit %q in legacy ping response", fields[2])
+	}
+	return status, nil
+}
+
+// describe returns the plain text of a description, which is a string or a
+// chat component with text and extra components
+func describe(raw json.RawMessage) string {
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return rcon.StripFormatting(text)
+	}
+
+	var component chatComponent
+	if err := json.Unmarshal(raw, &component); err != nil {
+		return ""
+	}
+	var b strings.Builder
+	component.writeText(&b)
+	return rcon.StripFormatting(b.String())
+}
+
+type chatComponent struct {
+	Text  string            `json:"text"`
+	Extra []json.RawMessage `json:"extra"`
+}
+
+func (c *chatComponent) writeText(b *strings.Builder) {
+	b.WriteString(c.Text)
+	for _, raw := range c.Extra {
+		var text string
+		if err := json.Unmarshal(raw, &text); err == nil {
+			b.WriteString(text)
+			continue
+		}
+		var extra chatComponent
+		if err := json.Unmarshal(raw, &extra); err == nil {
+			extra.writeText(b)
+		}
+	}
+}
+
+// splitAddress splits host:port for the handshake
+func splitAddress(address string) (string, uint16, error) {
+	host, portString, err := net.SplitHostPort(address)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid server address %q: %w", address, err)
+	}
+	port, err := strconv.ParseUint(portString, 10, 16)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid port in server address %q", address)
+	}
+	return host, uint16(port), nil
+}
+
+// writePacket writes packets, each prefixed with its length as a VarInt
+func writePacket(w io.Writer, packets ...[]byte) error {
+	var b bytes.Buffer
+	for _, packet := range packets {
+		writeVarInt(&b, int32(len(packet)))
+		b.Write(packet)
+	}
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// readPacket reads a length-prefixed packet and returns its ID and payload
+func readPacket(r *bufio.Reader) (int32, []byte, error) {
+	length, err := readVarInt(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	if length <= 0 || length > maxResponseSize {
+		return 0, nil, fmt.Errorf("invalid packet length %d", length)
+	}
+	data := make([]byte, length)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return 0, nil, err
+	}
+
+	payload := bytes.NewReader(data)
+	id, err := readVarInt(payload)
+	if err != nil {
+		return 0, nil, err
+	}
+	return id, data[len(data)-payload.Len():], nil
+}
+
+func writeVarInt(b *bytes.Buffer, value int32) {
+	v := uint32(value)
+	for v >= 0x80 {
+		b.WriteByte(byte(v) | 0x80)
+		v >>= 7
+	}
+	b.WriteByte(byte(v))
+}
+
+func readVarInt(r io.ByteReader) (int32, error) {
+	var value uint32
+	for shift := 0; shift < 35; shift += 7 {
+		c, err := r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		value |= uint32(c&0x7F) << shift
+		if c&0x80 == 0 {
+			return int32(value), nil
+		}
+	}
+	return 0, errors.New("VarInt is too long")
+}
+
+func writeString(b *bytes.Buffer, s string) {
+	writeVarInt(b, int32(len(s)))
+	b.WriteString(s)
+}
+
+func readString(r *bytes.Reader) (string, error) {
+	length, err := readVarInt(r)
+	if err != nil {
+		return "", err
+	}
+	if length < 0 || int(length) > r.Len() {
+		return "", fmt.Errorf("invalid string length %d", length)
+	}
+	data := make([]byte, length)
+	r.Read(data)
+	return string(data), nil
+}
@@ -0,0 +1,156 @@
+package ping
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"net"
+	"testing"
+	"time"
+	"unicode/utf16"
+)
+
+const statusJSON = `{
+  "version": {"name": "1.20.1", "protocol": 763},
+  "players": {"max": 20, "online": 2, "sample": [{"name": "Alex", "id": "1"}, {"name": "§eSteve", "id": "2"}]},
+  "description": {"text": "§aSurvival ", "extra": [{"text": "EU", "extra": [" server"]}]}
+}`
+
+// startFakeServer serves every connection with handle until the test ends
+func startFakeServer(t *testing.T, handle func(net.Conn)) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				conn.SetDeadline(time.Now().Add(5 * time.Second))
+				handle(conn)
+			}()
+		}
+	}()
+	return listener.Addr().String()
+}
+
+// serveModern answers the status request and ping of a 1.7+ server
+func serveModern(conn net.Conn) {
+	reader := bufio.NewReader(conn)
+	id, payload, err := readPacket(reader)
+	if err != nil || id != 0x00 {
+		return
+	}
+	handshake := bytes.NewReader(payload)
+	readVarInt(handshake)
+	readString(handshake)
+	var port uint16
+	binary.Read(handshake, binary.BigEndian, &port)
+	if next, _ := readVarInt(handshake); next != nextStateStatus {
+		return
+	}
+
+	if id, _, err := readPacket(reader); err != nil || id != 0x00 {
+		return
+	}
+	var response bytes.Buffer
+	writeVarInt(&response, 0x00)
+	writeString(&response, statusJSON)
+	writePacket(conn, response.Bytes())
+
+	// The pong echoes the ping
+	id, payload, err = readPacket(reader)
+	if err != nil || id != 0x01 {
+		return
+	}
+	var pong bytes.Buffer
+	writeVarInt(&pong, 0x01)
+	pong.Write(payload)
+	writePacket(conn, pong.Bytes())
+}
+
+// serveLegacy answers the legacy ping like a 1.6 server and drops anything else
+func serveLegacy(conn net.Conn) {
+	request := make([]byte, 2)
+	if _, err := conn.Read(request); err != nil || request[0] != 0xFE {
+		return
+	}
+	units := utf16.Encode([]rune("§1\x0078\x001.6.4\x00§bOld server\x003\x0010"))
+	binary.Write(conn, binary.BigEndian, byte(0xFF))
+	binary.Write(conn, binary.BigEndian, uint16(len(units)))
+	binary.Write(conn, binary.BigEndian, units)
+}
+
+func TestPing(t *testing.T) {
+	address := startFakeServer(t, serveModern)
+
+	status, err := Ping(address, 5*time.Second)
+	if err != nil {
+		t.Fatalf("Ping failed: %v", err)
+	}
+	if status.Version != "1.20.1" || status.Protocol != 763 {
+		t.Errorf("Expected version 1.20.1 (763), got %s (%d)", status.Version, status.Protocol)
+	}
+	if status.Online != 2 || status.Max != 20 {
+		t.Errorf("Expected 2/20 players, got %d/%d", status.Online, status.Max)
+	}
+	if len(status.Sample) != 2 || status.Sample[1] != "Steve" {
+		t.Errorf("Expected sample [Alex Steve], got %v", status.Sample)
+	}
+	if status.MOTD != "Survival EU server" {
+		t.Errorf("Expected MOTD %q, got %q", "Survival EU server", status.MOTD)
+	}
+	if status.Latency <= 0 || status.Legacy {
+		t.Errorf("Expected a modern status with latency, got %+v", status)
+	}
+}
+
+func TestPingLegacyFallback(t *testing.T) {
+	address := startFakeServer(t, serveLegacy)
+
+	status, err := Ping(address, 5*time.Second)
+	if err != nil {
+		t.Fatalf("Ping failed: %v", err)
+	}
+	if !status.Legacy || status.Version != "1.6.4" || status.Protocol != 78 {
+		t.Errorf("Expected legacy status of 1.6.4 (78), got %+v", status)
+	}
+	if status.MOTD != "Old server" || status.Online != 3 || status.Max != 10 {
+		t.Errorf("Expected Old server with 3/10 players, got %+v", status)
+	}
+}
+
+func TestPingOffline(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := listener.Addr().String()
+	listener.Close()
+
+	if _, err := Ping(address, time.Second); err == nil {
+		t.Error("Expected an error for a closed port")
+	}
+}
+
+func TestParseLegacyBeta(t *testing.T) {
+	// Servers before 1.4 separate MOTD, online and max players with §
+	status, err := parseLegacy("A Minecraft Server§5§20")
+	if err != nil {
+		t.Fatalf("parseLegacy failed: %v", err)
+	}
+	if status.MOTD != "A Minecraft Server" || status.Online != 5 || status.Max != 20 {
+		t.Errorf("Expected A Minecraft Server with 5/20 players, got %+v", status)
+	}
+
+	if _, err := parseLegacy("garbage"); err == nil {
+		t.Error("Expected an error for an invalid response")
+	}
+}
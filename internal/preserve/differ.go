@@ -2,9 +2,13 @@ package preserve
 
 import (
 	"fmt"
+	"path"
+	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/alexinslc/chunk/internal/config"
+	"github.com/alexinslc/chunk/internal/sources"
 )
 
 type VersionDiffer struct{}
@@ -13,41 +17,105 @@ func NewVersionDiffer() *VersionDiffer {
 	return &VersionDiffer{}
 }
 
+// modVersionPattern finds where the version starts in a mod jar name,
+// e.g. "jei-1.20.1-forge-15.2.0.27" or "Xaeros_Minimap_24.0.0_Forge_1.20"
+var modVersionPattern = regexp.MustCompile(`[-_+]v?\d`)
+
 type ModpackDiff struct {
-	MCVersionChange    *VersionChange
-	LoaderChange       *LoaderChange
-	ModsAdded          []ModInfo
-	ModsRemoved        []ModInfo
-	ModsUpdated        []ModUpdate
-	ModsUnchanged      []ModInfo
-	HasBreakingChanges bool
-	Recommendations    []string
+	MCVersionChange    *VersionChange `json:"mc_version_change,omitempty"`
+	LoaderChange       *LoaderChange  `json:"loader_change,omitempty"`
+	ModsAdded          []ModInfo      `json:"mods_added"`
+	ModsRemoved        []ModInfo      `json:"mods_removed"`
+	ModsUpdated        []ModUpdate    `json:"mods_updated"`
+	ModsUnchanged      []ModInfo      `json:"mods_unchanged"`
+	HasBreakingChanges bool           `json:"has_breaking_changes"`
+	Recommendations    []string       `json:"recommendations"`
 }
 
 type VersionChange struct {
-	From string
-	To   string
+	From string `json:"from"`
+	To   string `json:"to"`
 }
 
 type LoaderChange struct {
-	TypeFrom    string
-	TypeTo      string
-	VersionFrom string
-	VersionTo   string
+	TypeFrom    string `json:"type_from"`
+	TypeTo      string `json:"type_to"`
+	VersionFrom string `json:"version_from"`
+	VersionTo   string `json:"version_to"`
 }
 
 type ModInfo struct {
-	Name    string
-	Version string
-	ID      string
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	ID      string `json:"id"`
 }
 
 type ModUpdate struct {
-	Name        string
-	VersionFrom string
-	VersionTo   string
-	ID          string
-	IsBreaking  bool
+	Name        string `json:"name"`
+	VersionFrom string `json:"version_from"`
+	VersionTo   string `json:"version_to"`
+	ID          string `json:"id"`
+	IsBreaking  bool   `json:"is_breaking"`
+}
+
+// ParseModFileName derives a mod id and version from a jar name, so the same
+// mod can be matched across pack versions that only list files.
+// "mods/jei-1.20.1-forge-15.2.0.27.jar" gives "jei" and "1.20.1-forge-15.2.0.27".
+func ParseModFileName(fileName string) (id, version string) {
+	base := strings.TrimSuffix(path.Base(strings.ReplaceAll(fileName, "\\", "/")), ".jar")
+
+	loc := modVersionPattern.FindStringIndex(base)
+	if loc == nil || loc[0] == 0 {
+		return strings.ToLower(base), ""
+	}
+
+	return strings.ToLower(base[:loc[0]]), base[loc[0]+1:]
+}
+
+// ManifestFromModpack converts a modpack into the manifest of a server
+// installed from it. Client-only mods are left out.
+func ManifestFromModpack(modpack *sources.Modpack) *config.ChunkManifest {
+	manifest := &config.ChunkManifest{
+		Name:             modpack.Name,
+		Description:      modpack.Description,
+		MCVersion:        modpack.MCVersion,
+		Loader:           string(modpack.Loader),
+		LoaderVersion:    modpack.LoaderVersion,
+		RecommendedRAMGB: modpack.RecommendedRAM,
+		Dependencies:     modpack.Dependencies,
+		JVMArgs:          modpack.JVMArgs,
+		Env:              modpack.Env,
+		MinJava:          modpack.MinJava,
+		MaxJava:          modpack.MaxJava,
+	}
+	for _, mod := range modpack.Mods {
+		if mod.Side == sources.SideClient {
+			continue
+		}
+		manifest.Mods = append(manifest.Mods, ManifestMod(mod.Name, mod.Version, mod.FileName, mod.DownloadURL, string(mod.Side)))
+	}
+
+	return manifest
+}
+
+// ManifestMod identifies a mod by its jar name, which is stable across sources
+func ManifestMod(name, version, fileName, url, side string) config.ManifestMod {
+	id, fileVersion := ParseModFileName(fileName)
+	if id == "" {
+		id = strings.ToLower(name)
+	}
+	if fileVersion != "" {
+		version = fileVersion
+	}
+
+	return config.ManifestMod{
+		ID:       id,
+		Name:     name,
+		Version:  version,
+		URL:      url,
+		Side:     side,
+		FileName: fileName,
+	}
 }
 
 func (d *VersionDiffer) CompareMCVersions(from, to string) *VersionChange {
@@ -72,6 +140,10 @@ func (d *VersionDiffer) CompareLoaders(fromType, fromVersion, toType, toVersion
 
 func (d *VersionDiffer) CompareModpacks(oldManifest, newManifest *config.ChunkManifest) *ModpackDiff {
 	diff := &ModpackDiff{
+		ModsAdded:       []ModInfo{},
+		ModsRemoved:     []ModInfo{},
+		ModsUpdated:     []ModUpdate{},
+		ModsUnchanged:   []ModInfo{},
 		Recommendations: []string{},
 	}
 
@@ -83,25 +155,8 @@ func (d *VersionDiffer) CompareModpacks(oldManifest, newManifest *config.ChunkMa
 		newManifest.LoaderVersion,
 	)
 
-	oldMods := make(map[string]ModInfo)
-	// TODO: Implement mod comparison once ChunkManifest has Mods field
-	// for _, mod := range oldManifest.Mods {
-	// 	oldMods[mod.ID] = ModInfo{
-	// 		Name:    mod.Name,
-	// 		Version: mod.Version,
-	// 		ID:      mod.ID,
-	// 	}
-	// }
-
-	newMods := make(map[string]ModInfo)
-	// TODO: Implement mod comparison once ChunkManifest has Mods field
-	// for _, mod := range newManifest.Mods {
-	// 	newMods[mod.ID] = ModInfo{
-	// 		Name:    mod.Name,
-	// 		Version: mod.Version,
-	// 		ID:      mod.ID,
-	// 	}
-	// }
+	oldMods := manifestMods(oldManifest)
+	newMods := manifestMods(newManifest)
 
 	for id, newMod := range newMods {
 		if oldMod, exists := oldMods[id]; exists {
@@ -131,11 +186,45 @@ func (d *VersionDiffer) CompareModpacks(oldManifest, newManifest *config.ChunkMa
 		}
 	}
 
+	sortMods(diff)
 	d.generateRecommendations(diff)
 
 	return diff
 }
 
+// manifestMods indexes a manifest's mods by id, deriving ids from file names where missing
+func manifestMods(manifest *config.ChunkManifest) map[string]ModInfo {
+	mods := make(map[string]ModInfo, len(manifest.Mods))
+	for _, mod := range manifest.Mods {
+		info := ModInfo{ID: mod.ID, Name: mod.Name, Version: mod.Version}
+		if info.ID == "" || info.Version == "" {
+			id, version := ParseModFileName(mod.FileName)
+			if info.ID == "" {
+				info.ID = id
+			}
+			if info.Version == "" {
+				info.Version = version
+			}
+		}
+		if info.ID == "" {
+			continue
+		}
+		if info.Name == "" {
+			info.Name = info.ID
+		}
+		mods[info.ID] = info
+	}
+	return mods
+}
+
+// sortMods orders each mod list by id so reports are stable between runs
+func sortMods(diff *ModpackDiff) {
+	for _, mods := range [][]ModInfo{diff.ModsAdded, diff.ModsRemoved, diff.ModsUnchanged} {
+		sort.Slice(mods, func(i, j int) bool { return mods[i].ID < mods[j].ID })
+	}
+	sort.Slice(diff.ModsUpdated, func(i, j int) bool { return diff.ModsUpdated[i].ID < diff.ModsUpdated[j].ID })
+}
+
 func (d *VersionDiffer) isBreakingModUpdate(from, to string) bool {
 	fromParts := strings.Split(from, ".")
 	toParts := strings.Split(to, ".")
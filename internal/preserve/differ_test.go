@@ -0,0 +1,83 @@
+package preserve
+
+import (
+	"testing"
+
+	"github.com/alexinslc/chunk/internal/config"
+)
+
+func TestParseModFileName(t *testing.T) {
+	tests := []struct {
+		fileName    string
+		wantID      string
+		wantVersion string
+	}{
+		{"mods/jei-1.20.1-forge-15.2.0.27.jar", "jei", "1.20.1-forge-15.2.0.27"},
+		{"fabric-api-0.92.2+1.20.1.jar", "fabric-api", "0.92.2+1.20.1"},
+		{"Botania-1.20.1-443-FORGE.jar", "botania", "1.20.1-443-FORGE"},
+		{"Xaeros_Minimap_24.0.0_Forge_1.20.jar", "xaeros_minimap", "24.0.0_Forge_1.20"},
+		{"sodium-fabric-mc1.20.1-0.5.3.jar", "sodium-fabric-mc1.20.1", "0.5.3"},
+		{"mods\\appleskin-v2.5.1.jar", "appleskin", "v2.5.1"},
+		{"noversion.jar", "noversion", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fileName, func(t *testing.T) {
+			id, version := ParseModFileName(tt.fileName)
+			if id != tt.wantID || version != tt.wantVersion {
+				t.Errorf("ParseModFileName(%q) = (%q, %q), want (%q, %q)", tt.fileName, id, version, tt.wantID, tt.wantVersion)
+			}
+		})
+	}
+}
+
+func TestCompareModpacksMods(t *testing.T) {
+	oldManifest := &config.ChunkManifest{
+		MCVersion: "1.20.1",
+		Loader:    "forge",
+		Mods: []config.ManifestMod{
+			{ID: "jei", Name: "JEI", Version: "15.2.0"},
+			{ID: "create", Name: "Create", Version: "0.5.1"},
+			{FileName: "mods/ftb-chunks-forge-2001.3.0.jar"},
+			{ID: "mekanism", Name: "Mekanism", Version: "10.4.0"},
+		},
+	}
+	newManifest := &config.ChunkManifest{
+		MCVersion: "1.20.1",
+		Loader:    "forge",
+		Mods: []config.ManifestMod{
+			{ID: "jei", Name: "JEI", Version: "16.0.0"},
+			{ID: "create", Name: "Create", Version: "0.5.1"},
+			{FileName: "mods/ftb-chunks-forge-2001.3.1.jar"},
+			{ID: "ae2", Name: "Applied Energistics 2", Version: "15.0.0"},
+		},
+	}
+
+	diff := NewVersionDiffer().CompareModpacks(oldManifest, newManifest)
+
+	if diff.MCVersionChange != nil || diff.LoaderChange != nil {
+		t.Errorf("Expected no version changes, got %+v %+v", diff.MCVersionChange, diff.LoaderChange)
+	}
+	if len(diff.ModsAdded) != 1 || diff.ModsAdded[0].ID != "ae2" {
+		t.Errorf("ModsAdded = %+v, want ae2", diff.ModsAdded)
+	}
+	if len(diff.ModsRemoved) != 1 || diff.ModsRemoved[0].ID != "mekanism" {
+		t.Errorf("ModsRemoved = %+v, want mekanism", diff.ModsRemoved)
+	}
+	if len(diff.ModsUnchanged) != 1 || diff.ModsUnchanged[0].ID != "create" {
+		t.Errorf("ModsUnchanged = %+v, want create", diff.ModsUnchanged)
+	}
+
+	if len(diff.ModsUpdated) != 2 {
+		t.Fatalf("ModsUpdated = %+v, want ftb-chunks-forge and jei", diff.ModsUpdated)
+	}
+	if diff.ModsUpdated[0].ID != "ftb-chunks-forge" || diff.ModsUpdated[0].IsBreaking {
+		t.Errorf("Expected non-breaking ftb-chunks-forge update first, got %+v", diff.ModsUpdated[0])
+	}
+	if diff.ModsUpdated[1].ID != "jei" || !diff.ModsUpdated[1].IsBreaking {
+		t.Errorf("Expected breaking jei update, got %+v", diff.ModsUpdated[1])
+	}
+	if !diff.HasBreakingChanges {
+		t.Error("Expected breaking changes for a major jei update")
+	}
+}
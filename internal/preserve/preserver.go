@@ -4,8 +4,14 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+
+	"github.com/alexinslc/chunk/internal/converter"
+	"github.com/alexinslc/chunk/internal/sources"
 )
 
+// BackupDir is the directory inside a server that upgrades back data up to
+const BackupDir = ".chunk-backup"
+
 type DataPreserver struct{}
 
 func NewDataPreserver() *DataPreserver {
@@ -13,17 +19,14 @@ func NewDataPreserver() *DataPreserver {
 }
 
 func (p *DataPreserver) PreserveData(serverDir string) error {
-	criticalPaths := []string{
-		"world",
-		"world_nether",
-		"world_the_end",
+	criticalPaths := append(worldDirs(serverDir),
 		"server.properties",
 		"whitelist.json",
 		"ops.json",
 		"banned-players.json",
 		"banned-ips.json",
 		"usercache.json",
-	}
+	)
 
 	for _, path := range criticalPaths {
 		fullPath := filepath.Join(serverDir, path)
@@ -36,22 +39,20 @@ func (p *DataPreserver) PreserveData(serverDir string) error {
 }
 
 func (p *DataPreserver) BackupBeforeUpgrade(serverDir string) (string, error) {
-	backupDir := filepath.Join(serverDir, ".chunk-backup")
+	backupDir := filepath.Join(serverDir, BackupDir)
 
 	if err := os.MkdirAll(backupDir, 0755); err != nil {
 		return "", fmt.Errorf("failed to create backup directory: %w", err)
 	}
 
-	criticalPaths := []string{
-		"world",
-		"world_nether",
-		"world_the_end",
+	criticalPaths := append(worldDirs(serverDir),
 		"server.properties",
 		"whitelist.json",
 		"ops.json",
 		"banned-players.json",
 		"banned-ips.json",
-	}
+	)
+	criticalPaths = appendMissing(criticalPaths, p.GetEditedPackFiles(serverDir))
 
 	for _, path := range criticalPaths {
 		srcPath := filepath.Join(serverDir, path)
@@ -155,16 +156,13 @@ func (p *DataPreserver) CopyDir(src, dst string) error {
 func (p *DataPreserver) GetCriticalFiles(serverDir string) []string {
 	var existingFiles []string
 
-	criticalPaths := []string{
-		"world",
-		"world_nether",
-		"world_the_end",
+	criticalPaths := append(worldDirs(serverDir),
 		"server.properties",
 		"whitelist.json",
 		"ops.json",
 		"banned-players.json",
 		"banned-ips.json",
-	}
+	)
 
 	for _, path := range criticalPaths {
 		fullPath := filepath.Join(serverDir, path)
@@ -173,5 +171,60 @@ func (p *DataPreserver) GetCriticalFiles(serverDir string) []string {
 		}
 	}
 
-	return existingFiles
+	return appendMissing(existingFiles, p.GetEditedPackFiles(serverDir))
+}
+
+// GetEditedPackFiles returns files the modpack shipped through its override
+// layers that the user has since edited. Unedited pack files are left for the
+// new pack version to replace.
+func (p *DataPreserver) GetEditedPackFiles(serverDir string) []string {
+	record, err := sources.LoadOverridesRecord(serverDir)
+	if err != nil || record == nil {
+		return nil
+	}
+
+	edited, err := record.ModifiedFiles(serverDir)
+	if err != nil {
+		return nil
+	}
+
+	var paths []string
+	for _, path := range edited {
+		paths = append(paths, filepath.FromSlash(path))
+	}
+	return paths
+}
+
+// worldDirs returns the world directories of a server with their nether and
+// end dimensions: world, and the level-name of its server.properties
+func worldDirs(serverDir string) []string {
+	levels := []string{"world"}
+	if properties, err := converter.ReadServerProperties(serverDir); err == nil {
+		if name := properties["level-name"]; name != "" && name != "world" && filepath.IsLocal(name) {
+			levels = append(levels, name)
+		}
+	}
+
+	var dirs []string
+	for _, level := range levels {
+		dirs = append(dirs, level, level+"_nether", level+"_the_end")
+	}
+	return dirs
+}
+
+// appendMissing appends the paths not already present in list
+func appendMissing(list, paths []string) []string {
+	seen := make(map[string]bool, len(list))
+	for _, path := range list {
+		seen[path] = true
+	}
+
+	for _, path := range paths {
+		if !seen[path] {
+			seen[path] = true
+			list = append(list, path)
+		}
+	}
+
+	return list
 }
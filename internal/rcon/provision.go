@@ -0,0 +1,43 @@
+package rcon
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"strconv"
+)
+
+// DefaultPort is Minecraft's default RCON port, where the search for a free
+// port starts
+const DefaultPort = 25575
+
+// portRange is how many ports above the start FreePort tries
+const portRange = 100
+
+// GeneratePassword returns a random password that needs no escaping in
+// server.properties
+func GeneratePassword() (string, error) {
+	buf := make([]byte, 18)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate RCON password: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// FreePort returns the first port from start on that is neither in taken,
+// such as the RCON ports of other installations, nor in use on this host
+func FreePort(start int, taken map[int]bool) (int, error) {
+	for port := start; port < start+portRange && port <= 65535; port++ {
+		if taken[port] {
+			continue
+		}
+		listener, err := net.Listen("tcp", ":"+strconv.Itoa(port))
+		if err != nil {
+			continue
+		}
+		listener.Close()
+		return port, nil
+	}
+	return 0, fmt.Errorf("no free RCON port between %d and %d", start, start+portRange-1)
+}
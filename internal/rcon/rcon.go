@@ -0,0 +1,194 @@
+// Package rcon implements the client side of the Source RCON protocol, which
+// Minecraft servers expose for running console commands remotely.
+package rcon
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Packet types of the Source RCON protocol
+const (
+	typeResponse = 0
+	typeCommand  = 2
+	typeAuth     = 3
+	// typeAuthResponse shares its value with typeCommand; servers only send it
+	typeAuthResponse = 2
+)
+
+const (
+	// MaxCommandLength is the longest command Minecraft accepts in one packet
+	MaxCommandLength = 1446
+	// maxPacketSize bounds the packets read from the server
+	maxPacketSize = 1 << 16
+	// authFailedID is the request ID of the response to a wrong password
+	authFailedID = -1
+)
+
+// DefaultTimeout bounds connecting and every exchange with the server
+const DefaultTimeout = 10 * time.Second
+
+// ErrAuthFailed is returned when the server rejects the password
+var ErrAuthFailed = errors.New("RCON authentication failed: wrong password")
+
+// formatCodes matches Minecraft's § formatting codes
+var formatCodes = regexp.MustCompile("§.")
+
+// Client is an authenticated RCON connection
+type Client struct {
+	conn    net.Conn
+	timeout time.Duration
+	nextID  int32
+}
+
+type packet struct {
+	id   int32
+	kind int32
+	body string
+}
+
+// Dial connects to the RCON port at address and authenticates with password
+func Dial(address, password string, timeout time.Duration) (*Client, error) {
+	conn, err := net.DialTimeout("tcp", address, timeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to RCON at %s: %w", address, err)
+	}
+
+	client := &Client{conn: conn, timeout: timeout, nextID: 1}
+	if err := client.authenticate(password); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return client, nil
+}
+
+func (c *Client) authenticate(password string) error {
+	id := c.id()
+	if err := c.write(packet{id: id, kind: typeAuth, body: password}); err != nil {
+		return err
+	}
+
+	// Source servers send an empty response before the auth response
+	for {
+		response, err := c.read()
+		if err != nil {
+			return fmt.Errorf("failed to authenticate: %w", err)
+		}
+		if response.kind != typeAuthResponse {
+			continue
+		}
+		if response.id == authFailedID {
+			return ErrAuthFailed
+		}
+		if response.id != id {
+			return fmt.Errorf("failed to authenticate: unexpected response ID %d", response.id)
+		}
+		return nil
+	}
+}
+
+// Execute runs a console command and returns its output. Output spread over
+// several packets is joined.
+func (c *Client) Execute(command string) (string, error) {
+	if len(command) > MaxCommandLength {
+		return "", fmt.Errorf("command is longer than %d bytes", MaxCommandLength)
+	}
+
+	id := c.id()
+	if err := c.write(packet{id: id, kind: typeCommand, body: command}); err != nil {
+		return "", err
+	}
+
+	// The server answers requests in order, so the answer to an empty request
+	// marks the end of the output. It is only sent once the output started:
+	// Minecraft drops the connection when two requests arrive in one read.
+	var output strings.Builder
+	var endID int32
+	for {
+		response, err := c.read()
+		if err != nil {
+			return "", fmt.Errorf("failed to read command output: %w", err)
+		}
+		switch {
+		case response.id == id:
+			output.WriteString(response.body)
+			if endID == 0 {
+				endID = c.id()
+				if err := c.write(packet{id: endID, kind: typeResponse}); err != nil {
+					return "", err
+				}
+			}
+		case endID != 0 && response.id == endID:
+			return output.String(), nil
+		}
+	}
+}
+
+// Close closes the connection
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// StripFormatting removes Minecraft's § formatting codes from command output
+// and other text servers send, such as MOTDs
+func StripFormatting(s string) string {
+	return formatCodes.ReplaceAllString(s, "")
+}
+
+func (c *Client) id() int32 {
+	id := c.nextID
+	c.nextID++
+	return id
+}
+
+func (c *Client) write(p packet) error {
+	c.conn.SetWriteDeadline(time.Now().Add(c.timeout))
+	if _, err := c.conn.Write(encodePacket(p)); err != nil {
+		return fmt.Errorf("failed to send RCON request: %w", err)
+	}
+	return nil
+}
+
+func (c *Client) read() (packet, error) {
+	c.conn.SetReadDeadline(time.Now().Add(c.timeout))
+	return readPacket(c.conn)
+}
+
+// encodePacket frames a packet: its length, ID, type, body and two NUL bytes,
+// with integers in little-endian order
+func encodePacket(p packet) []byte {
+	var b bytes.Buffer
+	binary.Write(&b, binary.LittleEndian, int32(4+4+len(p.body)+2))
+	binary.Write(&b, binary.LittleEndian, p.id)
+	binary.Write(&b, binary.LittleEndian, p.kind)
+	b.WriteString(p.body)
+	b.Write([]byte{0, 0})
+	return b.Bytes()
+}
+
+func readPacket(r io.Reader) (packet, error) {
+	var length int32
+	if err := binary.Read(r, binary.LittleEndian, &length); err != nil {
+		return packet{}, err
+	}
+	if length < 10 || length > maxPacketSize {
+		return packet{}, fmt.Errorf("invalid packet length %d", length)
+	}
+
+	data := make([]byte, length)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return packet{}, err
+	}
+	return packet{
+		id:   int32(binary.LittleEndian.Uint32(data[0:4])),
+		kind: int32(binary.LittleEndian.Uint32(data[4:8])),
+		body: string(data[8 : length-2]),
+	}, nil
+}
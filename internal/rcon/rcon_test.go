@@ -0,0 +1,168 @@
+package rcon
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeServer answers RCON requests the way Minecraft does: one request per
+// read, responses split into 4096 byte packets and unknown request types
+// answered with an error message
+type fakeServer struct {
+	listener net.Listener
+	password string
+	commands chan string
+}
+
+func startFakeServer(t *testing.T, password string) *fakeServer {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	server := &fakeServer{listener: listener, password: password, commands: make(chan string, 10)}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.serve(conn)
+		}
+	}()
+	return server
+}
+
+func (s *fakeServer) serve(conn net.Conn) {
+	defer conn.Close()
+	authenticated := false
+	for {
+		request, err := readPacket(conn)
+		if err != nil {
+			return
+		}
+
+		switch {
+		case request.kind == typeAuth:
+			authenticated = request.body == s.password
+			id := request.id
+			if !authenticated {
+				id = authFailedID
+			}
+			conn.Write(encodePacket(packet{id: id, kind: typeAuthResponse}))
+		case !authenticated:
+			return
+		case request.kind == typeCommand:
+			s.commands <- request.body
+			output := s.execute(request.body)
+			for len(output) > 4096 {
+				conn.Write(encodePacket(packet{id: request.id, kind: typeResponse, body: output[:4096]}))
+				output = output[4096:]
+			}
+			conn.Write(encodePacket(packet{id: request.id, kind: typeResponse, body: output}))
+		default:
+			body := fmt.Sprintf("Unknown request %x", request.kind)
+			conn.Write(encodePacket(packet{id: request.id, kind: typeResponse, body: body}))
+		}
+	}
+}
+
+func (s *fakeServer) execute(command string) string {
+	switch {
+	case command == "list":
+		return "There are §a2§r of a max of 20 players online: Alex, Steve"
+	case strings.HasPrefix(command, "repeat "):
+		n, _ := strconv.Atoi(strings.TrimPrefix(command, "repeat "))
+		return strings.Repeat("x", n)
+	}
+	return ""
+}
+
+func TestClientExecute(t *testing.T) {
+	server := startFakeServer(t, "secret")
+
+	client, err := Dial(server.listener.Addr().String(), "secret", 5*time.Second)
+	if err != nil {
+		t.Fatalf("Dial failed: %v", err)
+	}
+	defer client.Close()
+
+	output, err := client.Execute("list")
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if want := "There are 2 of a max of 20 players online: Alex, Steve"; StripFormatting(output) != want {
+		t.Errorf("Expected %q, got %q", want, StripFormatting(output))
+	}
+	if command := <-server.commands; command != "list" {
+		t.Errorf("Expected server to receive list, got %q", command)
+	}
+
+	// Output spread over several packets is joined
+	output, err = client.Execute("repeat 10000")
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if len(output) != 10000 {
+		t.Errorf("Expected 10000 bytes of output, got %d", len(output))
+	}
+
+	// Commands without output do not block on the next one
+	output, err = client.Execute("save-all")
+	if err != nil || output != "" {
+		t.Errorf("Expected empty output, got %q, %v", output, err)
+	}
+	output, err = client.Execute("list")
+	if err != nil || !strings.HasPrefix(output, "There are") {
+		t.Errorf("Expected list output after an empty response, got %q, %v", output, err)
+	}
+
+	if _, err := client.Execute(strings.Repeat("x", MaxCommandLength+1)); err == nil {
+		t.Error("Expected an error for an overlong command")
+	}
+}
+
+func TestClientWrongPassword(t *testing.T) {
+	server := startFakeServer(t, "secret")
+
+	_, err := Dial(server.listener.Addr().String(), "wrong", 5*time.Second)
+	if !errors.Is(err, ErrAuthFailed) {
+		t.Errorf("Expected ErrAuthFailed, got %v", err)
+	}
+}
+
+func TestFreePort(t *testing.T) {
+	listener, err := net.Listen("tcp", ":0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	busy := listener.Addr().(*net.TCPAddr).Port
+
+	// A port in use is skipped, and so are the ports other servers use
+	port, err := FreePort(busy, map[int]bool{busy + 1: true})
+	if err != nil {
+		t.Fatalf("FreePort failed: %v", err)
+	}
+	if port <= busy+1 {
+		t.Errorf("Expected a port above %d, got %d", busy+1, port)
+	}
+}
+
+func TestGeneratePassword(t *testing.T) {
+	first, err := GeneratePassword()
+	if err != nil {
+		t.Fatalf("GeneratePassword failed: %v", err)
+	}
+	second, _ := GeneratePassword()
+	if len(first) != 24 || first == second {
+		t.Errorf("Expected distinct 24 character passwords, got %q and %q", first, second)
+	}
+}
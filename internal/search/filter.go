@@ -0,0 +1,340 @@
+package search
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SortOrder is the order search results are returned in
+type SortOrder string
+
+const (
+	// SortRelevance orders by match score, best first
+	SortRelevance SortOrder = "relevance"
+	// SortName orders alphabetically by name
+	SortName SortOrder = "name"
+	// SortNewest orders by last update, most recent first
+	SortNewest SortOrder = "newest"
+)
+
+// ParseSortOrder parses a --sort value
+func ParseSortOrder(s string) (SortOrder, error) {
+	switch order := SortOrder(strings.ToLower(s)); order {
+	case "":
+		return SortRelevance, nil
+	case SortRelevance, SortName, SortNewest:
+		return order, nil
+	default:
+		return "", fmt.Errorf("invalid sort order %q (must be relevance, name or newest)", s)
+	}
+}
+
+// Filter narrows search results by facet. Zero-valued fields do not filter.
+// A result that does not report a facet, such as a pack without a RAM
+// recommendation, never matches a filter on that facet.
+type Filter struct {
+	Loader string
+	// MCVersion is a version constraint, see ParseMCConstraint
+	MCVersion string
+	// Tags must all be present on a result
+	Tags     []string
+	MaxRAMGB int
+	// Author matches case-insensitively anywhere in the author name
+	Author string
+	Bench  string
+}
+
+// Facets are the filterable attributes of a search result
+type Facets struct {
+	Loaders    []string
+	MCVersions []string
+	Tags       []string
+	RAMGB      int
+	Author     string
+	Bench      string
+}
+
+// IsEmpty reports whether the filter matches everything
+func (f Filter) IsEmpty() bool {
+	return f.Loader == "" && f.MCVersion == "" && len(f.Tags) == 0 && f.MaxRAMGB == 0 && f.Author == "" && f.Bench == ""
+}
+
+// Validate checks the filter values, so a typo in a constraint is reported
+// instead of matching nothing
+func (f Filter) Validate() error {
+	if f.MaxRAMGB < 0 {
+		return fmt.Errorf("max RAM must be positive, got %d", f.MaxRAMGB)
+	}
+	if f.MCVersion != "" {
+		if _, err := ParseMCConstraint(f.MCVersion); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Match reports whether a result with the given facets passes the filter
+func (f Filter) Match(facets Facets) bool {
+	if f.Bench != "" && facets.Bench != f.Bench {
+		return false
+	}
+	if f.Loader != "" && !containsFold(facets.Loaders, f.Loader) {
+		return false
+	}
+	if f.MCVersion != "" {
+		constraint, err := ParseMCConstraint(f.MCVersion)
+		if err != nil || !constraint.MatchesAny(facets.MCVersions) {
+			return false
+		}
+	}
+	for _, tag := range f.Tags {
+		if !containsFold(facets.Tags, tag) {
+			return false
+		}
+	}
+	if f.MaxRAMGB > 0 && (facets.RAMGB <= 0 || facets.RAMGB > f.MaxRAMGB) {
+		return false
+	}
+	if f.Author != "" && !strings.Contains(strings.ToLower(facets.Author), strings.ToLower(f.Author)) {
+		return false
+	}
+	return true
+}
+
+// Facets returns the filterable attributes of a recipe
+func (r *Recipe) Facets() Facets {
+	facets := Facets{
+		Tags:   r.Tags,
+		RAMGB:  r.RecommendedRAMGB,
+		Author: r.Author,
+		Bench:  r.BenchName,
+	}
+	if r.Loader != "" {
+		facets.Loaders = []string{r.Loader}
+	}
+	if r.MCVersion != "" {
+		facets.MCVersions = []string{r.MCVersion}
+	}
+	return facets
+}
+
+// SortResults orders recipe search results. Ties keep their current order.
+func SortResults(results []*SearchResult, order SortOrder) {
+	switch order {
+	case SortName:
+		sort.SliceStable(results, func(i, j int) bool {
+			return strings.ToLower(results[i].Recipe.Name) < strings.ToLower(results[j].Recipe.Name)
+		})
+	case SortNewest:
+		sort.SliceStable(results, func(i, j int) bool {
+			return NewerThan(results[i].Recipe.UpdatedAt, results[j].Recipe.UpdatedAt)
+		})
+	default:
+		sort.SliceStable(results, func(i, j int) bool {
+			return results[i].Score > results[j].Score
+		})
+	}
+}
+
+// NewerThan orders update times for SortNewest. Unknown (zero) times sort last.
+func NewerThan(a, b time.Time) bool {
+	if a.IsZero() || b.IsZero() {
+		return !a.IsZero() && b.IsZero()
+	}
+	return a.After(b)
+}
+
+func containsFold(values []string, want string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, want) {
+			return true
+		}
+	}
+	return false
+}
+
+// MCConstraint is a Minecraft version constraint such as "1.20.1", "1.20.x",
+// ">=1.20" or ">=1.19.2,<1.21". Comma-separated terms must all match.
+type MCConstraint struct {
+	terms []mcTerm
+}
+
+type mcTerm struct {
+	op      string // "=", ">", ">=", "<", "<=" or "x" for a series
+	version mcVersion
+	raw     string
+}
+
+// mcVersion is a parsed release version. Pre-releases and release candidates
+// ("1.20.1-pre1", "1.20.1-rc1") sort before their release.
+type mcVersion struct {
+	parts      []int
+	prerelease bool
+}
+
+// ParseMCConstraint parses a --mc value
+func ParseMCConstraint(s string) (*MCConstraint, error) {
+	var constraint MCConstraint
+	for _, term := range strings.Split(s, ",") {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+
+		op := "="
+		for _, candidate := range []string{">=", "<=", ">", "<", "="} {
+			if strings.HasPrefix(term, candidate) {
+				op = candidate
+				term = strings.TrimSpace(strings.TrimPrefix(term, candidate))
+				break
+			}
+		}
+
+		raw := term
+		if series, ok := cutSeries(term); ok {
+			if op != "=" {
+				return nil, fmt.Errorf("invalid Minecraft version constraint %q: a series like 1.20.x cannot be combined with %s", s, op)
+			}
+			op, term = "x", series
+		}
+
+		version, ok := parseMCVersion(term)
+		if !ok {
+			return nil, fmt.Errorf("invalid Minecraft version constraint %q: %q is not a release version", s, raw)
+		}
+		constraint.terms = append(constraint.terms, mcTerm{op: op, version: version, raw: raw})
+	}
+
+	if len(constraint.terms) == 0 {
+		return nil, fmt.Errorf("empty Minecraft version constraint")
+	}
+	return &constraint, nil
+}
+
+// Matches reports whether version satisfies every term of the constraint.
+// Versions that are not releases, such as snapshots, only match an exact term.
+func (c *MCConstraint) Matches(version string) bool {
+	v, ok := parseMCVersion(version)
+	for _, term := range c.terms {
+		if !ok {
+			if term.op != "=" || term.raw != version {
+				return false
+			}
+			continue
+		}
+
+		cmp := compareMCVersions(v, term.version)
+		var matched bool
+		switch term.op {
+		case "=":
+			matched = cmp == 0
+		case ">":
+			matched = cmp > 0
+		case ">=":
+			matched = cmp >= 0
+		case "<":
+			matched = cmp < 0
+		case "<=":
+			matched = cmp <= 0
+		case "x":
+			matched = inSeries(v, term.version)
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// Exact returns the version if the constraint is a single exact version
+func (c *MCConstraint) Exact() (string, bool) {
+	if len(c.terms) != 1 || c.terms[0].op != "=" {
+		return "", false
+	}
+	return c.terms[0].raw, true
+}
+
+// MatchesAny reports whether any of the versions satisfies the constraint
+func (c *MCConstraint) MatchesAny(versions []string) bool {
+	for _, v := range versions {
+		if c.Matches(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// cutSeries strips a trailing ".x" or ".*"
+func cutSeries(s string) (string, bool) {
+	for _, suffix := range []string{".x", ".X", ".*"} {
+		if series, ok := strings.CutSuffix(s, suffix); ok {
+			return series, true
+		}
+	}
+	return s, false
+}
+
+func parseMCVersion(s string) (mcVersion, bool) {
+	var v mcVersion
+	release, suffix, hasSuffix := strings.Cut(s, "-")
+	if hasSuffix {
+		if suffix == "" {
+			return v, false
+		}
+		v.prerelease = true
+	}
+
+	for _, part := range strings.Split(release, ".") {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return v, false
+		}
+		v.parts = append(v.parts, n)
+	}
+	return v, true
+}
+
+// compareMCVersions compares numerically, treating missing parts as zero so
+// that 1.20 equals 1.20.0
+func compareMCVersions(a, b mcVersion) int {
+	for i := 0; i < len(a.parts) || i < len(b.parts); i++ {
+		var x, y int
+		if i < len(a.parts) {
+			x = a.parts[i]
+		}
+		if i < len(b.parts) {
+			y = b.parts[i]
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	switch {
+	case a.prerelease && !b.prerelease:
+		return -1
+	case !a.prerelease && b.prerelease:
+		return 1
+	}
+	return 0
+}
+
+// inSeries reports whether v starts with every part of series. Missing parts
+// count as zero, so 1.20 is in the 1.20.x series.
+func inSeries(v, series mcVersion) bool {
+	for i, part := range series.parts {
+		var x int
+		if i < len(v.parts) {
+			x = v.parts[i]
+		}
+		if x != part {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,127 @@
+package search
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMCConstraint(t *testing.T) {
+	tests := []struct {
+		constraint string
+		version    string
+		want       bool
+	}{
+		{constraint: "1.20.1", version: "1.20.1", want: true},
+		{constraint: "1.20.1", version: "1.20.2", want: false},
+		{constraint: "1.20", version: "1.20.0", want: true},
+		{constraint: "1.20.x", version: "1.20.4", want: true},
+		{constraint: "1.20.x", version: "1.20", want: true},
+		{constraint: "1.20.x", version: "1.21", want: false},
+		{constraint: ">=1.20", version: "1.20.1", want: true},
+		{constraint: ">=1.20", version: "1.19.4", want: false},
+		{constraint: ">=1.20", version: "1.20-pre1", want: false},
+		{constraint: ">1.20.1", version: "1.20.1", want: false},
+		{constraint: "<=1.18.2", version: "1.18.2", want: true},
+		{constraint: ">=1.19.2, <1.21", version: "1.20.6", want: true},
+		{constraint: ">=1.19.2,<1.21", version: "1.21", want: false},
+		{constraint: ">=1.20", version: "23w14a", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.constraint+" "+tt.version, func(t *testing.T) {
+			c, err := ParseMCConstraint(tt.constraint)
+			if err != nil {
+				t.Fatalf("ParseMCConstraint(%q) failed: %v", tt.constraint, err)
+			}
+			if got := c.Matches(tt.version); got != tt.want {
+				t.Errorf("Matches(%q) = %v, want %v", tt.version, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseMCConstraintInvalid(t *testing.T) {
+	for _, constraint := range []string{"", ",", ">=", "latest", ">=1.20.x", "1.20-"} {
+		if _, err := ParseMCConstraint(constraint); err == nil {
+			t.Errorf("Expected ParseMCConstraint(%q) to fail", constraint)
+		}
+	}
+}
+
+func TestFilterMatch(t *testing.T) {
+	recipe := &Recipe{
+		Name:             "Fabulously Optimized",
+		MCVersion:        "1.20.1",
+		Loader:           "fabric",
+		RecommendedRAMGB: 6,
+		Tags:             []string{"Performance", "vanilla+"},
+		Author:           "Fabulously Optimized Team",
+		BenchName:        "usechunk/recipes",
+	}
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   bool
+	}{
+		{name: "empty", filter: Filter{}, want: true},
+		{name: "loader", filter: Filter{Loader: "Fabric"}, want: true},
+		{name: "other loader", filter: Filter{Loader: "forge"}, want: false},
+		{name: "version range", filter: Filter{MCVersion: ">=1.20"}, want: true},
+		{name: "tags", filter: Filter{Tags: []string{"performance", "vanilla+"}}, want: true},
+		{name: "missing tag", filter: Filter{Tags: []string{"performance", "tech"}}, want: false},
+		{name: "under max ram", filter: Filter{MaxRAMGB: 8}, want: true},
+		{name: "over max ram", filter: Filter{MaxRAMGB: 4}, want: false},
+		{name: "author", filter: Filter{Author: "optimized"}, want: true},
+		{name: "bench", filter: Filter{Bench: "other/bench"}, want: false},
+		{name: "combined", filter: Filter{Loader: "fabric", MCVersion: "1.20.1", MaxRAMGB: 8}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Match(recipe.Facets()); got != tt.want {
+				t.Errorf("Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// A recipe without a RAM recommendation never passes a RAM filter
+	if (Filter{MaxRAMGB: 8}).Match((&Recipe{}).Facets()) {
+		t.Error("Expected a recipe without recommended_ram_gb to fail --max-ram")
+	}
+}
+
+func TestSortResults(t *testing.T) {
+	now := time.Now()
+	results := []*SearchResult{
+		{Recipe: &Recipe{Name: "beta", UpdatedAt: now.Add(-time.Hour)}, Score: 50},
+		{Recipe: &Recipe{Name: "Alpha"}, Score: 10},
+		{Recipe: &Recipe{Name: "gamma", UpdatedAt: now}, Score: 80},
+	}
+
+	names := func() []string {
+		var out []string
+		for _, r := range results {
+			out = append(out, r.Recipe.Name)
+		}
+		return out
+	}
+
+	for _, tt := range []struct {
+		order SortOrder
+		want  []string
+	}{
+		{order: SortRelevance, want: []string{"gamma", "beta", "Alpha"}},
+		{order: SortName, want: []string{"Alpha", "beta", "gamma"}},
+		{order: SortNewest, want: []string{"gamma", "beta", "Alpha"}},
+	} {
+		SortResults(results, tt.order)
+		got := names()
+		for i := range tt.want {
+			if got[i] != tt.want[i] {
+				t.Errorf("SortResults(%s) = %v, want %v", tt.order, got, tt.want)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,275 @@
+package search
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/alexinslc/chunk/internal/bench"
+	"github.com/alexinslc/chunk/internal/config"
+)
+
+// IndexVersion is bumped whenever the index layout or the indexed recipe
+// fields change, so indexes written by older versions are ignored
+const IndexVersion = 4
+
+// gramSize is the length in runes of the substrings the index is keyed by
+const gramSize = 3
+
+// Index is a precomputed search index for one bench. Every match fuzzyMatch
+// can score is a substring match, so recipes are keyed by the trigrams of
+// their searchable fields: a recipe can only match a query if it contains
+// every trigram of the query. Candidates are then scored with matchRecipe,
+// so ranking is identical to a full scan.
+type Index struct {
+	Version int `json:"version"`
+	// Head is the bench commit the index was built from
+	Head     string           `json:"head"`
+	Recipes  []*IndexedEntry  `json:"recipes"`
+	Postings map[string][]int `json:"postings"`
+}
+
+// IndexedEntry is a recipe stored in the index
+type IndexedEntry struct {
+	FilePath  string    `json:"file_path"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Recipe    *Recipe   `json:"recipe"`
+}
+
+// BuildIndex parses every recipe in a bench and writes its search index
+func BuildIndex(b config.Bench) error {
+	head, err := bench.HeadCommit(b.Path)
+	if err != nil {
+		return fmt.Errorf("failed to get HEAD of bench '%s': %w", b.Name, err)
+	}
+
+	recipes, err := LoadRecipesFromBench(b.Path, b.Name)
+	if err != nil {
+		return err
+	}
+	setUpdateTimes(b.Path, recipes)
+
+	index := &Index{
+		Version:  IndexVersion,
+		Head:     head,
+		Recipes:  make([]*IndexedEntry, 0, len(recipes)),
+		Postings: make(map[string][]int),
+	}
+	for id, recipe := range recipes {
+		index.Recipes = append(index.Recipes, &IndexedEntry{
+			FilePath:  recipe.FilePath,
+			UpdatedAt: recipe.UpdatedAt,
+			Recipe:    recipe,
+		})
+		for gram := range recipeGrams(recipe) {
+			index.Postings[gram] = append(index.Postings[gram], id)
+		}
+	}
+
+	path, err := bench.IndexPath(b.Name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create index directory: %w", err)
+	}
+
+	data, err := json.Marshal(index)
+	if err != nil {
+		return fmt.Errorf("failed to encode index: %w", err)
+	}
+
+	// Write to a temporary file first so a concurrent search never reads a
+	// partial index
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write index: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to write index: %w", err)
+	}
+
+	return nil
+}
+
+// EnsureIndex rebuilds the index of a bench if it is missing or stale
+func EnsureIndex(b config.Bench) error {
+	if _, err := LoadIndex(b); err == nil {
+		return nil
+	}
+	return BuildIndex(b)
+}
+
+// LoadIndex reads the index of a bench. It returns an error if the index is
+// missing, was written by another index version, or was built from a
+// different commit than the bench is at.
+func LoadIndex(b config.Bench) (*Index, error) {
+	path, err := bench.IndexPath(b.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read index: %w", err)
+	}
+
+	var index Index
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("failed to parse index: %w", err)
+	}
+	if index.Version != IndexVersion {
+		return nil, fmt.Errorf("index version %d is not supported", index.Version)
+	}
+
+	head, err := bench.HeadCommit(b.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get HEAD of bench '%s': %w", b.Name, err)
+	}
+	if head != index.Head {
+		return nil, fmt.Errorf("index of bench '%s' is stale", b.Name)
+	}
+
+	for _, entry := range index.Recipes {
+		entry.Recipe.FilePath = entry.FilePath
+		entry.Recipe.BenchName = b.Name
+		entry.Recipe.UpdatedAt = entry.UpdatedAt
+	}
+
+	return &index, nil
+}
+
+// Search returns the indexed recipes matching query and filter, scored like
+// a full scan
+func (idx *Index) Search(query string, filter Filter) []*SearchResult {
+	var results []*SearchResult
+	for _, id := range idx.candidates(strings.ToLower(query)) {
+		recipe := idx.Recipes[id].Recipe
+		if !filter.Match(recipe.Facets()) {
+			continue
+		}
+		if result := matchRecipe(recipe, query); result != nil {
+			results = append(results, result)
+		}
+	}
+	return results
+}
+
+// candidates returns the ids of recipes containing every trigram of query,
+// in bench order
+func (idx *Index) candidates(query string) []int {
+	var grams map[string]struct{}
+	if utf8.ValidString(query) {
+		grams = textGrams(query)
+	}
+	if len(grams) == 0 {
+		// Too short to narrow down, check every recipe
+		all := make([]int, len(idx.Recipes))
+		for id := range all {
+			all[id] = id
+		}
+		return all
+	}
+
+	var ids []int
+	first := true
+	for gram := range grams {
+		postings := idx.Postings[gram]
+		if first {
+			ids = append([]int(nil), postings...)
+			first = false
+		} else {
+			ids = intersect(ids, postings)
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+	}
+
+	return ids
+}
+
+// setUpdateTimes sets each recipe's UpdatedAt to the time of the last bench
+// commit touching it, using a single walk of the bench history
+func setUpdateTimes(benchPath string, recipes []*Recipe) {
+	cmd := exec.Command("git", "-C", benchPath, "-c", "core.quotePath=false",
+		"log", "--format=%x00%ct", "--name-only", "--", "Recipes")
+	output, err := cmd.Output()
+	if err != nil {
+		return
+	}
+
+	updated := make(map[string]time.Time)
+	var commitTime time.Time
+	for _, line := range strings.Split(string(output), "\n") {
+		if stamp, ok := strings.CutPrefix(line, "\x00"); ok {
+			seconds, err := strconv.ParseInt(stamp, 10, 64)
+			if err != nil {
+				commitTime = time.Time{}
+				continue
+			}
+			commitTime = time.Unix(seconds, 0)
+			continue
+		}
+		if line == "" || commitTime.IsZero() {
+			continue
+		}
+		// The log is newest first, so the first commit seen for a file wins
+		path := filepath.Join(benchPath, filepath.FromSlash(line))
+		if _, seen := updated[path]; !seen {
+			updated[path] = commitTime
+		}
+	}
+
+	for _, recipe := range recipes {
+		recipe.UpdatedAt = updated[recipe.FilePath]
+	}
+}
+
+// recipeGrams returns the trigrams of every field matchRecipe looks at
+func recipeGrams(recipe *Recipe) map[string]struct{} {
+	fields := append([]string{recipe.Name, recipe.Slug, recipe.Description, recipe.Author}, recipe.Tags...)
+	grams := make(map[string]struct{})
+	for _, field := range fields {
+		for gram := range textGrams(strings.ToLower(field)) {
+			grams[gram] = struct{}{}
+		}
+	}
+	return grams
+}
+
+// textGrams returns the distinct trigrams of text
+func textGrams(text string) map[string]struct{} {
+	runes := []rune(text)
+	grams := make(map[string]struct{})
+	for i := 0; i+gramSize <= len(runes); i++ {
+		grams[string(runes[i:i+gramSize])] = struct{}{}
+	}
+	return grams
+}
+
+// intersect returns the ids present in both sorted lists
+func intersect(a, b []int) []int {
+	var out []int
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] < b[j]:
+			i++
+		case a[i] > b[j]:
+			j++
+		default:
+			out = append(out, a[i])
+			i++
+			j++
+		}
+	}
+	return out
+}
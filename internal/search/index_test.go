@@ -0,0 +1,144 @@
+package search
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/alexinslc/chunk/internal/config"
+)
+
+// setupIndexBench creates a git bench with the given recipe files under a
+// temporary HOME
+func setupIndexBench(t *testing.T, recipes map[string]string) config.Bench {
+	t.Helper()
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+
+	benchPath := filepath.Join(tmpDir, "bench")
+	if err := os.MkdirAll(filepath.Join(benchPath, "Recipes"), 0755); err != nil {
+		t.Fatalf("Failed to create bench: %v", err)
+	}
+	if err := exec.Command("git", "init", benchPath).Run(); err != nil {
+		t.Skipf("Skipping test: git not available: %v", err)
+	}
+	writeRecipes(t, benchPath, recipes)
+	commitBench(t, benchPath)
+
+	return config.Bench{Name: "test/bench", Path: benchPath}
+}
+
+func writeRecipes(t *testing.T, benchPath string, recipes map[string]string) {
+	t.Helper()
+	for name, content := range recipes {
+		if err := os.WriteFile(filepath.Join(benchPath, "Recipes", name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write recipe: %v", err)
+		}
+	}
+}
+
+func commitBench(t *testing.T, benchPath string) {
+	t.Helper()
+	for _, args := range [][]string{
+		{"add", "."},
+		{"-c", "user.email=test@test.com", "-c", "user.name=Test User", "commit", "-m", "Update recipes"},
+	} {
+		if err := exec.Command("git", append([]string{"-C", benchPath}, args...)...).Run(); err != nil {
+			t.Skipf("Skipping test: git %v failed: %v", args, err)
+		}
+	}
+}
+
+func TestIndexMatchesFullScan(t *testing.T) {
+	b := setupIndexBench(t, map[string]string{
+		"all-the-mods-9.json": `{"name": "All The Mods 9", "description": "Kitchen sink pack with 400+ mods", "mc_version": "1.20.1", "loader": "forge", "tags": ["kitchen-sink", "tech"], "author": "ATM Team"}`,
+		"skyblock.yaml":       "name: Fabric Skyblock\ndescription: Survive on a floating island\nmc_version: \"1.20.1\"\nloader: fabric\ntags: [skyblock]\n",
+		"mods-plus.json":      `{"name": "Mods Plus", "mc_version": "1.19.2", "loader": "forge", "author": "Modder"}`,
+		"épique.json":         `{"name": "Épique Aventure", "mc_version": "1.20.1", "loader": "neoforge"}`,
+	})
+
+	if err := BuildIndex(b); err != nil {
+		t.Fatalf("BuildIndex failed: %v", err)
+	}
+	index, err := LoadIndex(b)
+	if err != nil {
+		t.Fatalf("LoadIndex failed: %v", err)
+	}
+
+	recipes, err := LoadRecipesFromBench(b.Path, b.Name)
+	if err != nil {
+		t.Fatalf("LoadRecipesFromBench failed: %v", err)
+	}
+
+	queries := []string{"all the mods", "MODS", "mod", "sky", "a", "kitchen-sink", "épique", "aventure", "forge", "island", ""}
+	for _, query := range queries {
+		t.Run(query, func(t *testing.T) {
+			want := make(map[string]int)
+			for _, recipe := range recipes {
+				if result := matchRecipe(recipe, query); result != nil {
+					want[recipe.Slug] = result.Score
+				}
+			}
+
+			got := make(map[string]int)
+			for _, result := range index.Search(query, Filter{}) {
+				got[result.Recipe.Slug] = result.Score
+				if result.Recipe.BenchName != b.Name || result.Recipe.FilePath == "" {
+					t.Errorf("Indexed recipe %s is missing its bench or file path", result.Recipe.Slug)
+				}
+			}
+
+			if len(got) != len(want) {
+				t.Fatalf("Index returned %v, full scan returned %v", got, want)
+			}
+			for slug, score := range want {
+				if got[slug] != score {
+					t.Errorf("Score of %s: index %d, full scan %d", slug, got[slug], score)
+				}
+			}
+		})
+	}
+}
+
+func TestSearchBenchFallsBackWithoutFreshIndex(t *testing.T) {
+	b := setupIndexBench(t, map[string]string{
+		"skyblock.json": `{"name": "Fabric Skyblock", "mc_version": "1.20.1", "loader": "fabric"}`,
+	})
+
+	// Missing index
+	if _, err := LoadIndex(b); err == nil {
+		t.Fatal("Expected LoadIndex to fail without an index")
+	}
+	if results := searchBench(b, "skyblock", Filter{}); len(results) != 1 {
+		t.Fatalf("Expected full scan to find 1 recipe, got %d", len(results))
+	}
+
+	if err := BuildIndex(b); err != nil {
+		t.Fatalf("BuildIndex failed: %v", err)
+	}
+
+	// A new commit makes the index stale
+	writeRecipes(t, b.Path, map[string]string{
+		"oneblock.json": `{"name": "Fabric Oneblock", "mc_version": "1.20.1", "loader": "fabric"}`,
+	})
+	commitBench(t, b.Path)
+
+	if _, err := LoadIndex(b); err == nil {
+		t.Fatal("Expected LoadIndex to reject an index built from another commit")
+	}
+	if results := searchBench(b, "fabric", Filter{}); len(results) != 2 {
+		t.Errorf("Expected full scan to find 2 recipes, got %d", len(results))
+	}
+
+	if err := EnsureIndex(b); err != nil {
+		t.Fatalf("EnsureIndex failed: %v", err)
+	}
+	index, err := LoadIndex(b)
+	if err != nil {
+		t.Fatalf("Expected rebuilt index to load: %v", err)
+	}
+	if results := index.Search("fabric", Filter{}); len(results) != 2 {
+		t.Errorf("Expected rebuilt index to find 2 recipes, got %d", len(results))
+	}
+}
@@ -6,16 +6,28 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
 
+// Recipe schema versions. Version 1 recipes point at a single pack archive;
+// version 2 adds explicit mod lists, server file overlays and launch settings.
+const (
+	RecipeSchemaV1 = 1
+	RecipeSchemaV2 = 2
+)
+
 // Recipe represents a modpack recipe from a bench
 type Recipe struct {
 	// File metadata
-	FilePath  string `json:"-" yaml:"-"`                           // Not exported in JSON/YAML
-	BenchName string `json:"-" yaml:"-"`                           // Not exported in JSON/YAML
-	Slug      string `json:"slug,omitempty" yaml:"slug,omitempty"` // Recipe filename without extension
+	FilePath  string    `json:"-" yaml:"-"`                           // Not exported in JSON/YAML
+	BenchName string    `json:"-" yaml:"-"`                           // Not exported in JSON/YAML
+	Slug      string    `json:"slug,omitempty" yaml:"slug,omitempty"` // Recipe filename without extension
+	UpdatedAt time.Time `json:"-" yaml:"-"`                           // Last bench commit touching the file, zero if unknown
+
+	// SchemaVersion is RecipeSchemaV1 when omitted
+	SchemaVersion int `json:"schema_version,omitempty" yaml:"schema_version,omitempty"`
 
 	// Recipe metadata
 	Name             string   `json:"name" yaml:"name"`
@@ -34,6 +46,49 @@ type Recipe struct {
 	DownloadURL      string   `json:"download_url,omitempty" yaml:"download_url,omitempty"`
 	DownloadSizeMB   int      `json:"download_size_mb,omitempty" yaml:"download_size_mb,omitempty"`
 	SHA256           string   `json:"sha256,omitempty" yaml:"sha256,omitempty"`
+
+	// Schema v2
+	Files           []RecipeFile      `json:"files,omitempty" yaml:"files,omitempty"`
+	ServerOverrides []RecipeOverride  `json:"server_overrides,omitempty" yaml:"server_overrides,omitempty"`
+	JVMArgs         []string          `json:"jvm_args,omitempty" yaml:"jvm_args,omitempty"`
+	Env             map[string]string `json:"env,omitempty" yaml:"env,omitempty"`
+	MinJava         int               `json:"min_java,omitempty" yaml:"min_java,omitempty"`
+	MaxJava         int               `json:"max_java,omitempty" yaml:"max_java,omitempty"`
+
+	// Variants are named server flavors selectable at install time
+	Variants []RecipeVariant `json:"variants,omitempty" yaml:"variants,omitempty"`
+	// Variant is the variant applied by WithVariant; bench recipes leave it empty
+	Variant string `json:"variant,omitempty" yaml:"variant,omitempty"`
+}
+
+// RecipeFile is a mod jar listed by a v2 recipe, downloaded into mods/
+type RecipeFile struct {
+	Name     string `json:"name,omitempty" yaml:"name,omitempty"`
+	Version  string `json:"version,omitempty" yaml:"version,omitempty"`
+	FileName string `json:"file_name" yaml:"file_name"`
+	URL      string `json:"url" yaml:"url"`
+	SHA256   string `json:"sha256,omitempty" yaml:"sha256,omitempty"`
+	SHA512   string `json:"sha512,omitempty" yaml:"sha512,omitempty"`
+	// Side is client, server or both (the default)
+	Side string `json:"side,omitempty" yaml:"side,omitempty"`
+}
+
+// RecipeOverride is a file a v2 recipe places in the server directory, such
+// as a config file, after the pack is installed
+type RecipeOverride struct {
+	// Path is relative to the server directory, with forward slashes
+	Path   string `json:"path" yaml:"path"`
+	URL    string `json:"url" yaml:"url"`
+	SHA256 string `json:"sha256,omitempty" yaml:"sha256,omitempty"`
+	SHA512 string `json:"sha512,omitempty" yaml:"sha512,omitempty"`
+}
+
+// Schema returns the recipe's schema version
+func (r *Recipe) Schema() int {
+	if r.SchemaVersion == 0 {
+		return RecipeSchemaV1
+	}
+	return r.SchemaVersion
 }
 
 // SearchResult represents a recipe match with relevance score
@@ -1,7 +1,6 @@
 package search
 
 import (
-	"sort"
 	"strings"
 
 	"github.com/alexinslc/chunk/internal/bench"
@@ -22,16 +21,16 @@ func NewSearcher() (*Searcher, error) {
 	return &Searcher{manager: manager}, nil
 }
 
-// Search searches for recipes matching the query across all benches
-// If benchFilter is not empty, only search in that specific bench
-func (s *Searcher) Search(query string, benchFilter string) ([]*SearchResult, error) {
+// Search searches for recipes matching the query and filter across all
+// benches. An empty query matches every recipe that passes the filter.
+func (s *Searcher) Search(query string, filter Filter, order SortOrder) ([]*SearchResult, error) {
 	benches := s.manager.List()
 
 	// Filter to specific bench if requested
-	if benchFilter != "" {
+	if filter.Bench != "" {
 		filtered := []config.Bench{}
 		for _, b := range benches {
-			if b.Name == benchFilter {
+			if b.Name == filter.Bench {
 				filtered = append(filtered, b)
 				break
 			}
@@ -41,32 +40,49 @@ func (s *Searcher) Search(query string, benchFilter string) ([]*SearchResult, er
 
 	var allResults []*SearchResult
 
-	// Load and search recipes from each bench
+	// Search each bench
 	for _, bench := range benches {
-		recipes, err := LoadRecipesFromBench(bench.Path, bench.Name)
-		if err != nil {
-			// Skip benches that fail to load
-			continue
-		}
-
-		for _, recipe := range recipes {
-			if result := matchRecipe(recipe, query); result != nil {
-				allResults = append(allResults, result)
-			}
-		}
+		allResults = append(allResults, searchBench(bench, query, filter)...)
 	}
 
-	// Sort results by score (highest first)
-	sort.Slice(allResults, func(i, j int) bool {
-		return allResults[i].Score > allResults[j].Score
-	})
+	SortResults(allResults, order)
 
 	return allResults, nil
 }
 
-// matchRecipe checks if a recipe matches the query and returns a SearchResult
+// searchBench searches one bench through its index, falling back to parsing
+// every recipe if the index is missing or stale
+func searchBench(bench config.Bench, query string, filter Filter) []*SearchResult {
+	if index, err := LoadIndex(bench); err == nil {
+		return index.Search(query, filter)
+	}
+
+	recipes, err := LoadRecipesFromBench(bench.Path, bench.Name)
+	if err != nil {
+		// Skip benches that fail to load
+		return nil
+	}
+	setUpdateTimes(bench.Path, recipes)
+
+	var results []*SearchResult
+	for _, recipe := range recipes {
+		if !filter.Match(recipe.Facets()) {
+			continue
+		}
+		if result := matchRecipe(recipe, query); result != nil {
+			results = append(results, result)
+		}
+	}
+	return results
+}
+
+// matchRecipe checks if a recipe matches the query and returns a SearchResult.
+// An empty query matches every recipe with a score of 0.
 func matchRecipe(recipe *Recipe, query string) *SearchResult {
 	query = strings.ToLower(query)
+	if query == "" {
+		return &SearchResult{Recipe: recipe}
+	}
 	score := 0
 	matchedField := ""
 
@@ -0,0 +1,117 @@
+package search
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RecipeVariant is a named flavor of a recipe, such as "lite" or
+// "performance". Set fields replace the recipe's; files and server overrides
+// are added to the recipe's, replacing entries with the same file name or path.
+type RecipeVariant struct {
+	Name             string           `json:"name" yaml:"name"`
+	Description      string           `json:"description,omitempty" yaml:"description,omitempty"`
+	DownloadURL      string           `json:"download_url,omitempty" yaml:"download_url,omitempty"`
+	DownloadSizeMB   int              `json:"download_size_mb,omitempty" yaml:"download_size_mb,omitempty"`
+	SHA256           string           `json:"sha256,omitempty" yaml:"sha256,omitempty"`
+	RecommendedRAMGB int              `json:"recommended_ram_gb,omitempty" yaml:"recommended_ram_gb,omitempty"`
+	DiskSpaceGB      int              `json:"disk_space_gb,omitempty" yaml:"disk_space_gb,omitempty"`
+	Files            []RecipeFile     `json:"files,omitempty" yaml:"files,omitempty"`
+	ServerOverrides  []RecipeOverride `json:"server_overrides,omitempty" yaml:"server_overrides,omitempty"`
+}
+
+// FindVariant returns the variant with the given name, matched case-insensitively
+func (r *Recipe) FindVariant(name string) (*RecipeVariant, bool) {
+	for i := range r.Variants {
+		if strings.EqualFold(r.Variants[i].Name, name) {
+			return &r.Variants[i], true
+		}
+	}
+	return nil, false
+}
+
+// VariantNames returns the names of the recipe's variants in recipe order
+func (r *Recipe) VariantNames() []string {
+	names := make([]string, 0, len(r.Variants))
+	for _, variant := range r.Variants {
+		names = append(names, variant.Name)
+	}
+	return names
+}
+
+// WithVariant returns a copy of the recipe with the named variant applied.
+// An empty name returns the recipe itself.
+func (r *Recipe) WithVariant(name string) (*Recipe, error) {
+	if name == "" {
+		return r, nil
+	}
+
+	variant, ok := r.FindVariant(name)
+	if !ok {
+		if len(r.Variants) == 0 {
+			return nil, fmt.Errorf("recipe \"%s\" has no variants", r.Slug)
+		}
+		return nil, fmt.Errorf("recipe \"%s\" has no variant \"%s\" (available: %s)", r.Slug, name, strings.Join(r.VariantNames(), ", "))
+	}
+
+	applied := *r
+	applied.Variant = variant.Name
+	if variant.Description != "" {
+		applied.Description = variant.Description
+	}
+	// A variant archive is a different file, so it never inherits the
+	// recipe's checksum or size
+	if variant.DownloadURL != "" {
+		applied.DownloadURL = variant.DownloadURL
+		applied.SHA256 = variant.SHA256
+		applied.DownloadSizeMB = variant.DownloadSizeMB
+	} else if variant.SHA256 != "" {
+		applied.SHA256 = variant.SHA256
+	}
+	if variant.RecommendedRAMGB > 0 {
+		applied.RecommendedRAMGB = variant.RecommendedRAMGB
+	}
+	if variant.DiskSpaceGB > 0 {
+		applied.DiskSpaceGB = variant.DiskSpaceGB
+	}
+	applied.Files = mergeRecipeFiles(r.Files, variant.Files)
+	applied.ServerOverrides = mergeRecipeOverrides(r.ServerOverrides, variant.ServerOverrides)
+
+	return &applied, nil
+}
+
+func mergeRecipeFiles(base, extra []RecipeFile) []RecipeFile {
+	if len(extra) == 0 {
+		return base
+	}
+	replaced := make(map[string]bool, len(extra))
+	for _, file := range extra {
+		replaced[strings.ToLower(file.FileName)] = true
+	}
+
+	merged := make([]RecipeFile, 0, len(base)+len(extra))
+	for _, file := range base {
+		if !replaced[strings.ToLower(file.FileName)] {
+			merged = append(merged, file)
+		}
+	}
+	return append(merged, extra...)
+}
+
+func mergeRecipeOverrides(base, extra []RecipeOverride) []RecipeOverride {
+	if len(extra) == 0 {
+		return base
+	}
+	replaced := make(map[string]bool, len(extra))
+	for _, override := range extra {
+		replaced[override.Path] = true
+	}
+
+	merged := make([]RecipeOverride, 0, len(base)+len(extra))
+	for _, override := range base {
+		if !replaced[override.Path] {
+			merged = append(merged, override)
+		}
+	}
+	return append(merged, extra...)
+}
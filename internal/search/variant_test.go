@@ -0,0 +1,75 @@
+package search
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWithVariant(t *testing.T) {
+	recipe := &Recipe{
+		Slug:             "atm9",
+		DownloadURL:      "https://example.com/atm9.zip",
+		SHA256:           "full-sum",
+		DownloadSizeMB:   900,
+		RecommendedRAMGB: 10,
+		Files: []RecipeFile{
+			{FileName: "spark.jar", URL: "https://example.com/spark.jar"},
+			{FileName: "chunky.jar", URL: "https://example.com/chunky-1.jar"},
+		},
+		Variants: []RecipeVariant{
+			{
+				Name:             "lite",
+				DownloadURL:      "https://example.com/atm9-lite.zip",
+				SHA256:           "lite-sum",
+				RecommendedRAMGB: 6,
+				Files:            []RecipeFile{{FileName: "chunky.jar", URL: "https://example.com/chunky-2.jar"}},
+			},
+			{
+				Name:  "performance",
+				Files: []RecipeFile{{FileName: "c2me.jar", URL: "https://example.com/c2me.jar"}},
+			},
+		},
+	}
+
+	lite, err := recipe.WithVariant("Lite")
+	if err != nil {
+		t.Fatalf("WithVariant failed: %v", err)
+	}
+	if lite.Variant != "lite" || lite.DownloadURL != "https://example.com/atm9-lite.zip" || lite.SHA256 != "lite-sum" {
+		t.Errorf("Expected the lite archive, got %s (%s, variant %q)", lite.DownloadURL, lite.SHA256, lite.Variant)
+	}
+	if lite.DownloadSizeMB != 0 {
+		t.Errorf("Expected the full archive size not to carry over, got %d", lite.DownloadSizeMB)
+	}
+	if lite.RecommendedRAMGB != 6 {
+		t.Errorf("Expected 6 GB RAM, got %d", lite.RecommendedRAMGB)
+	}
+	if len(lite.Files) != 2 || lite.Files[1].URL != "https://example.com/chunky-2.jar" {
+		t.Errorf("Expected the variant's chunky.jar to replace the recipe's, got %+v", lite.Files)
+	}
+
+	// The recipe itself is unchanged
+	if recipe.DownloadURL != "https://example.com/atm9.zip" || len(recipe.Files) != 2 || recipe.Variant != "" {
+		t.Error("WithVariant modified the recipe")
+	}
+
+	performance, err := recipe.WithVariant("performance")
+	if err != nil {
+		t.Fatalf("WithVariant failed: %v", err)
+	}
+	if performance.DownloadURL != recipe.DownloadURL || performance.SHA256 != "full-sum" || performance.RecommendedRAMGB != 10 {
+		t.Errorf("Expected unset fields to keep the recipe's values, got %+v", performance)
+	}
+	if len(performance.Files) != 3 {
+		t.Errorf("Expected 3 files, got %d", len(performance.Files))
+	}
+
+	if same, err := recipe.WithVariant(""); err != nil || same != recipe {
+		t.Errorf("Expected an empty variant to return the recipe, got %v", err)
+	}
+
+	_, err = recipe.WithVariant("tiny")
+	if err == nil || !strings.Contains(err.Error(), "lite, performance") {
+		t.Errorf("Expected an error listing the variants, got %v", err)
+	}
+}
@@ -0,0 +1,68 @@
+package sources
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// findZipFile returns the archive entry with the given name, or nil
+func findZipFile(reader *zip.ReadCloser, name string) *zip.File {
+	for _, file := range reader.File {
+		if file.Name == name {
+			return file
+		}
+	}
+	return nil
+}
+
+// safeJoin joins an archive entry name onto destDir, rejecting entries that
+// would escape it (zip-slip)
+func safeJoin(destDir, name string) (string, error) {
+	path := filepath.Join(destDir, filepath.FromSlash(name))
+	rel, err := filepath.Rel(destDir, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("illegal path in archive: %s", name)
+	}
+	return path, nil
+}
+
+// extractZipEntry writes a single archive entry to destPath
+func extractZipEntry(file *zip.File, destPath string) error {
+	if file.FileInfo().IsDir() {
+		return os.MkdirAll(destPath, 0755)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(destPath), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	mode := file.Mode().Perm()
+	if mode == 0 {
+		mode = 0644
+	}
+
+	outFile, err := os.OpenFile(destPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+
+	rc, err := file.Open()
+	if err != nil {
+		outFile.Close()
+		return fmt.Errorf("failed to open file in archive: %w", err)
+	}
+
+	_, err = io.Copy(outFile, rc)
+	rc.Close()
+	outFile.Close()
+
+	if err != nil {
+		return fmt.Errorf("failed to extract file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,458 @@
+package sources
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/alexinslc/chunk/internal/checksum"
+	"github.com/alexinslc/chunk/internal/search"
+	"github.com/alexinslc/chunk/internal/validation"
+)
+
+const (
+	// BundleExtension is the file extension of server bundles
+	BundleExtension = ".chunkbundle"
+	// BundleFormatVersion is the bundle layout this version of chunk writes and reads
+	BundleFormatVersion = 1
+
+	// BundleManifestFile describes the bundle and checksums every other entry
+	BundleManifestFile = "bundle.json"
+	// BundleRecipeFile holds the recipe snapshot the bundle was exported from
+	BundleRecipeFile = "recipe.json"
+
+	// Bundle entries are grouped by what they install
+	BundlePackDir   = "pack/"   // The pack archive, extracted over the server root
+	BundleLoaderDir = "loader/" // The loader installer or server jar
+	BundleServerDir = "server/" // Files the loader installer produced and recipe server files, copied to the server root
+	BundleModsDir   = "mods/"   // Server-side mod jars
+)
+
+// ErrInvalidBundle is returned for bundles that are malformed or fail verification
+var ErrInvalidBundle = errors.New("invalid bundle")
+
+// BundleManifest is bundle.json. Files is the checksum manifest: it lists
+// every entry of the bundle except bundle.json itself.
+type BundleManifest struct {
+	FormatVersion  int        `json:"format_version"`
+	Name           string     `json:"name"`
+	Identifier     string     `json:"identifier"`
+	Description    string     `json:"description,omitempty"`
+	Author         string     `json:"author,omitempty"`
+	Source         string     `json:"source,omitempty"`
+	MCVersion      string     `json:"mc_version"`
+	Loader         LoaderType `json:"loader"`
+	LoaderVersion  string     `json:"loader_version"`
+	RecommendedRAM int        `json:"recommended_ram,omitempty"`
+	Variant        string     `json:"variant,omitempty"`
+	// JVMArgs, Env, MinJava and MaxJava are the recipe's launch settings
+	JVMArgs    []string          `json:"jvm_args,omitempty"`
+	Env        map[string]string `json:"env,omitempty"`
+	MinJava    int               `json:"min_java,omitempty"`
+	MaxJava    int               `json:"max_java,omitempty"`
+	CreatedAt  time.Time         `json:"created_at"`
+	Pack       *BundleEntry      `json:"pack,omitempty"`
+	LoaderFile BundleEntry       `json:"loader_file"`
+	Mods       []BundleMod       `json:"mods"`
+	// ServerFiles are the recipe's server files, under server/. They are
+	// placed again once the server is configured, so they replace generated
+	// files as they do in a recipe install.
+	ServerFiles []BundleEntry `json:"server_files,omitempty"`
+	Files       []BundleFile  `json:"files"`
+}
+
+// BundleEntry names a bundled artifact and the URL it was originally downloaded from
+type BundleEntry struct {
+	Path string `json:"path"`
+	URL  string `json:"url,omitempty"`
+}
+
+// BundleMod is a bundled server mod. FileName is relative to mods/, as in Mod.
+type BundleMod struct {
+	BundleEntry
+	FileName string  `json:"filename"`
+	Name     string  `json:"name,omitempty"`
+	Version  string  `json:"version,omitempty"`
+	Side     ModSide `json:"side,omitempty"`
+}
+
+// BundleFile is the checksum of one bundle entry
+type BundleFile struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+	SHA512 string `json:"sha512"`
+}
+
+// BundleSource is a local file written into a bundle under Path
+type BundleSource struct {
+	Path     string
+	FilePath string
+}
+
+// IsBundle reports whether a path names a server bundle
+func IsBundle(path string) bool {
+	return strings.HasSuffix(strings.ToLower(path), BundleExtension)
+}
+
+// WriteBundle writes a bundle to destPath. The checksum manifest is computed
+// from the files, and the bundle is written to a temporary file first so an
+// interrupted export never leaves a truncated bundle behind.
+func WriteBundle(destPath string, manifest *BundleManifest, recipe map[string]interface{}, files []BundleSource) error {
+	recipeData, err := json.MarshalIndent(recipe, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal recipe snapshot: %w", err)
+	}
+
+	sort.Slice(files, func(a, b int) bool { return files[a].Path < files[b].Path })
+
+	manifest.FormatVersion = BundleFormatVersion
+	manifest.Files = []BundleFile{bundleFile(BundleRecipeFile, recipeData)}
+	for _, file := range files {
+		sums, err := checksum.CalculateFile(file.FilePath)
+		if err != nil {
+			return err
+		}
+		info, err := os.Stat(file.FilePath)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", file.FilePath, err)
+		}
+		manifest.Files = append(manifest.Files, BundleFile{
+			Path:   file.Path,
+			Size:   info.Size(),
+			SHA256: sums.SHA256,
+			SHA512: sums.SHA512,
+		})
+	}
+
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal bundle manifest: %w", err)
+	}
+
+	tmpPath := destPath + ".tmp"
+	out, err := os.Create(tmpPath)
+	if err != nil {
+		return fmt.Errorf("failed to create bundle: %w", err)
+	}
+	defer os.Remove(tmpPath)
+
+	writer := zip.NewWriter(out)
+	err = writeBundleEntries(writer, manifestData, recipeData, files)
+	if closeErr := writer.Close(); err == nil {
+		err = closeErr
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, destPath); err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+	return nil
+}
+
+func writeBundleEntries(writer *zip.Writer, manifestData, recipeData []byte, files []BundleSource) error {
+	// bundle.json goes first so it can be read without scanning the archive
+	for _, entry := range []struct {
+		name string
+		data []byte
+	}{{BundleManifestFile, manifestData}, {BundleRecipeFile, recipeData}} {
+		w, err := writer.Create(entry.name)
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(entry.data); err != nil {
+			return err
+		}
+	}
+
+	for _, file := range files {
+		if err := writeBundleFile(writer, file); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeBundleFile(writer *zip.Writer, file BundleSource) error {
+	in, err := os.Open(file.FilePath)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+
+	header, err := zip.FileInfoHeader(info)
+	if err != nil {
+		return err
+	}
+	header.Name = file.Path
+	// Jars and archives are already compressed
+	header.Method = zip.Store
+	if !strings.HasSuffix(file.Path, ".jar") && !strings.HasSuffix(file.Path, ".zip") && !strings.HasSuffix(file.Path, ".mrpack") {
+		header.Method = zip.Deflate
+	}
+
+	entry, err := writer.CreateHeader(header)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(entry, in)
+	return err
+}
+
+func bundleFile(name string, data []byte) BundleFile {
+	sums, _ := checksum.Calculate(bytes.NewReader(data))
+	return BundleFile{Path: name, Size: int64(len(data)), SHA256: sums.SHA256, SHA512: sums.SHA512}
+}
+
+// Bundle is an open server bundle
+type Bundle struct {
+	Path     string
+	Manifest *BundleManifest
+	reader   *zip.ReadCloser
+}
+
+// OpenBundle opens a bundle and reads its manifest
+func OpenBundle(path string) (*Bundle, error) {
+	reader, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrInvalidBundle, path, err)
+	}
+
+	manifest, err := readBundleManifest(reader)
+	if err != nil {
+		reader.Close()
+		return nil, err
+	}
+
+	return &Bundle{Path: path, Manifest: manifest, reader: reader}, nil
+}
+
+func readBundleManifest(reader *zip.ReadCloser) (*BundleManifest, error) {
+	file := findZipFile(reader, BundleManifestFile)
+	if file == nil {
+		return nil, fmt.Errorf("%w: %s not found", ErrInvalidBundle, BundleManifestFile)
+	}
+
+	rc, err := file.Open()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidBundle, err)
+	}
+	defer rc.Close()
+
+	var manifest BundleManifest
+	if err := json.NewDecoder(rc).Decode(&manifest); err != nil {
+		return nil, fmt.Errorf("%w: failed to parse %s: %v", ErrInvalidBundle, BundleManifestFile, err)
+	}
+
+	if manifest.FormatVersion != BundleFormatVersion {
+		return nil, fmt.Errorf("%w: unsupported bundle format %d", ErrInvalidBundle, manifest.FormatVersion)
+	}
+	if manifest.MCVersion == "" || manifest.Loader == "" || manifest.LoaderFile.Path == "" {
+		return nil, fmt.Errorf("%w: %s is missing the minecraft version or loader", ErrInvalidBundle, BundleManifestFile)
+	}
+	for name := range manifest.Env {
+		if !validation.IsValidEnvName(name) {
+			return nil, fmt.Errorf("%w: invalid environment variable name %q in %s", ErrInvalidBundle, name, BundleManifestFile)
+		}
+	}
+
+	return &manifest, nil
+}
+
+// Close closes the bundle file
+func (b *Bundle) Close() error {
+	return b.reader.Close()
+}
+
+// Verify checks every entry against the checksum manifest. Entries missing
+// from the manifest, and manifest files missing from the bundle, are errors.
+func (b *Bundle) Verify() error {
+	listed := make(map[string]BundleFile, len(b.Manifest.Files))
+	for _, file := range b.Manifest.Files {
+		listed[file.Path] = file
+	}
+
+	for _, file := range b.reader.File {
+		if file.Name == BundleManifestFile || file.FileInfo().IsDir() {
+			continue
+		}
+
+		expected, ok := listed[file.Name]
+		if !ok {
+			return fmt.Errorf("%w: %s is not in the checksum manifest", ErrInvalidBundle, file.Name)
+		}
+		delete(listed, file.Name)
+
+		if err := verifyBundleEntry(file, expected); err != nil {
+			return err
+		}
+	}
+
+	for name := range listed {
+		return fmt.Errorf("%w: %s is missing", ErrInvalidBundle, name)
+	}
+	return nil
+}
+
+func verifyBundleEntry(file *zip.File, expected BundleFile) error {
+	if int64(file.UncompressedSize64) != expected.Size {
+		return fmt.Errorf("%w: %s: expected %d bytes, got %d", ErrInvalidBundle, file.Name, expected.Size, file.UncompressedSize64)
+	}
+
+	rc, err := file.Open()
+	if err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrInvalidBundle, file.Name, err)
+	}
+	defer rc.Close()
+
+	reader, result := checksum.VerifyReader(rc, &checksum.Checksums{SHA256: expected.SHA256, SHA512: expected.SHA512})
+	if _, err := io.Copy(io.Discard, reader); err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrInvalidBundle, file.Name, err)
+	}
+	if err := result.Verify(file.Name); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidBundle, err)
+	}
+	return nil
+}
+
+// Checksums returns the recorded checksums of an entry
+func (b *Bundle) Checksums(name string) *checksum.Checksums {
+	for _, file := range b.Manifest.Files {
+		if file.Path == name {
+			return &checksum.Checksums{SHA256: file.SHA256, SHA512: file.SHA512}
+		}
+	}
+	return nil
+}
+
+// ExtractFile writes a single bundle entry to destPath
+func (b *Bundle) ExtractFile(name, destPath string) error {
+	file := findZipFile(b.reader, name)
+	if file == nil {
+		return fmt.Errorf("%w: %s not found", ErrInvalidBundle, name)
+	}
+	return extractZipEntry(file, destPath)
+}
+
+// ExtractDir writes every entry under prefix into destDir, relative to prefix
+func (b *Bundle) ExtractDir(prefix, destDir string) error {
+	for _, file := range b.reader.File {
+		if !strings.HasPrefix(file.Name, prefix) || file.Name == prefix {
+			continue
+		}
+
+		destPath, err := safeJoin(destDir, strings.TrimPrefix(file.Name, prefix))
+		if err != nil {
+			return err
+		}
+		if err := extractZipEntry(file, destPath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Modpack describes the bundled server. Mods carry their bundled checksums
+// and the URLs they were originally downloaded from.
+func (b *Bundle) Modpack() *Modpack {
+	manifest := b.Manifest
+	modpack := &Modpack{
+		Name:           manifest.Name,
+		Identifier:     manifest.Identifier,
+		Description:    manifest.Description,
+		MCVersion:      manifest.MCVersion,
+		Loader:         manifest.Loader,
+		LoaderVersion:  manifest.LoaderVersion,
+		Author:         manifest.Author,
+		Source:         "bundle",
+		RecommendedRAM: manifest.RecommendedRAM,
+		JVMArgs:        manifest.JVMArgs,
+		Env:            manifest.Env,
+		MinJava:        manifest.MinJava,
+		MaxJava:        manifest.MaxJava,
+		Variant:        manifest.Variant,
+	}
+	if manifest.Pack != nil {
+		modpack.ManifestURL = manifest.Pack.URL
+	}
+
+	for _, mod := range manifest.Mods {
+		sums := b.Checksums(mod.Path)
+		if sums == nil {
+			sums = &checksum.Checksums{}
+		}
+		modpack.Mods = append(modpack.Mods, &Mod{
+			Name:        mod.Name,
+			Version:     mod.Version,
+			FileName:    mod.FileName,
+			DownloadURL: mod.URL,
+			Side:        mod.Side,
+			Required:    true,
+			SHA256:      sums.SHA256,
+			SHA512:      sums.SHA512,
+		})
+	}
+
+	for _, file := range manifest.ServerFiles {
+		sums := b.Checksums(file.Path)
+		if sums == nil {
+			sums = &checksum.Checksums{}
+		}
+		modpack.ServerFiles = append(modpack.ServerFiles, &ServerFile{
+			Path:        strings.TrimPrefix(file.Path, BundleServerDir),
+			DownloadURL: file.URL,
+			SHA256:      sums.SHA256,
+			SHA512:      sums.SHA512,
+		})
+	}
+
+	return modpack
+}
+
+// BundleClient installs servers from bundles written by chunk bundle export.
+// It never touches the network.
+type BundleClient struct{}
+
+func NewBundleClient() *BundleClient {
+	return &BundleClient{}
+}
+
+func (c *BundleClient) Fetch(ctx context.Context, identifier string) (*Modpack, error) {
+	if !fileExists(identifier) {
+		return nil, fmt.Errorf("file not found: %s", identifier)
+	}
+
+	bundle, err := OpenBundle(identifier)
+	if err != nil {
+		return nil, err
+	}
+	defer bundle.Close()
+
+	return bundle.Modpack(), nil
+}
+
+func (c *BundleClient) Search(ctx context.Context, query string, filter search.Filter) ([]*ModpackSearchResult, error) {
+	return nil, fmt.Errorf("search not supported for bundles")
+}
+
+func (c *BundleClient) GetVersions(ctx context.Context, identifier string) ([]*Version, error) {
+	return nil, fmt.Errorf("version lookup not supported for bundles")
+}
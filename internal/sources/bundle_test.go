@@ -0,0 +1,219 @@
+package sources
+
+import (
+	"archive/zip"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeTestBundle writes a Fabric bundle with one mod and one loader file
+func writeTestBundle(t *testing.T, dir string) string {
+	t.Helper()
+
+	files := map[string]string{
+		"fabric-server-launch.jar": "launcher",
+		"jei.jar":                  "jei",
+		"server.properties":        "motd=bundled",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	manifest := &BundleManifest{
+		Name:          "Test Pack",
+		Identifier:    "test-pack",
+		MCVersion:     "1.20.1",
+		Loader:        LoaderFabric,
+		LoaderVersion: "0.15.0",
+		LoaderFile:    BundleEntry{Path: BundleLoaderDir + "fabric-server-launch.jar", URL: "https://example.com/fabric.jar"},
+		Mods: []BundleMod{{
+			BundleEntry: BundleEntry{Path: BundleModsDir + "jei.jar", URL: "https://example.com/jei.jar"},
+			FileName:    "jei.jar",
+			Name:        "JEI",
+			Side:        SideBoth,
+		}},
+		Env:         map[string]string{"TZ": "UTC"},
+		MaxJava:     21,
+		Variant:     "lite",
+		ServerFiles: []BundleEntry{{Path: BundleServerDir + "server.properties", URL: "https://example.com/server.properties"}},
+	}
+
+	bundlePath := filepath.Join(dir, "test"+BundleExtension)
+	err := WriteBundle(bundlePath, manifest, map[string]interface{}{"name": "Test Pack"}, []BundleSource{
+		{Path: BundleLoaderDir + "fabric-server-launch.jar", FilePath: filepath.Join(dir, "fabric-server-launch.jar")},
+		{Path: BundleModsDir + "jei.jar", FilePath: filepath.Join(dir, "jei.jar")},
+		{Path: BundleServerDir + "server.properties", FilePath: filepath.Join(dir, "server.properties")},
+	})
+	if err != nil {
+		t.Fatalf("WriteBundle failed: %v", err)
+	}
+	return bundlePath
+}
+
+func TestBundleRoundTrip(t *testing.T) {
+	tmpDir := t.TempDir()
+	bundlePath := writeTestBundle(t, tmpDir)
+
+	if DetectSource(bundlePath) != "bundle" {
+		t.Errorf("DetectSource(%q) = %q, want bundle", bundlePath, DetectSource(bundlePath))
+	}
+
+	bundle, err := OpenBundle(bundlePath)
+	if err != nil {
+		t.Fatalf("OpenBundle failed: %v", err)
+	}
+	defer bundle.Close()
+
+	if err := bundle.Verify(); err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if len(bundle.Manifest.Files) != 4 {
+		t.Errorf("Expected 4 checksummed files (including %s), got %d", BundleRecipeFile, len(bundle.Manifest.Files))
+	}
+
+	modpack := bundle.Modpack()
+	if modpack.Source != "bundle" || modpack.LoaderVersion != "0.15.0" {
+		t.Errorf("Unexpected modpack: %+v", modpack)
+	}
+	if len(modpack.Mods) != 1 || modpack.Mods[0].FileName != "jei.jar" || modpack.Mods[0].SHA512 == "" {
+		t.Errorf("Expected bundled mod with checksums, got %+v", modpack.Mods)
+	}
+	if len(modpack.ServerFiles) != 1 || modpack.ServerFiles[0].Path != "server.properties" || modpack.ServerFiles[0].SHA512 == "" {
+		t.Errorf("Expected bundled server file with checksums, got %+v", modpack.ServerFiles)
+	}
+	if modpack.Variant != "lite" {
+		t.Errorf("Expected bundled variant lite, got %q", modpack.Variant)
+	}
+	if modpack.Env["TZ"] != "UTC" || modpack.MaxJava != 21 {
+		t.Errorf("Expected bundled launch settings, got env %v and max java %d", modpack.Env, modpack.MaxJava)
+	}
+
+	destDir := filepath.Join(tmpDir, "server")
+	if err := bundle.ExtractDir(BundleServerDir, destDir); err != nil {
+		t.Fatalf("ExtractDir failed: %v", err)
+	}
+	if data, err := os.ReadFile(filepath.Join(destDir, "server.properties")); err != nil || string(data) != "motd=bundled" {
+		t.Errorf("Expected server.properties in server root, got %q (%v)", data, err)
+	}
+}
+
+func TestBundleVerifyDetectsTampering(t *testing.T) {
+	tmpDir := t.TempDir()
+	bundlePath := writeTestBundle(t, tmpDir)
+
+	tests := []struct {
+		name   string
+		modify func(name string) (string, bool) // Returns new content, or false to drop the entry
+		extra  bool
+	}{
+		{
+			name: "modified mod",
+			modify: func(name string) (string, bool) {
+				if name == BundleModsDir+"jei.jar" {
+					return "evil", true
+				}
+				return "", true
+			},
+		},
+		{
+			name: "missing mod",
+			modify: func(name string) (string, bool) {
+				return "", name != BundleModsDir+"jei.jar"
+			},
+		},
+		{
+			name:   "unlisted entry",
+			modify: func(name string) (string, bool) { return "", true },
+			extra:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tamperedPath := filepath.Join(t.TempDir(), "tampered"+BundleExtension)
+			rewriteZip(t, bundlePath, tamperedPath, tt.modify, tt.extra)
+
+			bundle, err := OpenBundle(tamperedPath)
+			if err != nil {
+				t.Fatalf("OpenBundle failed: %v", err)
+			}
+			defer bundle.Close()
+
+			if err := bundle.Verify(); !errors.Is(err, ErrInvalidBundle) {
+				t.Errorf("Expected ErrInvalidBundle, got %v", err)
+			}
+		})
+	}
+}
+
+func TestOpenBundleInvalid(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "empty"+BundleExtension)
+
+	out, _ := os.Create(path)
+	writer := zip.NewWriter(out)
+	writer.Close()
+	out.Close()
+
+	if _, err := OpenBundle(path); !errors.Is(err, ErrInvalidBundle) {
+		t.Errorf("Expected ErrInvalidBundle without %s, got %v", BundleManifestFile, err)
+	}
+
+	// Env names end up in the start scripts
+	path = filepath.Join(tmpDir, "env"+BundleExtension)
+	manifest := &BundleManifest{
+		MCVersion:  "1.20.1",
+		Loader:     LoaderFabric,
+		LoaderFile: BundleEntry{Path: BundleLoaderDir + "fabric-server-launch.jar"},
+		Env:        map[string]string{"TZ=UTC; rm -rf ~": "x"},
+	}
+	if err := WriteBundle(path, manifest, nil, nil); err != nil {
+		t.Fatalf("WriteBundle failed: %v", err)
+	}
+	if _, err := OpenBundle(path); !errors.Is(err, ErrInvalidBundle) {
+		t.Errorf("Expected ErrInvalidBundle for an invalid env name, got %v", err)
+	}
+}
+
+// rewriteZip copies a zip, letting modify replace or drop entries
+func rewriteZip(t *testing.T, src, dst string, modify func(name string) (string, bool), extra bool) {
+	t.Helper()
+
+	reader, err := zip.OpenReader(src)
+	if err != nil {
+		t.Fatalf("Failed to open %s: %v", src, err)
+	}
+	defer reader.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		t.Fatalf("Failed to create %s: %v", dst, err)
+	}
+	defer out.Close()
+	writer := zip.NewWriter(out)
+	defer writer.Close()
+
+	for _, file := range reader.File {
+		content, keep := modify(file.Name)
+		if !keep {
+			continue
+		}
+		if content == "" {
+			if err := writer.Copy(file); err != nil {
+				t.Fatalf("Failed to copy %s: %v", file.Name, err)
+			}
+			continue
+		}
+		w, _ := writer.Create(file.Name)
+		w.Write([]byte(content))
+	}
+
+	if extra {
+		w, _ := writer.Create(BundleModsDir + "extra.jar")
+		w.Write([]byte("extra"))
+	}
+}
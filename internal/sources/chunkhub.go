@@ -1,12 +1,17 @@
 package sources
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"strings"
 	"time"
+
+	"github.com/alexinslc/chunk/internal/mirror"
+	"github.com/alexinslc/chunk/internal/search"
 )
 
 const (
@@ -27,7 +32,8 @@ func NewChunkHubClient(baseURL string) *ChunkHubClient {
 	return &ChunkHubClient{
 		baseURL: baseURL,
 		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout:   30 * time.Second,
+			Transport: &mirror.Transport{},
 		},
 	}
 }
@@ -36,10 +42,10 @@ func (c *ChunkHubClient) SetAPIKey(apiKey string) {
 	c.apiKey = apiKey
 }
 
-func (c *ChunkHubClient) Fetch(identifier string) (*Modpack, error) {
+func (c *ChunkHubClient) Fetch(ctx context.Context, identifier string) (*Modpack, error) {
 	endpoint := fmt.Sprintf("%s/v1/modpacks/%s", c.baseURL, url.PathEscape(identifier))
 
-	resp, err := c.get(endpoint)
+	resp, err := c.get(ctx, endpoint)
 	if err != nil {
 		return nil, err
 	}
@@ -69,10 +75,21 @@ func (c *ChunkHubClient) Fetch(identifier string) (*Modpack, error) {
 	return result.Data, nil
 }
 
-func (c *ChunkHubClient) Search(query string) ([]*ModpackSearchResult, error) {
-	endpoint := fmt.Sprintf("%s/v1/modpacks/search?q=%s", c.baseURL, url.QueryEscape(query))
+func (c *ChunkHubClient) Search(ctx context.Context, query string, filter search.Filter) ([]*ModpackSearchResult, error) {
+	// The API filters by loader and exact Minecraft version; other facets
+	// are filtered after the search
+	params := url.Values{"q": {query}}
+	if filter.Loader != "" {
+		params.Set("loader", strings.ToLower(filter.Loader))
+	}
+	if constraint, err := search.ParseMCConstraint(filter.MCVersion); err == nil {
+		if version, ok := constraint.Exact(); ok {
+			params.Set("mc_version", version)
+		}
+	}
+	endpoint := fmt.Sprintf("%s/v1/modpacks/search?%s", c.baseURL, params.Encode())
 
-	resp, err := c.get(endpoint)
+	resp, err := c.get(ctx, endpoint)
 	if err != nil {
 		return nil, err
 	}
@@ -94,13 +111,13 @@ func (c *ChunkHubClient) Search(query string) ([]*ModpackSearchResult, error) {
 		item.Source = "chunkhub"
 	}
 
-	return result.Data, nil
+	return FilterResults(result.Data, filter), nil
 }
 
-func (c *ChunkHubClient) GetVersions(identifier string) ([]*Version, error) {
+func (c *ChunkHubClient) GetVersions(ctx context.Context, identifier string) ([]*Version, error) {
 	endpoint := fmt.Sprintf("%s/v1/modpacks/%s/versions", c.baseURL, url.PathEscape(identifier))
 
-	resp, err := c.get(endpoint)
+	resp, err := c.get(ctx, endpoint)
 	if err != nil {
 		return nil, err
 	}
@@ -125,8 +142,8 @@ func (c *ChunkHubClient) GetVersions(identifier string) ([]*Version, error) {
 	return result.Data, nil
 }
 
-func (c *ChunkHubClient) get(endpoint string) (*http.Response, error) {
-	req, err := http.NewRequest("GET", endpoint, nil)
+func (c *ChunkHubClient) get(ctx context.Context, endpoint string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -141,8 +158,13 @@ func (c *ChunkHubClient) get(endpoint string) (*http.Response, error) {
 	return c.httpClient.Do(req)
 }
 
-func (c *ChunkHubClient) DownloadFile(fileURL string, dest io.Writer) error {
-	resp, err := c.httpClient.Get(fileURL)
+func (c *ChunkHubClient) DownloadFile(ctx context.Context, fileURL string, dest io.Writer) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", fileURL, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return err
 	}
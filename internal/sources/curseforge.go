@@ -0,0 +1,359 @@
+package sources
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alexinslc/chunk/internal/mirror"
+)
+
+const (
+	CurseForgeAPIURL = "https://api.curseforge.com"
+	// CurseForgeCDNURL serves files whose authors disabled third-party distribution
+	CurseForgeCDNURL = "https://edge.forgecdn.net/files"
+
+	curseForgeManifestFile = "manifest.json"
+	// curseForgeHashSHA1 is the algo of SHA-1 entries in a file's hashes
+	curseForgeHashSHA1 = 1
+)
+
+// CurseForgeManifest is the manifest.json of a CurseForge modpack export
+type CurseForgeManifest struct {
+	Minecraft struct {
+		Version    string `json:"version"`
+		ModLoaders []struct {
+			ID      string `json:"id"`
+			Primary bool   `json:"primary"`
+		} `json:"modLoaders"`
+	} `json:"minecraft"`
+	ManifestType    string `json:"manifestType"`
+	ManifestVersion int    `json:"manifestVersion"`
+	Name            string `json:"name"`
+	Version         string `json:"version"`
+	Author          string `json:"author"`
+	Files           []struct {
+		ProjectID int  `json:"projectID"`
+		FileID    int  `json:"fileID"`
+		Required  bool `json:"required"`
+	} `json:"files"`
+	Overrides string `json:"overrides"`
+}
+
+// CurseForgeFile is a resolved CurseForge project file. SHA1 is the strongest
+// hash CurseForge publishes.
+type CurseForgeFile struct {
+	FileName    string
+	DownloadURL string
+	SHA1        string
+}
+
+// CurseForgeFileResolver resolves a manifest file entry to a downloadable file.
+// The default implementation queries the CurseForge API; tests can substitute a stand-in.
+type CurseForgeFileResolver interface {
+	ResolveFile(ctx context.Context, projectID, fileID int) (*CurseForgeFile, error)
+}
+
+// CurseForgeAPIResolver resolves files through the CurseForge REST API
+type CurseForgeAPIResolver struct {
+	BaseURL    string
+	APIKey     string
+	httpClient *http.Client
+}
+
+// NewCurseForgeAPIResolver creates a resolver for the public CurseForge API
+func NewCurseForgeAPIResolver(apiKey string) *CurseForgeAPIResolver {
+	return &CurseForgeAPIResolver{
+		BaseURL: CurseForgeAPIURL,
+		APIKey:  apiKey,
+		httpClient: &http.Client{
+			Timeout:   30 * time.Second,
+			Transport: &mirror.Transport{},
+		},
+	}
+}
+
+// ResolveFile looks up a project file and returns its download location
+func (r *CurseForgeAPIResolver) ResolveFile(ctx context.Context, projectID, fileID int) (*CurseForgeFile, error) {
+	fileURL := fmt.Sprintf("%s/v1/mods/%d/files/%d", strings.TrimSuffix(r.BaseURL, "/"), projectID, fileID)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", fileURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if r.APIKey != "" {
+		req.Header.Set("x-api-key", r.APIKey)
+	}
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+
+	if resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusUnauthorized {
+		return nil, fmt.Errorf("curseforge api rejected the request (status %d): set curseforge_api_key in the chunk config", resp.StatusCode)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("curseforge api error: status %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Data struct {
+			FileName    string `json:"fileName"`
+			DownloadURL string `json:"downloadUrl"`
+			Hashes      []struct {
+				Value string `json:"value"`
+				Algo  int    `json:"algo"`
+			} `json:"hashes"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("invalid curseforge api response: %w", err)
+	}
+
+	if result.Data.FileName == "" {
+		return nil, fmt.Errorf("curseforge file %d of project %d has no file name", fileID, projectID)
+	}
+
+	downloadURL := result.Data.DownloadURL
+	if downloadURL == "" {
+		// Authors can opt out of API distribution; the CDN path is still derivable
+		downloadURL = fmt.Sprintf("%s/%d/%d/%s", CurseForgeCDNURL, fileID/1000, fileID%1000, result.Data.FileName)
+	}
+
+	file := &CurseForgeFile{
+		FileName:    result.Data.FileName,
+		DownloadURL: downloadURL,
+	}
+	for _, hash := range result.Data.Hashes {
+		if hash.Algo == curseForgeHashSHA1 {
+			file.SHA1 = hash.Value
+		}
+	}
+	return file, nil
+}
+
+// CurseForgeParser reads CurseForge modpack exports
+type CurseForgeParser struct {
+	resolver CurseForgeFileResolver
+}
+
+// NewCurseForgeParser creates a parser; a nil resolver uses the CurseForge API
+func NewCurseForgeParser(resolver CurseForgeFileResolver) *CurseForgeParser {
+	if resolver == nil {
+		resolver = NewCurseForgeAPIResolver("")
+	}
+	return &CurseForgeParser{
+		resolver: resolver,
+	}
+}
+
+// SetResolver replaces the file resolver used for mod download URLs
+func (p *CurseForgeParser) SetResolver(resolver CurseForgeFileResolver) {
+	p.resolver = resolver
+}
+
+// IsCurseForgePack reports whether the archive contains a CurseForge manifest.json
+func IsCurseForgePack(filePath string) bool {
+	reader, err := zip.OpenReader(filePath)
+	if err != nil {
+		return false
+	}
+	defer reader.Close()
+
+	return findZipFile(reader, curseForgeManifestFile) != nil
+}
+
+// ReadManifest reads manifest.json from a CurseForge export
+func (p *CurseForgeParser) ReadManifest(filePath string) (*CurseForgeManifest, error) {
+	reader, err := zip.OpenReader(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open curseforge pack: %w", err)
+	}
+	defer reader.Close()
+
+	manifestFile := findZipFile(reader, curseForgeManifestFile)
+	if manifestFile == nil {
+		return nil, fmt.Errorf("manifest.json not found in curseforge pack")
+	}
+
+	rc, err := manifestFile.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+	defer rc.Close()
+
+	var manifest CurseForgeManifest
+	if err := json.NewDecoder(rc).Decode(&manifest); err != nil {
+		return nil, fmt.Errorf("%w: invalid manifest.json: %v", ErrInvalidManifest, err)
+	}
+
+	if manifest.ManifestType != "" && manifest.ManifestType != "minecraftModpack" {
+		return nil, fmt.Errorf("%w: unsupported manifestType %q", ErrInvalidManifest, manifest.ManifestType)
+	}
+
+	if manifest.Overrides == "" {
+		manifest.Overrides = "overrides"
+	}
+
+	return &manifest, nil
+}
+
+// Parse reads a CurseForge export and resolves every file to a download URL
+func (p *CurseForgeParser) Parse(ctx context.Context, filePath string) (*Modpack, error) {
+	manifest, err := p.ReadManifest(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	modpack := &Modpack{
+		Name:         manifest.Name,
+		Identifier:   filepath.Base(filePath),
+		MCVersion:    manifest.Minecraft.Version,
+		Author:       manifest.Author,
+		Source:       "local",
+		OverridesDir: manifest.Overrides,
+	}
+
+	if loaderID := primaryLoaderID(manifest); loaderID != "" {
+		loader, version, err := ParseCurseForgeLoaderID(loaderID)
+		if err != nil {
+			return nil, err
+		}
+		modpack.Loader = loader
+		modpack.LoaderVersion = version
+	}
+
+	mods, err := p.resolveMods(ctx, manifest)
+	if err != nil {
+		return nil, err
+	}
+	modpack.Mods = mods
+
+	return modpack, nil
+}
+
+// resolveMods resolves manifest files concurrently, preserving manifest order
+func (p *CurseForgeParser) resolveMods(ctx context.Context, manifest *CurseForgeManifest) ([]*Mod, error) {
+	mods := make([]*Mod, len(manifest.Files))
+	errs := make([]error, len(manifest.Files))
+
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, 8)
+
+	for i, file := range manifest.Files {
+		wg.Add(1)
+		go func(i, projectID, fileID int, required bool) {
+			defer wg.Done()
+
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			resolved, err := p.resolver.ResolveFile(ctx, projectID, fileID)
+			if err != nil {
+				errs[i] = fmt.Errorf("failed to resolve curseforge project %d file %d: %w", projectID, fileID, err)
+				return
+			}
+			// The hash is all that ties the download to the file the pack lists
+			if resolved.SHA1 == "" {
+				errs[i] = fmt.Errorf("curseforge project %d file %d has no SHA-1 hash to verify it with", projectID, fileID)
+				return
+			}
+
+			mods[i] = &Mod{
+				Name:        strings.TrimSuffix(resolved.FileName, filepath.Ext(resolved.FileName)),
+				Version:     fmt.Sprintf("%d", fileID),
+				FileName:    resolved.FileName,
+				DownloadURL: resolved.DownloadURL,
+				Side:        SideBoth, // CurseForge manifests do not record sides
+				Required:    required,
+				SHA1:        resolved.SHA1,
+			}
+		}(i, file.ProjectID, file.FileID, file.Required)
+	}
+
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return mods, nil
+}
+
+// Extract copies the overrides directory of a CurseForge export into destDir
+// and records the copied files in OverridesRecordFile
+func (p *CurseForgeParser) Extract(filePath, destDir string) error {
+	manifest, err := p.ReadManifest(filePath)
+	if err != nil {
+		return err
+	}
+
+	reader, err := zip.OpenReader(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to open curseforge pack: %w", err)
+	}
+	defer reader.Close()
+
+	if err := os.MkdirAll(destDir, 0755); err != nil {
+		return fmt.Errorf("failed to create destination directory: %w", err)
+	}
+
+	record := &OverridesRecord{}
+	if err := extractLayer(reader, manifest.Overrides, destDir, record); err != nil {
+		return err
+	}
+
+	return record.Save(destDir)
+}
+
+// primaryLoaderID returns the primary mod loader id, or the first one listed
+func primaryLoaderID(manifest *CurseForgeManifest) string {
+	for _, loader := range manifest.Minecraft.ModLoaders {
+		if loader.Primary {
+			return loader.ID
+		}
+	}
+	if len(manifest.Minecraft.ModLoaders) > 0 {
+		return manifest.Minecraft.ModLoaders[0].ID
+	}
+	return ""
+}
+
+// ParseCurseForgeLoaderID splits a CurseForge loader id such as "forge-47.2.0"
+// into its loader type and version
+func ParseCurseForgeLoaderID(id string) (LoaderType, string, error) {
+	name, version, found := strings.Cut(id, "-")
+	if !found || version == "" {
+		return "", "", fmt.Errorf("%w: malformed loader id %q", ErrInvalidManifest, id)
+	}
+
+	switch strings.ToLower(name) {
+	case "forge":
+		return LoaderForge, version, nil
+	case "neoforge":
+		return LoaderNeoForge, version, nil
+	case "fabric":
+		return LoaderFabric, version, nil
+	case "quilt":
+		return LoaderQuilt, version, nil
+	default:
+		return "", "", fmt.Errorf("unsupported loader in curseforge manifest: %s", id)
+	}
+}
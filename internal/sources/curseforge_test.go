@@ -0,0 +1,279 @@
+package sources
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// createTestZip writes a zip archive with the given entries
+func createTestZip(t *testing.T, path string, entries map[string]string) {
+	t.Helper()
+
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("Failed to create zip: %v", err)
+	}
+	defer file.Close()
+
+	writer := zip.NewWriter(file)
+	for name, content := range entries {
+		w, err := writer.Create(name)
+		if err != nil {
+			t.Fatalf("Failed to add %s: %v", name, err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Failed to close zip: %v", err)
+	}
+}
+
+const testCurseForgeManifest = `{
+  "minecraft": {
+    "version": "1.20.1",
+    "modLoaders": [
+      {"id": "forge-47.2.0", "primary": true}
+    ]
+  },
+  "manifestType": "minecraftModpack",
+  "manifestVersion": 1,
+  "name": "Test CF Pack",
+  "version": "1.0.0",
+  "author": "Tester",
+  "files": [
+    {"projectID": 238222, "fileID": 4712866, "required": true},
+    {"projectID": 306612, "fileID": 4596743, "required": false}
+  ],
+  "overrides": "overrides"
+}`
+
+// fakeCurseForgeResolver resolves files from a fixed table
+type fakeCurseForgeResolver struct {
+	files map[int]*CurseForgeFile
+}
+
+func (f *fakeCurseForgeResolver) ResolveFile(ctx context.Context, projectID, fileID int) (*CurseForgeFile, error) {
+	file, ok := f.files[fileID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return file, nil
+}
+
+func TestCurseForgeParserParse(t *testing.T) {
+	tmpDir := t.TempDir()
+	packPath := filepath.Join(tmpDir, "pack.zip")
+	createTestZip(t, packPath, map[string]string{
+		"manifest.json":             testCurseForgeManifest,
+		"overrides/config/jei.toml": "# jei",
+	})
+
+	parser := NewCurseForgeParser(&fakeCurseForgeResolver{files: map[int]*CurseForgeFile{
+		4712866: {FileName: "jei-1.20.1-15.2.0.jar", DownloadURL: "https://example.com/jei.jar", SHA1: "a1"},
+		4596743: {FileName: "jade-1.20.1-11.6.3.jar", DownloadURL: "https://example.com/jade.jar", SHA1: "b2"},
+	}})
+
+	modpack, err := parser.Parse(context.Background(), packPath)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if modpack.Name != "Test CF Pack" {
+		t.Errorf("Expected name 'Test CF Pack', got '%s'", modpack.Name)
+	}
+	if modpack.MCVersion != "1.20.1" {
+		t.Errorf("Expected mc version '1.20.1', got '%s'", modpack.MCVersion)
+	}
+	if modpack.Loader != LoaderForge || modpack.LoaderVersion != "47.2.0" {
+		t.Errorf("Expected forge 47.2.0, got %s %s", modpack.Loader, modpack.LoaderVersion)
+	}
+	if modpack.OverridesDir != "overrides" {
+		t.Errorf("Expected overrides dir 'overrides', got '%s'", modpack.OverridesDir)
+	}
+	if len(modpack.Mods) != 2 {
+		t.Fatalf("Expected 2 mods, got %d", len(modpack.Mods))
+	}
+
+	// Manifest order is preserved despite concurrent resolution
+	if modpack.Mods[0].FileName != "jei-1.20.1-15.2.0.jar" || modpack.Mods[0].DownloadURL != "https://example.com/jei.jar" || modpack.Mods[0].SHA1 != "a1" {
+		t.Errorf("Unexpected first mod: %+v", modpack.Mods[0])
+	}
+	if modpack.Mods[1].Required {
+		t.Error("Expected second mod to be optional")
+	}
+}
+
+func TestCurseForgeParserParseUnresolvable(t *testing.T) {
+	tmpDir := t.TempDir()
+	packPath := filepath.Join(tmpDir, "pack.zip")
+	createTestZip(t, packPath, map[string]string{"manifest.json": testCurseForgeManifest})
+
+	parser := NewCurseForgeParser(&fakeCurseForgeResolver{files: map[int]*CurseForgeFile{}})
+	if _, err := parser.Parse(context.Background(), packPath); err == nil {
+		t.Error("Expected error when a file cannot be resolved")
+	}
+
+	// Files are only downloaded when they can be verified
+	parser = NewCurseForgeParser(&fakeCurseForgeResolver{files: map[int]*CurseForgeFile{
+		4712866: {FileName: "jei.jar", DownloadURL: "https://example.com/jei.jar"},
+		4596743: {FileName: "jade.jar", DownloadURL: "https://example.com/jade.jar", SHA1: "b2"},
+	}})
+	if _, err := parser.Parse(context.Background(), packPath); err == nil {
+		t.Error("Expected error when a file has no hash")
+	}
+}
+
+func TestCurseForgeAPIResolver(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("x-api-key") != "test-key" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/mods/238222/files/4712866":
+			fmt.Fprint(w, `{"data":{"fileName":"jei.jar","downloadUrl":"https://cdn.example.com/jei.jar","hashes":[{"value":"md5sum","algo":2},{"value":"sha1sum","algo":1}]}}`)
+		case "/v1/mods/306612/files/4596743":
+			// Third-party distribution disabled
+			fmt.Fprint(w, `{"data":{"fileName":"jade.jar","downloadUrl":null}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	resolver := NewCurseForgeAPIResolver("test-key")
+	resolver.BaseURL = server.URL
+
+	tests := []struct {
+		name      string
+		projectID int
+		fileID    int
+		wantURL   string
+		wantSHA1  string
+		wantErr   bool
+	}{
+		{name: "download url from api", projectID: 238222, fileID: 4712866, wantURL: "https://cdn.example.com/jei.jar", wantSHA1: "sha1sum"},
+		{name: "cdn fallback", projectID: 306612, fileID: 4596743, wantURL: CurseForgeCDNURL + "/4596/743/jade.jar"},
+		{name: "not found", projectID: 1, fileID: 2, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file, err := resolver.ResolveFile(context.Background(), tt.projectID, tt.fileID)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ResolveFile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && file.DownloadURL != tt.wantURL {
+				t.Errorf("Expected URL %s, got %s", tt.wantURL, file.DownloadURL)
+			}
+			if err == nil && file.SHA1 != tt.wantSHA1 {
+				t.Errorf("Expected SHA-1 %q, got %q", tt.wantSHA1, file.SHA1)
+			}
+		})
+	}
+
+	resolver.APIKey = ""
+	if _, err := resolver.ResolveFile(context.Background(), 238222, 4712866); err == nil {
+		t.Error("Expected error without API key")
+	}
+}
+
+func TestCurseForgeParserExtract(t *testing.T) {
+	tmpDir := t.TempDir()
+	packPath := filepath.Join(tmpDir, "pack.zip")
+	createTestZip(t, packPath, map[string]string{
+		"manifest.json":                   testCurseForgeManifest,
+		"modlist.html":                    "<ul></ul>",
+		"overrides/config/jei.toml":       "# jei",
+		"overrides/kubejs/server/main.js": "// script",
+	})
+
+	destDir := filepath.Join(tmpDir, "server")
+	parser := NewCurseForgeParser(&fakeCurseForgeResolver{})
+	if err := parser.Extract(packPath, destDir); err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	for _, path := range []string{"config/jei.toml", "kubejs/server/main.js"} {
+		if _, err := os.Stat(filepath.Join(destDir, path)); err != nil {
+			t.Errorf("Expected %s to be extracted: %v", path, err)
+		}
+	}
+	for _, path := range []string{"manifest.json", "modlist.html", "overrides"} {
+		if _, err := os.Stat(filepath.Join(destDir, path)); err == nil {
+			t.Errorf("Expected %s not to be extracted", path)
+		}
+	}
+}
+
+func TestCurseForgeParserExtractRejectsZipSlip(t *testing.T) {
+	tmpDir := t.TempDir()
+	packPath := filepath.Join(tmpDir, "pack.zip")
+	createTestZip(t, packPath, map[string]string{
+		"manifest.json":           testCurseForgeManifest,
+		"overrides/../../evil.sh": "#!/bin/sh",
+	})
+
+	parser := NewCurseForgeParser(&fakeCurseForgeResolver{})
+	if err := parser.Extract(packPath, filepath.Join(tmpDir, "server")); err == nil {
+		t.Error("Expected error for path escaping destination")
+	}
+}
+
+func TestParseCurseForgeLoaderID(t *testing.T) {
+	tests := []struct {
+		id          string
+		wantLoader  LoaderType
+		wantVersion string
+		wantErr     bool
+	}{
+		{id: "forge-47.2.0", wantLoader: LoaderForge, wantVersion: "47.2.0"},
+		{id: "neoforge-20.4.80-beta", wantLoader: LoaderNeoForge, wantVersion: "20.4.80-beta"},
+		{id: "fabric-0.15.3", wantLoader: LoaderFabric, wantVersion: "0.15.3"},
+		{id: "quilt-0.23.1", wantLoader: LoaderQuilt, wantVersion: "0.23.1"},
+		{id: "forge", wantErr: true},
+		{id: "rift-1.0", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			loader, version, err := ParseCurseForgeLoaderID(tt.id)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseCurseForgeLoaderID(%q) error = %v, wantErr %v", tt.id, err, tt.wantErr)
+			}
+			if loader != tt.wantLoader || version != tt.wantVersion {
+				t.Errorf("ParseCurseForgeLoaderID(%q) = %s, %s; want %s, %s", tt.id, loader, version, tt.wantLoader, tt.wantVersion)
+			}
+		})
+	}
+}
+
+func TestLocalClientFetchCurseForgeZip(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+
+	packPath := filepath.Join(tmpDir, "pack.zip")
+	createTestZip(t, packPath, map[string]string{"manifest.json": testCurseForgeManifest})
+
+	client := NewLocalClient()
+	client.SetCurseForgeResolver(&fakeCurseForgeResolver{files: map[int]*CurseForgeFile{
+		4712866: {FileName: "jei.jar", DownloadURL: "https://example.com/jei.jar", SHA1: "a1"},
+		4596743: {FileName: "jade.jar", DownloadURL: "https://example.com/jade.jar", SHA1: "b2"},
+	}})
+
+	modpack, err := client.Fetch(context.Background(), packPath)
+	if err != nil {
+		t.Fatalf("Fetch failed: %v", err)
+	}
+	if modpack.Loader != LoaderForge || len(modpack.Mods) != 2 {
+		t.Errorf("Expected forge pack with 2 mods, got %s with %d mods", modpack.Loader, len(modpack.Mods))
+	}
+}
@@ -0,0 +1,60 @@
+package sources
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/alexinslc/chunk/internal/search"
+)
+
+// Facets returns the filterable attributes of a search result
+func (r *ModpackSearchResult) Facets() search.Facets {
+	facets := search.Facets{
+		Tags:       r.Tags,
+		MCVersions: r.MCVersions,
+		RAMGB:      r.RecommendedRAM,
+		Author:     r.Author,
+		Bench:      r.Bench,
+	}
+	for _, loader := range r.Loaders {
+		facets.Loaders = append(facets.Loaders, string(loader))
+	}
+	if len(facets.Loaders) == 0 && r.Loader != "" {
+		facets.Loaders = []string{string(r.Loader)}
+	}
+	if len(facets.MCVersions) == 0 && r.MCVersion != "" {
+		facets.MCVersions = []string{r.MCVersion}
+	}
+	return facets
+}
+
+// FilterResults returns the results that pass filter, keeping their order.
+// Sources apply it to whatever their API returns, since most APIs support
+// only some facets.
+func FilterResults(results []*ModpackSearchResult, filter search.Filter) []*ModpackSearchResult {
+	if filter.IsEmpty() {
+		return results
+	}
+	filtered := make([]*ModpackSearchResult, 0, len(results))
+	for _, result := range results {
+		if filter.Match(result.Facets()) {
+			filtered = append(filtered, result)
+		}
+	}
+	return filtered
+}
+
+// SortResults orders search results. Sources rank by their own relevance, so
+// SortRelevance keeps the order results were returned in.
+func SortResults(results []*ModpackSearchResult, order search.SortOrder) {
+	switch order {
+	case search.SortName:
+		sort.SliceStable(results, func(i, j int) bool {
+			return strings.ToLower(results[i].Name) < strings.ToLower(results[j].Name)
+		})
+	case search.SortNewest:
+		sort.SliceStable(results, func(i, j int) bool {
+			return search.NewerThan(results[i].UpdatedAt, results[j].UpdatedAt)
+		})
+	}
+}
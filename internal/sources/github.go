@@ -1,12 +1,16 @@
 package sources
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
 	"strings"
 	"time"
+
+	"github.com/alexinslc/chunk/internal/mirror"
+	"github.com/alexinslc/chunk/internal/search"
 )
 
 const (
@@ -21,7 +25,8 @@ type GitHubClient struct {
 func NewGitHubClient() *GitHubClient {
 	return &GitHubClient{
 		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout:   30 * time.Second,
+			Transport: &mirror.Transport{},
 		},
 	}
 }
@@ -30,13 +35,13 @@ func (g *GitHubClient) SetToken(token string) {
 	g.token = token
 }
 
-func (g *GitHubClient) Fetch(identifier string) (*Modpack, error) {
+func (g *GitHubClient) Fetch(ctx context.Context, identifier string) (*Modpack, error) {
 	owner, repo, err := parseGitHubIdentifier(identifier)
 	if err != nil {
 		return nil, err
 	}
 
-	chunkJSON, err := g.fetchChunkJSON(owner, repo)
+	chunkJSON, err := g.fetchChunkJSON(ctx, owner, repo)
 	if err != nil {
 		return nil, err
 	}
@@ -57,11 +62,11 @@ func (g *GitHubClient) Fetch(identifier string) (*Modpack, error) {
 	return modpack, nil
 }
 
-func (g *GitHubClient) Search(query string) ([]*ModpackSearchResult, error) {
+func (g *GitHubClient) Search(ctx context.Context, query string, filter search.Filter) ([]*ModpackSearchResult, error) {
 	searchURL := fmt.Sprintf("%s/search/repositories?q=%s+.chunk.json+in:repo",
 		GitHubAPIURL, url.QueryEscape(query))
 
-	resp, err := g.get(searchURL)
+	resp, err := g.get(ctx, searchURL)
 	if err != nil {
 		return nil, err
 	}
@@ -73,8 +78,10 @@ func (g *GitHubClient) Search(query string) ([]*ModpackSearchResult, error) {
 
 	var result struct {
 		Items []struct {
-			FullName    string `json:"full_name"`
-			Description string `json:"description"`
+			FullName    string    `json:"full_name"`
+			Description string    `json:"description"`
+			Topics      []string  `json:"topics"`
+			UpdatedAt   time.Time `json:"updated_at"`
 			Owner       struct {
 				Login string `json:"login"`
 			} `json:"owner"`
@@ -89,7 +96,7 @@ func (g *GitHubClient) Search(query string) ([]*ModpackSearchResult, error) {
 	for _, item := range result.Items {
 		owner, repo, _ := parseGitHubIdentifier(item.FullName)
 
-		chunkJSON, err := g.fetchChunkJSON(owner, repo)
+		chunkJSON, err := g.fetchChunkJSON(ctx, owner, repo)
 		if err != nil {
 			continue
 		}
@@ -101,13 +108,18 @@ func (g *GitHubClient) Search(query string) ([]*ModpackSearchResult, error) {
 			MCVersion:   chunkJSON.MCVersion,
 			Loader:      LoaderType(chunkJSON.Loader),
 			Source:      "github",
+
+			Tags:           item.Topics,
+			Author:         item.Owner.Login,
+			RecommendedRAM: chunkJSON.RecommendedRAMGB,
+			UpdatedAt:      item.UpdatedAt,
 		})
 	}
 
-	return results, nil
+	return FilterResults(results, filter), nil
 }
 
-func (g *GitHubClient) GetVersions(identifier string) ([]*Version, error) {
+func (g *GitHubClient) GetVersions(ctx context.Context, identifier string) ([]*Version, error) {
 	owner, repo, err := parseGitHubIdentifier(identifier)
 	if err != nil {
 		return nil, err
@@ -115,7 +127,7 @@ func (g *GitHubClient) GetVersions(identifier string) ([]*Version, error) {
 
 	tagsURL := fmt.Sprintf("%s/repos/%s/%s/tags", GitHubAPIURL, owner, repo)
 
-	resp, err := g.get(tagsURL)
+	resp, err := g.get(ctx, tagsURL)
 	if err != nil {
 		return nil, err
 	}
@@ -148,11 +160,11 @@ func (g *GitHubClient) GetVersions(identifier string) ([]*Version, error) {
 	return versions, nil
 }
 
-func (g *GitHubClient) fetchChunkJSON(owner, repo string) (*ChunkManifest, error) {
+func (g *GitHubClient) fetchChunkJSON(ctx context.Context, owner, repo string) (*ChunkManifest, error) {
 	contentURL := fmt.Sprintf("%s/repos/%s/%s/contents/.chunk.json",
 		GitHubAPIURL, owner, repo)
 
-	resp, err := g.get(contentURL)
+	resp, err := g.get(ctx, contentURL)
 	if err != nil {
 		return nil, err
 	}
@@ -192,10 +204,10 @@ func (g *GitHubClient) fetchChunkJSON(owner, repo string) (*ChunkManifest, error
 	return &manifest, nil
 }
 
-func (g *GitHubClient) get(endpoint string) (*http.Response, error) {
+func (g *GitHubClient) get(ctx context.Context, endpoint string) (*http.Response, error) {
 	GetGitHubRateLimiter().Wait()
 
-	req, err := http.NewRequest("GET", endpoint, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
 	if err != nil {
 		return nil, err
 	}
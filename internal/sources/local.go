@@ -1,23 +1,39 @@
 package sources
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/alexinslc/chunk/internal/config"
+	"github.com/alexinslc/chunk/internal/search"
 )
 
 type LocalClient struct {
-	mrpackParser *MRPackParser
+	mrpackParser     *MRPackParser
+	curseforgeParser *CurseForgeParser
 }
 
 func NewLocalClient() *LocalClient {
+	apiKey := ""
+	if cfg, err := config.Load(); err == nil {
+		apiKey = cfg.GetCurseForgeAPIKey()
+	}
+
 	return &LocalClient{
-		mrpackParser: NewMRPackParser(),
+		mrpackParser:     NewMRPackParser(),
+		curseforgeParser: NewCurseForgeParser(NewCurseForgeAPIResolver(apiKey)),
 	}
 }
 
-func (l *LocalClient) Fetch(identifier string) (*Modpack, error) {
+// SetCurseForgeResolver replaces how CurseForge manifest files are resolved to download URLs
+func (l *LocalClient) SetCurseForgeResolver(resolver CurseForgeFileResolver) {
+	l.curseforgeParser.SetResolver(resolver)
+}
+
+func (l *LocalClient) Fetch(ctx context.Context, identifier string) (*Modpack, error) {
 	if !fileExists(identifier) {
 		return nil, fmt.Errorf("file not found: %s", identifier)
 	}
@@ -25,24 +41,36 @@ func (l *LocalClient) Fetch(identifier string) (*Modpack, error) {
 	ext := strings.ToLower(filepath.Ext(identifier))
 
 	switch ext {
-	case ".mrpack":
-		return l.mrpackParser.Parse(identifier)
-	case ".zip":
-		return l.parseZip(identifier)
+	case ".mrpack", ".zip":
+		return l.ParseArchive(ctx, identifier)
 	default:
 		return nil, fmt.Errorf("unsupported file format: %s", ext)
 	}
 }
 
-func (l *LocalClient) Search(query string) ([]*ModpackSearchResult, error) {
+func (l *LocalClient) Search(ctx context.Context, query string, filter search.Filter) ([]*ModpackSearchResult, error) {
 	return nil, fmt.Errorf("search not supported for local files")
 }
 
-func (l *LocalClient) GetVersions(identifier string) ([]*Version, error) {
+func (l *LocalClient) GetVersions(ctx context.Context, identifier string) ([]*Version, error) {
 	return nil, fmt.Errorf("version lookup not supported for local files")
 }
 
-func (l *LocalClient) parseZip(filePath string) (*Modpack, error) {
+// ParseArchive reads a pack archive by its contents rather than its extension:
+// Modrinth packs, CurseForge exports and generic zips are recognized.
+func (l *LocalClient) ParseArchive(ctx context.Context, filePath string) (*Modpack, error) {
+	if IsCurseForgePack(filePath) && !IsMRPack(filePath) {
+		return l.curseforgeParser.Parse(ctx, filePath)
+	}
+
+	if strings.ToLower(filepath.Ext(filePath)) == ".mrpack" {
+		return l.mrpackParser.Parse(filePath)
+	}
+
+	return l.parseZip(ctx, filePath)
+}
+
+func (l *LocalClient) parseZip(ctx context.Context, filePath string) (*Modpack, error) {
 	modpack, err := l.mrpackParser.Parse(filePath)
 	if err == nil {
 		return modpack, nil
@@ -60,6 +88,9 @@ func (l *LocalClient) Extract(filePath, destDir string) error {
 
 	switch ext {
 	case ".mrpack", ".zip":
+		if IsCurseForgePack(filePath) && !IsMRPack(filePath) {
+			return l.curseforgeParser.Extract(filePath, destDir)
+		}
 		return l.mrpackParser.Extract(filePath, destDir)
 	default:
 		return fmt.Errorf("unsupported file format for extraction: %s", ext)
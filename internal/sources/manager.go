@@ -1,7 +1,11 @@
 package sources
 
 import (
+	"context"
 	"fmt"
+	"strings"
+
+	"github.com/alexinslc/chunk/internal/search"
 )
 
 type SourceManager struct {
@@ -10,6 +14,7 @@ type SourceManager struct {
 	modrinth *ModrinthClient
 	local    *LocalClient
 	recipe   *RecipeClient
+	bundle   *BundleClient
 }
 
 func NewSourceManager() *SourceManager {
@@ -19,74 +24,107 @@ func NewSourceManager() *SourceManager {
 		modrinth: NewModrinthClient(),
 		local:    NewLocalClient(),
 		recipe:   NewRecipeClient(),
+		bundle:   NewBundleClient(),
 	}
 }
 
-func (s *SourceManager) Fetch(identifier string) (*Modpack, error) {
+func (s *SourceManager) Fetch(ctx context.Context, identifier string) (*Modpack, error) {
 	sourceType := DetectSource(identifier)
 
 	switch sourceType {
 	case "recipe":
-		return s.recipe.Fetch(identifier)
+		return s.recipe.Fetch(ctx, identifier)
 	case "chunkhub":
-		return s.chunkhub.Fetch(identifier)
+		return s.chunkhub.Fetch(ctx, identifier)
 	case "github":
-		return s.github.Fetch(identifier)
+		return s.github.Fetch(ctx, identifier)
 	case "modrinth":
-		return s.modrinth.Fetch(identifier)
+		return s.modrinth.Fetch(ctx, identifier)
 	case "local":
-		return s.local.Fetch(identifier)
+		return s.local.Fetch(ctx, identifier)
+	case "bundle":
+		return s.bundle.Fetch(ctx, identifier)
 	default:
+		if plugin, ok := s.plugin(sourceType); ok {
+			return plugin.Fetch(ctx, identifier)
+		}
 		return nil, fmt.Errorf("unknown source type: %s", sourceType)
 	}
 }
 
-func (s *SourceManager) Search(query string) ([]*ModpackSearchResult, error) {
-	var allResults []*ModpackSearchResult
-
-	// Search recipes first (local benches)
-	recipeResults, err := s.recipe.Search(query)
-	if err == nil {
-		allResults = append(allResults, recipeResults...)
+// FetchVariant fetches a modpack with a recipe variant applied. Only recipes
+// have variants, and bundles hold the one they were exported with; an empty
+// variant is the same as Fetch.
+func (s *SourceManager) FetchVariant(ctx context.Context, identifier, variant string) (*Modpack, error) {
+	if variant == "" {
+		return s.Fetch(ctx, identifier)
 	}
-
-	chunkHubResults, err := s.chunkhub.Search(query)
-	if err == nil {
-		allResults = append(allResults, chunkHubResults...)
+	switch sourceType := DetectSource(identifier); sourceType {
+	case "recipe":
+	case "bundle":
+		modpack, err := s.Fetch(ctx, identifier)
+		if err != nil {
+			return nil, err
+		}
+		if modpack.Variant != variant {
+			return nil, fmt.Errorf("bundle %s holds variant %q, not %q", identifier, modpack.Variant, variant)
+		}
+		return modpack, nil
+	default:
+		return nil, fmt.Errorf("variants are only supported for recipes, not %s sources", sourceType)
 	}
+	return s.recipe.FetchVariant(ctx, identifier, variant)
+}
 
-	modrinthResults, err := s.modrinth.Search(query)
-	if err == nil {
-		allResults = append(allResults, modrinthResults...)
-	}
+// Search searches local benches and every remote source. Results are
+// filtered by each source and then sorted across sources.
+func (s *SourceManager) Search(ctx context.Context, query string, filter search.Filter, order search.SortOrder) ([]*ModpackSearchResult, error) {
+	var allResults []*ModpackSearchResult
 
-	githubResults, err := s.github.Search(query)
+	// Search recipes first (local benches)
+	recipeResults, err := s.recipe.Search(ctx, query, filter)
 	if err == nil {
-		allResults = append(allResults, githubResults...)
+		allResults = append(allResults, recipeResults...)
 	}
 
-	if len(allResults) == 0 {
-		return nil, fmt.Errorf("no results found for query: %s", query)
+	// Only recipes belong to a bench
+	if filter.Bench == "" {
+		remotes := []ModpackSource{s.chunkhub, s.modrinth, s.github}
+		for _, plugin := range DiscoverPlugins() {
+			remotes = append(remotes, plugin)
+		}
+		for _, source := range remotes {
+			results, err := source.Search(ctx, query, filter)
+			if err == nil {
+				allResults = append(allResults, results...)
+			}
+		}
 	}
 
+	SortResults(allResults, order)
 	return allResults, nil
 }
 
-func (s *SourceManager) GetVersions(identifier string) ([]*Version, error) {
+func (s *SourceManager) GetVersions(ctx context.Context, identifier string) ([]*Version, error) {
 	sourceType := DetectSource(identifier)
 
 	switch sourceType {
 	case "recipe":
-		return s.recipe.GetVersions(identifier)
+		return s.recipe.GetVersions(ctx, identifier)
 	case "chunkhub":
-		return s.chunkhub.GetVersions(identifier)
+		return s.chunkhub.GetVersions(ctx, identifier)
 	case "github":
-		return s.github.GetVersions(identifier)
+		return s.github.GetVersions(ctx, identifier)
 	case "modrinth":
-		return s.modrinth.GetVersions(identifier)
+		return s.modrinth.GetVersions(ctx, identifier)
 	case "local":
-		return s.local.GetVersions(identifier)
+		return s.local.GetVersions(ctx, identifier)
+	case "bundle":
+		return s.bundle.GetVersions(ctx, identifier)
 	default:
+		if plugin, ok := s.plugin(sourceType); ok {
+			return plugin.GetVersions(ctx, identifier)
+		}
 		return nil, fmt.Errorf("unknown source type: %s", sourceType)
 	}
 }
@@ -103,7 +141,21 @@ func (s *SourceManager) GetClient(sourceType string) (ModpackSource, error) {
 		return s.modrinth, nil
 	case "local":
 		return s.local, nil
+	case "bundle":
+		return s.bundle, nil
 	default:
+		if plugin, ok := s.plugin(sourceType); ok {
+			return plugin, nil
+		}
 		return nil, fmt.Errorf("unknown source type: %s", sourceType)
 	}
 }
+
+// plugin returns the source plugin for a "plugin:<name>" source type
+func (s *SourceManager) plugin(sourceType string) (*PluginClient, bool) {
+	name, ok := strings.CutPrefix(sourceType, "plugin:")
+	if !ok {
+		return nil, false
+	}
+	return FindPlugin(name)
+}
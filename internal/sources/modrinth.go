@@ -1,6 +1,7 @@
 package sources
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -8,6 +9,9 @@ import (
 	"net/url"
 	"strings"
 	"time"
+
+	"github.com/alexinslc/chunk/internal/mirror"
+	"github.com/alexinslc/chunk/internal/search"
 )
 
 const (
@@ -22,7 +26,8 @@ type ModrinthClient struct {
 func NewModrinthClient() *ModrinthClient {
 	return &ModrinthClient{
 		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout:   30 * time.Second,
+			Transport: &mirror.Transport{},
 		},
 	}
 }
@@ -31,12 +36,12 @@ func (m *ModrinthClient) SetAPIKey(apiKey string) {
 	m.apiKey = apiKey
 }
 
-func (m *ModrinthClient) Fetch(identifier string) (*Modpack, error) {
+func (m *ModrinthClient) Fetch(ctx context.Context, identifier string) (*Modpack, error) {
 	slug := strings.TrimPrefix(identifier, "modrinth:")
 
 	projectURL := fmt.Sprintf("%s/project/%s", ModrinthAPIURL, url.PathEscape(slug))
 
-	resp, err := m.get(projectURL)
+	resp, err := m.get(ctx, projectURL)
 	if err != nil {
 		return nil, err
 	}
@@ -84,11 +89,15 @@ func (m *ModrinthClient) Fetch(identifier string) (*Modpack, error) {
 	return modpack, nil
 }
 
-func (m *ModrinthClient) Search(query string) ([]*ModpackSearchResult, error) {
-	searchURL := fmt.Sprintf("%s/search?query=%s&facets=[[\"project_type:modpack\"]]",
-		ModrinthAPIURL, url.QueryEscape(query))
+func (m *ModrinthClient) Search(ctx context.Context, query string, filter search.Filter) ([]*ModpackSearchResult, error) {
+	facets, err := json.Marshal(modrinthFacets(filter))
+	if err != nil {
+		return nil, err
+	}
+	searchURL := fmt.Sprintf("%s/search?query=%s&facets=%s",
+		ModrinthAPIURL, url.QueryEscape(query), url.QueryEscape(string(facets)))
 
-	resp, err := m.get(searchURL)
+	resp, err := m.get(ctx, searchURL)
 	if err != nil {
 		return nil, err
 	}
@@ -100,12 +109,15 @@ func (m *ModrinthClient) Search(query string) ([]*ModpackSearchResult, error) {
 
 	var result struct {
 		Hits []struct {
-			Slug         string   `json:"slug"`
-			Title        string   `json:"title"`
-			Description  string   `json:"description"`
-			GameVersions []string `json:"game_versions"`
-			Loaders      []string `json:"loaders"`
-			Downloads    int      `json:"downloads"`
+			Slug         string    `json:"slug"`
+			Title        string    `json:"title"`
+			Description  string    `json:"description"`
+			GameVersions []string  `json:"game_versions"`
+			Loaders      []string  `json:"loaders"`
+			Categories   []string  `json:"categories"`
+			Author       string    `json:"author"`
+			Downloads    int       `json:"downloads"`
+			DateModified time.Time `json:"date_modified"`
 		} `json:"hits"`
 	}
 
@@ -120,9 +132,10 @@ func (m *ModrinthClient) Search(query string) ([]*ModpackSearchResult, error) {
 			mcVersion = hit.GameVersions[0]
 		}
 
+		loaders := modrinthLoaders(hit.Loaders, hit.Categories)
 		var loader LoaderType
-		if len(hit.Loaders) > 0 {
-			loader = LoaderType(strings.ToLower(hit.Loaders[0]))
+		if len(loaders) > 0 {
+			loader = loaders[0]
 		}
 
 		results = append(results, &ModpackSearchResult{
@@ -133,18 +146,66 @@ func (m *ModrinthClient) Search(query string) ([]*ModpackSearchResult, error) {
 			Loader:      loader,
 			Source:      "modrinth",
 			Downloads:   hit.Downloads,
+
+			MCVersions: hit.GameVersions,
+			Loaders:    loaders,
+			Tags:       hit.Categories,
+			Author:     hit.Author,
+			UpdatedAt:  hit.DateModified,
 		})
 	}
 
-	return results, nil
+	return FilterResults(results, filter), nil
 }
 
-func (m *ModrinthClient) GetVersions(identifier string) ([]*Version, error) {
+// modrinthLoaders returns every loader a search hit supports. Search hits
+// list loaders among the categories, so both are read.
+func modrinthLoaders(loaders, categories []string) []LoaderType {
+	var result []LoaderType
+	seen := make(map[LoaderType]bool)
+	add := func(name string) {
+		loader := LoaderType(strings.ToLower(name))
+		if !seen[loader] {
+			seen[loader] = true
+			result = append(result, loader)
+		}
+	}
+
+	for _, name := range loaders {
+		add(name)
+	}
+	for _, category := range categories {
+		if c := LoaderType(strings.ToLower(category)); c == LoaderForge || c == LoaderFabric || c == LoaderNeoForge || c == LoaderQuilt {
+			add(category)
+		}
+	}
+	return result
+}
+
+// modrinthFacets narrows a Modrinth search with the facets its API supports.
+// Version ranges and RAM are filtered after the search.
+func modrinthFacets(filter search.Filter) [][]string {
+	facets := [][]string{{"project_type:modpack"}}
+	if filter.Loader != "" {
+		facets = append(facets, []string{"categories:" + strings.ToLower(filter.Loader)})
+	}
+	if constraint, err := search.ParseMCConstraint(filter.MCVersion); err == nil {
+		if version, ok := constraint.Exact(); ok {
+			facets = append(facets, []string{"versions:" + version})
+		}
+	}
+	for _, tag := range filter.Tags {
+		facets = append(facets, []string{"categories:" + strings.ToLower(tag)})
+	}
+	return facets
+}
+
+func (m *ModrinthClient) GetVersions(ctx context.Context, identifier string) ([]*Version, error) {
 	slug := strings.TrimPrefix(identifier, "modrinth:")
 
 	versionsURL := fmt.Sprintf("%s/project/%s/version", ModrinthAPIURL, url.PathEscape(slug))
 
-	resp, err := m.get(versionsURL)
+	resp, err := m.get(ctx, versionsURL)
 	if err != nil {
 		return nil, err
 	}
@@ -203,10 +264,10 @@ func (m *ModrinthClient) GetVersions(identifier string) ([]*Version, error) {
 	return versions, nil
 }
 
-func (m *ModrinthClient) get(endpoint string) (*http.Response, error) {
+func (m *ModrinthClient) get(ctx context.Context, endpoint string) (*http.Response, error) {
 	GetModrinthRateLimiter().Wait()
 
-	req, err := http.NewRequest("GET", endpoint, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -220,8 +281,13 @@ func (m *ModrinthClient) get(endpoint string) (*http.Response, error) {
 	return m.httpClient.Do(req)
 }
 
-func (m *ModrinthClient) DownloadFile(fileURL string, dest io.Writer) error {
-	resp, err := m.httpClient.Get(fileURL)
+func (m *ModrinthClient) DownloadFile(ctx context.Context, fileURL string, dest io.Writer) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", fileURL, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := m.httpClient.Do(req)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,53 @@
+package sources
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alexinslc/chunk/internal/config"
+	"github.com/alexinslc/chunk/internal/mirror"
+	"github.com/alexinslc/chunk/internal/search"
+)
+
+func TestModrinthSearchLoaders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"hits": [
+			{"slug": "multi", "title": "Multi", "loaders": ["Fabric", "quilt"], "categories": ["neoforge", "technology"]},
+			{"slug": "categories", "title": "Categories", "categories": ["adventure", "forge"]}
+		]}`))
+	}))
+	defer server.Close()
+
+	client := NewModrinthClient()
+	client.httpClient = &http.Client{Transport: &mirror.Transport{
+		Rewriter: mirror.NewRewriter([]config.MirrorRule{{Prefix: ModrinthAPIURL + "/", Mirror: server.URL + "/"}}),
+	}}
+
+	results, err := client.Search(context.Background(), "pack", search.Filter{})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(results))
+	}
+	if got := results[0].Facets().Loaders; len(got) != 3 || got[0] != "fabric" || got[1] != "quilt" || got[2] != "neoforge" {
+		t.Errorf("Expected every loader of the hit, got %v", got)
+	}
+	if results[0].Loader != LoaderFabric {
+		t.Errorf("Expected fabric as the primary loader, got %s", results[0].Loader)
+	}
+	if got := results[1].Facets().Loaders; len(got) != 1 || got[0] != "forge" {
+		t.Errorf("Expected the loader from the categories, got %v", got)
+	}
+
+	// Any of a hit's loaders matches a loader filter
+	results, err = client.Search(context.Background(), "pack", search.Filter{Loader: "neoforge"})
+	if err != nil {
+		t.Fatalf("Filtered search failed: %v", err)
+	}
+	if len(results) != 1 || results[0].Identifier != "modrinth:multi" {
+		t.Errorf("Expected only the multi-loader pack, got %+v", results)
+	}
+}
@@ -4,9 +4,9 @@ import (
 	"archive/zip"
 	"encoding/json"
 	"fmt"
-	"io"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 type MRPackParser struct{}
@@ -15,6 +15,17 @@ func NewMRPackParser() *MRPackParser {
 	return &MRPackParser{}
 }
 
+// IsMRPack reports whether the archive contains a modrinth.index.json
+func IsMRPack(filePath string) bool {
+	reader, err := zip.OpenReader(filePath)
+	if err != nil {
+		return false
+	}
+	defer reader.Close()
+
+	return findZipFile(reader, "modrinth.index.json") != nil
+}
+
 func (p *MRPackParser) Parse(filePath string) (*Modpack, error) {
 	reader, err := zip.OpenReader(filePath)
 	if err != nil {
@@ -56,6 +67,10 @@ func (p *MRPackParser) Parse(filePath string) (*Modpack, error) {
 	if manifest.Dependencies.Forge != "" {
 		modpack.Loader = LoaderForge
 		modpack.LoaderVersion = manifest.Dependencies.Forge
+	} else if manifest.Dependencies.QuiltLoader != "" {
+		// Quilt packs may also list fabric-loader; Quilt runs Fabric mods, not the reverse
+		modpack.Loader = LoaderQuilt
+		modpack.LoaderVersion = manifest.Dependencies.QuiltLoader
 	} else if manifest.Dependencies.FabricLoader != "" {
 		modpack.Loader = LoaderFabric
 		modpack.LoaderVersion = manifest.Dependencies.FabricLoader
@@ -91,6 +106,10 @@ func (p *MRPackParser) Parse(filePath string) (*Modpack, error) {
 	return modpack, nil
 }
 
+// Extract applies the mrpack override layers to destDir: overrides/ first,
+// then server-overrides/ on top. client-overrides/ is never copied to a server.
+// Files placed by the layers are recorded in OverridesRecordFile.
+// Archives without modrinth.index.json are extracted as-is.
 func (p *MRPackParser) Extract(filePath, destDir string) error {
 	reader, err := zip.OpenReader(filePath)
 	if err != nil {
@@ -102,39 +121,63 @@ func (p *MRPackParser) Extract(filePath, destDir string) error {
 		return fmt.Errorf("failed to create destination directory: %w", err)
 	}
 
-	for _, file := range reader.File {
-		if file.Name == "modrinth.index.json" {
-			continue
+	if findZipFile(reader, "modrinth.index.json") == nil {
+		return extractAll(reader, destDir)
+	}
+
+	record := &OverridesRecord{}
+	for _, layer := range []string{LayerOverrides, LayerServerOverrides} {
+		if err := extractLayer(reader, layer, destDir, record); err != nil {
+			return err
 		}
+	}
 
-		path := filepath.Join(destDir, file.Name)
+	return record.Save(destDir)
+}
 
-		if file.FileInfo().IsDir() {
-			os.MkdirAll(path, file.Mode())
+// extractLayer copies the contents of one override directory into destDir
+func extractLayer(reader *zip.ReadCloser, layer, destDir string, record *OverridesRecord) error {
+	prefix := strings.Trim(layer, "/") + "/"
+
+	for _, file := range reader.File {
+		if !strings.HasPrefix(file.Name, prefix) {
 			continue
 		}
 
-		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
-			return fmt.Errorf("failed to create directory: %w", err)
+		relPath := strings.TrimPrefix(file.Name, prefix)
+		if relPath == "" {
+			continue
 		}
 
-		outFile, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, file.Mode())
+		destPath, err := safeJoin(destDir, relPath)
 		if err != nil {
-			return fmt.Errorf("failed to create file: %w", err)
+			return err
 		}
 
-		rc, err := file.Open()
-		if err != nil {
-			outFile.Close()
-			return fmt.Errorf("failed to open file in archive: %w", err)
+		if err := extractZipEntry(file, destPath); err != nil {
+			return err
 		}
 
-		_, err = io.Copy(outFile, rc)
-		rc.Close()
-		outFile.Close()
+		if !file.FileInfo().IsDir() {
+			if err := record.add(relPath, layer, destPath); err != nil {
+				return fmt.Errorf("failed to record %s: %w", relPath, err)
+			}
+		}
+	}
 
+	return nil
+}
+
+// extractAll extracts every entry of a generic zip into destDir
+func extractAll(reader *zip.ReadCloser, destDir string) error {
+	for _, file := range reader.File {
+		destPath, err := safeJoin(destDir, file.Name)
 		if err != nil {
-			return fmt.Errorf("failed to extract file: %w", err)
+			return err
+		}
+
+		if err := extractZipEntry(file, destPath); err != nil {
+			return err
 		}
 	}
 
@@ -165,6 +208,7 @@ type MRPackManifest struct {
 		Forge        string `json:"forge,omitempty"`
 		FabricLoader string `json:"fabric-loader,omitempty"`
 		NeoForge     string `json:"neoforge,omitempty"`
+		QuiltLoader  string `json:"quilt-loader,omitempty"`
 		Fabric       string `json:"fabric,omitempty"`
 	} `json:"dependencies"`
 }
@@ -0,0 +1,131 @@
+package sources
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/alexinslc/chunk/internal/checksum"
+)
+
+// OverridesRecordFile lists the files a pack archive placed in the server directory
+const OverridesRecordFile = ".chunk-overrides.json"
+
+// Override layers in the order they are applied
+const (
+	LayerOverrides       = "overrides"
+	LayerServerOverrides = "server-overrides"
+	LayerClientOverrides = "client-overrides"
+)
+
+// OverrideFile is a file copied from a pack's override layer
+type OverrideFile struct {
+	Path   string `json:"path"`  // Relative to the server directory, slash-separated
+	Layer  string `json:"layer"` // Layer the final content came from
+	SHA256 string `json:"sha256"`
+}
+
+// OverridesRecord tracks pack-owned files so upgrades can tell them apart from user edits
+type OverridesRecord struct {
+	Files []*OverrideFile `json:"files"`
+}
+
+// add records a file, replacing an earlier layer's entry for the same path
+func (r *OverridesRecord) add(relPath, layer, filePath string) error {
+	sums, err := checksum.CalculateFile(filePath)
+	if err != nil {
+		return err
+	}
+
+	entry := &OverrideFile{
+		Path:   filepath.ToSlash(relPath),
+		Layer:  layer,
+		SHA256: sums.SHA256,
+	}
+
+	for i, existing := range r.Files {
+		if existing.Path == entry.Path {
+			r.Files[i] = entry
+			return nil
+		}
+	}
+	r.Files = append(r.Files, entry)
+	return nil
+}
+
+// Save writes the record into the server directory
+func (r *OverridesRecord) Save(serverDir string) error {
+	if r.Files == nil {
+		r.Files = []*OverrideFile{}
+	}
+	sort.Slice(r.Files, func(i, j int) bool {
+		return r.Files[i].Path < r.Files[j].Path
+	})
+
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal overrides record: %w", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(serverDir, OverridesRecordFile), data, 0644); err != nil {
+		return fmt.Errorf("failed to write overrides record: %w", err)
+	}
+
+	return nil
+}
+
+// LoadOverridesRecord reads the record from a server directory, returning nil if none exists
+func LoadOverridesRecord(serverDir string) (*OverridesRecord, error) {
+	data, err := os.ReadFile(filepath.Join(serverDir, OverridesRecordFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read overrides record: %w", err)
+	}
+
+	var record OverridesRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, fmt.Errorf("failed to parse overrides record: %w", err)
+	}
+
+	return &record, nil
+}
+
+// Contains reports whether a server-relative path came from the pack
+func (r *OverridesRecord) Contains(relPath string) bool {
+	relPath = filepath.ToSlash(relPath)
+	for _, file := range r.Files {
+		if file.Path == relPath {
+			return true
+		}
+	}
+	return false
+}
+
+// ModifiedFiles returns pack-owned files whose content no longer matches what
+// the pack shipped, i.e. files the user has edited. Deleted files are skipped.
+func (r *OverridesRecord) ModifiedFiles(serverDir string) ([]string, error) {
+	var modified []string
+
+	for _, file := range r.Files {
+		path := filepath.Join(serverDir, filepath.FromSlash(file.Path))
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			continue
+		}
+
+		sums, err := checksum.CalculateFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		if !strings.EqualFold(sums.SHA256, file.SHA256) {
+			modified = append(modified, file.Path)
+		}
+	}
+
+	return modified, nil
+}
@@ -0,0 +1,167 @@
+package sources
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testMRPackIndex = `{
+  "formatVersion": 1,
+  "game": "minecraft",
+  "versionId": "1.0.0",
+  "name": "Layered Pack",
+  "files": [],
+  "dependencies": {"minecraft": "1.20.1", "fabric-loader": "0.15.3"}
+}`
+
+func TestMRPackExtractLayers(t *testing.T) {
+	tmpDir := t.TempDir()
+	packPath := filepath.Join(tmpDir, "pack.mrpack")
+	createTestZip(t, packPath, map[string]string{
+		"modrinth.index.json":                    testMRPackIndex,
+		"overrides/config/shared.toml":           "from overrides",
+		"overrides/config/common.toml":           "common",
+		"server-overrides/config/shared.toml":    "from server-overrides",
+		"server-overrides/kubejs/server/main.js": "// server script",
+		"client-overrides/options.txt":           "fov:90",
+		"client-overrides/config/shared.toml":    "from client-overrides",
+	})
+
+	destDir := filepath.Join(tmpDir, "server")
+	if err := NewMRPackParser().Extract(packPath, destDir); err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: "config/shared.toml", want: "from server-overrides"},
+		{path: "config/common.toml", want: "common"},
+		{path: "kubejs/server/main.js", want: "// server script"},
+	}
+	for _, tt := range tests {
+		data, err := os.ReadFile(filepath.Join(destDir, tt.path))
+		if err != nil {
+			t.Errorf("Expected %s to be extracted: %v", tt.path, err)
+			continue
+		}
+		if string(data) != tt.want {
+			t.Errorf("%s = %q, want %q", tt.path, data, tt.want)
+		}
+	}
+
+	for _, path := range []string{"options.txt", "modrinth.index.json", "overrides", "server-overrides", "client-overrides"} {
+		if _, err := os.Stat(filepath.Join(destDir, path)); err == nil {
+			t.Errorf("Expected %s not to be extracted", path)
+		}
+	}
+
+	record, err := LoadOverridesRecord(destDir)
+	if err != nil || record == nil {
+		t.Fatalf("Expected overrides record, got %v, %v", record, err)
+	}
+	if len(record.Files) != 3 {
+		t.Fatalf("Expected 3 recorded files, got %d", len(record.Files))
+	}
+	for _, file := range record.Files {
+		if file.Path == "config/shared.toml" && file.Layer != LayerServerOverrides {
+			t.Errorf("Expected shared.toml from %s, got %s", LayerServerOverrides, file.Layer)
+		}
+	}
+}
+
+func TestMRPackExtractGenericZip(t *testing.T) {
+	tmpDir := t.TempDir()
+	packPath := filepath.Join(tmpDir, "serverpack.zip")
+	createTestZip(t, packPath, map[string]string{
+		"mods/jei.jar":       "jar",
+		"config/common.toml": "common",
+	})
+
+	destDir := filepath.Join(tmpDir, "server")
+	if err := NewMRPackParser().Extract(packPath, destDir); err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	for _, path := range []string{"mods/jei.jar", "config/common.toml"} {
+		if _, err := os.Stat(filepath.Join(destDir, path)); err != nil {
+			t.Errorf("Expected %s to be extracted: %v", path, err)
+		}
+	}
+}
+
+func TestOverridesRecordModifiedFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	packPath := filepath.Join(tmpDir, "pack.mrpack")
+	createTestZip(t, packPath, map[string]string{
+		"modrinth.index.json":       testMRPackIndex,
+		"overrides/config/a.toml":   "a",
+		"overrides/config/b.toml":   "b",
+		"overrides/config/gone.txt": "gone",
+	})
+
+	destDir := filepath.Join(tmpDir, "server")
+	if err := NewMRPackParser().Extract(packPath, destDir); err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	// User edits one file and deletes another
+	if err := os.WriteFile(filepath.Join(destDir, "config", "b.toml"), []byte("edited"), 0644); err != nil {
+		t.Fatalf("Failed to edit file: %v", err)
+	}
+	if err := os.Remove(filepath.Join(destDir, "config", "gone.txt")); err != nil {
+		t.Fatalf("Failed to remove file: %v", err)
+	}
+
+	record, err := LoadOverridesRecord(destDir)
+	if err != nil {
+		t.Fatalf("LoadOverridesRecord failed: %v", err)
+	}
+
+	modified, err := record.ModifiedFiles(destDir)
+	if err != nil {
+		t.Fatalf("ModifiedFiles failed: %v", err)
+	}
+	if len(modified) != 1 || modified[0] != "config/b.toml" {
+		t.Errorf("Expected only config/b.toml to be modified, got %v", modified)
+	}
+
+	if !record.Contains(filepath.Join("config", "a.toml")) {
+		t.Error("Expected config/a.toml to be pack-owned")
+	}
+	if record.Contains("server.properties") {
+		t.Error("Expected server.properties not to be pack-owned")
+	}
+}
+
+func TestLoadOverridesRecordMissing(t *testing.T) {
+	record, err := LoadOverridesRecord(t.TempDir())
+	if err != nil || record != nil {
+		t.Errorf("Expected nil record without error, got %v, %v", record, err)
+	}
+}
+
+func TestMRPackParseQuilt(t *testing.T) {
+	tmpDir := t.TempDir()
+	packPath := filepath.Join(tmpDir, "quilt.mrpack")
+	createTestZip(t, packPath, map[string]string{
+		"modrinth.index.json": `{
+  "formatVersion": 1,
+  "game": "minecraft",
+  "versionId": "1.0.0",
+  "name": "Quilt Pack",
+  "files": [],
+  "dependencies": {"minecraft": "1.20.1", "quilt-loader": "0.23.1", "fabric-loader": "0.15.3"}
+}`,
+	})
+
+	modpack, err := NewMRPackParser().Parse(packPath)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if modpack.Loader != LoaderQuilt || modpack.LoaderVersion != "0.23.1" {
+		t.Errorf("Expected quilt 0.23.1, got %s %s", modpack.Loader, modpack.LoaderVersion)
+	}
+}
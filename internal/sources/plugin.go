@@ -0,0 +1,399 @@
+package sources
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/alexinslc/chunk/internal/search"
+	"github.com/alexinslc/chunk/internal/validation"
+)
+
+const (
+	// PluginPrefix is the executable name prefix of source plugins
+	PluginPrefix = "chunk-source-"
+	// PluginProtocolVersion is sent with every request so plugins can reject
+	// requests they do not understand
+	PluginProtocolVersion = 1
+	// pluginTimeout bounds a single plugin call
+	pluginTimeout = 5 * time.Minute
+)
+
+var pluginNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// builtinSources cannot be replaced by plugins
+var builtinSources = map[string]bool{
+	"recipe":   true,
+	"chunkhub": true,
+	"github":   true,
+	"modrinth": true,
+	"local":    true,
+	"bundle":   true,
+}
+
+// PluginClient fetches modpacks from an external chunk-source-<name>
+// executable. Each call runs the executable with the method name as its only
+// argument, writes a JSON request to its stdin and reads a JSON response from
+// its stdout. Stderr is passed through to the user.
+type PluginClient struct {
+	Name string
+	Path string
+}
+
+// PluginRequest is the JSON a plugin receives on stdin
+type PluginRequest struct {
+	Protocol   int    `json:"protocol"`
+	Method     string `json:"method"`
+	Identifier string `json:"identifier,omitempty"`
+	Query      string `json:"query,omitempty"`
+	// Filter is set for searches with facet filters
+	Filter *PluginFilter `json:"filter,omitempty"`
+}
+
+// PluginFilter is the wire form of search.Filter. Plugins may use it to narrow
+// their search; chunk filters the results again either way.
+type PluginFilter struct {
+	Loader    string   `json:"loader,omitempty"`
+	MCVersion string   `json:"mc_version,omitempty"`
+	Tags      []string `json:"tags,omitempty"`
+	MaxRAMGB  int      `json:"max_ram_gb,omitempty"`
+	Author    string   `json:"author,omitempty"`
+}
+
+// PluginResponse is the JSON a plugin writes to stdout. Exactly one of the
+// result fields is set for the method called, or Error on failure.
+type PluginResponse struct {
+	Error    string               `json:"error,omitempty"`
+	Modpack  *PluginModpack       `json:"modpack,omitempty"`
+	Results  []PluginSearchResult `json:"results,omitempty"`
+	Versions []PluginVersion      `json:"versions,omitempty"`
+}
+
+// PluginModpack is the wire form of Modpack
+type PluginModpack struct {
+	Name           string      `json:"name"`
+	Identifier     string      `json:"identifier"`
+	Description    string      `json:"description,omitempty"`
+	MCVersion      string      `json:"mc_version"`
+	Loader         string      `json:"loader"`
+	LoaderVersion  string      `json:"loader_version,omitempty"`
+	Author         string      `json:"author,omitempty"`
+	Mods           []PluginMod `json:"mods,omitempty"`
+	Dependencies   []string    `json:"dependencies,omitempty"`
+	RecommendedRAM int         `json:"recommended_ram_gb,omitempty"`
+}
+
+// PluginMod is the wire form of Mod
+type PluginMod struct {
+	Name        string `json:"name"`
+	Version     string `json:"version,omitempty"`
+	FileName    string `json:"file_name"`
+	DownloadURL string `json:"download_url"`
+	Side        string `json:"side,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+	SHA256      string `json:"sha256,omitempty"`
+	SHA512      string `json:"sha512,omitempty"`
+}
+
+// PluginSearchResult is the wire form of ModpackSearchResult
+type PluginSearchResult struct {
+	Name           string    `json:"name"`
+	Identifier     string    `json:"identifier"`
+	Description    string    `json:"description,omitempty"`
+	MCVersion      string    `json:"mc_version,omitempty"`
+	MCVersions     []string  `json:"mc_versions,omitempty"`
+	Loader         string    `json:"loader,omitempty"`
+	Downloads      int       `json:"downloads,omitempty"`
+	Tags           []string  `json:"tags,omitempty"`
+	Author         string    `json:"author,omitempty"`
+	RecommendedRAM int       `json:"recommended_ram_gb,omitempty"`
+	UpdatedAt      time.Time `json:"updated_at,omitempty"`
+}
+
+// PluginVersion is the wire form of Version
+type PluginVersion struct {
+	Version     string `json:"version"`
+	MCVersion   string `json:"mc_version,omitempty"`
+	Loader      string `json:"loader,omitempty"`
+	ReleaseDate string `json:"release_date,omitempty"`
+	IsStable    bool   `json:"stable,omitempty"`
+	DownloadURL string `json:"download_url,omitempty"`
+	SHA256      string `json:"sha256,omitempty"`
+	SHA512      string `json:"sha512,omitempty"`
+}
+
+// PluginSourceType returns the DetectSource type of a plugin
+func PluginSourceType(name string) string {
+	return "plugin:" + name
+}
+
+// FindPlugin returns the source plugin for name if chunk-source-<name> is on PATH
+func FindPlugin(name string) (*PluginClient, bool) {
+	if !pluginNamePattern.MatchString(name) || builtinSources[name] {
+		return nil, false
+	}
+	path, err := exec.LookPath(PluginPrefix + name)
+	if err != nil {
+		return nil, false
+	}
+	return &PluginClient{Name: name, Path: path}, true
+}
+
+// DiscoverPlugins returns every source plugin on PATH, sorted by name. When
+// the same plugin is in several PATH directories the first one wins.
+func DiscoverPlugins() []*PluginClient {
+	seen := make(map[string]bool)
+	var plugins []*PluginClient
+
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			name, ok := pluginName(entry.Name())
+			if !ok || seen[name] {
+				continue
+			}
+			// LookPath applies the platform's executable checks
+			plugin, ok := FindPlugin(name)
+			if !ok {
+				continue
+			}
+			seen[name] = true
+			plugins = append(plugins, plugin)
+		}
+	}
+
+	sort.Slice(plugins, func(a, b int) bool {
+		return plugins[a].Name < plugins[b].Name
+	})
+	return plugins
+}
+
+// pluginName extracts the plugin name from an executable file name
+func pluginName(fileName string) (string, bool) {
+	if !strings.HasPrefix(fileName, PluginPrefix) {
+		return "", false
+	}
+	name := strings.TrimPrefix(fileName, PluginPrefix)
+	if runtime.GOOS == "windows" {
+		name = strings.TrimSuffix(name, filepath.Ext(name))
+	}
+	if !pluginNamePattern.MatchString(name) || builtinSources[name] {
+		return "", false
+	}
+	return name, true
+}
+
+// pluginIdentifier splits "<name>:<pack>" into the plugin name and the pack
+func pluginIdentifier(identifier string) (name, pack string, ok bool) {
+	name, pack, found := strings.Cut(identifier, ":")
+	// "bench::recipe" is recipe syntax, not a plugin identifier
+	if !found || pack == "" || strings.HasPrefix(pack, ":") || !pluginNamePattern.MatchString(name) {
+		return "", "", false
+	}
+	return name, pack, true
+}
+
+// Fetch resolves a modpack through the plugin
+func (p *PluginClient) Fetch(ctx context.Context, identifier string) (*Modpack, error) {
+	pack := p.stripPrefix(identifier)
+	resp, err := p.call(ctx, PluginRequest{Method: "fetch", Identifier: pack})
+	if err != nil {
+		return nil, err
+	}
+	if resp.Modpack == nil {
+		return nil, fmt.Errorf("source plugin %s returned no modpack", p.Name)
+	}
+	if resp.Modpack.Identifier == "" {
+		resp.Modpack.Identifier = pack
+	}
+	return p.toModpack(resp.Modpack)
+}
+
+// Search searches the plugin's registry
+func (p *PluginClient) Search(ctx context.Context, query string, filter search.Filter) ([]*ModpackSearchResult, error) {
+	req := PluginRequest{Method: "search", Query: query}
+	if !filter.IsEmpty() {
+		req.Filter = &PluginFilter{
+			Loader:    filter.Loader,
+			MCVersion: filter.MCVersion,
+			Tags:      filter.Tags,
+			MaxRAMGB:  filter.MaxRAMGB,
+			Author:    filter.Author,
+		}
+	}
+	resp, err := p.call(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*ModpackSearchResult, 0, len(resp.Results))
+	for _, r := range resp.Results {
+		results = append(results, &ModpackSearchResult{
+			Name:        r.Name,
+			Identifier:  p.addPrefix(r.Identifier),
+			Description: r.Description,
+			MCVersion:   r.MCVersion,
+			Loader:      LoaderType(strings.ToLower(r.Loader)),
+			Source:      p.Name,
+			Downloads:   r.Downloads,
+
+			MCVersions:     r.MCVersions,
+			Tags:           r.Tags,
+			Author:         r.Author,
+			RecommendedRAM: r.RecommendedRAM,
+			UpdatedAt:      r.UpdatedAt,
+		})
+	}
+	return FilterResults(results, filter), nil
+}
+
+// GetVersions lists the versions the plugin knows for a modpack
+func (p *PluginClient) GetVersions(ctx context.Context, identifier string) ([]*Version, error) {
+	resp, err := p.call(ctx, PluginRequest{Method: "versions", Identifier: p.stripPrefix(identifier)})
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make([]*Version, 0, len(resp.Versions))
+	for _, v := range resp.Versions {
+		versions = append(versions, &Version{
+			Version:     v.Version,
+			MCVersion:   v.MCVersion,
+			Loader:      LoaderType(strings.ToLower(v.Loader)),
+			ReleaseDate: v.ReleaseDate,
+			IsStable:    v.IsStable,
+			DownloadURL: v.DownloadURL,
+			SHA256:      v.SHA256,
+			SHA512:      v.SHA512,
+		})
+	}
+	return versions, nil
+}
+
+// call runs the plugin for one request
+func (p *PluginClient) call(ctx context.Context, req PluginRequest) (*PluginResponse, error) {
+	req.Protocol = PluginProtocolVersion
+	input, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode plugin request: %w", err)
+	}
+
+	callCtx, cancel := context.WithTimeout(ctx, pluginTimeout)
+	defer cancel()
+
+	var stdout bytes.Buffer
+	cmd := exec.CommandContext(callCtx, p.Path, req.Method)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = os.Stderr
+
+	runErr := cmd.Run()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if callCtx.Err() == context.DeadlineExceeded {
+		return nil, fmt.Errorf("source plugin %s timed out after %s", p.Name, pluginTimeout)
+	}
+
+	var resp PluginResponse
+	if err := json.Unmarshal(stdout.Bytes(), &resp); err != nil {
+		if runErr != nil {
+			return nil, fmt.Errorf("source plugin %s failed: %w", p.Name, runErr)
+		}
+		return nil, fmt.Errorf("source plugin %s returned invalid JSON: %w", p.Name, err)
+	}
+	if resp.Error != "" {
+		return nil, fmt.Errorf("source plugin %s: %s", p.Name, resp.Error)
+	}
+	if runErr != nil {
+		return nil, fmt.Errorf("source plugin %s failed: %w", p.Name, runErr)
+	}
+
+	return &resp, nil
+}
+
+// toModpack validates and converts a plugin modpack
+func (p *PluginClient) toModpack(m *PluginModpack) (*Modpack, error) {
+	loader := LoaderType(strings.ToLower(m.Loader))
+	switch loader {
+	case LoaderForge, LoaderFabric, LoaderNeoForge, LoaderQuilt:
+	default:
+		return nil, fmt.Errorf("source plugin %s returned unsupported loader: %q", p.Name, m.Loader)
+	}
+	if m.MCVersion == "" {
+		return nil, fmt.Errorf("source plugin %s returned no mc_version", p.Name)
+	}
+
+	mods := make([]*Mod, 0, len(m.Mods))
+	for _, mod := range m.Mods {
+		if mod.DownloadURL == "" || mod.FileName == "" {
+			return nil, fmt.Errorf("source plugin %s returned mod %q without file_name or download_url", p.Name, mod.Name)
+		}
+		if !validation.IsValidFileName(mod.FileName) {
+			return nil, fmt.Errorf("source plugin %s returned mod %q with file_name %q: must be a bare file name", p.Name, mod.Name, mod.FileName)
+		}
+		side := ModSide(strings.ToLower(mod.Side))
+		switch side {
+		case "":
+			side = SideBoth
+		case SideClient, SideServer, SideBoth:
+		default:
+			return nil, fmt.Errorf("source plugin %s returned mod %q with unsupported side: %q", p.Name, mod.Name, mod.Side)
+		}
+		mods = append(mods, &Mod{
+			Name:        mod.Name,
+			Version:     mod.Version,
+			FileName:    mod.FileName,
+			DownloadURL: mod.DownloadURL,
+			Side:        side,
+			Required:    mod.Required,
+			SHA256:      mod.SHA256,
+			SHA512:      mod.SHA512,
+		})
+	}
+
+	dependencies := m.Dependencies
+	if dependencies == nil {
+		dependencies = []string{}
+	}
+
+	return &Modpack{
+		Name:           m.Name,
+		Identifier:     p.addPrefix(m.Identifier),
+		Description:    m.Description,
+		MCVersion:      m.MCVersion,
+		Loader:         loader,
+		LoaderVersion:  m.LoaderVersion,
+		Author:         m.Author,
+		Source:         p.Name,
+		Mods:           mods,
+		Dependencies:   dependencies,
+		RecommendedRAM: m.RecommendedRAM,
+	}, nil
+}
+
+// stripPrefix removes "<name>:" so the plugin sees only its own identifier
+func (p *PluginClient) stripPrefix(identifier string) string {
+	return strings.TrimPrefix(identifier, p.Name+":")
+}
+
+// addPrefix qualifies an identifier returned by the plugin so it routes back
+// to the plugin
+func (p *PluginClient) addPrefix(identifier string) string {
+	if identifier == "" || strings.HasPrefix(identifier, p.Name+":") {
+		return identifier
+	}
+	return p.Name + ":" + identifier
+}
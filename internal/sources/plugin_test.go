@@ -0,0 +1,174 @@
+package sources
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/alexinslc/chunk/internal/search"
+)
+
+// fakePluginScript answers each method with canned JSON and fails for the
+// identifier "broken"
+const fakePluginScript = `#!/bin/sh
+request=$(cat)
+case "$request" in
+  *'"identifier":"broken"'*)
+    echo '{"error": "pack not found"}'
+    exit 1
+    ;;
+esac
+case "$1" in
+  fetch)
+    cat <<'EOF'
+{"modpack": {"name": "Registry Pack", "identifier": "registry-pack", "mc_version": "1.20.1", "loader": "Fabric",
+  "mods": [{"name": "Sodium", "file_name": "sodium.jar", "download_url": "https://registry.example.com/sodium.jar", "side": "client"},
+           {"name": "Lithium", "file_name": "lithium.jar", "download_url": "https://registry.example.com/lithium.jar", "sha512": "abc"}]}}
+EOF
+    ;;
+  search)
+    echo '{"results": [{"name": "Registry Pack", "identifier": "registry-pack", "loader": "fabric"}]}'
+    ;;
+  versions)
+    echo '{"versions": [{"version": "2.0.0", "mc_version": "1.20.1", "stable": true}]}'
+    ;;
+esac
+`
+
+func installFakePlugin(t *testing.T, name string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("Skipping test: fake plugin is a shell script")
+	}
+
+	binDir := t.TempDir()
+	path := filepath.Join(binDir, PluginPrefix+name)
+	if err := os.WriteFile(path, []byte(fakePluginScript), 0755); err != nil {
+		t.Fatalf("Failed to write plugin: %v", err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestDetectSourcePlugin(t *testing.T) {
+	installFakePlugin(t, "acme")
+
+	tests := []struct {
+		identifier string
+		want       string
+	}{
+		{identifier: "acme:registry-pack", want: "plugin:acme"},
+		{identifier: "acme::registry-pack", want: "recipe"},
+		{identifier: "missing:registry-pack", want: "recipe"},
+		{identifier: "modrinth:some-modpack", want: "modrinth"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.identifier, func(t *testing.T) {
+			if got := DetectSource(tt.identifier); got != tt.want {
+				t.Errorf("DetectSource(%q) = %q, want %q", tt.identifier, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPluginClient(t *testing.T) {
+	installFakePlugin(t, "acme")
+	manager := &SourceManager{}
+
+	modpack, err := manager.Fetch(context.Background(), "acme:registry-pack")
+	if err != nil {
+		t.Fatalf("Fetch failed: %v", err)
+	}
+	if modpack.Identifier != "acme:registry-pack" {
+		t.Errorf("Expected identifier 'acme:registry-pack', got '%s'", modpack.Identifier)
+	}
+	if modpack.Loader != LoaderFabric {
+		t.Errorf("Expected loader 'fabric', got '%s'", modpack.Loader)
+	}
+	if modpack.Source != "acme" {
+		t.Errorf("Expected source 'acme', got '%s'", modpack.Source)
+	}
+	if len(modpack.Mods) != 2 {
+		t.Fatalf("Expected 2 mods, got %d", len(modpack.Mods))
+	}
+	if modpack.Mods[0].Side != SideClient || modpack.Mods[1].Side != SideBoth {
+		t.Errorf("Expected sides client and both, got %s and %s", modpack.Mods[0].Side, modpack.Mods[1].Side)
+	}
+	if modpack.Mods[1].SHA512 != "abc" {
+		t.Errorf("Expected SHA512 'abc', got '%s'", modpack.Mods[1].SHA512)
+	}
+
+	versions, err := manager.GetVersions(context.Background(), "acme:registry-pack")
+	if err != nil {
+		t.Fatalf("GetVersions failed: %v", err)
+	}
+	if len(versions) != 1 || versions[0].Version != "2.0.0" || !versions[0].IsStable {
+		t.Errorf("Unexpected versions: %+v", versions)
+	}
+
+	plugin, ok := FindPlugin("acme")
+	if !ok {
+		t.Fatal("Expected plugin to be found on PATH")
+	}
+	results, err := plugin.Search(context.Background(), "registry", search.Filter{})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results) != 1 || results[0].Identifier != "acme:registry-pack" {
+		t.Errorf("Expected search result 'acme:registry-pack', got %+v", results)
+	}
+
+	// The plugin ignores the filter, so chunk applies it to the results
+	results, err = plugin.Search(context.Background(), "registry", search.Filter{Loader: "forge"})
+	if err != nil {
+		t.Fatalf("Filtered search failed: %v", err)
+	}
+	if len(results) != 0 {
+		t.Errorf("Expected the forge filter to drop the fabric result, got %+v", results)
+	}
+
+	if _, err := manager.Fetch(context.Background(), "acme:broken"); err == nil {
+		t.Error("Expected plugin error to be returned")
+	}
+}
+
+func TestDiscoverPlugins(t *testing.T) {
+	installFakePlugin(t, "acme")
+
+	found := false
+	for _, plugin := range DiscoverPlugins() {
+		if plugin.Name == "modrinth" {
+			t.Error("Plugins must not shadow built-in sources")
+		}
+		if plugin.Name == "acme" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("Expected DiscoverPlugins to find chunk-source-acme")
+	}
+}
+
+func TestPluginToModpackRejectsInvalidMods(t *testing.T) {
+	client := &PluginClient{Name: "acme"}
+
+	tests := []struct {
+		name string
+		mod  PluginMod
+	}{
+		{name: "path in file name", mod: PluginMod{Name: "Evil", FileName: "../start.sh", DownloadURL: "https://example.com/evil.jar"}},
+		{name: "nested file name", mod: PluginMod{Name: "Evil", FileName: "../../.bashrc", DownloadURL: "https://example.com/evil.jar"}},
+		{name: "unknown side", mod: PluginMod{Name: "Sodium", FileName: "sodium.jar", DownloadURL: "https://example.com/sodium.jar", Side: "dedicated"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			modpack := &PluginModpack{Name: "Pack", Identifier: "pack", MCVersion: "1.20.1", Loader: "fabric", Mods: []PluginMod{tt.mod}}
+			if _, err := client.toModpack(modpack); err == nil {
+				t.Errorf("Expected %s to be rejected", tt.name)
+			}
+		})
+	}
+}
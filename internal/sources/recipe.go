@@ -1,6 +1,7 @@
 package sources
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -8,17 +9,23 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/alexinslc/chunk/internal/bench"
+	"github.com/alexinslc/chunk/internal/cache"
 	"github.com/alexinslc/chunk/internal/checksum"
 	"github.com/alexinslc/chunk/internal/config"
+	"github.com/alexinslc/chunk/internal/mirror"
 	"github.com/alexinslc/chunk/internal/search"
+	"github.com/alexinslc/chunk/internal/ui"
+	"github.com/alexinslc/chunk/internal/validation"
 )
 
 // RecipeClient handles fetching modpacks from local recipe benches
 type RecipeClient struct {
 	httpClient *http.Client
+	downloader *cache.Downloader
 	manager    *bench.Manager
 }
 
@@ -44,11 +51,14 @@ func NewRecipeClient() *RecipeClient {
 		// but findRecipe will return an appropriate error when called
 		manager = nil
 	}
+	httpClient := &http.Client{
+		Timeout:   10 * time.Minute,
+		Transport: &mirror.Transport{},
+	}
 	return &RecipeClient{
-		httpClient: &http.Client{
-			Timeout: 10 * time.Minute,
-		},
-		manager: manager,
+		httpClient: httpClient,
+		downloader: cache.NewDefaultDownloader(httpClient),
+		manager:    manager,
 	}
 }
 
@@ -56,19 +66,25 @@ func NewRecipeClient() *RecipeClient {
 // Supports formats:
 //   - "atm9" - searches all benches (core bench first)
 //   - "usechunk/recipes::atm9" - forces specific bench
-func (c *RecipeClient) Fetch(identifier string) (*Modpack, error) {
+func (c *RecipeClient) Fetch(ctx context.Context, identifier string) (*Modpack, error) {
+	return c.FetchVariant(ctx, identifier, "")
+}
+
+// FetchVariant fetches a modpack from a recipe with the named variant applied.
+// An empty variant fetches the recipe as written.
+func (c *RecipeClient) FetchVariant(ctx context.Context, identifier, variant string) (*Modpack, error) {
 	// Parse identifier to extract bench and recipe name
 	benchName, recipeName := ParseRecipeIdentifier(identifier)
 
 	// Find the recipe
-	recipe, err := c.FindRecipe(recipeName, benchName)
+	recipe, err := c.FindRecipeVariant(recipeName, benchName, variant)
 	if err != nil {
 		return nil, err
 	}
 
-	// Validate recipe has download URL
-	if recipe.DownloadURL == "" {
-		return nil, fmt.Errorf("recipe \"%s\" does not have a download_url", recipe.Slug)
+	// Validate recipe has something to install
+	if recipe.DownloadURL == "" && len(recipe.Files) == 0 {
+		return nil, fmt.Errorf("recipe \"%s\" does not have a download_url or files", recipe.Slug)
 	}
 
 	// Convert recipe to Modpack
@@ -81,13 +97,13 @@ func (c *RecipeClient) Fetch(identifier string) (*Modpack, error) {
 }
 
 // Search searches for recipes in local benches
-func (c *RecipeClient) Search(query string) ([]*ModpackSearchResult, error) {
+func (c *RecipeClient) Search(ctx context.Context, query string, filter search.Filter) ([]*ModpackSearchResult, error) {
 	searcher, err := search.NewSearcher()
 	if err != nil {
 		return nil, fmt.Errorf("failed to create searcher: %w", err)
 	}
 
-	results, err := searcher.Search(query, "")
+	results, err := searcher.Search(query, filter, search.SortRelevance)
 	if err != nil {
 		return nil, err
 	}
@@ -103,6 +119,12 @@ func (c *RecipeClient) Search(query string) ([]*ModpackSearchResult, error) {
 			MCVersion:   r.MCVersion,
 			Loader:      LoaderType(r.Loader),
 			Source:      "recipe",
+
+			Tags:           r.Tags,
+			Author:         r.Author,
+			RecommendedRAM: r.RecommendedRAMGB,
+			Bench:          r.BenchName,
+			UpdatedAt:      r.UpdatedAt,
 		})
 	}
 
@@ -110,7 +132,7 @@ func (c *RecipeClient) Search(query string) ([]*ModpackSearchResult, error) {
 }
 
 // GetVersions returns available versions for a recipe
-func (c *RecipeClient) GetVersions(identifier string) ([]*Version, error) {
+func (c *RecipeClient) GetVersions(ctx context.Context, identifier string) ([]*Version, error) {
 	// Parse identifier
 	benchName, recipeName := ParseRecipeIdentifier(identifier)
 
@@ -138,6 +160,15 @@ func (c *RecipeClient) GetVersions(identifier string) ([]*Version, error) {
 	return []*Version{version}, nil
 }
 
+// FindRecipeVariant finds a recipe like FindRecipe and applies the named variant
+func (c *RecipeClient) FindRecipeVariant(recipeName, benchFilter, variant string) (*search.Recipe, error) {
+	recipe, err := c.FindRecipe(recipeName, benchFilter)
+	if err != nil {
+		return nil, err
+	}
+	return recipe.WithVariant(variant)
+}
+
 // FindRecipe searches for a recipe in local benches
 func (c *RecipeClient) FindRecipe(recipeName string, benchFilter string) (*search.Recipe, error) {
 	if c.manager == nil {
@@ -166,8 +197,8 @@ func (c *RecipeClient) FindRecipe(recipeName string, benchFilter string) (*searc
 	}
 
 	// Search for recipe in benches (load recipes once per bench)
-	for _, bench := range searchBenches {
-		recipes, err := search.LoadRecipesFromBench(bench.Path, bench.Name)
+	for _, b := range searchBenches {
+		recipes, err := search.LoadRecipesFromBench(b.Path, b.Name)
 		if err != nil {
 			// Skip benches that fail to load
 			continue
@@ -176,14 +207,14 @@ func (c *RecipeClient) FindRecipe(recipeName string, benchFilter string) (*searc
 		// Look for exact slug match first, then name match
 		for _, recipe := range recipes {
 			if recipe.Slug == recipeName {
-				return recipe, nil
+				return c.checkSignature(b, recipe)
 			}
 		}
 
 		// If no slug match, try name match (case-insensitive)
 		for _, recipe := range recipes {
 			if strings.EqualFold(recipe.Name, recipeName) {
-				return recipe, nil
+				return c.checkSignature(b, recipe)
 			}
 		}
 	}
@@ -191,6 +222,24 @@ func (c *RecipeClient) FindRecipe(recipeName string, benchFilter string) (*searc
 	return nil, fmt.Errorf("recipe \"%s\" not found in installed benches", recipeName)
 }
 
+// warnedRecipes holds the recipe files already reported as unverified, so
+// that repeated lookups during one install warn only once
+var warnedRecipes sync.Map
+
+// checkSignature applies the bench's signature policy to a found recipe
+func (c *RecipeClient) checkSignature(b config.Bench, recipe *search.Recipe) (*search.Recipe, error) {
+	warning, err := c.manager.CheckRecipe(b, recipe.FilePath)
+	if err != nil {
+		return nil, fmt.Errorf("recipe \"%s\" from bench \"%s\" rejected: %w", recipe.Slug, b.Name, err)
+	}
+	if warning != nil {
+		if _, warned := warnedRecipes.LoadOrStore(recipe.FilePath, true); !warned {
+			ui.PrintWarning(fmt.Sprintf("Recipe \"%s\" from bench \"%s\" could not be verified: %v", recipe.Slug, b.Name, warning))
+		}
+	}
+	return recipe, nil
+}
+
 // recipeToModpack converts a Recipe to a Modpack
 func (c *RecipeClient) recipeToModpack(recipe *search.Recipe) (*Modpack, error) {
 	// Parse loader type
@@ -202,10 +251,20 @@ func (c *RecipeClient) recipeToModpack(recipe *search.Recipe) (*Modpack, error)
 		loaderType = LoaderFabric
 	case "neoforge":
 		loaderType = LoaderNeoForge
+	case "quilt":
+		loaderType = LoaderQuilt
 	default:
 		return nil, fmt.Errorf("unsupported loader type: %s", recipe.Loader)
 	}
 
+	// Env names end up in the start scripts, so anything but a plain
+	// variable name is refused rather than quoted
+	for name := range recipe.Env {
+		if !validation.IsValidEnvName(name) {
+			return nil, fmt.Errorf("invalid environment variable name %q in recipe %s", name, recipe.Slug)
+		}
+	}
+
 	modpack := &Modpack{
 		Name:           recipe.Name,
 		Identifier:     recipe.Slug,
@@ -215,18 +274,62 @@ func (c *RecipeClient) recipeToModpack(recipe *search.Recipe) (*Modpack, error)
 		LoaderVersion:  recipe.LoaderVersion,
 		Author:         recipe.Author,
 		Source:         fmt.Sprintf("recipe:%s", recipe.BenchName),
-		Mods:           []*Mod{}, // Recipes don't include individual mod lists
+		Mods:           []*Mod{}, // v1 recipes don't include individual mod lists
 		Dependencies:   []string{},
 		RecommendedRAM: recipe.RecommendedRAMGB,
 		ManifestURL:    recipe.DownloadURL,
+		JVMArgs:        recipe.JVMArgs,
+		Env:            recipe.Env,
+		MinJava:        recipe.MinJava,
+		MaxJava:        recipe.MaxJava,
+		Variant:        recipe.Variant,
+	}
+
+	for _, file := range recipe.Files {
+		// File names are joined to mods/, so a path could write anywhere
+		if !validation.IsValidFileName(file.FileName) {
+			return nil, fmt.Errorf("invalid file name %q in recipe %s: must be a bare file name", file.FileName, recipe.Slug)
+		}
+		side := ModSide(strings.ToLower(file.Side))
+		if side == "" {
+			side = SideBoth
+		}
+		name := file.Name
+		if name == "" {
+			name = strings.TrimSuffix(file.FileName, ".jar")
+		}
+		modpack.Mods = append(modpack.Mods, &Mod{
+			Name:        name,
+			Version:     file.Version,
+			FileName:    file.FileName,
+			DownloadURL: file.URL,
+			Side:        side,
+			Required:    true,
+			SHA256:      file.SHA256,
+			SHA512:      file.SHA512,
+		})
+	}
+
+	for _, override := range recipe.ServerOverrides {
+		modpack.ServerFiles = append(modpack.ServerFiles, &ServerFile{
+			Path:        override.Path,
+			DownloadURL: override.URL,
+			SHA256:      override.SHA256,
+			SHA512:      override.SHA512,
+		})
 	}
 
 	return modpack, nil
 }
 
 // DownloadFile downloads a file from the recipe's download URL with progress
-func (c *RecipeClient) DownloadFile(downloadURL string, dest io.Writer, progressCallback func(downloaded, total int64)) error {
-	resp, err := c.httpClient.Get(downloadURL)
+func (c *RecipeClient) DownloadFile(ctx context.Context, downloadURL string, dest io.Writer, progressCallback func(downloaded, total int64)) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", downloadURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to download: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return fmt.Errorf("failed to download: %w", err)
 	}
@@ -266,6 +369,12 @@ func (c *RecipeClient) DownloadFile(downloadURL string, dest io.Writer, progress
 	return nil
 }
 
+// DownloadToFile downloads a file to destPath, resuming an interrupted earlier
+// attempt when possible. If expected has checksums the complete file is verified.
+func (c *RecipeClient) DownloadToFile(ctx context.Context, downloadURL, destPath string, expected *checksum.Checksums, progressCallback func(downloaded, total int64)) error {
+	return c.downloader.Download(ctx, downloadURL, destPath, expected, progressCallback)
+}
+
 // VerifyChecksum verifies the SHA256 checksum of a file
 func VerifyChecksum(filePath string, expectedSHA256 string) error {
 	if expectedSHA256 == "" {
@@ -1,10 +1,16 @@
 package sources
 
 import (
+	"context"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/alexinslc/chunk/internal/bench"
+	"github.com/alexinslc/chunk/internal/config"
+	"github.com/alexinslc/chunk/internal/search"
 )
 
 func TestRecipeClientFetch(t *testing.T) {
@@ -95,7 +101,7 @@ func TestRecipeClientFetch(t *testing.T) {
 
 	// Test fetching recipe
 	client := NewRecipeClient()
-	modpack, err := client.Fetch("test-modpack")
+	modpack, err := client.Fetch(context.Background(), "test-modpack")
 	if err != nil {
 		t.Fatalf("Fetch failed: %v", err)
 	}
@@ -193,7 +199,7 @@ func TestRecipeClientFetchWithBench(t *testing.T) {
 
 	// Test fetching with explicit bench specification
 	client := NewRecipeClient()
-	modpack, err := client.Fetch("my-bench::test-modpack")
+	modpack, err := client.Fetch(context.Background(), "my-bench::test-modpack")
 	if err != nil {
 		t.Fatalf("Fetch with bench failed: %v", err)
 	}
@@ -226,7 +232,7 @@ func TestRecipeClientFetchNotFound(t *testing.T) {
 	}
 
 	client := NewRecipeClient()
-	_, err := client.Fetch("nonexistent-recipe")
+	_, err := client.Fetch(context.Background(), "nonexistent-recipe")
 	if err == nil {
 		t.Error("Expected error when fetching nonexistent recipe")
 	}
@@ -331,7 +337,7 @@ func TestRecipeClientSearch(t *testing.T) {
 
 	// Test search
 	client := NewRecipeClient()
-	results, err := client.Search("all the mods")
+	results, err := client.Search(context.Background(), "all the mods", search.Filter{})
 	if err != nil {
 		t.Fatalf("Search failed: %v", err)
 	}
@@ -346,4 +352,141 @@ func TestRecipeClientSearch(t *testing.T) {
 			t.Errorf("Expected source 'recipe', got '%s'", results[0].Source)
 		}
 	}
+
+	// An empty query with filters lists every matching recipe
+	filter := search.Filter{Loader: "Forge", MCVersion: ">=1.20", Tags: []string{"kitchen-sink"}}
+	results, err = client.Search(context.Background(), "", filter)
+	if err != nil {
+		t.Fatalf("Filtered search failed: %v", err)
+	}
+	if len(results) != 1 || results[0].Identifier != "atm9" {
+		t.Fatalf("Expected only atm9 to match the filter, got %+v", results)
+	}
+	if results[0].Bench != "test-bench" || results[0].UpdatedAt.IsZero() {
+		t.Errorf("Expected bench and update time on the result, got %+v", results[0])
+	}
+}
+
+func TestRecipeClientFetchSignaturePolicy(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", tmpDir)
+	defer os.Setenv("HOME", originalHome)
+
+	benchDir := filepath.Join(tmpDir, ".chunk", "Benches", "third-party")
+	recipesDir := filepath.Join(benchDir, "Recipes")
+	if err := os.MkdirAll(recipesDir, 0755); err != nil {
+		t.Fatalf("Failed to create recipes dir: %v", err)
+	}
+	recipeFile := filepath.Join(recipesDir, "test-modpack.json")
+	recipeContent := `{
+		"name": "Test Modpack",
+		"mc_version": "1.20.1",
+		"loader": "fabric",
+		"download_url": "http://example.com/test.mrpack"
+	}`
+	if err := os.WriteFile(recipeFile, []byte(recipeContent), 0644); err != nil {
+		t.Fatalf("Failed to create recipe: %v", err)
+	}
+
+	publicKey, privateKey, err := bench.GenerateKey()
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+
+	cfg := &config.Config{
+		ConfigVersion:   "1.0",
+		SignaturePolicy: "require",
+		Benches: []config.Bench{{
+			Name:        "third-party",
+			Path:        benchDir,
+			TrustedKeys: []string{publicKey},
+		}},
+	}
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+
+	if _, err := NewRecipeClient().Fetch(context.Background(), "test-modpack"); err == nil {
+		t.Fatal("Expected unsigned recipe to be rejected in require mode")
+	}
+
+	signer, _ := bench.ParsePrivateKey(privateKey)
+	if err := bench.SignRecipe(recipeFile, signer); err != nil {
+		t.Fatalf("SignRecipe() error = %v", err)
+	}
+	if _, err := NewRecipeClient().Fetch(context.Background(), "test-modpack"); err != nil {
+		t.Fatalf("Expected signed recipe to be accepted, got %v", err)
+	}
+
+	tampered := strings.Replace(recipeContent, "example.com", "evil.example.com", 1)
+	if err := os.WriteFile(recipeFile, []byte(tampered), 0644); err != nil {
+		t.Fatalf("Failed to tamper recipe: %v", err)
+	}
+	if _, err := NewRecipeClient().Fetch(context.Background(), "test-modpack"); err == nil {
+		t.Fatal("Expected tampered recipe to be rejected in require mode")
+	}
+}
+
+func TestRecipeToModpackSchemaV2(t *testing.T) {
+	recipe := &search.Recipe{
+		SchemaVersion: search.RecipeSchemaV2,
+		Name:          "Lite Pack",
+		Slug:          "lite-pack",
+		MCVersion:     "1.20.1",
+		Loader:        "Fabric",
+		BenchName:     "test-bench",
+		Files: []search.RecipeFile{
+			{Name: "Lithium", Version: "0.11.2", FileName: "lithium.jar", URL: "https://example.com/lithium.jar", SHA512: "abc", Side: "server"},
+			{FileName: "sodium.jar", URL: "https://example.com/sodium.jar", Side: "client"},
+			{FileName: "ferritecore.jar", URL: "https://example.com/ferritecore.jar"},
+		},
+		ServerOverrides: []search.RecipeOverride{
+			{Path: "config/lithium.properties", URL: "https://example.com/lithium.properties", SHA256: "def"},
+		},
+		JVMArgs: []string{"-Dfml.readTimeout=180"},
+		Env:     map[string]string{"TZ": "UTC"},
+		MinJava: 17,
+		MaxJava: 21,
+	}
+
+	modpack, err := NewRecipeClient().recipeToModpack(recipe)
+	if err != nil {
+		t.Fatalf("recipeToModpack failed: %v", err)
+	}
+
+	if modpack.ManifestURL != "" {
+		t.Errorf("Expected no manifest URL, got '%s'", modpack.ManifestURL)
+	}
+	if len(modpack.Mods) != 3 {
+		t.Fatalf("Expected 3 mods, got %d", len(modpack.Mods))
+	}
+	if mod := modpack.Mods[0]; mod.Name != "Lithium" || mod.Side != SideServer || mod.SHA512 != "abc" || mod.DownloadURL != "https://example.com/lithium.jar" {
+		t.Errorf("Unexpected first mod: %+v", mod)
+	}
+	if modpack.Mods[1].Side != SideClient || modpack.Mods[2].Side != SideBoth {
+		t.Errorf("Expected sides client and both, got %s and %s", modpack.Mods[1].Side, modpack.Mods[2].Side)
+	}
+	if modpack.Mods[2].Name != "ferritecore" {
+		t.Errorf("Expected name from file name, got '%s'", modpack.Mods[2].Name)
+	}
+	if len(modpack.ServerFiles) != 1 || modpack.ServerFiles[0].Path != "config/lithium.properties" || modpack.ServerFiles[0].SHA256 != "def" {
+		t.Errorf("Unexpected server files: %+v", modpack.ServerFiles)
+	}
+	if len(modpack.JVMArgs) != 1 || modpack.Env["TZ"] != "UTC" || modpack.MinJava != 17 || modpack.MaxJava != 21 {
+		t.Errorf("Launch settings not carried over: %+v", modpack)
+	}
+
+	recipe.Env = map[string]string{"TZ=UTC; rm -rf": "x"}
+	if _, err := NewRecipeClient().recipeToModpack(recipe); err == nil {
+		t.Error("Expected an error for an invalid environment variable name")
+	}
+
+	recipe.Env = nil
+	for _, fileName := range []string{"../start.sh", "../../.bashrc", `..\start.bat`, "..", ""} {
+		recipe.Files = []search.RecipeFile{{FileName: fileName, URL: "https://example.com/evil.jar"}}
+		if _, err := NewRecipeClient().recipeToModpack(recipe); err == nil {
+			t.Errorf("Expected an error for file name %q", fileName)
+		}
+	}
 }
@@ -1,8 +1,12 @@
 package sources
 
 import (
+	"context"
 	"errors"
 	"strings"
+	"time"
+
+	"github.com/alexinslc/chunk/internal/search"
 )
 
 var (
@@ -13,9 +17,9 @@ var (
 )
 
 type ModpackSource interface {
-	Fetch(identifier string) (*Modpack, error)
-	Search(query string) ([]*ModpackSearchResult, error)
-	GetVersions(identifier string) ([]*Version, error)
+	Fetch(ctx context.Context, identifier string) (*Modpack, error)
+	Search(ctx context.Context, query string, filter search.Filter) ([]*ModpackSearchResult, error)
+	GetVersions(ctx context.Context, identifier string) ([]*Version, error)
 }
 
 type Modpack struct {
@@ -31,6 +35,24 @@ type Modpack struct {
 	Dependencies   []string
 	RecommendedRAM int
 	ManifestURL    string
+	OverridesDir   string // Archive directory copied over the server root
+	// ServerFiles are placed in the server directory after the pack is
+	// installed, replacing any file at the same path
+	ServerFiles []*ServerFile
+	JVMArgs     []string
+	Env         map[string]string
+	MinJava     int
+	MaxJava     int
+	// Variant is the recipe variant the modpack was resolved with
+	Variant string
+}
+
+// ServerFile is a single file downloaded into the server directory
+type ServerFile struct {
+	Path        string // Relative to the server directory, with forward slashes
+	DownloadURL string
+	SHA256      string
+	SHA512      string
 }
 
 type ModpackSearchResult struct {
@@ -41,6 +63,15 @@ type ModpackSearchResult struct {
 	Loader      LoaderType
 	Source      string
 	Downloads   int
+	// MCVersions and Loaders list every supported Minecraft version and
+	// loader when a source reports more than one
+	MCVersions     []string
+	Loaders        []LoaderType
+	Tags           []string
+	Author         string
+	RecommendedRAM int
+	Bench          string // Bench of a recipe result
+	UpdatedAt      time.Time
 }
 
 type Version struct {
@@ -63,6 +94,7 @@ type Mod struct {
 	Required    bool
 	SHA256      string
 	SHA512      string
+	SHA1        string // Only set by sources that publish no stronger hash
 }
 
 type LoaderType string
@@ -71,6 +103,7 @@ const (
 	LoaderForge    LoaderType = "forge"
 	LoaderFabric   LoaderType = "fabric"
 	LoaderNeoForge LoaderType = "neoforge"
+	LoaderQuilt    LoaderType = "quilt"
 )
 
 type ModSide string
@@ -82,11 +115,23 @@ const (
 )
 
 func DetectSource(identifier string) string {
+	// Server bundles, wherever they are
+	if IsBundle(identifier) {
+		return "bundle"
+	}
+
 	// Local file paths
 	if len(identifier) > 0 && (identifier[0] == '.' || identifier[0] == '/') {
 		return "local"
 	}
 
+	// Source plugins: <name>:pack with chunk-source-<name> on PATH
+	if name, _, ok := pluginIdentifier(identifier); ok {
+		if _, found := FindPlugin(name); found {
+			return PluginSourceType(name)
+		}
+	}
+
 	// Modrinth prefix
 	if len(identifier) > 9 && identifier[:9] == "modrinth:" {
 		return "modrinth"
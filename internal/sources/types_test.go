@@ -95,8 +95,8 @@ func TestModpackStructure(t *testing.T) {
 }
 
 func TestLoaderTypes(t *testing.T) {
-	loaders := []LoaderType{LoaderForge, LoaderFabric, LoaderNeoForge}
-	expected := []string{"forge", "fabric", "neoforge"}
+	loaders := []LoaderType{LoaderForge, LoaderFabric, LoaderNeoForge, LoaderQuilt}
+	expected := []string{"forge", "fabric", "neoforge", "quilt"}
 
 	for i, loader := range loaders {
 		if string(loader) != expected[i] {
@@ -0,0 +1,147 @@
+package supervisor
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// ErrNotRunning is returned when stopping a server that is not running
+var ErrNotRunning = errors.New("server is not running")
+
+// ErrRunning is returned by EnsureStopped for a server that is still running
+var ErrRunning = errors.New("server is running")
+
+// stopGrace is how much longer than the supervisor's own stop sequence
+// Stop waits before killing the supervisor
+var stopGrace = 5 * time.Second
+
+// pollInterval is how often the state file and processes are checked
+var pollInterval = 100 * time.Millisecond
+
+// EnsureStopped fails with ErrRunning while a server started by chunk runs
+// in serverDir, so its files are not replaced or removed under it
+func EnsureStopped(serverDir string) error {
+	state, err := ReadState(serverDir)
+	if err != nil {
+		return err
+	}
+	if state != nil && state.Running() {
+		return fmt.Errorf("%w in %s: stop it first with chunk stop", ErrRunning, serverDir)
+	}
+	return nil
+}
+
+// Stop stops the server supervised in serverDir. The supervisor is asked to
+// stop it gracefully; if that fails or takes longer than the supervisor's
+// stop sequence, the supervisor and the server are killed.
+func Stop(serverDir string) error {
+	state, err := ReadState(serverDir)
+	if err != nil {
+		return err
+	}
+	if state == nil || !state.Running() {
+		return ErrNotRunning
+	}
+
+	if state.supervisorAlive() {
+		if err := requestStop(state.SupervisorPID); err == nil {
+			if waitStopped(serverDir, state.SupervisorPID, state.StopTimeout+killDelay+stopGrace) {
+				return nil
+			}
+		}
+		if process, err := os.FindProcess(state.SupervisorPID); err == nil {
+			process.Kill()
+		}
+		waitExit(state.SupervisorPID, killDelay)
+
+		// The supervisor may have restarted the server since the state was read
+		if latest, err := ReadState(serverDir); err == nil && latest != nil {
+			state = latest
+		}
+	}
+
+	// Without its supervisor, the server has to be stopped directly
+	if state.serverAlive() {
+		process, err := os.FindProcess(state.PID)
+		if err != nil {
+			return fmt.Errorf("failed to find server process: %w", err)
+		}
+		terminate(process)
+		if !waitExit(state.PID, killDelay) {
+			process.Kill()
+			if !waitExit(state.PID, killDelay) {
+				return fmt.Errorf("failed to stop server process %d", state.PID)
+			}
+		}
+	}
+
+	state.Status = StatusStopped
+	state.PID = 0
+	state.ServerStart = ""
+	state.StoppedAt = time.Now()
+	state.NextRestart = time.Time{}
+	return WriteState(serverDir, state)
+}
+
+// WaitStarted waits until the supervisor with the given pid reports the
+// server as running. exited receives the supervisor's exit, so a supervisor
+// that fails early is reported instead of waited on.
+func WaitStarted(serverDir string, pid int, exited <-chan error, timeout time.Duration) (*State, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		state, err := ReadState(serverDir)
+		if err != nil {
+			return nil, err
+		}
+		if state != nil && state.SupervisorPID == pid {
+			switch state.Status {
+			case StatusRunning:
+				return state, nil
+			case StatusStopped, StatusCrashed:
+				return state, fmt.Errorf("server exited right after starting, see %s", state.LogFile)
+			}
+		}
+
+		select {
+		case err := <-exited:
+			if err != nil {
+				return state, fmt.Errorf("supervisor exited: %w", err)
+			}
+			return state, errors.New("supervisor exited before the server started")
+		case <-time.After(pollInterval):
+		}
+		if time.Now().After(deadline) {
+			return state, fmt.Errorf("server did not start within %s", timeout)
+		}
+	}
+}
+
+// waitStopped waits up to timeout for a supervisor to record that it stopped
+// or to exit, and reports whether it did
+func waitStopped(serverDir string, pid int, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for processAlive(pid) {
+		if state, err := ReadState(serverDir); err == nil && state != nil && !state.Active() {
+			return true
+		}
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(pollInterval)
+	}
+	return true
+}
+
+// waitExit waits up to timeout for a process to exit and reports whether it did
+func waitExit(pid int, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for processAlive(pid) {
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(pollInterval)
+	}
+	return true
+}
@@ -0,0 +1,45 @@
+package supervisor
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/alexinslc/chunk/internal/config"
+	"github.com/alexinslc/chunk/internal/converter"
+	"github.com/alexinslc/chunk/internal/sources"
+)
+
+// LaunchCommand returns the command line and extra KEY=VALUE environment
+// that start the server installed in serverDir, the same way its start.sh does
+func LaunchCommand(serverDir string) ([]string, []string, error) {
+	manifest, err := config.LoadChunkManifest(filepath.Join(serverDir, config.ChunkManifestFile))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read installed manifest: %w", err)
+	}
+
+	opts := &converter.ConversionOptions{
+		DestDir:        serverDir,
+		ModpackName:    manifest.Name,
+		MCVersion:      manifest.MCVersion,
+		Loader:         sources.LoaderType(manifest.Loader),
+		LoaderVersion:  manifest.LoaderVersion,
+		RecommendedRAM: manifest.RecommendedRAMGB,
+		JVMArgs:        manifest.JVMArgs,
+		Env:            manifest.Env,
+		MinJava:        manifest.MinJava,
+		MaxJava:        manifest.MaxJava,
+	}
+	command, err := converter.NewScriptGenerator().LaunchCommand(opts)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to determine how to launch the server: %w", err)
+	}
+
+	env := make([]string, 0, len(manifest.Env))
+	for name, value := range manifest.Env {
+		env = append(env, name+"="+value)
+	}
+	sort.Strings(env)
+
+	return command, env, nil
+}
@@ -0,0 +1,71 @@
+//go:build !windows
+
+package supervisor
+
+import (
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// processAlive reports whether a process with the given pid exists
+func processAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	err := syscall.Kill(pid, 0)
+	return err == nil || err == syscall.EPERM
+}
+
+// processStart identifies when the process with the given pid started, so a
+// pid reused by another process after the original exited, or after a reboot,
+// is told apart from it. It returns "" if the start cannot be determined.
+func processStart(pid int) string {
+	if pid <= 0 {
+		return ""
+	}
+
+	// On Linux, the start is the boot plus the clock ticks since boot
+	if stat, err := os.ReadFile("/proc/" + strconv.Itoa(pid) + "/stat"); err == nil {
+		bootID, err := os.ReadFile("/proc/sys/kernel/random/boot_id")
+		if err != nil {
+			return ""
+		}
+		// Fields follow the command name, which may itself contain spaces;
+		// starttime is the 22nd field, the 20th after the name
+		end := strings.LastIndexByte(string(stat), ')')
+		if end < 0 {
+			return ""
+		}
+		fields := strings.Fields(string(stat[end+1:]))
+		if len(fields) < 20 {
+			return ""
+		}
+		return strings.TrimSpace(string(bootID)) + ":" + fields[19]
+	}
+
+	// Elsewhere, ps reports the start time to the second
+	out, err := exec.Command("ps", "-o", "lstart=", "-p", strconv.Itoa(pid)).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// requestStop asks the supervisor with the given pid to stop its server
+func requestStop(pid int) error {
+	return syscall.Kill(pid, syscall.SIGTERM)
+}
+
+// terminate asks a server process to exit
+func terminate(process *os.Process) error {
+	return process.Signal(syscall.SIGTERM)
+}
+
+// Detach makes cmd run in its own session, so the supervisor outlives the
+// terminal that started it
+func Detach(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+}
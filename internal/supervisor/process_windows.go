@@ -0,0 +1,77 @@
+//go:build windows
+
+package supervisor
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"strconv"
+	"syscall"
+)
+
+const (
+	processQueryLimitedInformation = 0x1000
+	stillActive                    = 259
+	detachedProcess                = 0x00000008
+)
+
+// errNoGracefulStop is returned by requestStop, as Windows cannot deliver
+// SIGTERM to another process; chunk stop then ends the processes directly
+var errNoGracefulStop = errors.New("graceful stop is not supported on Windows")
+
+// processAlive reports whether a process with the given pid exists
+func processAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	handle, err := syscall.OpenProcess(processQueryLimitedInformation, false, uint32(pid))
+	if err != nil {
+		return false
+	}
+	defer syscall.CloseHandle(handle)
+
+	var code uint32
+	if err := syscall.GetExitCodeProcess(handle, &code); err != nil {
+		return false
+	}
+	return code == stillActive
+}
+
+// processStart identifies when the process with the given pid started, so a
+// pid reused by another process after the original exited is told apart from
+// it. It returns "" if the start cannot be determined.
+func processStart(pid int) string {
+	if pid <= 0 {
+		return ""
+	}
+	handle, err := syscall.OpenProcess(processQueryLimitedInformation, false, uint32(pid))
+	if err != nil {
+		return ""
+	}
+	defer syscall.CloseHandle(handle)
+
+	var creation, exit, kernel, user syscall.Filetime
+	if err := syscall.GetProcessTimes(handle, &creation, &exit, &kernel, &user); err != nil {
+		return ""
+	}
+	return strconv.FormatInt(creation.Nanoseconds(), 10)
+}
+
+// requestStop asks the supervisor with the given pid to stop its server
+func requestStop(pid int) error {
+	return errNoGracefulStop
+}
+
+// terminate asks a server process to exit
+func terminate(process *os.Process) error {
+	return process.Kill()
+}
+
+// Detach makes cmd run without the console that started it, so the
+// supervisor outlives it
+func Detach(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		CreationFlags: syscall.CREATE_NEW_PROCESS_GROUP | detachedProcess,
+	}
+}
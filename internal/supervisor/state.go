@@ -0,0 +1,143 @@
+package supervisor
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// StateFile is the state the supervisor keeps in the server directory
+const StateFile = ".chunk-server.json"
+
+// Status is the lifecycle state of a supervised server
+type Status string
+
+const (
+	StatusStarting Status = "starting"
+	StatusRunning  Status = "running"
+	// StatusRestarting is the backoff delay before restarting a crashed server
+	StatusRestarting Status = "restarting"
+	StatusStopping   Status = "stopping"
+	StatusStopped    Status = "stopped"
+	// StatusCrashed means the server exited with an error and was not
+	// restarted, or the supervisor itself died
+	StatusCrashed Status = "crashed"
+	// StatusUnsupervised means the supervisor died but the server process is
+	// still running
+	StatusUnsupervised Status = "unsupervised"
+)
+
+// State is the content of the state file
+type State struct {
+	Status        Status    `json:"status"`
+	SupervisorPID int       `json:"supervisor_pid"`
+	PID           int       `json:"pid,omitempty"` // Server process, while it runs
+	StartedAt     time.Time `json:"started_at,omitzero"`
+	StoppedAt     time.Time `json:"stopped_at,omitzero"`
+	ExitCode      int       `json:"exit_code,omitempty"`
+	Restarts      int       `json:"restarts"`
+	NextRestart   time.Time `json:"next_restart,omitzero"`
+	LogFile       string    `json:"log_file"`
+	Command       []string  `json:"command"`
+	// SupervisorStart and ServerStart record when the processes started, so
+	// a stale pid reused by another process is not mistaken for them
+	SupervisorStart string `json:"supervisor_start,omitempty"`
+	ServerStart     string `json:"server_start,omitempty"`
+	// StopTimeout and Restart record how the supervisor was started, so
+	// chunk stop knows how long to wait and chunk restart can reuse them
+	StopTimeout time.Duration `json:"stop_timeout"`
+	Restart     RestartPolicy `json:"restart"`
+}
+
+// Active reports whether the state describes a supervisor that has not exited
+func (s *State) Active() bool {
+	switch s.Status {
+	case StatusStopped, StatusCrashed, StatusUnsupervised:
+		return false
+	}
+	return true
+}
+
+// Current returns the status, corrected for a supervisor that died without
+// updating the state file
+func (s *State) Current() Status {
+	if !s.Active() || s.supervisorAlive() {
+		return s.Status
+	}
+	if s.serverAlive() {
+		return StatusUnsupervised
+	}
+	return StatusCrashed
+}
+
+// supervisorAlive reports whether the recorded supervisor is still running
+func (s *State) supervisorAlive() bool {
+	return sameProcess(s.SupervisorPID, s.SupervisorStart)
+}
+
+// serverAlive reports whether the recorded server process is still running
+func (s *State) serverAlive() bool {
+	return s.PID != 0 && sameProcess(s.PID, s.ServerStart)
+}
+
+// sameProcess reports whether pid is alive and is the process that started
+// at start. State files without a recorded start can only be checked by pid.
+func sameProcess(pid int, start string) bool {
+	if !processAlive(pid) {
+		return false
+	}
+	if start == "" {
+		return true
+	}
+	current := processStart(pid)
+	return current == "" || current == start
+}
+
+// Running reports whether a supervisor or an orphaned server is still alive
+func (s *State) Running() bool {
+	switch s.Current() {
+	case StatusStopped, StatusCrashed:
+		return false
+	}
+	return true
+}
+
+// ReadState reads the state file of a server directory. It returns nil if
+// the server was never started by chunk.
+func ReadState(serverDir string) (*State, error) {
+	data, err := os.ReadFile(filepath.Join(serverDir, StateFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", StateFile, err)
+	}
+
+	var state State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", StateFile, err)
+	}
+	return &state, nil
+}
+
+// WriteState replaces the state file of a server directory
+func WriteState(serverDir string, state *State) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode state: %w", err)
+	}
+
+	// Write to a temporary file first so chunk status never reads a partial state
+	path := filepath.Join(serverDir, StateFile)
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", StateFile, err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to write %s: %w", StateFile, err)
+	}
+	return nil
+}
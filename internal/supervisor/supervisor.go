@@ -0,0 +1,277 @@
+// Package supervisor runs an installed server in the background: it launches
+// the server from its launch layout, captures its output to rotating logs,
+// stops it gracefully and optionally restarts it after a crash.
+package supervisor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+)
+
+const (
+	DefaultStopTimeout = 60 * time.Second
+	DefaultMaxRestarts = 5
+	DefaultBackoff     = 5 * time.Second
+	DefaultMaxBackoff  = 5 * time.Minute
+)
+
+// killDelay is how long a server gets to exit after SIGTERM before it is killed
+var killDelay = 10 * time.Second
+
+// stableUptime is how long a server has to run before a crash no longer
+// counts towards MaxRestarts and the backoff starts over
+var stableUptime = 10 * time.Minute
+
+// RestartPolicy controls restarting a server that exited with an error
+type RestartPolicy struct {
+	Enabled bool `json:"enabled"`
+	// MaxRestarts is how many consecutive crashes are restarted; zero is unlimited
+	MaxRestarts int `json:"max_restarts"`
+	// Backoff is the delay before the first restart. It doubles after every
+	// further crash, up to MaxBackoff.
+	Backoff    time.Duration `json:"backoff"`
+	MaxBackoff time.Duration `json:"max_backoff"`
+}
+
+// Options configures a Supervisor
+type Options struct {
+	ServerDir string
+	// Command is the server command line; LaunchCommand(ServerDir) when empty
+	Command []string
+	// Env holds extra KEY=VALUE pairs added to the environment of the server
+	Env []string
+	// StopTimeout is how long the server gets to exit after the stop command
+	// before it is terminated
+	StopTimeout time.Duration
+	Restart     RestartPolicy
+	MaxLogSize  int64
+	MaxLogFiles int
+}
+
+// Supervisor runs one server and keeps its state file up to date
+type Supervisor struct {
+	opts  Options
+	log   *RotatingLog
+	state *State
+}
+
+// New creates a supervisor, filling in defaults for unset options
+func New(opts Options) *Supervisor {
+	if opts.StopTimeout <= 0 {
+		opts.StopTimeout = DefaultStopTimeout
+	}
+	if opts.Restart.Backoff <= 0 {
+		opts.Restart.Backoff = DefaultBackoff
+	}
+	if opts.Restart.MaxBackoff < opts.Restart.Backoff {
+		opts.Restart.MaxBackoff = max(DefaultMaxBackoff, opts.Restart.Backoff)
+	}
+	if opts.MaxLogSize <= 0 {
+		opts.MaxLogSize = DefaultMaxLogSize
+	}
+	if opts.MaxLogFiles <= 0 {
+		opts.MaxLogFiles = DefaultMaxLogFiles
+	}
+	return &Supervisor{opts: opts}
+}
+
+// Run starts the server and supervises it until ctx is cancelled, which
+// stops the server gracefully, or until the server exits for good. It returns
+// an error if the server could not be started or crashed without being restarted.
+func (s *Supervisor) Run(ctx context.Context) error {
+	if len(s.opts.Command) == 0 {
+		command, env, err := LaunchCommand(s.opts.ServerDir)
+		if err != nil {
+			return err
+		}
+		s.opts.Command = command
+		s.opts.Env = append(env, s.opts.Env...)
+	}
+
+	logPath, err := filepath.Abs(filepath.Join(s.opts.ServerDir, filepath.FromSlash(ConsoleLog)))
+	if err != nil {
+		return fmt.Errorf("failed to resolve log path: %w", err)
+	}
+	s.log, err = OpenRotatingLog(logPath, s.opts.MaxLogSize, s.opts.MaxLogFiles)
+	if err != nil {
+		return err
+	}
+	defer s.log.Close()
+
+	s.state = &State{
+		Status:          StatusStarting,
+		SupervisorPID:   os.Getpid(),
+		SupervisorStart: processStart(os.Getpid()),
+		LogFile:         logPath,
+		Command:         s.opts.Command,
+		StopTimeout:     s.opts.StopTimeout,
+		Restart:         s.opts.Restart,
+	}
+	s.save()
+
+	backoff := s.opts.Restart.Backoff
+	crashes := 0
+	for {
+		started := time.Now()
+		code, stopped, err := s.runOnce(ctx)
+		if err != nil {
+			s.logf("%v", err)
+			s.finish(StatusCrashed, -1)
+			return err
+		}
+		if stopped {
+			s.logf("server stopped")
+			s.finish(StatusStopped, code)
+			return nil
+		}
+		if code == 0 {
+			s.logf("server exited")
+			s.finish(StatusStopped, code)
+			return nil
+		}
+
+		s.logf("server exited with code %d", code)
+		if time.Since(started) >= stableUptime {
+			crashes = 0
+			backoff = s.opts.Restart.Backoff
+		}
+		crashes++
+		policy := s.opts.Restart
+		if !policy.Enabled || (policy.MaxRestarts > 0 && crashes > policy.MaxRestarts) {
+			s.finish(StatusCrashed, code)
+			return fmt.Errorf("server exited with code %d", code)
+		}
+
+		s.state.Status = StatusRestarting
+		s.state.PID = 0
+		s.state.ServerStart = ""
+		s.state.ExitCode = code
+		s.state.Restarts++
+		s.state.NextRestart = time.Now().Add(backoff)
+		s.save()
+		s.logf("restarting in %s", backoff)
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			s.finish(StatusStopped, code)
+			return nil
+		case <-timer.C:
+		}
+		backoff = min(backoff*2, policy.MaxBackoff)
+	}
+}
+
+// runOnce runs the server until it exits or ctx is cancelled. stopped is
+// true if the server was stopped because of ctx.
+func (s *Supervisor) runOnce(ctx context.Context) (code int, stopped bool, err error) {
+	cmd := exec.Command(s.opts.Command[0], s.opts.Command[1:]...)
+	cmd.Dir = s.opts.ServerDir
+	cmd.Env = append(os.Environ(), s.opts.Env...)
+	cmd.Stdout = s.log
+	cmd.Stderr = s.log
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to open server stdin: %w", err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return 0, false, fmt.Errorf("failed to start server: %w", err)
+	}
+	s.state.Status = StatusRunning
+	s.state.PID = cmd.Process.Pid
+	s.state.ServerStart = processStart(cmd.Process.Pid)
+	s.state.StartedAt = time.Now()
+	s.state.NextRestart = time.Time{}
+	s.save()
+	s.logf("server started (pid %d)", cmd.Process.Pid)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+
+	select {
+	case err := <-done:
+		return exitCode(err), false, nil
+	case <-ctx.Done():
+	}
+
+	return s.stop(cmd, stdin, done), true, nil
+}
+
+// stop sends the stop command to the server, then terminates and finally
+// kills it if it does not exit in time. It returns the exit code.
+func (s *Supervisor) stop(cmd *exec.Cmd, stdin io.WriteCloser, done <-chan error) int {
+	s.state.Status = StatusStopping
+	s.save()
+	s.logf("stopping server")
+
+	io.WriteString(stdin, "stop\n")
+	if err, ok := waitDone(done, s.opts.StopTimeout); ok {
+		return exitCode(err)
+	}
+
+	s.logf("server did not stop within %s, terminating it", s.opts.StopTimeout)
+	terminate(cmd.Process)
+	if err, ok := waitDone(done, killDelay); ok {
+		return exitCode(err)
+	}
+
+	s.logf("server did not terminate within %s, killing it", killDelay)
+	cmd.Process.Kill()
+	return exitCode(<-done)
+}
+
+func waitDone(done <-chan error, timeout time.Duration) (error, bool) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case err := <-done:
+		return err, true
+	case <-timer.C:
+		return nil, false
+	}
+}
+
+// finish records that the supervisor is exiting
+func (s *Supervisor) finish(status Status, code int) {
+	s.state.Status = status
+	s.state.PID = 0
+	s.state.ServerStart = ""
+	s.state.ExitCode = code
+	s.state.StoppedAt = time.Now()
+	s.state.NextRestart = time.Time{}
+	s.save()
+}
+
+func (s *Supervisor) save() {
+	if err := WriteState(s.opts.ServerDir, s.state); err != nil {
+		s.logf("%v", err)
+	}
+}
+
+// logf writes a supervisor message to the console log
+func (s *Supervisor) logf(format string, args ...interface{}) {
+	fmt.Fprintf(s.log, "[chunk %s] %s\n", time.Now().Format("2006-01-02 15:04:05"), fmt.Sprintf(format, args...))
+}
+
+// exitCode returns the exit code of a finished command, or -1 if it was
+// killed by a signal
+func exitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	return -1
+}
@@ -0,0 +1,235 @@
+package supervisor
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeServer echoes to stdout and stderr and exits when it reads "stop"
+const fakeServer = `echo "server ready"
+echo "warming up" >&2
+while read line; do
+  if [ "$line" = "stop" ]; then
+    echo "saving worlds"
+    exit 0
+  fi
+done
+`
+
+func skipOnWindows(t *testing.T) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("Skipping test: fake server is a shell script")
+	}
+}
+
+// waitForStatus polls the state file until it reports status
+func waitForStatus(t *testing.T, serverDir string, status Status) *State {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		state, err := ReadState(serverDir)
+		if err != nil {
+			t.Fatalf("ReadState failed: %v", err)
+		}
+		if state != nil && state.Status == status {
+			return state
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatalf("Timed out waiting for status %s", status)
+	return nil
+}
+
+func readLog(t *testing.T, serverDir string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(serverDir, filepath.FromSlash(ConsoleLog)))
+	if err != nil {
+		t.Fatalf("Failed to read console log: %v", err)
+	}
+	return string(data)
+}
+
+func TestSupervisorGracefulStop(t *testing.T) {
+	skipOnWindows(t)
+	serverDir := t.TempDir()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		done <- New(Options{ServerDir: serverDir, Command: []string{"sh", "-c", fakeServer}}).Run(ctx)
+	}()
+
+	state := waitForStatus(t, serverDir, StatusRunning)
+	if state.PID == 0 || state.SupervisorPID != os.Getpid() {
+		t.Errorf("Expected server and supervisor pids, got %d and %d", state.PID, state.SupervisorPID)
+	}
+	if !state.Running() {
+		t.Error("Expected a running state")
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	state, err := ReadState(serverDir)
+	if err != nil {
+		t.Fatalf("ReadState failed: %v", err)
+	}
+	if state.Status != StatusStopped || state.PID != 0 || state.ExitCode != 0 {
+		t.Errorf("Expected a clean stop, got %+v", state)
+	}
+
+	log := readLog(t, serverDir)
+	for _, want := range []string{"server ready", "warming up", "stopping server", "saving worlds", "server stopped"} {
+		if !strings.Contains(log, want) {
+			t.Errorf("Expected console log to contain %q, got:\n%s", want, log)
+		}
+	}
+}
+
+func TestSupervisorTerminatesStuckServer(t *testing.T) {
+	skipOnWindows(t)
+	serverDir := t.TempDir()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		done <- New(Options{
+			ServerDir:   serverDir,
+			Command:     []string{"sh", "-c", "while read line; do :; done"},
+			StopTimeout: 100 * time.Millisecond,
+		}).Run(ctx)
+	}()
+
+	waitForStatus(t, serverDir, StatusRunning)
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if log := readLog(t, serverDir); !strings.Contains(log, "terminating it") {
+		t.Errorf("Expected the server to be terminated, got:\n%s", log)
+	}
+	if state := waitForStatus(t, serverDir, StatusStopped); state.ExitCode != -1 {
+		t.Errorf("Expected exit code -1 for a terminated server, got %d", state.ExitCode)
+	}
+}
+
+func TestSupervisorRestartPolicy(t *testing.T) {
+	skipOnWindows(t)
+	serverDir := t.TempDir()
+
+	err := New(Options{
+		ServerDir: serverDir,
+		Command:   []string{"sh", "-c", "echo crashing; exit 3"},
+		Restart: RestartPolicy{
+			Enabled:     true,
+			MaxRestarts: 2,
+			Backoff:     10 * time.Millisecond,
+		},
+	}).Run(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "code 3") {
+		t.Fatalf("Expected the crash to be reported, got %v", err)
+	}
+
+	state, err := ReadState(serverDir)
+	if err != nil {
+		t.Fatalf("ReadState failed: %v", err)
+	}
+	if state.Status != StatusCrashed || state.Restarts != 2 || state.ExitCode != 3 {
+		t.Errorf("Expected crashed after 2 restarts with code 3, got %+v", state)
+	}
+	if state.Running() {
+		t.Error("Expected a crashed server not to be running")
+	}
+
+	log := readLog(t, serverDir)
+	if got := strings.Count(log, "crashing"); got != 3 {
+		t.Errorf("Expected 3 runs, got %d:\n%s", got, log)
+	}
+	if !strings.Contains(log, "restarting in 10ms") || !strings.Contains(log, "restarting in 20ms") {
+		t.Errorf("Expected doubling backoff, got:\n%s", log)
+	}
+}
+
+func TestSupervisorNoRestart(t *testing.T) {
+	skipOnWindows(t)
+	serverDir := t.TempDir()
+
+	err := New(Options{ServerDir: serverDir, Command: []string{"sh", "-c", "exit 1"}}).Run(context.Background())
+	if err == nil {
+		t.Fatal("Expected an error for a crash without restart policy")
+	}
+	if state, _ := ReadState(serverDir); state == nil || state.Restarts != 0 || state.Status != StatusCrashed {
+		t.Errorf("Expected crashed without restarts, got %+v", state)
+	}
+}
+
+func TestStateCurrent(t *testing.T) {
+	serverDir := t.TempDir()
+	if state, err := ReadState(serverDir); err != nil || state != nil {
+		t.Fatalf("Expected no state for a server never started, got %+v, %v", state, err)
+	}
+
+	// A pid that cannot exist stands in for a supervisor that was killed
+	state := &State{Status: StatusRunning, SupervisorPID: 1 << 30, PID: 1 << 30}
+	if err := WriteState(serverDir, state); err != nil {
+		t.Fatalf("WriteState failed: %v", err)
+	}
+	state, err := ReadState(serverDir)
+	if err != nil {
+		t.Fatalf("ReadState failed: %v", err)
+	}
+	if got := state.Current(); got != StatusCrashed {
+		t.Errorf("Expected a dead supervisor to be reported as crashed, got %s", got)
+	}
+	if err := Stop(serverDir); err != ErrNotRunning {
+		t.Errorf("Expected ErrNotRunning, got %v", err)
+	}
+	if err := EnsureStopped(serverDir); err != nil {
+		t.Errorf("Expected a crashed server to count as stopped, got %v", err)
+	}
+
+	state.SupervisorPID = os.Getpid()
+	if got := state.Current(); got != StatusRunning {
+		t.Errorf("Expected a live supervisor to be reported as running, got %s", got)
+	}
+	if err := WriteState(serverDir, state); err != nil {
+		t.Fatalf("WriteState failed: %v", err)
+	}
+	if err := EnsureStopped(serverDir); !errors.Is(err, ErrRunning) {
+		t.Errorf("Expected ErrRunning, got %v", err)
+	}
+
+	// A live pid that started at another time belongs to another process
+	state.SupervisorStart = processStart(os.Getpid())
+	if state.SupervisorStart == "" {
+		t.Skip("Process start times are not available")
+	}
+	if got := state.Current(); got != StatusRunning {
+		t.Errorf("Expected the recorded supervisor to be reported as running, got %s", got)
+	}
+	state.SupervisorStart = "reused"
+	if got := state.Current(); got != StatusCrashed {
+		t.Errorf("Expected a reused supervisor pid to be reported as crashed, got %s", got)
+	}
+	if err := WriteState(serverDir, state); err != nil {
+		t.Fatalf("WriteState failed: %v", err)
+	}
+	if err := Stop(serverDir); err != ErrNotRunning {
+		t.Errorf("Expected Stop to leave a reused pid alone, got %v", err)
+	}
+	if err := EnsureStopped(serverDir); err != nil {
+		t.Errorf("Expected a reused pid to count as stopped, got %v", err)
+	}
+}
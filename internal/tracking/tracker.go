@@ -2,22 +2,46 @@ package tracking
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strings"
 	"time"
 )
 
+// ErrNameTaken is returned when an instance name is already used by another installation
+var ErrNameTaken = errors.New("instance name already in use")
+
+// namePattern is what instance names may look like, e.g. "survival-eu"
+var namePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+// invalidNameChars matches runs of characters that cannot appear in a name
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9_-]+`)
+
 // Installation represents a single modpack installation record
 type Installation struct {
+	// Name identifies the installation in commands such as chunk upgrade <name>;
+	// unique across the registry
+	Name           string                 `json:"name"`
 	Slug           string                 `json:"slug"`
 	Version        string                 `json:"version"`
 	Bench          string                 `json:"bench"`
 	Path           string                 `json:"path"`
+	Variant        string                 `json:"variant,omitempty"`      // Recipe variant; upgrades keep it
+	ServiceUnit    string                 `json:"service_unit,omitempty"` // Unit file written by chunk service install
+	RCON           *RCON                  `json:"rcon,omitempty"`         // Console enabled by chunk install --rcon
 	InstalledAt    time.Time              `json:"installed_at"`
 	RecipeSnapshot map[string]interface{} `json:"recipe_snapshot"`
 }
 
+// RCON holds the credentials of an RCON console chunk enabled
+type RCON struct {
+	Port     int    `json:"port"`
+	Password string `json:"password"`
+}
+
 // InstallationRegistry contains all tracked installations
 type InstallationRegistry struct {
 	Installations []*Installation `json:"installations"`
@@ -71,9 +95,87 @@ func (t *Tracker) Load() (*InstallationRegistry, error) {
 		registry.Installations = []*Installation{}
 	}
 
+	// Records written before instance names existed are named on first load
+	if assignMissingNames(&registry) {
+		// Read-only callers still get names if the registry cannot be written
+		_ = t.Save(&registry)
+	}
+
 	return &registry, nil
 }
 
+// ValidateName checks that name can be used as an instance name
+func ValidateName(name string) error {
+	if !namePattern.MatchString(name) {
+		return fmt.Errorf("invalid instance name %q: use up to 64 lowercase letters, digits, '-' or '_', starting with a letter or digit", name)
+	}
+	return nil
+}
+
+// assignMissingNames names every unnamed installation after its directory, or
+// its slug when the directory has a generic name. Returns true if any changed.
+func assignMissingNames(registry *InstallationRegistry) bool {
+	changed := false
+	for _, installation := range registry.Installations {
+		if installation.Name == "" {
+			installation.Name = uniqueName(registry, defaultName(installation))
+			changed = true
+		}
+	}
+	return changed
+}
+
+// defaultName derives an instance name from an installation's path or slug
+func defaultName(installation *Installation) string {
+	for _, candidate := range []string{filepath.Base(installation.Path), installation.Slug} {
+		name := strings.Trim(invalidNameChars.ReplaceAllString(strings.ToLower(candidate), "-"), "-_")
+		if len(name) > 64 {
+			name = name[:64]
+		}
+		if name != "" && name != "server" && namePattern.MatchString(name) {
+			return name
+		}
+	}
+	return "server"
+}
+
+// uniqueName returns base, or base-2, base-3... if base is already used
+func uniqueName(registry *InstallationRegistry, base string) string {
+	name := base
+	for n := 2; nameInUse(registry, name, ""); n++ {
+		name = fmt.Sprintf("%s-%d", base, n)
+	}
+	return name
+}
+
+// nameInUse reports whether an installation other than the one at path uses name
+func nameInUse(registry *InstallationRegistry, name, path string) bool {
+	for _, installation := range registry.Installations {
+		if installation.Name == name && installation.Path != path {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckName returns ErrNameTaken if name belongs to an installation other than
+// the one at path, so an install can fail before any files are written
+func (t *Tracker) CheckName(name, path string) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+
+	registry, err := t.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load registry: %w", err)
+	}
+
+	if nameInUse(registry, name, path) {
+		return fmt.Errorf("%w: %s", ErrNameTaken, name)
+	}
+	return nil
+}
+
 // Save writes the installation registry to disk
 func (t *Tracker) Save(registry *InstallationRegistry) error {
 	if registry == nil {
@@ -90,9 +192,15 @@ func (t *Tracker) Save(registry *InstallationRegistry) error {
 		return fmt.Errorf("failed to marshal registry: %w", err)
 	}
 
-	if err := os.WriteFile(t.registryPath, data, 0644); err != nil {
+	// installed.json holds RCON passwords, so only its owner may read it.
+	// WriteFile keeps the mode of an existing file, which older versions
+	// created world-readable.
+	if err := os.WriteFile(t.registryPath, data, 0600); err != nil {
 		return fmt.Errorf("failed to write installed.json: %w", err)
 	}
+	if err := os.Chmod(t.registryPath, 0600); err != nil {
+		return fmt.Errorf("failed to restrict installed.json: %w", err)
+	}
 
 	return nil
 }
@@ -112,6 +220,27 @@ func (t *Tracker) AddInstallation(installation *Installation) error {
 		return fmt.Errorf("failed to load registry: %w", err)
 	}
 
+	// Reinstalls and upgrades keep the name and service unit the path already
+	// has. The RCON console is the caller's to carry, since only upgrades keep
+	// the server.properties that enables it.
+	for _, existing := range registry.Installations {
+		if existing.Path != installation.Path {
+			continue
+		}
+		if installation.Name == "" {
+			installation.Name = existing.Name
+		}
+		if installation.ServiceUnit == "" {
+			installation.ServiceUnit = existing.ServiceUnit
+		}
+	}
+
+	if installation.Name == "" {
+		installation.Name = uniqueName(registry, defaultName(installation))
+	} else if nameInUse(registry, installation.Name, installation.Path) {
+		return fmt.Errorf("%w: %s", ErrNameTaken, installation.Name)
+	}
+
 	// Check if installation at this path already exists
 	for i, existing := range registry.Installations {
 		if existing.Path == installation.Path {
@@ -169,6 +298,26 @@ func (t *Tracker) GetInstallation(path string) (*Installation, error) {
 	return nil, nil // Not found
 }
 
+// GetInstallationByName retrieves an installation record by instance name
+func (t *Tracker) GetInstallationByName(name string) (*Installation, error) {
+	if name == "" {
+		return nil, fmt.Errorf("name cannot be empty")
+	}
+
+	registry, err := t.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load registry: %w", err)
+	}
+
+	for _, installation := range registry.Installations {
+		if installation.Name == name {
+			return installation, nil
+		}
+	}
+
+	return nil, nil // Not found
+}
+
 // ListInstallations returns all installation records
 func (t *Tracker) ListInstallations() ([]*Installation, error) {
 	registry, err := t.Load()
@@ -194,10 +343,17 @@ func (t *Tracker) UpdateInstallation(installation *Installation) error {
 		return fmt.Errorf("failed to load registry: %w", err)
 	}
 
+	if installation.Name != "" && nameInUse(registry, installation.Name, installation.Path) {
+		return fmt.Errorf("%w: %s", ErrNameTaken, installation.Name)
+	}
+
 	// Find and update the installation
 	found := false
 	for i, existing := range registry.Installations {
 		if existing.Path == installation.Path {
+			if installation.Name == "" {
+				installation.Name = existing.Name
+			}
 			registry.Installations[i] = installation
 			found = true
 			break
@@ -229,5 +385,11 @@ func validateInstallation(installation *Installation) error {
 		return fmt.Errorf("installed_at is required")
 	}
 
+	if installation.Name != "" {
+		if err := ValidateName(installation.Name); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
@@ -2,8 +2,10 @@ package tracking
 
 import (
 	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"testing"
 	"time"
@@ -103,6 +105,30 @@ func TestTrackerSaveAndLoad(t *testing.T) {
 	}
 }
 
+func TestTrackerSavePrivate(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file modes are not enforced on Windows")
+	}
+	tracker := createTestTracker(t)
+	defer cleanupTestTracker(t, tracker)
+
+	// Registries written by older versions were world-readable
+	if err := os.WriteFile(tracker.registryPath, []byte(`{"installations":[]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := tracker.Save(&InstallationRegistry{}); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	info, err := os.Stat(tracker.registryPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("Expected installed.json to be private, got %v", info.Mode().Perm())
+	}
+}
+
 func TestTrackerAddInstallation(t *testing.T) {
 	tracker := createTestTracker(t)
 	defer cleanupTestTracker(t, tracker)
@@ -600,6 +626,140 @@ func TestTrackerJSONFormat(t *testing.T) {
 
 // Helper functions
 
+func TestTrackerMigratesUnnamedInstallations(t *testing.T) {
+	tracker := createTestTracker(t)
+	defer cleanupTestTracker(t, tracker)
+
+	// A registry written before instance names existed
+	legacy := `{"installations": [
+  {"slug": "atm9", "version": "0.3.2", "path": "/opt/minecraft/survival", "installed_at": "2025-01-15T10:30:00Z"},
+  {"slug": "atm9", "version": "0.3.2", "path": "/opt/minecraft/atm9/server", "installed_at": "2025-01-15T10:30:00Z"},
+  {"slug": "atm9", "version": "0.3.2", "path": "/srv/server", "installed_at": "2025-01-15T10:30:00Z"},
+  {"slug": "Vault Hunters", "version": "3.0", "path": "/srv/VH Server", "installed_at": "2025-01-15T10:30:00Z"}
+]}`
+	if err := os.WriteFile(tracker.registryPath, []byte(legacy), 0644); err != nil {
+		t.Fatalf("Failed to write registry: %v", err)
+	}
+
+	list, err := tracker.ListInstallations()
+	if err != nil {
+		t.Fatalf("ListInstallations failed: %v", err)
+	}
+
+	want := []string{"survival", "atm9", "atm9-2", "vh-server"}
+	for i, inst := range list {
+		if inst.Name != want[i] {
+			t.Errorf("Installation at %s named %q, want %q", inst.Path, inst.Name, want[i])
+		}
+	}
+
+	// The migration is written back so names stay stable
+	data, err := os.ReadFile(tracker.registryPath)
+	if err != nil {
+		t.Fatalf("Failed to read registry: %v", err)
+	}
+	if !strings.Contains(string(data), `"name": "atm9-2"`) {
+		t.Errorf("Expected migrated names to be saved, got:\n%s", data)
+	}
+}
+
+func TestTrackerInstallationNames(t *testing.T) {
+	tracker := createTestTracker(t)
+	defer cleanupTestTracker(t, tracker)
+
+	survival := &Installation{
+		Name:        "survival-eu",
+		Slug:        "atm9",
+		Version:     "0.3.2",
+		Path:        "/opt/minecraft/eu",
+		ServiceUnit: "/etc/systemd/system/chunk-survival-eu.service",
+		RCON:        &RCON{Port: 25575, Password: "secret"},
+		InstalledAt: time.Now().UTC(),
+	}
+	if err := tracker.AddInstallation(survival); err != nil {
+		t.Fatalf("AddInstallation failed: %v", err)
+	}
+
+	found, err := tracker.GetInstallationByName("survival-eu")
+	if err != nil || found == nil || found.Path != "/opt/minecraft/eu" {
+		t.Fatalf("GetInstallationByName = %+v, %v", found, err)
+	}
+
+	// Another path cannot take the name
+	duplicate := &Installation{
+		Name:        "survival-eu",
+		Slug:        "atm9",
+		Version:     "0.3.2",
+		Path:        "/opt/minecraft/us",
+		InstalledAt: time.Now().UTC(),
+	}
+	if err := tracker.AddInstallation(duplicate); !errors.Is(err, ErrNameTaken) {
+		t.Errorf("Expected ErrNameTaken, got %v", err)
+	}
+	if err := tracker.CheckName("survival-eu", "/opt/minecraft/us"); !errors.Is(err, ErrNameTaken) {
+		t.Errorf("Expected CheckName to report ErrNameTaken, got %v", err)
+	}
+	if err := tracker.CheckName("survival-eu", "/opt/minecraft/eu"); err != nil {
+		t.Errorf("Expected the owner to keep its name, got %v", err)
+	}
+
+	// Upgrades re-track without a name and keep the existing one, and the
+	// service unit along with it
+	upgraded := &Installation{
+		Slug:        "atm9",
+		Version:     "0.3.3",
+		Path:        "/opt/minecraft/eu",
+		InstalledAt: time.Now().UTC(),
+	}
+	if err := tracker.AddInstallation(upgraded); err != nil {
+		t.Fatalf("AddInstallation failed: %v", err)
+	}
+	if upgraded.Name != "survival-eu" {
+		t.Errorf("Expected upgrade to keep name survival-eu, got %q", upgraded.Name)
+	}
+	if upgraded.ServiceUnit != survival.ServiceUnit {
+		t.Errorf("Expected upgrade to keep the service unit, got %q", upgraded.ServiceUnit)
+	}
+	if upgraded.RCON != nil {
+		t.Errorf("Expected the record to hold only the RCON console it was given, got %+v", upgraded.RCON)
+	}
+
+	if err := tracker.AddInstallation(&Installation{
+		Name:        "Survival EU",
+		Slug:        "atm9",
+		Version:     "0.3.2",
+		Path:        "/opt/minecraft/other",
+		InstalledAt: time.Now().UTC(),
+	}); err == nil {
+		t.Error("Expected invalid name to be rejected")
+	}
+}
+
+func TestValidateName(t *testing.T) {
+	tests := []struct {
+		name    string
+		wantErr bool
+	}{
+		{"survival-eu", false},
+		{"atm9_2", false},
+		{"9lives", false},
+		{"", true},
+		{"-leading", true},
+		{"Upper", true},
+		{"has space", true},
+		{"../escape", true},
+		{strings.Repeat("a", 65), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateName(tt.name); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateName(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func createTestTracker(t *testing.T) *Tracker {
 	tmpDir, err := os.MkdirTemp("", "chunk-tracking-test-*")
 	if err != nil {
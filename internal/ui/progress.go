@@ -139,3 +139,13 @@ func PrintWarning(message string) {
 func PrintInfo(message string) {
 	fmt.Printf("ℹ %s\n", message)
 }
+
+// Verbose enables PrintVerbose output; set by the global --verbose flag
+var Verbose bool
+
+// PrintVerbose prints detail that is only shown with --verbose
+func PrintVerbose(message string) {
+	if Verbose {
+		fmt.Printf("· %s\n", message)
+	}
+}
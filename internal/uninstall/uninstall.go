@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/alexinslc/chunk/internal/supervisor"
 	"github.com/alexinslc/chunk/internal/tracking"
 	"github.com/alexinslc/chunk/internal/ui"
 )
@@ -63,6 +64,11 @@ func (u *Uninstaller) Uninstall(opts *Options) (*Result, error) {
 		return nil, fmt.Errorf("server directory does not exist: %s", serverDir)
 	}
 
+	// A running server would keep writing to the files being removed
+	if err := supervisor.EnsureStopped(serverDir); err != nil {
+		return nil, err
+	}
+
 	// Get installation info from tracker
 	installation, err := u.tracker.GetInstallation(serverDir)
 	if err != nil {
@@ -125,6 +131,16 @@ func (u *Uninstaller) Uninstall(opts *Options) (*Result, error) {
 		}
 	}
 
+	// Remove the systemd unit chunk service install created
+	if installation != nil && installation.ServiceUnit != "" {
+		if err := os.Remove(installation.ServiceUnit); err != nil && !os.IsNotExist(err) {
+			ui.PrintWarning(fmt.Sprintf("Failed to remove service unit %s: %v", installation.ServiceUnit, err))
+		} else {
+			result.RemovedPaths = append(result.RemovedPaths, installation.ServiceUnit)
+			ui.PrintSuccess(fmt.Sprintf("Removed: %s", installation.ServiceUnit))
+		}
+	}
+
 	// Remove from tracking
 	if err := u.tracker.RemoveInstallation(serverDir); err != nil {
 		ui.PrintWarning(fmt.Sprintf("Failed to update installation tracking: %v", err))
@@ -151,9 +167,11 @@ func (u *Uninstaller) determinePathsToRemoveAndPreserve(serverDir string, keepWo
 		"forge-installer.jar",
 		"fabric-installer.jar",
 		"neoforge-installer.jar",
+		"quilt-installer.jar",
 		"server.jar",
 		"forge.jar",
 		"fabric-server-launch.jar",
+		"quilt-server-launch.jar",
 		"run.sh",
 		"run.bat",
 		"user_jvm_args.txt",
@@ -258,6 +276,9 @@ func (u *Uninstaller) displayUninstallPlan(installation *tracking.Installation,
 			fmt.Printf("  - %s\n", path)
 		}
 	}
+	if installation != nil && installation.ServiceUnit != "" {
+		fmt.Printf("  - %s (service unit)\n", installation.ServiceUnit)
+	}
 
 	if len(toPreserve) > 0 {
 		fmt.Println()
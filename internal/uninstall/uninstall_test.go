@@ -1,12 +1,14 @@
 package uninstall
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/alexinslc/chunk/internal/supervisor"
 	"github.com/alexinslc/chunk/internal/tracking"
 )
 
@@ -57,6 +59,28 @@ func TestUninstallDirectoryNotExists(t *testing.T) {
 	}
 }
 
+func TestUninstallRunningServer(t *testing.T) {
+	uninstaller, err := NewUninstaller()
+	if err != nil {
+		t.Fatalf("NewUninstaller failed: %v", err)
+	}
+
+	// This process stands in for the supervisor of a running server
+	serverDir := t.TempDir()
+	state := &supervisor.State{Status: supervisor.StatusRunning, SupervisorPID: os.Getpid()}
+	if err := supervisor.WriteState(serverDir, state); err != nil {
+		t.Fatalf("WriteState failed: %v", err)
+	}
+
+	_, err = uninstaller.Uninstall(&Options{ServerDir: serverDir, Force: true})
+	if !errors.Is(err, supervisor.ErrRunning) {
+		t.Errorf("Expected ErrRunning, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(serverDir, supervisor.StateFile)); err != nil {
+		t.Errorf("Expected the server directory to be left alone: %v", err)
+	}
+}
+
 func TestUninstallWithForceAndKeepWorlds(t *testing.T) {
 	// Create test server directory
 	tmpDir := t.TempDir()
@@ -405,3 +429,53 @@ func TestUninstallEmptyDirectory(t *testing.T) {
 		t.Error("Expected no paths to be removed from empty directory")
 	}
 }
+
+func TestUninstallRemovesServiceUnit(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	serverDir := filepath.Join(t.TempDir(), "server")
+	if err := os.MkdirAll(filepath.Join(serverDir, "mods"), 0755); err != nil {
+		t.Fatalf("Failed to create mods directory: %v", err)
+	}
+	unitPath := filepath.Join(t.TempDir(), "chunk-atm9.service")
+	if err := os.WriteFile(unitPath, []byte("[Unit]\n"), 0644); err != nil {
+		t.Fatalf("Failed to write unit: %v", err)
+	}
+
+	tracker, err := tracking.NewTracker()
+	if err != nil {
+		t.Fatalf("Failed to create tracker: %v", err)
+	}
+	installation := &tracking.Installation{
+		Slug:        "atm9",
+		Version:     "1.0.0",
+		Bench:       "test",
+		Path:        serverDir,
+		ServiceUnit: unitPath,
+		InstalledAt: time.Now(),
+	}
+	if err := tracker.AddInstallation(installation); err != nil {
+		t.Fatalf("Failed to add installation: %v", err)
+	}
+
+	uninstaller, err := NewUninstaller()
+	if err != nil {
+		t.Fatalf("NewUninstaller failed: %v", err)
+	}
+	result, err := uninstaller.Uninstall(&Options{ServerDir: serverDir, KeepWorlds: true, Force: true})
+	if err != nil {
+		t.Fatalf("Uninstall failed: %v", err)
+	}
+
+	if _, err := os.Stat(unitPath); !os.IsNotExist(err) {
+		t.Error("Expected the service unit to be removed")
+	}
+	found := false
+	for _, path := range result.RemovedPaths {
+		if path == unitPath {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected %s in removed paths, got %v", unitPath, result.RemovedPaths)
+	}
+}
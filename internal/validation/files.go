@@ -55,6 +55,7 @@ func (f *FileValidator) validateServerJar(serverDir string) *ValidationError {
 		"server.jar",
 		"forge-*.jar",
 		"fabric-server-launch.jar",
+		"quilt-server-launch.jar",
 		"neoforge-*.jar",
 	}
 
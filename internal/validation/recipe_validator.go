@@ -12,6 +12,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/alexinslc/chunk/internal/java"
 	"github.com/alexinslc/chunk/internal/search"
 )
 
@@ -30,6 +31,10 @@ const (
 var (
 	mcVersionRegexp = regexp.MustCompile(`^\d+\.\d+(\.\d+)?$`)
 	semverRegexp    = regexp.MustCompile(`^\d+\.\d+\.\d+(-[a-zA-Z0-9.-]+)?(\+[a-zA-Z0-9.-]+)?$`)
+	sha256Regexp    = regexp.MustCompile(`^[a-fA-F0-9]{64}$`)
+	sha512Regexp    = regexp.MustCompile(`^[a-fA-F0-9]{128}$`)
+	envNameRegexp   = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	variantRegexp   = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
 )
 
 // NewRecipeValidator creates a new recipe validator
@@ -82,6 +87,12 @@ func (v *RecipeValidator) ValidateRecipe(recipe *search.Recipe, filePath string)
 	// Validate license
 	v.validateLicense(recipe, result)
 
+	// Validate schema v2 fields
+	v.validateSchema(recipe, result)
+
+	// Validate variants
+	v.validateVariants(recipe, result)
+
 	// Validate naming (slug matches filename)
 	if filePath != "" {
 		v.validateNaming(recipe, filePath, result)
@@ -145,19 +156,20 @@ func (v *RecipeValidator) validateRequiredFields(recipe *search.Recipe, result *
 		result.Errors = append(result.Errors, RecipeValidationError{
 			Field:      "loader",
 			Message:    "Loader is required",
-			Suggestion: "Add loader (forge, fabric, or neoforge)",
+			Suggestion: "Add loader (forge, fabric, neoforge, or quilt)",
 		})
 	}
 
-	if recipe.DownloadURL == "" {
+	// A v2 recipe may list its mods instead of pointing at an archive
+	if recipe.DownloadURL == "" && len(recipe.Files) == 0 {
 		result.Errors = append(result.Errors, RecipeValidationError{
 			Field:      "download_url",
 			Message:    "Download URL is required",
-			Suggestion: "Add download_url pointing to the modpack archive",
+			Suggestion: "Add download_url pointing to the modpack archive, or list mods in files (schema_version 2)",
 		})
 	}
 
-	if recipe.SHA256 == "" {
+	if recipe.SHA256 == "" && recipe.DownloadURL != "" {
 		result.Warnings = append(result.Warnings, RecipeValidationWarning{
 			Field:   "sha256",
 			Message: "SHA-256 checksum is recommended for integrity verification",
@@ -193,6 +205,7 @@ func (v *RecipeValidator) validateLoader(recipe *search.Recipe, result *Validati
 		"forge":    true,
 		"fabric":   true,
 		"neoforge": true,
+		"quilt":    true,
 	}
 
 	loader := strings.ToLower(recipe.Loader)
@@ -200,7 +213,7 @@ func (v *RecipeValidator) validateLoader(recipe *search.Recipe, result *Validati
 		result.Errors = append(result.Errors, RecipeValidationError{
 			Field:      "loader",
 			Message:    fmt.Sprintf("Invalid loader: %s", recipe.Loader),
-			Suggestion: "Use one of: forge, fabric, neoforge",
+			Suggestion: "Use one of: forge, fabric, neoforge, quilt",
 		})
 	}
 
@@ -275,6 +288,273 @@ func (v *RecipeValidator) validateNaming(recipe *search.Recipe, filePath string,
 	}
 }
 
+// validateSchema checks schema_version and the fields it introduces
+func (v *RecipeValidator) validateSchema(recipe *search.Recipe, result *ValidationResult) {
+	schema := recipe.Schema()
+	if schema != search.RecipeSchemaV1 && schema != search.RecipeSchemaV2 {
+		result.Errors = append(result.Errors, RecipeValidationError{
+			Field:      "schema_version",
+			Message:    fmt.Sprintf("Unsupported schema version: %d", recipe.SchemaVersion),
+			Suggestion: "Use schema_version 1 or 2",
+		})
+		return
+	}
+
+	usesV2 := len(recipe.Files) > 0 || len(recipe.ServerOverrides) > 0 || len(recipe.JVMArgs) > 0 ||
+		len(recipe.Env) > 0 || recipe.MinJava != 0 || recipe.MaxJava != 0
+	for _, variant := range recipe.Variants {
+		usesV2 = usesV2 || len(variant.Files) > 0 || len(variant.ServerOverrides) > 0
+	}
+	if schema == search.RecipeSchemaV1 {
+		if usesV2 {
+			result.Errors = append(result.Errors, RecipeValidationError{
+				Field:      "schema_version",
+				Message:    "files, server_overrides, jvm_args, env, min_java and max_java require schema version 2",
+				Suggestion: "Add \"schema_version\": 2",
+			})
+		}
+		return
+	}
+
+	v.validateFiles(recipe, result)
+	v.validateServerOverrides(recipe, result)
+	v.validateLaunchSettings(recipe, result)
+}
+
+func (v *RecipeValidator) validateFiles(recipe *search.Recipe, result *ValidationResult) {
+	seen := make(map[string]bool)
+	for i, file := range recipe.Files {
+		field := fmt.Sprintf("files[%d]", i)
+
+		switch {
+		case file.FileName == "":
+			result.Errors = append(result.Errors, RecipeValidationError{
+				Field:      field + ".file_name",
+				Message:    "File name is required",
+				Suggestion: "Add file_name with the jar name to save in mods/",
+			})
+		case !IsValidFileName(file.FileName):
+			result.Errors = append(result.Errors, RecipeValidationError{
+				Field:      field + ".file_name",
+				Message:    fmt.Sprintf("File name must not contain a path: %s", file.FileName),
+				Suggestion: "Use a bare file name such as \"sodium.jar\"",
+			})
+		case seen[strings.ToLower(file.FileName)]:
+			result.Errors = append(result.Errors, RecipeValidationError{
+				Field:      field + ".file_name",
+				Message:    fmt.Sprintf("Duplicate file name: %s", file.FileName),
+				Suggestion: "List each mod jar once",
+			})
+		}
+		seen[strings.ToLower(file.FileName)] = true
+
+		v.validateEntryURL(field+".url", file.URL, result)
+		v.validateEntryHashes(field, file.SHA256, file.SHA512, false, result)
+
+		switch file.Side {
+		case "", "client", "server", "both":
+		default:
+			result.Errors = append(result.Errors, RecipeValidationError{
+				Field:      field + ".side",
+				Message:    fmt.Sprintf("Invalid side: %s", file.Side),
+				Suggestion: "Use one of: client, server, both",
+			})
+		}
+	}
+}
+
+func (v *RecipeValidator) validateServerOverrides(recipe *search.Recipe, result *ValidationResult) {
+	seen := make(map[string]bool)
+	for i, override := range recipe.ServerOverrides {
+		field := fmt.Sprintf("server_overrides[%d]", i)
+
+		clean := filepath.ToSlash(filepath.Clean(filepath.FromSlash(override.Path)))
+		switch {
+		case override.Path == "":
+			result.Errors = append(result.Errors, RecipeValidationError{
+				Field:      field + ".path",
+				Message:    "Path is required",
+				Suggestion: "Add the path relative to the server directory (e.g., \"config/server.toml\")",
+			})
+		case strings.HasPrefix(override.Path, "/") || filepath.IsAbs(override.Path) || clean == "." ||
+			clean == ".." || strings.HasPrefix(clean, "../"):
+			result.Errors = append(result.Errors, RecipeValidationError{
+				Field:      field + ".path",
+				Message:    fmt.Sprintf("Path must stay inside the server directory: %s", override.Path),
+				Suggestion: "Use a relative path without \"..\"",
+			})
+		case seen[clean]:
+			result.Errors = append(result.Errors, RecipeValidationError{
+				Field:      field + ".path",
+				Message:    fmt.Sprintf("Duplicate path: %s", override.Path),
+				Suggestion: "List each file once",
+			})
+		}
+		seen[clean] = true
+
+		v.validateEntryURL(field+".url", override.URL, result)
+		v.validateEntryHashes(field, override.SHA256, override.SHA512, true, result)
+	}
+}
+
+func (v *RecipeValidator) validateLaunchSettings(recipe *search.Recipe, result *ValidationResult) {
+	for name := range recipe.Env {
+		if !IsValidEnvName(name) {
+			result.Errors = append(result.Errors, RecipeValidationError{
+				Field:      "env",
+				Message:    fmt.Sprintf("Invalid environment variable name: %s", name),
+				Suggestion: "Use letters, digits and underscores, not starting with a digit",
+			})
+		}
+	}
+
+	for i, arg := range recipe.JVMArgs {
+		if strings.TrimSpace(arg) == "" {
+			result.Errors = append(result.Errors, RecipeValidationError{
+				Field:      fmt.Sprintf("jvm_args[%d]", i),
+				Message:    "JVM argument is empty",
+				Suggestion: "Remove the empty entry",
+			})
+		}
+	}
+
+	if recipe.MinJava < 0 || recipe.MaxJava < 0 {
+		result.Errors = append(result.Errors, RecipeValidationError{
+			Field:      "min_java",
+			Message:    "Java versions must be positive",
+			Suggestion: "Use a Java major version such as 17 or 21",
+		})
+		return
+	}
+	if recipe.MinJava != 0 && recipe.MaxJava != 0 && recipe.MinJava > recipe.MaxJava {
+		result.Errors = append(result.Errors, RecipeValidationError{
+			Field:      "max_java",
+			Message:    fmt.Sprintf("max_java (%d) is lower than min_java (%d)", recipe.MaxJava, recipe.MinJava),
+			Suggestion: "Swap the values or widen the range",
+		})
+	}
+	if recipe.MaxJava != 0 && recipe.MCVersion != "" {
+		if required := java.GetRequiredJavaVersion(recipe.MCVersion); recipe.MaxJava < required {
+			result.Errors = append(result.Errors, RecipeValidationError{
+				Field:      "max_java",
+				Message:    fmt.Sprintf("Minecraft %s needs Java %d or newer, but max_java is %d", recipe.MCVersion, required, recipe.MaxJava),
+				Suggestion: fmt.Sprintf("Raise max_java to at least %d", required),
+			})
+		}
+	}
+}
+
+func (v *RecipeValidator) validateVariants(recipe *search.Recipe, result *ValidationResult) {
+	seen := make(map[string]bool)
+	for i, variant := range recipe.Variants {
+		field := fmt.Sprintf("variants[%d]", i)
+
+		switch {
+		case variant.Name == "":
+			result.Errors = append(result.Errors, RecipeValidationError{
+				Field:      field + ".name",
+				Message:    "Variant name is required",
+				Suggestion: "Name the variant, e.g. \"lite\" or \"performance\"",
+			})
+		case !variantRegexp.MatchString(variant.Name):
+			result.Errors = append(result.Errors, RecipeValidationError{
+				Field:      field + ".name",
+				Message:    fmt.Sprintf("Invalid variant name: %s", variant.Name),
+				Suggestion: "Use lowercase letters, digits and hyphens",
+			})
+		case seen[variant.Name]:
+			result.Errors = append(result.Errors, RecipeValidationError{
+				Field:      field + ".name",
+				Message:    fmt.Sprintf("Duplicate variant name: %s", variant.Name),
+				Suggestion: "Give each variant a unique name",
+			})
+		}
+		seen[variant.Name] = true
+
+		if variant.DownloadURL != "" {
+			v.validateEntryURL(field+".download_url", variant.DownloadURL, result)
+		}
+		if variant.RecommendedRAMGB < 0 || variant.DiskSpaceGB < 0 {
+			result.Errors = append(result.Errors, RecipeValidationError{
+				Field:      field,
+				Message:    "RAM and disk space must be positive",
+				Suggestion: "Remove the field to keep the recipe's value",
+			})
+		}
+
+		// Variant files are checked like the recipe's, under the variant's field
+		if recipe.Schema() == search.RecipeSchemaV2 {
+			entries := &ValidationResult{}
+			v.validateFiles(&search.Recipe{Files: variant.Files}, entries)
+			v.validateServerOverrides(&search.Recipe{ServerOverrides: variant.ServerOverrides}, entries)
+			for _, err := range entries.Errors {
+				err.Field = field + "." + err.Field
+				result.Errors = append(result.Errors, err)
+			}
+			for _, warn := range entries.Warnings {
+				warn.Field = field + "." + warn.Field
+				result.Warnings = append(result.Warnings, warn)
+			}
+		}
+	}
+}
+
+// validateEntryURL checks the URL of a files or server_overrides entry
+func (v *RecipeValidator) validateEntryURL(field, entryURL string, result *ValidationResult) {
+	if entryURL == "" {
+		result.Errors = append(result.Errors, RecipeValidationError{
+			Field:      field,
+			Message:    "URL is required",
+			Suggestion: "Add the direct download URL",
+		})
+		return
+	}
+
+	parsedURL, err := url.Parse(entryURL)
+	if err != nil || (parsedURL.Scheme != "http" && parsedURL.Scheme != "https") || parsedURL.Host == "" {
+		result.Errors = append(result.Errors, RecipeValidationError{
+			Field:      field,
+			Message:    fmt.Sprintf("Invalid URL: %s", entryURL),
+			Suggestion: "Use an absolute http or https URL",
+		})
+	}
+}
+
+// validateEntryHashes checks the checksums of a files or server_overrides
+// entry. Overrides replace server files verbatim, so they must be pinned.
+func (v *RecipeValidator) validateEntryHashes(field, sha256Sum, sha512Sum string, required bool, result *ValidationResult) {
+	if sha256Sum != "" && !sha256Regexp.MatchString(sha256Sum) {
+		result.Errors = append(result.Errors, RecipeValidationError{
+			Field:      field + ".sha256",
+			Message:    "SHA-256 checksum must be 64 hex characters",
+			Suggestion: "Recalculate the checksum with sha256sum",
+		})
+	}
+	if sha512Sum != "" && !sha512Regexp.MatchString(sha512Sum) {
+		result.Errors = append(result.Errors, RecipeValidationError{
+			Field:      field + ".sha512",
+			Message:    "SHA-512 checksum must be 128 hex characters",
+			Suggestion: "Recalculate the checksum with sha512sum",
+		})
+	}
+	if sha256Sum != "" || sha512Sum != "" {
+		return
+	}
+
+	if required {
+		result.Errors = append(result.Errors, RecipeValidationError{
+			Field:      field + ".sha256",
+			Message:    "Checksum is required",
+			Suggestion: "Add sha256 or sha512 so the file can be verified",
+		})
+	} else {
+		result.Warnings = append(result.Warnings, RecipeValidationWarning{
+			Field:   field + ".sha256",
+			Message: "Checksum is recommended for integrity verification",
+		})
+	}
+}
+
 func (v *RecipeValidator) validateURLReachability(downloadURL string) (int64, error) {
 	resp, err := v.httpClient.Head(downloadURL)
 	if err != nil {
@@ -335,6 +615,18 @@ func isValidMCVersion(version string) bool {
 	return mcVersionRegexp.MatchString(version)
 }
 
+// IsValidEnvName checks if name can be set as an environment variable by the
+// start scripts
+func IsValidEnvName(name string) bool {
+	return envNameRegexp.MatchString(name)
+}
+
+// IsValidFileName checks if name is a bare file name, which cannot leave
+// the directory it is joined to
+func IsValidFileName(name string) bool {
+	return name != "" && name != "." && name != ".." && !strings.ContainsAny(name, `/\`)
+}
+
 // isValidSemver checks if a version follows semantic versioning
 func isValidSemver(version string) bool {
 	// Basic semver check: MAJOR.MINOR.PATCH
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/alexinslc/chunk/internal/search"
@@ -181,9 +182,9 @@ func TestValidateLoader(t *testing.T) {
 			expectError: false,
 		},
 		{
-			name:        "invalid loader",
+			name:        "valid quilt",
 			loader:      "quilt",
-			expectError: true,
+			expectError: false,
 		},
 		{
 			name:        "invalid loader",
@@ -529,6 +530,245 @@ func TestIsValidSPDXLicense(t *testing.T) {
 	}
 }
 
+func TestValidateSchemaV2(t *testing.T) {
+	validator := NewRecipeValidator()
+	sha256Sum := strings.Repeat("a", 64)
+
+	base := func() *search.Recipe {
+		return &search.Recipe{
+			SchemaVersion: search.RecipeSchemaV2,
+			Name:          "Lite Pack",
+			MCVersion:     "1.20.1",
+			Loader:        "fabric",
+			LoaderVersion: "0.15.0",
+			License:       "MIT",
+			Files: []search.RecipeFile{
+				{FileName: "lithium.jar", URL: "https://example.com/lithium.jar", SHA256: sha256Sum, Side: "server"},
+			},
+			ServerOverrides: []search.RecipeOverride{
+				{Path: "config/lithium.properties", URL: "https://example.com/lithium.properties", SHA256: sha256Sum},
+			},
+			JVMArgs: []string{"-Dfml.readTimeout=180"},
+			Env:     map[string]string{"TZ": "UTC"},
+			MinJava: 17,
+			MaxJava: 21,
+		}
+	}
+
+	tests := []struct {
+		name       string
+		modify     func(r *search.Recipe)
+		errorField string
+	}{
+		{name: "valid files-only recipe", modify: func(r *search.Recipe) {}},
+		{
+			name:       "v2 fields without schema_version",
+			modify:     func(r *search.Recipe) { r.SchemaVersion = 0; r.DownloadURL = "https://example.com/pack.zip" },
+			errorField: "schema_version",
+		},
+		{
+			name:       "unsupported schema_version",
+			modify:     func(r *search.Recipe) { r.SchemaVersion = 3 },
+			errorField: "schema_version",
+		},
+		{
+			name:       "no download_url or files",
+			modify:     func(r *search.Recipe) { r.Files = nil },
+			errorField: "download_url",
+		},
+		{
+			name:       "file name with path",
+			modify:     func(r *search.Recipe) { r.Files[0].FileName = "../lithium.jar" },
+			errorField: "files[0].file_name",
+		},
+		{
+			name: "duplicate file name",
+			modify: func(r *search.Recipe) {
+				r.Files = append(r.Files, search.RecipeFile{FileName: "Lithium.jar", URL: "https://example.com/other.jar", SHA256: sha256Sum})
+			},
+			errorField: "files[1].file_name",
+		},
+		{
+			name:       "file with invalid url",
+			modify:     func(r *search.Recipe) { r.Files[0].URL = "ftp://example.com/lithium.jar" },
+			errorField: "files[0].url",
+		},
+		{
+			name:       "malformed checksum",
+			modify:     func(r *search.Recipe) { r.Files[0].SHA256 = "abc123" },
+			errorField: "files[0].sha256",
+		},
+		{
+			name:       "invalid side",
+			modify:     func(r *search.Recipe) { r.Files[0].Side = "dedicated" },
+			errorField: "files[0].side",
+		},
+		{
+			name:       "override escaping the server directory",
+			modify:     func(r *search.Recipe) { r.ServerOverrides[0].Path = "config/../../etc/passwd" },
+			errorField: "server_overrides[0].path",
+		},
+		{
+			name:       "absolute override path",
+			modify:     func(r *search.Recipe) { r.ServerOverrides[0].Path = "/etc/passwd" },
+			errorField: "server_overrides[0].path",
+		},
+		{
+			name:       "override without checksum",
+			modify:     func(r *search.Recipe) { r.ServerOverrides[0].SHA256 = "" },
+			errorField: "server_overrides[0].sha256",
+		},
+		{
+			name:       "invalid env name",
+			modify:     func(r *search.Recipe) { r.Env["1BAD"] = "x" },
+			errorField: "env",
+		},
+		{
+			name:       "min_java above max_java",
+			modify:     func(r *search.Recipe) { r.MinJava = 21; r.MaxJava = 17 },
+			errorField: "max_java",
+		},
+		{
+			name:       "max_java too old for the Minecraft version",
+			modify:     func(r *search.Recipe) { r.MinJava = 0; r.MaxJava = 11 },
+			errorField: "max_java",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recipe := base()
+			tt.modify(recipe)
+			result := validator.ValidateRecipe(recipe, "")
+
+			if tt.errorField == "" {
+				if len(result.Errors) > 0 {
+					t.Errorf("expected no errors, got: %v", result.Errors)
+				}
+				return
+			}
+
+			found := false
+			for _, err := range result.Errors {
+				if err.Field == tt.errorField {
+					found = true
+					break
+				}
+			}
+			if !found {
+				t.Errorf("expected error for field '%s', got: %v", tt.errorField, result.Errors)
+			}
+		})
+	}
+
+	t.Run("file without checksum warns", func(t *testing.T) {
+		recipe := base()
+		recipe.Files[0].SHA256 = ""
+		result := validator.ValidateRecipe(recipe, "")
+		if len(result.Errors) > 0 {
+			t.Errorf("expected no errors, got: %v", result.Errors)
+		}
+		found := false
+		for _, warn := range result.Warnings {
+			if warn.Field == "files[0].sha256" {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("expected checksum warning, got: %v", result.Warnings)
+		}
+	})
+}
+
+func TestValidateVariants(t *testing.T) {
+	validator := NewRecipeValidator()
+
+	base := func() *search.Recipe {
+		return &search.Recipe{
+			Name:          "All The Mods 9",
+			MCVersion:     "1.20.1",
+			Loader:        "forge",
+			LoaderVersion: "47.3.0",
+			DownloadURL:   "https://example.com/atm9.zip",
+			SHA256:        "abc123",
+			License:       "MIT",
+			Variants: []search.RecipeVariant{
+				{Name: "lite", DownloadURL: "https://example.com/atm9-lite.zip", SHA256: "def456", RecommendedRAMGB: 6},
+				{Name: "full"},
+			},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		modify     func(r *search.Recipe)
+		errorField string
+	}{
+		{name: "valid variants", modify: func(r *search.Recipe) {}},
+		{
+			name:       "missing name",
+			modify:     func(r *search.Recipe) { r.Variants[1].Name = "" },
+			errorField: "variants[1].name",
+		},
+		{
+			name:       "invalid name",
+			modify:     func(r *search.Recipe) { r.Variants[1].Name = "Full Pack" },
+			errorField: "variants[1].name",
+		},
+		{
+			name:       "duplicate name",
+			modify:     func(r *search.Recipe) { r.Variants[1].Name = "lite" },
+			errorField: "variants[1].name",
+		},
+		{
+			name:       "invalid download url",
+			modify:     func(r *search.Recipe) { r.Variants[0].DownloadURL = "ftp://example.com/lite.zip" },
+			errorField: "variants[0].download_url",
+		},
+		{
+			name: "variant files without schema 2",
+			modify: func(r *search.Recipe) {
+				r.Variants[0].Files = []search.RecipeFile{{FileName: "spark.jar", URL: "https://example.com/spark.jar"}}
+			},
+			errorField: "schema_version",
+		},
+		{
+			name: "invalid variant file",
+			modify: func(r *search.Recipe) {
+				r.SchemaVersion = search.RecipeSchemaV2
+				r.Variants[0].Files = []search.RecipeFile{{FileName: "mods/spark.jar", URL: "https://example.com/spark.jar"}}
+			},
+			errorField: "variants[0].files[0].file_name",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recipe := base()
+			tt.modify(recipe)
+			result := validator.ValidateRecipe(recipe, "")
+
+			if tt.errorField == "" {
+				if len(result.Errors) > 0 {
+					t.Errorf("expected no errors, got: %v", result.Errors)
+				}
+				return
+			}
+
+			found := false
+			for _, err := range result.Errors {
+				if err.Field == tt.errorField {
+					found = true
+					break
+				}
+			}
+			if !found {
+				t.Errorf("expected error for field '%s', got: %v", tt.errorField, result.Errors)
+			}
+		})
+	}
+}
+
 func TestValidateRecipeIntegration(t *testing.T) {
 	validator := NewRecipeValidator()
 
@@ -558,7 +798,7 @@ func TestValidateRecipeIntegration(t *testing.T) {
 		recipe := &search.Recipe{
 			Name:        "Invalid Pack",
 			MCVersion:   "1.20.x",  // Invalid format
-			Loader:      "quilt",   // Invalid loader
+			Loader:      "vanilla", // Invalid loader
 			DownloadURL: "ftp://example.com/pack.zip", // Invalid scheme
 			License:     "Custom",  // Invalid SPDX
 		}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
 )
 
 type SmokeTest struct{}
@@ -60,6 +61,7 @@ func (s *SmokeTest) testServerJarExists(serverDir string) TestResult {
 		"minecraft_server.*.jar",
 		"forge-*.jar",
 		"fabric-server-launch.jar",
+		"quilt-server-launch.jar",
 		"neoforge-*.jar",
 	}
 
@@ -144,6 +146,14 @@ func (s *SmokeTest) testStartScriptExists(serverDir string) TestResult {
 }
 
 func (s *SmokeTest) testStartScriptExecutable(serverDir string) TestResult {
+	if runtime.GOOS == "windows" {
+		return TestResult{
+			Name:    "Script Permissions",
+			Passed:  true,
+			Message: "Skipped (no executable bit on Windows)",
+		}
+	}
+
 	scriptPath := filepath.Join(serverDir, "start.sh")
 
 	info, err := os.Stat(scriptPath)